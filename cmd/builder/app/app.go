@@ -1,23 +1,38 @@
 package app
 
 import (
+	"bytes"
 	"context"
 	_ "embed"
+	"errors"
 	"fmt"
 	htmltpl "html/template"
+	"io"
 	"maps"
 	"os"
+	"path"
 	"path/filepath"
+	"runtime"
 	"slices"
 	"strconv"
 	"strings"
+	"sync"
 
 	"gh.tarampamp.am/error-pages/v4/internal/appmeta"
+	"gh.tarampamp.am/error-pages/v4/internal/archive"
+	"gh.tarampamp.am/error-pages/v4/internal/cdn"
 	"gh.tarampamp.am/error-pages/v4/internal/cli"
 	"gh.tarampamp.am/error-pages/v4/internal/cli/shared"
 	"gh.tarampamp.am/error-pages/v4/internal/codes"
+	"gh.tarampamp.am/error-pages/v4/internal/errgroup"
+	"gh.tarampamp.am/error-pages/v4/internal/formats"
+	"gh.tarampamp.am/error-pages/v4/internal/minify"
+	"gh.tarampamp.am/error-pages/v4/internal/precompress"
+	"gh.tarampamp.am/error-pages/v4/internal/raster"
 	tpl "gh.tarampamp.am/error-pages/v4/internal/template"
 	"gh.tarampamp.am/error-pages/v4/internal/template/tploader"
+	"gh.tarampamp.am/error-pages/v4/internal/webconf"
+	"gh.tarampamp.am/error-pages/v4/l10n"
 	"gh.tarampamp.am/error-pages/v4/templates"
 )
 
@@ -30,12 +45,40 @@ var indexTpl string
 type App struct {
 	cmd cli.Command
 
+	mu       sync.Mutex      // protects the manifest and the history, since the pages are rendered concurrently
+	manifest []manifestEntry // every written file
+	jobs     chan struct{}   // limits the number of pages rendered concurrently
+
+	oversized struct { // the HAProxy errorfiles exceeding the size budget
+		count   int
+		largest string // the display path of the largest one
+		size    int
+	}
+
 	opt struct {
 		createIndex         bool
-		targetDirAbsPath    string
+		targetDirAbsPath    string // the directory the files are written into (the staging one during the build)
+		outDirAbsPath       string // the directory to place the built pages into (if not the archive)
+		archivePath         string // the absolute path of the archive to pack the pages into (if requested)
+		archiveFormat       archive.Format
 		disableBuiltInCodes bool
+		builtInCodes        string // the set of the built-in codes
 		addHTTPCodes        map[string]codes.Description
-		customTemplate      string
+		codesFile           *codes.File // nil if not set
+		templates           []string    // built-in templates to render (all, if empty)
+		codesFilter         codes.Filter
+		jobs                uint
+		formats             []formats.Format
+		customTemplates     struct{ html, json, xml, text, yaml, markdown, svg string }
+		emitConfig          []webconf.Server
+		haproxyErrorFiles   bool
+		haproxySizeBudget   uint
+		targets             []cdn.Target
+		configRoot          string
+		minify              bool
+		precompress         []precompress.Encoding
+		locales             []string
+		stripL10nScript     bool
 		l10nDisabled        bool
 		homepageURL         string
 		links               []tpl.Link
@@ -57,8 +100,29 @@ func NewApp(name string) *App {
 		createIndexFlag         = newCreateIndexFlag()
 		targetDirPath           = newTargetDirPath(".")
 		disableBuiltInCodesFlag = shared.NewDisableBuiltInCodesFlag()
+		builtInCodesFlag        = shared.NewBuiltInCodesFlag()
+		templatesFlag           = newTemplatesFlag()
+		codesFlag               = newCodesFlag()
+		jobsFlag                = newJobsFlag()
 		addHTTPCodesFlag        = shared.NewAddHTTPCodesFlag()
+		codesFileFlag           = shared.NewCodesFileFlag()
+		formatsFlag             = newFormatsFlag()
 		templateFlag            = newTemplateFlag()
+		jsonTemplateFlag        = newJSONTemplateFlag()
+		xmlTemplateFlag         = newXMLTemplateFlag()
+		textTemplateFlag        = newPlainTextTemplateFlag()
+		yamlTemplateFlag        = newYAMLTemplateFlag()
+		markdownTemplateFlag    = newMarkdownTemplateFlag()
+		svgTemplateFlag         = newSVGTemplateFlag()
+		emitConfigFlag          = newEmitConfigFlag()
+		configRootFlag          = newConfigRootFlag()
+		haproxyErrorFilesFlag   = newHAProxyErrorFilesFlag()
+		haproxySizeBudgetFlag   = newHAProxySizeBudgetFlag()
+		targetFlag              = newTargetFlag()
+		minifyFlag              = newMinifyFlag()
+		precompressFlag         = newPrecompressFlag()
+		localesFlag             = newLocalesFlag()
+		stripL10nScriptFlag     = newStripL10nScriptFlag()
 		disableL10nFlag         = shared.NewDisableL10nFlag()
 		homepageURLFlag         = shared.NewHomepageURLFlag(app.opt.homepageURL)
 		addLinksFlag            = shared.NewAddLinksFlag()
@@ -68,8 +132,29 @@ func NewApp(name string) *App {
 		&createIndexFlag,
 		&targetDirPath,
 		&disableBuiltInCodesFlag,
+		&builtInCodesFlag,
 		&addHTTPCodesFlag,
+		&codesFileFlag,
+		&templatesFlag,
+		&codesFlag,
+		&jobsFlag,
+		&formatsFlag,
 		&templateFlag,
+		&jsonTemplateFlag,
+		&xmlTemplateFlag,
+		&textTemplateFlag,
+		&yamlTemplateFlag,
+		&markdownTemplateFlag,
+		&svgTemplateFlag,
+		&emitConfigFlag,
+		&configRootFlag,
+		&haproxyErrorFilesFlag,
+		&haproxySizeBudgetFlag,
+		&targetFlag,
+		&minifyFlag,
+		&precompressFlag,
+		&localesFlag,
+		&stripL10nScriptFlag,
 		&disableL10nFlag,
 		&homepageURLFlag,
 		&addLinksFlag,
@@ -81,7 +166,25 @@ func NewApp(name string) *App {
 
 		app.opt.targetDirAbsPath, _ = filepath.Abs(app.opt.targetDirAbsPath) //nolint:errcheck // checked by validator
 
+		if f, ok := archive.FromPath(app.opt.targetDirAbsPath); ok {
+			// the pages are likely to be extracted next to the archive, into the directory with the same name
+			app.opt.archivePath, app.opt.archiveFormat = app.opt.targetDirAbsPath, f
+			app.opt.targetDirAbsPath = archive.TrimExtension(app.opt.archivePath)
+		} else {
+			app.opt.outDirAbsPath = app.opt.targetDirAbsPath
+		}
+
 		setIfFlagIsSet(&app.opt.disableBuiltInCodes, disableBuiltInCodesFlag)
+		app.opt.builtInCodes = *builtInCodesFlag.Value
+
+		app.opt.templates, _ = parseTemplateNames(*templatesFlag.Value) //nolint:errcheck // the flag validates itself
+		app.opt.codesFilter, _ = codes.ParseFilter(*codesFlag.Value)    //nolint:errcheck // the flag validates itself
+
+		app.opt.jobs = uint(runtime.NumCPU()) //nolint:gosec // always positive
+
+		if *jobsFlag.Value > 0 {
+			app.opt.jobs = *jobsFlag.Value
+		}
 
 		if addHTTPCodesFlag.Value != nil && addHTTPCodesFlag.IsSet() {
 			if parsed, err := shared.ParseAddHTTPCodes(*addHTTPCodesFlag.Value); err == nil {
@@ -89,7 +192,50 @@ func NewApp(name string) *App {
 			}
 		}
 
-		setIfFlagIsSet(&app.opt.customTemplate, templateFlag)
+		if codesFileFlag.Value != nil && codesFileFlag.IsSet() && *codesFileFlag.Value != "" {
+			if file, err := codes.LoadFile(*codesFileFlag.Value); err == nil {
+				app.opt.codesFile = file
+			}
+		}
+
+		app.opt.formats, _ = parseFormats(*formatsFlag.Value) //nolint:errcheck // the flag validates itself
+
+		app.opt.emitConfig, _ = parseServers(*emitConfigFlag.Value) //nolint:errcheck // the flag validates itself
+		app.opt.configRoot = app.opt.targetDirAbsPath
+
+		setIfFlagIsSet(&app.opt.configRoot, configRootFlag)
+		setIfFlagIsSet(&app.opt.haproxyErrorFiles, haproxyErrorFilesFlag)
+
+		app.opt.haproxySizeBudget = *haproxySizeBudgetFlag.Value
+		app.opt.targets, _ = parseTargets(*targetFlag.Value) //nolint:errcheck // the flag validates itself
+
+		if slices.Contains(app.opt.targets, cdn.Cloudflare) && !slices.Contains(app.opt.formats, formats.HTMLFormat) {
+			return errors.New("the Cloudflare pages are HTML only, but the html format is not requested (see --formats)")
+		}
+
+		if _, ok := cdn.ALBContentType(app.opt.formats[0]); slices.Contains(app.opt.targets, cdn.AWS) && !ok {
+			return fmt.Errorf("the ALB fixed responses do not support the %s format, put another one first "+
+				"(see --formats)", app.opt.formats[0])
+		}
+
+		setIfFlagIsSet(&app.opt.minify, minifyFlag)
+
+		app.opt.precompress, _ = parseEncodings(*precompressFlag.Value) //nolint:errcheck // the flag validates itself
+		app.opt.locales, _ = parseLocales(*localesFlag.Value)           //nolint:errcheck // the flag validates itself
+
+		if len(app.opt.locales) > 0 && !slices.Contains(app.opt.formats, formats.HTMLFormat) {
+			return errors.New("the localized pages are HTML only, but the html format is not requested (see --formats)")
+		}
+
+		setIfFlagIsSet(&app.opt.stripL10nScript, stripL10nScriptFlag)
+
+		setIfFlagIsSet(&app.opt.customTemplates.html, templateFlag)
+		setIfFlagIsSet(&app.opt.customTemplates.json, jsonTemplateFlag)
+		setIfFlagIsSet(&app.opt.customTemplates.xml, xmlTemplateFlag)
+		setIfFlagIsSet(&app.opt.customTemplates.text, textTemplateFlag)
+		setIfFlagIsSet(&app.opt.customTemplates.yaml, yamlTemplateFlag)
+		setIfFlagIsSet(&app.opt.customTemplates.markdown, markdownTemplateFlag)
+		setIfFlagIsSet(&app.opt.customTemplates.svg, svgTemplateFlag)
 		setIfFlagIsSet(&app.opt.homepageURL, homepageURLFlag)
 
 		if addLinksFlag.Value != nil && addLinksFlag.IsSet() {
@@ -98,14 +244,41 @@ func NewApp(name string) *App {
 			}
 		}
 
-		// load custom template content if a source is provided (either URL, file path, or raw template string)
-		if src := app.opt.customTemplate; src != "" {
-			t, err := tploader.LoadTemplateContent(ctx, src)
+		// load custom templates content if a source is provided (either URL, file path, or raw template string)
+		for _, item := range []struct {
+			format formats.Format
+			src    *string
+		}{
+			{formats.HTMLFormat, &app.opt.customTemplates.html},
+			{formats.JSONFormat, &app.opt.customTemplates.json},
+			{formats.XMLFormat, &app.opt.customTemplates.xml},
+			{formats.PlainTextFormat, &app.opt.customTemplates.text},
+			{formats.YAMLFormat, &app.opt.customTemplates.yaml},
+			{formats.MarkdownFormat, &app.opt.customTemplates.markdown},
+			{formats.SVGFormat, &app.opt.customTemplates.svg},
+		} {
+			if *item.src == "" {
+				continue
+			}
+
+			if !slices.Contains(app.opt.formats, item.format) {
+				return fmt.Errorf("custom %s template is given, but the format is not requested (see --formats)",
+					item.format,
+				)
+			}
+
+			t, err := tploader.LoadTemplateContent(ctx, *item.src)
 			if err != nil {
-				return fmt.Errorf("load custom template: %w", err)
+				return fmt.Errorf("load custom %s template: %w", item.format, err)
 			}
 
-			app.opt.customTemplate = t
+			*item.src = t
+		}
+
+		if len(app.opt.templates) > 0 &&
+			(app.opt.customTemplates.html != "" || !slices.Contains(app.opt.formats, formats.HTMLFormat)) {
+			return errors.New("--templates selects the built-in HTML templates, but the custom one is used, " +
+				"or the html format is not requested")
 		}
 
 		setIfFlagIsSet(&app.opt.l10nDisabled, disableL10nFlag)
@@ -133,22 +306,49 @@ func setIfFlagIsSet[T cli.FlagType](target *T, source cli.Flag[T]) {
 // Run starts the CLI command execution.
 func (a *App) Run(ctx context.Context, args []string) error { return a.cmd.Run(ctx, args) }
 
-type historyItem struct{ Code, Message, RelativePath string }
+type (
+	historyItem struct {
+		historyFile
+
+		Code, Message string
+		Alternatives  []historyFile // the same page in other formats
+	}
+	historyFile struct {
+		Format, RelativePath string
+		Size, OriginalSize   int              // the size of the file, and the size before the minification
+		Compressed           []historyEncoded // the precompressed copies
+	}
+	historyEncoded struct {
+		Encoding string
+		Size     int
+	}
+)
 
 const fileMode os.FileMode = 0o664
 
-func (a *App) run(_ context.Context) error {
-	httpCodes := codes.New(a.opt.disableBuiltInCodes)
+// build renders the pages (and everything around them) into the target directory.
+func (a *App) build(ctx context.Context) error {
+	set := a.opt.builtInCodes
+	if a.opt.disableBuiltInCodes || a.opt.codesFile != nil && a.opt.codesFile.Mode == codes.FileModeReplace {
+		set = codes.BuiltInNone
+	}
+
+	httpCodes := codes.New(set)
+
+	if a.opt.codesFile != nil {
+		maps.Copy(httpCodes, a.opt.codesFile.Codes)
+	}
+
 	maps.Copy(httpCodes, a.opt.addHTTPCodes)
 
 	var history = make(map[string][]historyItem)
 
-	if a.opt.customTemplate != "" {
-		if err := a.renderCustomTemplate(httpCodes, history); err != nil {
+	if !slices.Contains(a.opt.formats, formats.HTMLFormat) || a.opt.customTemplates.html != "" {
+		if err := a.renderCustomTemplates(ctx, httpCodes, history); err != nil {
 			return err
 		}
 	} else {
-		if err := a.renderBuiltInTemplates(httpCodes, history); err != nil {
+		if err := a.renderBuiltInTemplates(ctx, httpCodes, history); err != nil {
 			return err
 		}
 	}
@@ -174,67 +374,102 @@ func (a *App) run(_ context.Context) error {
 
 	indexPath := filepath.Join(a.opt.targetDirAbsPath, "index.html")
 
-	return os.WriteFile(indexPath, []byte(buf.String()), fileMode)
+	return a.writeFile(indexPath, []byte(buf.String()), manifestEntry{})
 }
 
-// renderCustomTemplate renders all numeric HTTP codes using the custom template and writes them directly into
-// the target directory as {code}.html files.
-func (a *App) renderCustomTemplate(httpCodes codes.Codes, history map[string][]historyItem) error {
-	t, err := tpl.New(a.opt.customTemplate)
-	if err != nil {
-		return fmt.Errorf("parse custom template: %w", err)
-	}
-
-	for _, codeStr := range httpCodes.Codes() {
-		codeUint, parseErr := strconv.ParseUint(codeStr, 10, 16)
-		if parseErr != nil {
-			continue // skip wildcard codes like "4xx"
+// templates parses the templates for all requested formats, except HTML and PNG (the images are drawn without a
+// template). Custom templates take precedence over the built-in ones.
+func (a *App) templates() (map[formats.Format]*tpl.Template, error) {
+	var result = make(map[formats.Format]*tpl.Template, len(a.opt.formats))
+
+	for _, f := range a.opt.formats {
+		var src, builtIn string
+
+		switch f {
+		case formats.JSONFormat:
+			src, builtIn = a.opt.customTemplates.json, templates.JSON
+		case formats.XMLFormat:
+			src, builtIn = a.opt.customTemplates.xml, templates.XML
+		case formats.PlainTextFormat:
+			src, builtIn = a.opt.customTemplates.text, templates.PlaintText
+		case formats.YAMLFormat:
+			src, builtIn = a.opt.customTemplates.yaml, templates.YAML
+		case formats.MarkdownFormat:
+			src, builtIn = a.opt.customTemplates.markdown, templates.Markdown
+		case formats.SVGFormat:
+			src, builtIn = a.opt.customTemplates.svg, templates.SVG
+		default:
+			continue // HTML templates are handled separately, PNG has no template
 		}
 
-		code := uint16(codeUint)
-
-		desc, ok := httpCodes.Find(code)
-		if !ok {
-			continue
+		if src == "" {
+			src = builtIn
 		}
 
-		content, renderErr := t.Render(tpl.Data{
-			StatusCode:  code,
-			Message:     desc.Short,
-			Description: desc.Full,
-			HomepageURL: a.opt.homepageURL,
-			Links:       a.opt.links,
-			Config:      tpl.Config{L10nDisabled: a.opt.l10nDisabled},
-		})
-		if renderErr != nil {
-			return fmt.Errorf("render custom template for code %s: %w", codeStr, renderErr)
+		t, err := tpl.New(src)
+		if err != nil {
+			return nil, fmt.Errorf("parse %s template: %w", f, err)
 		}
 
-		outPath := filepath.Join(a.opt.targetDirAbsPath, codeStr+".html")
+		result[f] = t
+	}
+
+	return result, nil
+}
+
+// renderCustomTemplates renders all numeric HTTP codes using the custom HTML template (if HTML is requested) and
+// the templates for other formats, and writes them directly into the target directory as {code}.{ext} files.
+func (a *App) renderCustomTemplates(
+	ctx context.Context,
+	httpCodes codes.Codes,
+	history map[string][]historyItem,
+) error {
+	set, err := a.templates()
+	if err != nil {
+		return err
+	}
 
-		if wErr := os.WriteFile(outPath, content, fileMode); wErr != nil {
-			return fmt.Errorf("write %s: %w", outPath, wErr)
+	if slices.Contains(a.opt.formats, formats.HTMLFormat) {
+		t, tplErr := tpl.New(a.opt.customTemplates.html)
+		if tplErr != nil {
+			return fmt.Errorf("parse custom template: %w", tplErr)
 		}
 
-		history["custom"] = append(history["custom"], historyItem{
-			Code:         codeStr,
-			Message:      desc.Short,
-			RelativePath: "." + strings.TrimPrefix(outPath, a.opt.targetDirAbsPath),
-		})
+		set[formats.HTMLFormat] = t
 	}
 
-	return nil
+	var name = "custom"
+
+	if a.opt.customTemplates.html == "" {
+		name = "built-in" // no HTML pages, only the other formats
+	}
+
+	return a.renderPages(ctx, a.opt.targetDirAbsPath, name, set, httpCodes, history)
 }
 
-// renderBuiltInTemplates renders all numeric HTTP codes for every built-in HTML template and writes them into
-// per-template subdirectories as {templateName}/{code}.html files.
-func (a *App) renderBuiltInTemplates(httpCodes codes.Codes, history map[string][]historyItem) error { //nolint:cyclop
+// renderBuiltInTemplates renders all numeric HTTP codes for every built-in HTML template (or the requested ones) and
+// writes them into per-template subdirectories as {templateName}/{code}.{ext} files. Pages in other formats are
+// rendered into every subdirectory too, so each of them is self-contained. The templates are rendered concurrently.
+func (a *App) renderBuiltInTemplates(
+	ctx context.Context,
+	httpCodes codes.Codes,
+	history map[string][]historyItem,
+) error {
 	builtIn := templates.BuiltInHTML()
 
-	allTemplateNames := slices.Collect(maps.Keys(builtIn))
-	slices.Sort(allTemplateNames)
+	templateNames := a.opt.templates
+	if len(templateNames) == 0 {
+		templateNames = slices.Sorted(maps.Keys(builtIn))
+	}
+
+	common, err := a.templates()
+	if err != nil {
+		return err
+	}
 
-	for _, templateName := range allTemplateNames {
+	eg, _ := errgroup.New(ctx)
+
+	for _, templateName := range templateNames {
 		subDir := filepath.Join(a.opt.targetDirAbsPath, templateName)
 
 		if mkErr := os.MkdirAll(subDir, 0o775); mkErr != nil { //nolint:mnd
@@ -246,44 +481,410 @@ func (a *App) renderBuiltInTemplates(httpCodes codes.Codes, history map[string][
 			return fmt.Errorf("parse built-in template %q: %w", templateName, tplErr)
 		}
 
-		for _, codeStr := range httpCodes.Codes() {
-			codeUint, parseErr := strconv.ParseUint(codeStr, 10, 16)
-			if parseErr != nil {
-				continue // skip wildcard codes like "4xx"
-			}
+		set := maps.Clone(common)
+		set[formats.HTMLFormat] = t
 
-			code := uint16(codeUint)
+		eg.Go(func(ctx context.Context) error {
+			return a.renderPages(ctx, subDir, templateName, set, httpCodes, history)
+		})
+	}
 
-			desc, ok := httpCodes.Find(code)
-			if !ok {
-				continue
+	return eg.Wait()
+}
+
+// renderPages renders the HTTP codes (the ones selected by the codes filter) using the given templates (in the order
+// of the requested formats) and writes them into the directory as {code}.{ext} files. The entries with wildcards
+// and ranges (e.g. "4xx" or "400-451") are rendered with the lowest code they cover, and named after the entry (e.g.
+// "4xx.html"). The excluded codes (e.g. "!404") are not rendered. The pages are rendered concurrently, limited by the
+// number of jobs.
+func (a *App) renderPages(
+	ctx context.Context,
+	dir, name string,
+	set map[formats.Format]*tpl.Template,
+	httpCodes codes.Codes,
+	history map[string][]historyItem,
+) error {
+	for _, locale := range a.opt.locales {
+		if err := os.MkdirAll(filepath.Join(dir, locale), 0o775); err != nil { //nolint:mnd
+			return fmt.Errorf("create directory for locale %q: %w", locale, err)
+		}
+	}
+
+	var (
+		eg, _    = errgroup.New(ctx)
+		mu       sync.Mutex
+		rendered = make([]codePage, 0, len(httpCodes)) // the exact codes only
+	)
+
+	for _, key := range httpCodes.Codes() {
+		code, ok := codes.LowestCode(key)
+		if !ok || !a.opt.codesFilter.Match(code) {
+			continue
+		}
+
+		var page = codePage{
+			key:   strings.ToLower(strings.ReplaceAll(key, "*", "x")),
+			code:  code,
+			desc:  httpCodes[key],
+			exact: key == strconv.FormatUint(uint64(code), 10),
+		}
+
+		if _, found := httpCodes.Find(code); page.exact && !found {
+			continue // excluded
+		}
+
+		eg.Go(func(ctx context.Context) error {
+			select {
+			case a.jobs <- struct{}{}:
+				defer func() { <-a.jobs }()
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+
+			item, err := a.renderCode(dir, name, set, page)
+			if err != nil {
+				return err
 			}
 
-			content, renderErr := t.Render(tpl.Data{
-				StatusCode:  code,
-				Message:     desc.Short,
-				Description: desc.Full,
-				HomepageURL: a.opt.homepageURL,
-				Links:       a.opt.links,
-				Config:      tpl.Config{L10nDisabled: a.opt.l10nDisabled},
-			})
-			if renderErr != nil {
-				return fmt.Errorf("render template %q for code %s: %w", templateName, codeStr, renderErr)
+			a.mu.Lock()
+			history[name] = append(history[name], item)
+			a.mu.Unlock()
+
+			if page.exact {
+				mu.Lock()
+				rendered = append(rendered, page)
+				mu.Unlock()
 			}
 
-			outPath := filepath.Join(subDir, codeStr+".html")
+			return nil
+		})
+	}
+
+	if err := eg.Wait(); err != nil {
+		return err
+	}
+
+	slices.SortFunc(rendered, func(a, b codePage) int { return int(a.code) - int(b.code) })
+
+	if err := a.renderTargets(dir, name, set, rendered); err != nil {
+		return err
+	}
+
+	var codesList = make([]uint16, len(rendered))
+
+	for i, page := range rendered {
+		codesList[i] = page.code
+	}
+
+	return a.writeConfigs(dir, codesList)
+}
+
+// codePage is an entry of the codes to render the page for.
+type codePage struct {
+	key   string // the file name (without extension), the code itself or the entry like "4xx" or "400-451"
+	code  uint16 // the code to render the page with
+	desc  codes.Description
+	exact bool // the entry is an exact code, not the one with wildcards or the range
+}
+
+// renderCode renders and writes the page of the code in all requested formats and locales.
+func (a *App) renderCode(
+	dir, name string,
+	set map[formats.Format]*tpl.Template,
+	page codePage,
+) (historyItem, error) {
+	var (
+		item  = historyItem{Code: page.key, Message: page.desc.Short}
+		entry = manifestEntry{Template: name, Code: page.code}
+		data  = a.pageData(page.code, page.desc)
+	)
+
+	if !page.exact {
+		entry.Code, entry.Pattern = 0, page.key
+	}
+
+	for _, f := range a.opt.formats {
+		content, renderErr := renderPage(set, f, data)
+		if renderErr != nil {
+			return item, fmt.Errorf("render %s template %q for code %s: %w", f, name, page.key, renderErr)
+		}
+
+		outPath := filepath.Join(dir, page.key+f.Extension())
 
-			if wErr := os.WriteFile(outPath, content, fileMode); wErr != nil {
-				return fmt.Errorf("write %s: %w", outPath, wErr)
+		content, file, wErr := a.writePage(outPath, f, content, entry)
+		if wErr != nil {
+			return item, wErr
+		}
+
+		// the errorfiles are for the exact codes only, HAProxy has no wildcards
+		if a.opt.haproxyErrorFiles && f == a.opt.formats[0] && page.exact {
+			if hErr := a.writeHAProxyErrorFile(dir, name, page.code, page.desc.Short, f, content); hErr != nil {
+				return item, hErr
 			}
+		}
+
+		file.RelativePath = "." + strings.TrimPrefix(outPath, a.opt.targetDirAbsPath)
+
+		if item.RelativePath == "" {
+			item.historyFile = file
+		} else {
+			item.Alternatives = append(item.Alternatives, file)
+		}
+	}
+
+	localized, lErr := a.renderLocalized(dir, page.key, set[formats.HTMLFormat], data, page.desc, entry)
+	if lErr != nil {
+		return item, fmt.Errorf("render localized pages of template %q for code %s: %w", name, page.key, lErr)
+	}
+
+	item.Alternatives = append(item.Alternatives, localized...)
+
+	return item, nil
+}
+
+// renderPage renders the page in the format using the template of the set. The PNG images are drawn without a
+// template, showing the code and its message.
+func renderPage(set map[formats.Format]*tpl.Template, f formats.Format, data tpl.Data) ([]byte, error) {
+	if f == formats.PNGFormat {
+		var buf bytes.Buffer
+
+		if err := raster.PNG(&buf, data.Image.Width, data.Image.Height,
+			strconv.Itoa(int(data.StatusCode)), data.Message,
+		); err != nil {
+			return nil, err
+		}
+
+		return buf.Bytes(), nil
+	}
+
+	return set[f].Render(data)
+}
+
+// pageData returns the data to render the page of the code with. The links of the code follow the common ones.
+func (a *App) pageData(code uint16, desc codes.Description) tpl.Data {
+	return tpl.Data{
+		StatusCode:         code,
+		OriginalStatusCode: code,
+		Message:            desc.Short,
+		Description:        desc.Full,
+		HomepageURL:        a.opt.homepageURL,
+		Links:              append(slices.Clip(a.opt.links), desc.Links...),
+		Image:              tpl.DefaultImage(),
+		Config:             tpl.Config{L10nDisabled: a.opt.l10nDisabled},
+	}
+}
+
+// renderLocalized renders the HTML page for every requested locale, with the localizable texts translated at build
+// time (the message and description of the code are taken from its own translations, if it has them), and writes
+// them into the {locale} subdirectories of the directory.
+func (a *App) renderLocalized(
+	dir, fileName string,
+	t *tpl.Template,
+	data tpl.Data,
+	desc codes.Description,
+	entry manifestEntry,
+) ([]historyFile, error) {
+	if len(a.opt.locales) == 0 || t == nil {
+		return nil, nil
+	}
+
+	data.Config.L10nDisabled = data.Config.L10nDisabled || a.opt.stripL10nScript
+
+	var files = make([]historyFile, 0, len(a.opt.locales))
+
+	for _, locale := range a.opt.locales {
+		translated := desc.Translate(locale)
+		data.Message, data.Description = translated.Short, translated.Full
+
+		content, err := t.Render(data)
+		if err != nil {
+			return nil, err
+		}
+
+		outPath := filepath.Join(dir, locale, fileName+formats.HTMLFormat.Extension())
+
+		localized := entry
+		localized.Locale = locale
+
+		_, file, wErr := a.writePage(outPath, formats.HTMLFormat, []byte(l10n.LocalizeHTML(string(content), locale)),
+			localized,
+		)
+		if wErr != nil {
+			return nil, wErr
+		}
+
+		file.Format, file.RelativePath = locale, "."+strings.TrimPrefix(outPath, a.opt.targetDirAbsPath)
+
+		files = append(files, file)
+	}
+
+	return files, nil
+}
+
+// writePage writes the page into the file (minified, if requested and the format supports it) and its precompressed
+// copies next to it. It returns the written content and the details for the index.
+func (a *App) writePage(
+	outPath string,
+	f formats.Format,
+	content []byte,
+	entry manifestEntry,
+) ([]byte, historyFile, error) {
+	var file = historyFile{Format: f.String(), OriginalSize: len(content)}
+
+	entry.Format = f.String()
+
+	if a.opt.minify && f == formats.HTMLFormat {
+		minified, err := minify.HTML(content)
+		if err != nil {
+			return nil, file, fmt.Errorf("minify %s: %w", outPath, err)
+		}
+
+		content = minified
+	}
+
+	file.Size = len(content)
+
+	if err := a.writeFile(outPath, content, entry); err != nil {
+		return nil, file, err
+	}
+
+	if f == formats.PNGFormat {
+		return content, file, nil // PNG is already compressed
+	}
+
+	for _, e := range a.opt.precompress {
+		compressed, err := e.Compress(content)
+		if err != nil {
+			return nil, file, fmt.Errorf("compress %s using %s: %w", outPath, e, err)
+		}
+
+		encoded := entry
+		encoded.Encoding = e.String()
 
-			history[templateName] = append(history[templateName], historyItem{
-				Code:         codeStr,
-				Message:      desc.Short,
-				RelativePath: "." + strings.TrimPrefix(outPath, a.opt.targetDirAbsPath),
-			})
+		if wErr := a.writeFile(outPath+e.Extension(), compressed, encoded); wErr != nil {
+			return nil, file, wErr
+		}
+
+		file.Compressed = append(file.Compressed, historyEncoded{Encoding: e.String(), Size: len(compressed)})
+	}
+
+	return content, file, nil
+}
+
+// Sizes returns the human-readable sizes of the file for the index, e.g. "12.1 KiB → 9.8 KiB (gzip 3.2 KiB)". The
+// size before the minification is shown only if it differs.
+func (f historyFile) Sizes() string {
+	var b strings.Builder
+
+	if f.OriginalSize != f.Size {
+		b.WriteString(formatSize(f.OriginalSize) + " → ")
+	}
+
+	b.WriteString(formatSize(f.Size))
+
+	for i, c := range f.Compressed {
+		if i == 0 {
+			b.WriteString(" (")
+		} else {
+			b.WriteString(", ")
+		}
+
+		b.WriteString(c.Encoding + " " + formatSize(c.Size))
+
+		if i == len(f.Compressed)-1 {
+			b.WriteString(")")
+		}
+	}
+
+	return b.String()
+}
+
+func formatSize(n int) string {
+	const kib = 1024
+
+	if n < kib {
+		return strconv.Itoa(n) + " B"
+	}
+
+	return strconv.FormatFloat(float64(n)/kib, 'f', 1, 64) + " KiB"
+}
+
+// writeConfigs writes the requested web server configuration snippets for the pages in the directory.
+func (a *App) writeConfigs(dir string, codesList []uint16) error {
+	if len(a.opt.emitConfig) == 0 {
+		return nil
+	}
+
+	rel, relErr := filepath.Rel(a.opt.targetDirAbsPath, dir)
+	if relErr != nil {
+		return relErr
+	}
+
+	var layout = webconf.Layout{
+		Root:       path.Join(filepath.ToSlash(a.opt.configRoot), filepath.ToSlash(rel)),
+		Codes:      codesList,
+		Formats:    a.opt.formats,
+		ErrorFiles: a.opt.haproxyErrorFiles,
+
+		Precompressed: a.opt.precompress,
+	}
+
+	for _, srv := range a.opt.emitConfig {
+		content, err := webconf.Generate(srv, layout)
+		if err != nil {
+			return err
+		}
+
+		outPath := filepath.Join(dir, srv.FileName())
+
+		if wErr := a.writeFile(outPath, content, manifestEntry{}); wErr != nil {
+			return wErr
 		}
 	}
 
 	return nil
 }
+
+// writeHAProxyErrorFile writes the page as a complete raw HTTP response into the {code}.http file, and records it if
+// the file exceeds the size budget (HAProxy refuses to start with an errorfile that does not fit the buffer), so the
+// warning is printed once the build is done (see [App.warnOversized]).
+func (a *App) writeHAProxyErrorFile(
+	dir, name string,
+	code uint16,
+	reason string,
+	f formats.Format,
+	body []byte,
+) error {
+	var (
+		content = webconf.HAProxyErrorFile(code, reason, f, body)
+		outPath = filepath.Join(dir, strconv.FormatUint(uint64(code), 10)+".http")
+	)
+
+	if wErr := a.writeFile(outPath, content, manifestEntry{Template: name, Code: code, Format: "http"}); wErr != nil {
+		return wErr
+	}
+
+	if budget := a.opt.haproxySizeBudget; budget > 0 && uint(len(content)) > budget {
+		a.mu.Lock()
+
+		if a.oversized.count++; len(content) > a.oversized.size {
+			a.oversized.largest, a.oversized.size = a.displayPath(outPath), len(content)
+		}
+
+		a.mu.Unlock()
+	}
+
+	return nil
+}
+
+// warnOversized prints the single warning about the HAProxy errorfiles exceeding the size budget, if any.
+func (a *App) warnOversized(out io.Writer) {
+	if a.oversized.count == 0 {
+		return
+	}
+
+	_, _ = fmt.Fprintf(out, "warning: %d HAProxy errorfile(s) exceed the size budget of %d bytes (the largest one is "+
+		"%s, %d bytes), HAProxy refuses to start with them unless the tune.bufsize is increased\n",
+		a.oversized.count, a.opt.haproxySizeBudget, a.oversized.largest, a.oversized.size,
+	)
+}
@@ -4,11 +4,24 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
 
+	"gh.tarampamp.am/error-pages/v4/internal/archive"
+	"gh.tarampamp.am/error-pages/v4/internal/cdn"
 	"gh.tarampamp.am/error-pages/v4/internal/cli"
+	"gh.tarampamp.am/error-pages/v4/internal/codes"
+	"gh.tarampamp.am/error-pages/v4/internal/formats"
+	"gh.tarampamp.am/error-pages/v4/internal/precompress"
 	tpl "gh.tarampamp.am/error-pages/v4/internal/template"
 	"gh.tarampamp.am/error-pages/v4/internal/template/tploader"
+	"gh.tarampamp.am/error-pages/v4/internal/webconf"
+	"gh.tarampamp.am/error-pages/v4/l10n"
+	"gh.tarampamp.am/error-pages/v4/templates"
 )
 
 func newCreateIndexFlag() cli.Flag[bool] {
@@ -21,8 +34,9 @@ func newCreateIndexFlag() cli.Flag[bool] {
 
 func newTargetDirPath(def string) cli.Flag[string] {
 	return cli.Flag[string]{
-		Names:   []string{"out", "target-dir", "o"},
-		Usage:   "Directory to place the built error pages",
+		Names: []string{"out", "target-dir", "o"},
+		Usage: "Directory to place the built error pages, or the path of the .tar.gz (.tgz) or .zip archive to pack " +
+			"them into",
 		Default: def,
 		EnvVars: []string{"OUT_DIR"},
 		Validator: func(_ *cli.Command, dir string) error {
@@ -30,6 +44,14 @@ func newTargetDirPath(def string) cli.Flag[string] {
 				return errors.New("missing target directory")
 			}
 
+			if _, ok := archive.FromPath(dir); ok {
+				if stat, err := os.Stat(dir); err == nil && stat.IsDir() {
+					return fmt.Errorf("'%s' is a directory, not an archive", dir)
+				}
+
+				dir = filepath.Dir(dir) // the archive is created, so the directory it is placed in must exist
+			}
+
 			if stat, err := os.Stat(dir); err != nil {
 				return fmt.Errorf("cannot access the target directory '%s': %w", dir, err)
 			} else if !stat.IsDir() {
@@ -41,27 +63,404 @@ func newTargetDirPath(def string) cli.Flag[string] {
 	}
 }
 
-func newTemplateFlag() cli.Flag[string] {
+func newTemplatesFlag() cli.Flag[string] {
 	return cli.Flag[string]{
-		Names:   []string{"template"},
-		Usage:   "Custom template for error pages",
-		EnvVars: []string{"TEMPLATE"},
-		Validator: func(_ *cli.Command, src string) error {
-			if tploader.IsURL(src) || tploader.IsFilePath(src) {
-				// if it's a URL or file path, we will attempt to load it later, so just skip validation for now
-				return nil
-			}
+		Names: []string{"templates"},
+		Usage: "Comma-separated list of the built-in templates to render (" +
+			strings.Join(slices.Sorted(maps.Keys(templates.BuiltInHTML())), "/") + "); all of them by default",
+		EnvVars: []string{"TEMPLATES"},
+		Validator: func(_ *cli.Command, s string) error {
+			_, err := parseTemplateNames(s)
 
-			t, err := tpl.New(src)
-			if err != nil {
-				return fmt.Errorf("custom template parsing: %w", err)
-			}
+			return err
+		},
+	}
+}
+
+// parseTemplateNames parses the comma-separated list of the built-in template names. Duplicates are ignored, the
+// order is preserved.
+func parseTemplateNames(s string) ([]string, error) {
+	var (
+		builtIn = templates.BuiltInHTML()
+		result  []string
+	)
+
+	for part := range strings.SplitSeq(s, ",") {
+		if part = strings.TrimSpace(part); part == "" {
+			continue
+		}
+
+		if _, ok := builtIn[part]; !ok {
+			return nil, fmt.Errorf("unknown template %q", part)
+		}
+
+		if !slices.Contains(result, part) {
+			result = append(result, part)
+		}
+	}
+
+	return result, nil
+}
+
+func newCodesFlag() cli.Flag[string] {
+	return cli.Flag[string]{
+		Names: []string{"codes"},
+		Usage: "Comma-separated list of HTTP codes to render the pages for - exact codes, codes with wildcards, or " +
+			"ranges (e.g. '404,5xx,420-429'); all of them by default",
+		EnvVars: []string{"CODES"},
+		Validator: func(_ *cli.Command, s string) error {
+			_, err := codes.ParseFilter(s)
+
+			return err
+		},
+	}
+}
+
+func newJobsFlag() cli.Flag[uint] {
+	return cli.Flag[uint]{
+		Names:   []string{"jobs", "j"},
+		Usage:   "Number of pages to render concurrently (the number of CPUs by default)",
+		EnvVars: []string{"JOBS"},
+	}
+}
+
+func newFormatsFlag() cli.Flag[string] {
+	all := make([]string, 0, len(formats.All()))
+
+	for _, f := range formats.All() {
+		all = append(all, f.String())
+	}
+
+	return cli.Flag[string]{
+		Names:   []string{"formats"},
+		Usage:   "Comma-separated list of formats to render the error pages in (" + strings.Join(all, "/") + ")",
+		Default: formats.HTMLFormat.String(),
+		EnvVars: []string{"FORMATS"},
+		Validator: func(_ *cli.Command, s string) error {
+			_, err := parseFormats(s)
+
+			return err
+		},
+	}
+}
+
+// parseFormats parses the comma-separated list of formats. Duplicates are ignored, the order is preserved.
+func parseFormats(s string) ([]formats.Format, error) {
+	var result []formats.Format
+
+	for part := range strings.SplitSeq(s, ",") {
+		if part = strings.TrimSpace(part); part == "" {
+			continue
+		}
+
+		f, ok := formats.FromString(part)
+		if !ok {
+			return nil, fmt.Errorf("unknown format %q", part)
+		}
+
+		if !slices.Contains(result, f) {
+			result = append(result, f)
+		}
+	}
 
-			if err = t.RenderTo(tpl.Data{}, io.Discard); err != nil {
-				return fmt.Errorf("custom template rendering test: %w", err)
+	if len(result) == 0 {
+		return nil, errors.New("at least one format is required")
+	}
+
+	return result, nil
+}
+
+func newEmitConfigFlag() cli.Flag[string] {
+	all := make([]string, 0, len(webconf.Servers()))
+
+	for _, srv := range webconf.Servers() {
+		all = append(all, string(srv))
+	}
+
+	return cli.Flag[string]{
+		Names: []string{"emit-config"},
+		Usage: "Comma-separated list of web servers to write the configuration snippets for, next to the pages (" +
+			strings.Join(all, "/") + ")",
+		EnvVars: []string{"EMIT_CONFIG"},
+		Validator: func(_ *cli.Command, s string) error {
+			_, err := parseServers(s)
+
+			return err
+		},
+	}
+}
+
+// parseServers parses the comma-separated list of web servers. Duplicates are ignored, the order is preserved.
+func parseServers(s string) ([]webconf.Server, error) {
+	var result []webconf.Server
+
+	for part := range strings.SplitSeq(s, ",") {
+		if part = strings.ToLower(strings.TrimSpace(part)); part == "" {
+			continue
+		}
+
+		if srv := webconf.Server(part); !slices.Contains(webconf.Servers(), srv) {
+			return nil, fmt.Errorf("unsupported web server %q", part)
+		} else if !slices.Contains(result, srv) {
+			result = append(result, srv)
+		}
+	}
+
+	return result, nil
+}
+
+func newConfigRootFlag() cli.Flag[string] {
+	return cli.Flag[string]{
+		Names: []string{"config-root"},
+		Usage: "Path to the directory with the built error pages on the target server, used in the configuration " +
+			"snippets (the absolute path of the target directory by default)",
+		EnvVars: []string{"CONFIG_ROOT"},
+		Validator: func(_ *cli.Command, s string) error {
+			if !path.IsAbs(filepath.ToSlash(s)) {
+				return fmt.Errorf("the config root must be an absolute path, got %q", s)
 			}
 
 			return nil
 		},
 	}
 }
+
+func newHAProxyErrorFilesFlag() cli.Flag[bool] {
+	return cli.Flag[bool]{
+		Names: []string{"haproxy-errorfiles"},
+		Usage: "Also write {code}.http files with the complete raw HTTP responses for the HAProxy errorfile " +
+			"directive (the first of the formats is used)",
+		EnvVars: []string{"HAPROXY_ERRORFILES"},
+	}
+}
+
+func newHAProxySizeBudgetFlag() cli.Flag[uint] {
+	return cli.Flag[uint]{
+		Names: []string{"haproxy-size-budget"},
+		Usage: "Warn when the HAProxy errorfile exceeds this size in bytes (must fit the buffer, which is " +
+			"tune.bufsize minus tune.maxrewrite)",
+		Default: 16384 - 1024, //nolint:mnd // HAProxy defaults
+		EnvVars: []string{"HAPROXY_SIZE_BUDGET"},
+	}
+}
+
+func newTargetFlag() cli.Flag[string] {
+	all := make([]string, 0, len(cdn.Targets()))
+
+	for _, t := range cdn.Targets() {
+		all = append(all, string(t))
+	}
+
+	return cli.Flag[string]{
+		Names: []string{"target"},
+		Usage: "Comma-separated list of CDNs (and cloud load balancers) to also render the custom error pages and " +
+			"configuration for, into the {target} subdirectories (" + strings.Join(all, "/") + ")",
+		EnvVars: []string{"TARGET"},
+		Validator: func(_ *cli.Command, s string) error {
+			_, err := parseTargets(s)
+
+			return err
+		},
+	}
+}
+
+// parseTargets parses the comma-separated list of CDNs. Duplicates are ignored, the order is preserved.
+func parseTargets(s string) ([]cdn.Target, error) {
+	var result []cdn.Target
+
+	for part := range strings.SplitSeq(s, ",") {
+		if part = strings.ToLower(strings.TrimSpace(part)); part == "" {
+			continue
+		}
+
+		if t := cdn.Target(part); !slices.Contains(cdn.Targets(), t) {
+			return nil, fmt.Errorf("unsupported target %q", part)
+		} else if !slices.Contains(result, t) {
+			result = append(result, t)
+		}
+	}
+
+	return result, nil
+}
+
+func newMinifyFlag() cli.Flag[bool] {
+	return cli.Flag[bool]{
+		Names:   []string{"minify"},
+		Usage:   "Minify the HTML pages, including the inline styles and scripts",
+		EnvVars: []string{"MINIFY"},
+	}
+}
+
+func newPrecompressFlag() cli.Flag[string] {
+	all := make([]string, 0, len(precompress.Encodings()))
+
+	for _, e := range precompress.Encodings() {
+		all = append(all, e.String())
+	}
+
+	return cli.Flag[string]{
+		Names: []string{"precompress"},
+		Usage: "Comma-separated list of encodings to write the compressed copies of the pages with, next to them " +
+			"(" + strings.Join(all, "/") + "), e.g. for the nginx gzip_static or the Caddy precompressed options",
+		EnvVars: []string{"PRECOMPRESS"},
+		Validator: func(_ *cli.Command, s string) error {
+			_, err := parseEncodings(s)
+
+			return err
+		},
+	}
+}
+
+// parseEncodings parses the comma-separated list of encodings. Duplicates are ignored, the order is preserved.
+func parseEncodings(s string) ([]precompress.Encoding, error) {
+	var result []precompress.Encoding
+
+	for part := range strings.SplitSeq(s, ",") {
+		if part = strings.TrimSpace(part); part == "" {
+			continue
+		}
+
+		e, ok := precompress.FromString(part)
+		if !ok {
+			return nil, fmt.Errorf("unsupported encoding %q", part)
+		}
+
+		if !slices.Contains(result, e) {
+			result = append(result, e)
+		}
+	}
+
+	return result, nil
+}
+
+func newLocalesFlag() cli.Flag[string] {
+	return cli.Flag[string]{
+		Names: []string{"locales"},
+		Usage: "Comma-separated list of languages to render the translated copies of the HTML pages in, into the " +
+			"{locale} subdirectories (" + strings.Join(l10n.Languages(), "/") + "); the texts are translated at " +
+			"build time, so the copies do not rely on the localization script",
+		EnvVars: []string{"LOCALES"},
+		Validator: func(_ *cli.Command, s string) error {
+			_, err := parseLocales(s)
+
+			return err
+		},
+	}
+}
+
+// parseLocales parses the comma-separated list of languages, resolving them to the supported language codes (e.g.
+// "de-AT" to "de"). Duplicates are ignored, the order is preserved.
+func parseLocales(s string) ([]string, error) {
+	var result []string
+
+	for part := range strings.SplitSeq(s, ",") {
+		if part = strings.TrimSpace(part); part == "" {
+			continue
+		}
+
+		lang, ok := l10n.ResolveLanguage(part)
+		if !ok {
+			return nil, fmt.Errorf("unsupported locale %q", part)
+		}
+
+		if lang == "en" {
+			return nil, fmt.Errorf("locale %q is the default one, the pages are already in English", part)
+		}
+
+		if !slices.Contains(result, lang) {
+			result = append(result, lang)
+		}
+	}
+
+	return result, nil
+}
+
+func newStripL10nScriptFlag() cli.Flag[bool] {
+	return cli.Flag[bool]{
+		Names: []string{"strip-l10n-script"},
+		Usage: "Remove the localization script from the translated copies of the pages (see --locales), so they " +
+			"contain no inline script for the localization and always stay in their language",
+		EnvVars: []string{"STRIP_L10N_SCRIPT"},
+	}
+}
+
+func newTemplateFlag() cli.Flag[string] {
+	return cli.Flag[string]{
+		Names:     []string{"template"},
+		Usage:     "Custom template for error pages",
+		EnvVars:   []string{"TEMPLATE"},
+		Validator: validateCustomTemplate,
+	}
+}
+
+func newJSONTemplateFlag() cli.Flag[string] {
+	return cli.Flag[string]{
+		Names:     []string{"json-template"},
+		Usage:     "Custom JSON template for error pages (used when the json format is requested)",
+		EnvVars:   []string{"JSON_TEMPLATE"},
+		Validator: validateCustomTemplate,
+	}
+}
+
+func newXMLTemplateFlag() cli.Flag[string] {
+	return cli.Flag[string]{
+		Names:     []string{"xml-template"},
+		Usage:     "Custom XML template for error pages (used when the xml format is requested)",
+		EnvVars:   []string{"XML_TEMPLATE"},
+		Validator: validateCustomTemplate,
+	}
+}
+
+func newPlainTextTemplateFlag() cli.Flag[string] {
+	return cli.Flag[string]{
+		Names:     []string{"plaintext-template"},
+		Usage:     "Custom plain text template for error pages (used when the text format is requested)",
+		EnvVars:   []string{"TEXT_TEMPLATE", "PLAINTEXT_TEMPLATE"},
+		Validator: validateCustomTemplate,
+	}
+}
+
+func newYAMLTemplateFlag() cli.Flag[string] {
+	return cli.Flag[string]{
+		Names:     []string{"yaml-template"},
+		Usage:     "Custom YAML template for error pages (used when the yaml format is requested)",
+		EnvVars:   []string{"YAML_TEMPLATE"},
+		Validator: validateCustomTemplate,
+	}
+}
+
+func newMarkdownTemplateFlag() cli.Flag[string] {
+	return cli.Flag[string]{
+		Names:     []string{"markdown-template"},
+		Usage:     "Custom Markdown template for error pages (used when the markdown format is requested)",
+		EnvVars:   []string{"MARKDOWN_TEMPLATE"},
+		Validator: validateCustomTemplate,
+	}
+}
+
+func newSVGTemplateFlag() cli.Flag[string] {
+	return cli.Flag[string]{
+		Names:     []string{"svg-template"},
+		Usage:     "Custom SVG template for error pages (used when the svg format is requested)",
+		EnvVars:   []string{"SVG_TEMPLATE"},
+		Validator: validateCustomTemplate,
+	}
+}
+
+func validateCustomTemplate(_ *cli.Command, src string) error {
+	if tploader.IsURL(src) || tploader.IsFilePath(src) {
+		// if it's a URL or file path, we will attempt to load it later, so just skip validation for now
+		return nil
+	}
+
+	t, err := tpl.New(src)
+	if err != nil {
+		return fmt.Errorf("custom template parsing: %w", err)
+	}
+
+	if err = t.RenderTo(tpl.Data{}, io.Discard); err != nil {
+		return fmt.Errorf("custom template rendering test: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package app
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// manifestFileName is the name of the manifest file, written into the root of the output.
+const manifestFileName = "manifest.json"
+
+// manifestEntry describes a single written file. The details, which make no sense for the file (e.g. the status
+// code for the index), are omitted.
+type manifestEntry struct {
+	Path     string `json:"path"` // slash-separated, relative to the output root
+	Template string `json:"template,omitempty"`
+	Code     uint16 `json:"code,omitempty"`
+	Pattern  string `json:"pattern,omitempty"` // for the pages of the entries with wildcards, e.g. "4xx"
+	Format   string `json:"format,omitempty"`
+	Locale   string `json:"locale,omitempty"`   // for the translated copies
+	Encoding string `json:"encoding,omitempty"` // for the precompressed copies
+	Target   string `json:"target,omitempty"`   // for the pages of the CDNs
+	Size     int    `json:"size"`
+	SHA256   string `json:"sha256"`
+}
+
+// writeFile writes the file and records it in the manifest (the path, size and checksum of the entry are set here).
+func (a *App) writeFile(path string, content []byte, entry manifestEntry) error {
+	if err := os.WriteFile(path, content, fileMode); err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+
+	rel, err := filepath.Rel(a.opt.targetDirAbsPath, path)
+	if err != nil {
+		return err
+	}
+
+	sum := sha256.Sum256(content)
+
+	entry.Path, entry.Size, entry.SHA256 = filepath.ToSlash(rel), len(content), hex.EncodeToString(sum[:])
+
+	a.mu.Lock()
+	a.manifest = append(a.manifest, entry)
+	a.mu.Unlock()
+
+	return nil
+}
+
+// writeManifest writes the manifest with every written file (except the manifest itself), sorted by the path.
+func (a *App) writeManifest() error {
+	var files = make([]manifestEntry, len(a.manifest))
+
+	copy(files, a.manifest)
+	slices.SortFunc(files, func(a, b manifestEntry) int { return strings.Compare(a.Path, b.Path) })
+
+	content, err := json.MarshalIndent(struct {
+		Files []manifestEntry `json:"files"`
+	}{Files: files}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	outPath := filepath.Join(a.opt.targetDirAbsPath, manifestFileName)
+
+	if wErr := os.WriteFile(outPath, append(content, '\n'), fileMode); wErr != nil {
+		return fmt.Errorf("write %s: %w", outPath, wErr)
+	}
+
+	return nil
+}
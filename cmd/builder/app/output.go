@@ -0,0 +1,223 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"gh.tarampamp.am/error-pages/v4/internal/archive"
+)
+
+// stagingDirPattern is the pattern of the temporary directory the pages are built in.
+const stagingDirPattern = ".error-pages-*"
+
+// backupDirPattern is the pattern of the temporary directory the replaced entries of the output are moved into, while
+// the new ones are published.
+const backupDirPattern = ".error-pages-backup-*"
+
+// run builds the pages into the staging directory (next to the output, so the files can be moved instead of copied)
+// and publishes them, only if everything was built successfully - on error, nothing is written into the output.
+func (a *App) run(ctx context.Context) error {
+	var parent = a.opt.outDirAbsPath
+
+	if a.opt.archivePath != "" {
+		parent = filepath.Dir(a.opt.archivePath)
+	}
+
+	staging, err := os.MkdirTemp(parent, stagingDirPattern)
+	if err != nil {
+		return fmt.Errorf("create staging directory: %w", err)
+	}
+
+	defer func() { _ = os.RemoveAll(staging) }()
+
+	a.opt.targetDirAbsPath = staging
+	a.jobs = make(chan struct{}, a.opt.jobs)
+
+	if bErr := a.build(ctx); bErr != nil {
+		return bErr
+	}
+
+	if mErr := a.writeManifest(); mErr != nil {
+		return mErr
+	}
+
+	a.warnOversized(a.cmd.Output)
+
+	if a.opt.archivePath != "" {
+		return a.writeArchive()
+	}
+
+	return publish(staging, a.opt.outDirAbsPath)
+}
+
+// publish moves the entries of the staging directory into the output one. The existing entries with the same names
+// (e.g. the pages of the previous build) are moved aside first, and are restored if anything fails, so the output is
+// either fully replaced or left as it was. On success, the files of the replaced entries, which were not written by
+// the previous build (are not listed in its manifest) and are not overwritten by the new one, are moved back.
+func publish(staging, out string) error {
+	entries, err := os.ReadDir(staging)
+	if err != nil {
+		return err
+	}
+
+	owned := previousFiles(out)
+
+	backup, err := os.MkdirTemp(out, backupDirPattern)
+	if err != nil {
+		return fmt.Errorf("create backup directory: %w", err)
+	}
+
+	var moved, published []string // the names of the entries moved into the backup and into the output
+
+	// rollback removes the published entries and restores the backed up ones, the backup is kept if that fails
+	rollback := func(cause error) error {
+		var errs []error
+
+		for _, name := range published {
+			errs = append(errs, os.RemoveAll(filepath.Join(out, name)))
+		}
+
+		for _, name := range moved {
+			errs = append(errs, os.Rename(filepath.Join(backup, name), filepath.Join(out, name)))
+		}
+
+		if rErr := errors.Join(errs...); rErr != nil {
+			return fmt.Errorf("%w (restore the previous output from %s: %w)", cause, backup, rErr)
+		}
+
+		return errors.Join(cause, os.RemoveAll(backup))
+	}
+
+	for _, e := range entries {
+		var to = filepath.Join(out, e.Name())
+
+		if _, stErr := os.Lstat(to); errors.Is(stErr, os.ErrNotExist) {
+			continue
+		}
+
+		if mvErr := os.Rename(to, filepath.Join(backup, e.Name())); mvErr != nil {
+			return rollback(fmt.Errorf("replace %s: %w", to, mvErr))
+		}
+
+		moved = append(moved, e.Name())
+	}
+
+	for _, e := range entries {
+		var from, to = filepath.Join(staging, e.Name()), filepath.Join(out, e.Name())
+
+		if mvErr := os.Rename(from, to); mvErr != nil {
+			return rollback(fmt.Errorf("move %s: %w", to, mvErr))
+		}
+
+		published = append(published, e.Name())
+	}
+
+	if kErr := keepForeignFiles(backup, out, owned); kErr != nil {
+		return fmt.Errorf("restore the files not written by the builder (they are kept in %s): %w", backup, kErr)
+	}
+
+	return os.RemoveAll(backup)
+}
+
+// previousFiles returns the slash-separated paths of the files written by the previous build into the output
+// directory (listed in its manifest, and the manifest itself). The set is empty if there is no manifest.
+func previousFiles(out string) map[string]struct{} {
+	var result = map[string]struct{}{manifestFileName: {}}
+
+	content, err := os.ReadFile(filepath.Join(out, manifestFileName))
+	if err != nil {
+		return result
+	}
+
+	var manifest struct {
+		Files []struct {
+			Path string `json:"path"`
+		} `json:"files"`
+	}
+
+	if json.Unmarshal(content, &manifest) != nil {
+		return result
+	}
+
+	for _, f := range manifest.Files {
+		result[f.Path] = struct{}{}
+	}
+
+	return result
+}
+
+// keepForeignFiles moves the files of the backup directory back into the output one, except the ones written by the
+// previous build (see [previousFiles]) and the ones replaced by the new build.
+func keepForeignFiles(backup, out string, owned map[string]struct{}) error {
+	return filepath.WalkDir(backup, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		rel, relErr := filepath.Rel(backup, path)
+		if relErr != nil {
+			return relErr
+		}
+
+		if _, ok := owned[filepath.ToSlash(rel)]; ok {
+			return nil
+		}
+
+		var to = filepath.Join(out, rel)
+
+		if _, stErr := os.Lstat(to); !errors.Is(stErr, os.ErrNotExist) {
+			return stErr // the file is replaced by the new build (nil error), or can not be checked
+		}
+
+		if mkErr := os.MkdirAll(filepath.Dir(to), 0o775); mkErr != nil { //nolint:mnd
+			return mkErr
+		}
+
+		return os.Rename(path, to)
+	})
+}
+
+// displayPath returns the path of the file being built (in the staging directory) as it will be after the build:
+// in the output directory, or in the archive ("{archive}:{path}").
+func (a *App) displayPath(path string) string {
+	rel, err := filepath.Rel(a.opt.targetDirAbsPath, path)
+	if err != nil {
+		return path
+	}
+
+	if a.opt.archivePath != "" {
+		return a.opt.archivePath + ":" + filepath.ToSlash(rel)
+	}
+
+	return filepath.Join(a.opt.outDirAbsPath, rel)
+}
+
+// writeArchive packs the built pages into the temporary file next to the archive, and renames it to the archive on
+// success (so the previous archive, if any, is never left half-written).
+func (a *App) writeArchive() error {
+	f, err := os.CreateTemp(filepath.Dir(a.opt.archivePath), stagingDirPattern)
+	if err != nil {
+		return fmt.Errorf("create archive: %w", err)
+	}
+
+	defer func() { _ = os.Remove(f.Name()) }() // no-op after the rename
+
+	if wErr := archive.Write(f, a.opt.archiveFormat, a.opt.targetDirAbsPath); wErr != nil {
+		return errors.Join(fmt.Errorf("write %s archive: %w", a.opt.archiveFormat, wErr), f.Close())
+	}
+
+	if cErr := f.Close(); cErr != nil {
+		return cErr
+	}
+
+	if chErr := os.Chmod(f.Name(), fileMode); chErr != nil {
+		return chErr
+	}
+
+	return os.Rename(f.Name(), a.opt.archivePath)
+}
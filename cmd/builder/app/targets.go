@@ -0,0 +1,160 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+
+	"gh.tarampamp.am/error-pages/v4/internal/cdn"
+	"gh.tarampamp.am/error-pages/v4/internal/codes"
+	"gh.tarampamp.am/error-pages/v4/internal/formats"
+	"gh.tarampamp.am/error-pages/v4/internal/minify"
+	tpl "gh.tarampamp.am/error-pages/v4/internal/template"
+)
+
+// renderTargets renders the pages for the requested CDNs (and cloud load balancers), and writes them into the
+// {target} subdirectories of the directory. The pages are the exact codes rendered into the directory.
+func (a *App) renderTargets(dir, name string, set map[formats.Format]*tpl.Template, pages []codePage) error {
+	for _, target := range a.opt.targets {
+		targetDir := filepath.Join(dir, string(target))
+
+		if err := os.MkdirAll(targetDir, 0o775); err != nil { //nolint:mnd
+			return fmt.Errorf("create directory for target %q: %w", target, err)
+		}
+
+		var err error
+
+		switch target {
+		case cdn.Cloudflare:
+			err = a.renderCloudflarePages(targetDir, name, set[formats.HTMLFormat])
+		case cdn.AWS:
+			err = a.renderAWSConfigs(targetDir, dir, name, set, pages)
+		default:
+			err = fmt.Errorf("unsupported target %q", target)
+		}
+
+		if err != nil {
+			return fmt.Errorf("render %s pages of template %q: %w", target, name, err)
+		}
+	}
+
+	return nil
+}
+
+// renderCloudflarePages renders the Cloudflare custom error pages, with the Cloudflare tokens in place of the
+// description, and checks them against the Cloudflare requirements.
+func (a *App) renderCloudflarePages(dir, name string, t *tpl.Template) error {
+	for _, p := range cdn.CloudflarePages() {
+		content, err := a.renderTargetPage(t, p.StatusCode, p.Message, p.Token)
+		if err != nil {
+			return fmt.Errorf("%s page: %w", p.Name, err)
+		}
+
+		if vErr := cdn.ValidateCloudflare(p, content); vErr != nil {
+			return fmt.Errorf("%s page: %w", p.Name, vErr)
+		}
+
+		var entry = manifestEntry{
+			Template: name,
+			Code:     p.StatusCode,
+			Format:   formats.HTMLFormat.String(),
+			Target:   string(cdn.Cloudflare),
+		}
+
+		if wErr := a.writeFile(filepath.Join(dir, p.Name+formats.HTMLFormat.Extension()), content, entry); wErr != nil {
+			return wErr
+		}
+	}
+
+	return nil
+}
+
+// renderTargetPage renders the HTML page with the token in place of the description (minified, if requested).
+func (a *App) renderTargetPage(t *tpl.Template, code uint16, message, token string) ([]byte, error) {
+	content, err := t.Render(a.pageData(code, codes.Description{Short: message, Full: cdn.Placeholder}))
+	if err != nil {
+		return nil, err
+	}
+
+	if content, err = cdn.InjectToken(content, token, message); err != nil {
+		return nil, err
+	}
+
+	if a.opt.minify {
+		return minify.HTML(content)
+	}
+
+	return content, nil
+}
+
+// renderAWSConfigs writes the actions of the ALB listener rules with the compact pages as the fixed responses (in the
+// first of the formats), and the CustomErrorResponses of the CloudFront distribution with the pages of the
+// directory (the paths are relative to the output root).
+func (a *App) renderAWSConfigs(
+	targetDir, pagesDir, name string,
+	set map[formats.Format]*tpl.Template,
+	pages []codePage,
+) error {
+	var (
+		f              = a.opt.formats[0]
+		contentType, _ = cdn.ALBContentType(f) // checked when the options are parsed
+		responses      = make([]cdn.ALBResponse, 0, len(pages))
+		errorPages     = make([]cdn.CloudFrontErrorPage, 0, len(pages))
+	)
+
+	rel, relErr := filepath.Rel(a.opt.targetDirAbsPath, pagesDir)
+	if relErr != nil {
+		return relErr
+	}
+
+	for _, page := range pages {
+		errorPages = append(errorPages, cdn.CloudFrontErrorPage{
+			Code: page.code,
+			Path: path.Join("/", filepath.ToSlash(rel), page.key+f.Extension()),
+		})
+
+		if !cdn.IsALBStatusCode(page.code) {
+			continue
+		}
+
+		data := a.pageData(page.code, page.desc)
+		data.Config.L10nDisabled = true // no room for the script
+
+		body, err := set[f].Render(data)
+		if err != nil {
+			return fmt.Errorf("render %s template for code %d: %w", f, page.code, err)
+		}
+
+		if f == formats.HTMLFormat {
+			if body, err = cdn.CompactHTML(body, cdn.ALBMaxBodySize); err != nil {
+				return fmt.Errorf("the %s page of code %d does not fit the ALB fixed response: %w", f, page.code, err)
+			}
+		}
+
+		responses = append(responses, cdn.ALBResponse{StatusCode: page.code, ContentType: contentType, Body: body})
+	}
+
+	albRules, albErr := cdn.ALBFixedResponses(responses)
+	if albErr != nil {
+		return albErr
+	}
+
+	cfResponses, cfErr := cdn.CloudFrontErrorResponses(errorPages)
+	if cfErr != nil {
+		return cfErr
+	}
+
+	for fileName, content := range map[string][]byte{
+		"alb-fixed-responses.json": albRules,
+		"cloudfront.json":          cfResponses,
+	} {
+		entry := manifestEntry{Template: name, Format: formats.JSONFormat.String(), Target: string(cdn.AWS)}
+
+		if err := a.writeFile(filepath.Join(targetDir, fileName), content, entry); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
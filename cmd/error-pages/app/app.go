@@ -4,24 +4,17 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"maps"
 	"net"
-	"net/http"
-	"slices"
-	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"gh.tarampamp.am/error-pages/v4/internal/appmeta"
 	"gh.tarampamp.am/error-pages/v4/internal/cli"
-	"gh.tarampamp.am/error-pages/v4/internal/cli/shared"
 	"gh.tarampamp.am/error-pages/v4/internal/codes"
 	"gh.tarampamp.am/error-pages/v4/internal/errgroup"
 	"gh.tarampamp.am/error-pages/v4/internal/httpserver"
 	"gh.tarampamp.am/error-pages/v4/internal/logger"
-	tpl "gh.tarampamp.am/error-pages/v4/internal/template"
-	"gh.tarampamp.am/error-pages/v4/internal/template/tploader"
-	"gh.tarampamp.am/error-pages/v4/templates"
 )
 
 //go:generate go run ./generate/readme.go -out ../../../docs/CLI.md
@@ -32,25 +25,15 @@ type App struct {
 
 	opt struct {
 		http struct {
-			addr string
-			port uint
+			addr       string
+			port       uint
+			drainDelay time.Duration
 		}
-		errorPages struct {
-			defaultCodeToRender uint
-			sendSameHTTPCode    bool
-			showDetails         bool
-			proxyHeaders        []string
-			disableBuiltInCodes bool
-			addHTTPCodes        map[string]codes.Description
-			templateName        string
-			rotationMode        tpl.RotationMode
-			homepageURL         string
-			links               []tpl.Link
-			customTemplates     struct {
-				html, json, xml, text string
-			}
-			l10nDisabled bool
+		extProc struct {
+			port  uint // zero means disabled
+			codes codes.Filter
 		}
+		errorPages errorPagesOptions
 	}
 }
 
@@ -64,59 +47,61 @@ func NewApp(name string) *App { //nolint:funlen
 		},
 	}
 
-	allTemplateNames := slices.Collect(maps.Keys(templates.BuiltInHTML()))
-	slices.Sort(allTemplateNames)
-
 	app.opt.http.addr = "0.0.0.0" // bind to all interfaces by default
 	app.opt.http.port = 8080
-	app.opt.errorPages.defaultCodeToRender = uint(http.StatusNotFound)
-	app.opt.errorPages.proxyHeaders = []string{"X-Request-Id", "X-Trace-Id", "X-Correlation-Id", "X-Amzn-Trace-Id"}
-	app.opt.errorPages.templateName = templates.HTMLTemplateNameAppDown
-	app.opt.errorPages.rotationMode = tpl.RotationModeDisabled
-	app.opt.errorPages.homepageURL = "/"
+	app.opt.errorPages = newErrorPagesOptions()
 
 	var (
-		logLevelFlag            = newLogLevelFlag()
-		logFormatFlag           = newLogFormatFlag()
-		httpAddrFlag            = newHTTPAddrFlag(app.opt.http.addr)
-		httpPortFlag            = newHTTPPortFlag(app.opt.http.port)
-		defaultCodeToRenderFlag = newDefaultCodeToRenderFlag(app.opt.errorPages.defaultCodeToRender)
-		sendSameHTTPCodeFlag    = newSendSameHTTPCodeFlag()
-		showDetailsFlag         = newShowDetailsFlag()
-		proxyHeadersListFlag    = newProxyHeadersListFlag(app.opt.errorPages.proxyHeaders)
-		disableBuiltInCodesFlag = shared.NewDisableBuiltInCodesFlag()
-		addHTTPCodesFlag        = shared.NewAddHTTPCodesFlag()
-		templateNameFlag        = newTemplateNameFlag(allTemplateNames, app.opt.errorPages.templateName)
-		rotationModeFlag        = newRotationModeFlag(app.opt.errorPages.rotationMode)
-		homepageURLFlag         = shared.NewHomepageURLFlag(app.opt.errorPages.homepageURL)
-		addLinksFlag            = shared.NewAddLinksFlag()
-		htmlTemplateFlag        = newHTMLTemplateFlag()
-		jsonTemplateFlag        = newJSONTemplateFlag()
-		xmlTemplateFlag         = newXMLTemplateFlag()
-		textTemplateFlag        = newPlainTextTemplateFlag()
-		disableL10nFlag         = shared.NewDisableL10nFlag()
+		logLevelFlag   = newLogLevelFlag()
+		logFormatFlag  = newLogFormatFlag()
+		httpAddrFlag   = newHTTPAddrFlag(app.opt.http.addr)
+		httpPortFlag   = newHTTPPortFlag(app.opt.http.port)
+		drainDelayFlag = newDrainDelayFlag()
+		extProcPort    = newExtProcPortFlag()
+		extProcCodes   = newInterceptCodesFlag()
+		epFlags        = newErrorPagesFlags(app.opt.errorPages)
 	)
 
+	app.cmd.Commands = []*cli.Command{
+		newHealthcheckCommand(app.opt.http.addr, app.opt.http.port),
+		newRenderCommand(app.opt.errorPages),
+		newLintCommand(),
+		newMigrateTemplateCommand(),
+		newPreviewCommand(app.opt.errorPages),
+		newProxyCommand(app.opt.errorPages),
+	}
+
 	app.cmd.Flags = []cli.Flagger{
 		&logLevelFlag,
 		&logFormatFlag,
 		&httpAddrFlag,
 		&httpPortFlag,
-		&defaultCodeToRenderFlag,
-		&sendSameHTTPCodeFlag,
-		&showDetailsFlag,
-		&proxyHeadersListFlag,
-		&disableBuiltInCodesFlag,
-		&addHTTPCodesFlag,
-		&templateNameFlag,
-		&rotationModeFlag,
-		&homepageURLFlag,
-		&addLinksFlag,
-		&htmlTemplateFlag,
-		&jsonTemplateFlag,
-		&xmlTemplateFlag,
-		&textTemplateFlag,
-		&disableL10nFlag,
+		&drainDelayFlag,
+		&extProcPort,
+		&extProcCodes,
+		&epFlags.defaultCodeToRender,
+		&epFlags.sendSameHTTPCode,
+		&epFlags.showDetails,
+		&epFlags.source,
+		&epFlags.proxyHeadersList,
+		&epFlags.mapCodes,
+		&epFlags.redirects,
+		&epFlags.disableBuiltInCodes,
+		&epFlags.builtInCodes,
+		&epFlags.addHTTPCodes,
+		&epFlags.codesFile,
+		&epFlags.templateName,
+		&epFlags.rotationMode,
+		&epFlags.homepageURL,
+		&epFlags.addLinks,
+		&epFlags.htmlTemplate,
+		&epFlags.jsonTemplate,
+		&epFlags.xmlTemplate,
+		&epFlags.textTemplate,
+		&epFlags.yamlTemplate,
+		&epFlags.markdownTemplate,
+		&epFlags.svgTemplate,
+		&epFlags.disableL10n,
 	}
 
 	app.cmd.Action = func(ctx context.Context, _ *cli.Command, _ []string) error {
@@ -134,45 +119,13 @@ func NewApp(name string) *App { //nolint:funlen
 
 		setIfFlagIsSet(&app.opt.http.addr, httpAddrFlag)
 		setIfFlagIsSet(&app.opt.http.port, httpPortFlag)
-		setIfFlagIsSet(&app.opt.errorPages.defaultCodeToRender, defaultCodeToRenderFlag)
-		setIfFlagIsSet(&app.opt.errorPages.sendSameHTTPCode, sendSameHTTPCodeFlag)
-		setIfFlagIsSet(&app.opt.errorPages.showDetails, showDetailsFlag)
-		setIfFlagIsSet(&app.opt.errorPages.disableBuiltInCodes, disableBuiltInCodesFlag)
-
-		if proxyHeadersListFlag.Value != nil && proxyHeadersListFlag.IsSet() {
-			app.opt.errorPages.proxyHeaders = splitProxyHeadersList(*proxyHeadersListFlag.Value)
-		}
-
-		slices.Sort(app.opt.errorPages.proxyHeaders)
-
-		if addHTTPCodesFlag.Value != nil && addHTTPCodesFlag.IsSet() {
-			if parsed, err := shared.ParseAddHTTPCodes(*addHTTPCodesFlag.Value); err == nil {
-				app.opt.errorPages.addHTTPCodes = parsed
-			}
-		}
-
-		setIfFlagIsSet(&app.opt.errorPages.templateName, templateNameFlag)
-
-		if rotationModeFlag.Value != nil && rotationModeFlag.IsSet() {
-			app.opt.errorPages.rotationMode = tpl.RotationMode(*rotationModeFlag.Value)
-		}
-
-		setIfFlagIsSet(&app.opt.errorPages.homepageURL, homepageURLFlag)
-
-		if addLinksFlag.Value != nil && addLinksFlag.IsSet() {
-			if parsed, err := shared.ParseLinks(*addLinksFlag.Value); err == nil {
-				app.opt.errorPages.links = parsed
-			}
-		}
-
-		setIfFlagIsSet(&app.opt.errorPages.customTemplates.html, htmlTemplateFlag)
-		setIfFlagIsSet(&app.opt.errorPages.customTemplates.json, jsonTemplateFlag)
-		setIfFlagIsSet(&app.opt.errorPages.customTemplates.xml, xmlTemplateFlag)
-		setIfFlagIsSet(&app.opt.errorPages.customTemplates.text, textTemplateFlag)
-		setIfFlagIsSet(&app.opt.errorPages.l10nDisabled, disableL10nFlag)
+		setIfFlagIsSet(&app.opt.http.drainDelay, drainDelayFlag)
+		setIfFlagIsSet(&app.opt.extProc.port, extProcPort)
+		app.opt.extProc.codes, _ = codes.ParseFilter(*extProcCodes.Value) //nolint:errcheck // the flag validates itself
+		epFlags.apply(&app.opt.errorPages)
 
 		// load custom templates concurrently if specified
-		if err := app.loadTemplates(ctx); err != nil {
+		if err := app.opt.errorPages.loadTemplates(ctx); err != nil {
 			log.Error("Failed to load custom templates", logger.Error(err))
 
 			return errors.New("failed to load custom templates")
@@ -193,129 +146,108 @@ func NewApp(name string) *App { //nolint:funlen
 // Help returns the help message.
 func (a *App) Help() string { return a.cmd.Help() }
 
-// setIfFlagIsSet copies source's value into target only if the flag was explicitly provided by the user, not just
-// defaulted. This matters because Flag.Value is always non-nil (set to default) after parsing, so IsSet is the only
-// reliable way to know whether the user actually supplied the value.
-func setIfFlagIsSet[T cli.FlagType](target *T, source cli.Flag[T]) {
-	if target == nil || source.Value == nil || !source.IsSet() {
-		return
-	}
-
-	*target = *source.Value
-}
-
-// loadTemplates loads custom templates concurrently if they are specified in the options and appear to be from a
-// valid source (URL or file path), and does nothing otherwise.
-func (a *App) loadTemplates(ctx context.Context) error {
-	ct := &a.opt.errorPages.customTemplates
-	eg, _ := errgroup.New(ctx)
-
-	if src := ct.html; src != "" {
-		eg.Go(func(ctx context.Context) error {
-			t, err := tploader.LoadTemplateContent(ctx, src)
-			if err != nil {
-				return fmt.Errorf("load HTML template: %w", err)
-			}
+// CommandNames returns the names of all subcommands.
+func (a *App) CommandNames() []string {
+	names := make([]string, 0, len(a.cmd.Commands))
 
-			ct.html = t
-
-			return nil
-		})
+	for _, sub := range a.cmd.Commands {
+		names = append(names, sub.Name)
 	}
 
-	if src := ct.json; src != "" {
-		eg.Go(func(ctx context.Context) error {
-			t, err := tploader.LoadTemplateContent(ctx, src)
-			if err != nil {
-				return fmt.Errorf("load JSON template: %w", err)
-			}
-
-			ct.json = t
-
-			return nil
-		})
-	}
-
-	if src := ct.xml; src != "" {
-		eg.Go(func(ctx context.Context) error {
-			t, err := tploader.LoadTemplateContent(ctx, src)
-			if err != nil {
-				return fmt.Errorf("load XML template: %w", err)
-			}
-
-			ct.xml = t
+	return names
+}
 
-			return nil
-		})
+// CommandHelp returns the help message of the subcommand with the given name, or an empty string if there is no
+// such subcommand.
+func (a *App) CommandHelp(name string) string {
+	if sub := a.cmd.Subcommand(name); sub != nil {
+		return sub.Help()
 	}
 
-	if src := ct.text; src != "" {
-		eg.Go(func(ctx context.Context) error {
-			t, err := tploader.LoadTemplateContent(ctx, src)
-			if err != nil {
-				return fmt.Errorf("load plain text template: %w", err)
-			}
-
-			ct.text = t
+	return ""
+}
 
-			return nil
-		})
+// setIfFlagIsSet copies source's value into target only if the flag was explicitly provided by the user, not just
+// defaulted. This matters because Flag.Value is always non-nil (set to default) after parsing, so IsSet is the only
+// reliable way to know whether the user actually supplied the value.
+func setIfFlagIsSet[T cli.FlagType](target *T, source cli.Flag[T]) {
+	if target == nil || source.Value == nil || !source.IsSet() {
+		return
 	}
 
-	return eg.Wait()
+	*target = *source.Value
 }
 
 // Run starts the CLI command execution.
 func (a *App) Run(ctx context.Context, args []string) error { return a.cmd.Run(ctx, args) }
 
-// run opens the TCP listener, starts the HTTP server, and blocks until the context is canceled or the server fails.
+// run opens the TCP (or Unix socket) listener, starts the HTTP server, and blocks until the context is canceled or
+// the server fails.
 func (a *App) run(ctx context.Context, log *logger.Logger) error {
-	log.Info("Opening TCP port",
-		logger.String("addr", a.opt.http.addr),
-		logger.Uint64("port", uint64(a.opt.http.port)),
-	)
+	network, address := listenAddress(a.opt.http.addr, a.opt.http.port)
+
+	if network == "unix" {
+		log.Info("Opening Unix socket", logger.String("path", address))
+	} else {
+		log.Info("Opening TCP port",
+			logger.String("addr", a.opt.http.addr),
+			logger.Uint64("port", uint64(a.opt.http.port)),
+		)
+	}
 
-	ln, lnErr := (&net.ListenConfig{}).Listen(ctx, "tcp", net.JoinHostPort(
-		a.opt.http.addr,
-		strconv.Itoa(int(a.opt.http.port)),
-	))
+	ln, lnErr := (&net.ListenConfig{}).Listen(ctx, network, address)
 	if lnErr != nil {
 		return fmt.Errorf("listen http: %w", lnErr)
 	}
 
 	defer func() { _ = ln.Close() }() // just in case, although http.Server should take care of it when shutting down
 
-	httpCodes := codes.New(a.opt.errorPages.disableBuiltInCodes)
+	var extProcLn net.Listener // nil, unless the ext_proc listener is enabled
+
+	if a.opt.extProc.port != 0 {
+		if extProcLn, lnErr = a.listenExtProc(ctx, log); lnErr != nil {
+			return lnErr
+		}
+
+		defer func() { _ = extProcLn.Close() }()
+	}
 
 	// after this, we CAN'T modify httpCodes anymore, because it used concurrently
-	maps.Copy(httpCodes, a.opt.errorPages.addHTTPCodes)
-
-	templater, tErr := tpl.NewTemplates(
-		tpl.WithCustomHTMLTemplate(a.opt.errorPages.customTemplates.html),
-		tpl.WithCustomJSONTemplate(a.opt.errorPages.customTemplates.json),
-		tpl.WithCustomXMLTemplate(a.opt.errorPages.customTemplates.xml),
-		tpl.WithCustomPlainTextTemplate(a.opt.errorPages.customTemplates.text),
-		tpl.WithHTMLTemplateName(a.opt.errorPages.templateName),
-		tpl.WithRotationMode(a.opt.errorPages.rotationMode),
-	)
+	httpCodes := a.opt.errorPages.httpCodes()
+
+	templater, tErr := a.opt.errorPages.templates()
 	if tErr != nil {
 		return fmt.Errorf("initialize templates: %w", tErr)
 	}
 
+	// make sure every configured template can be rendered, before reporting the server as ready
+	if err := templater.TestRender(a.opt.errorPages.testRenderData(httpCodes)); err != nil {
+		return fmt.Errorf("test templates rendering: %w", err)
+	}
+
+	// the readiness flag is set by the server once it starts serving, and reset when the graceful shutdown begins
+	var readiness atomic.Bool
+
 	server := httpserver.New(
 		httpserver.NewHandler(
 			log,
 			uint16(a.opt.errorPages.defaultCodeToRender), //nolint:gosec // validated to be in range 1-65535
 			a.opt.errorPages.sendSameHTTPCode,
 			a.opt.errorPages.proxyHeaders,
-			httpCodes.Find,
+			a.opt.errorPages.codeMapping,
+			a.opt.errorPages.redirects,
+			httpCodes.Compile().Find,
 			templater.Get,
 			a.opt.errorPages.showDetails,
 			a.opt.errorPages.l10nDisabled,
 			a.opt.errorPages.homepageURL,
 			a.opt.errorPages.links,
+			a.opt.errorPages.source,
+			readiness.Load,
 		),
 		httpserver.WithErrorLog(logger.NewStdLog(log, logger.ErrorLevel)),
+		httpserver.WithReadiness(&readiness),
+		httpserver.WithDrainDelay(a.opt.http.drainDelay),
 	)
 
 	log.Info("Server configuration",
@@ -324,15 +256,21 @@ func (a *App) run(ctx context.Context, log *logger.Logger) error {
 		logger.Bool("custom_json_template", strings.TrimSpace(a.opt.errorPages.customTemplates.json) != ""),
 		logger.Bool("custom_xml_template", strings.TrimSpace(a.opt.errorPages.customTemplates.xml) != ""),
 		logger.Bool("custom_text_template", strings.TrimSpace(a.opt.errorPages.customTemplates.text) != ""),
+		logger.Bool("custom_yaml_template", strings.TrimSpace(a.opt.errorPages.customTemplates.yaml) != ""),
+		logger.Bool("custom_markdown_template", strings.TrimSpace(a.opt.errorPages.customTemplates.markdown) != ""),
+		logger.Bool("custom_svg_template", strings.TrimSpace(a.opt.errorPages.customTemplates.svg) != ""),
 		logger.String("template_name", a.opt.errorPages.templateName),
 		logger.String("rotation_mode", string(a.opt.errorPages.rotationMode)),
 		logger.Uint64("default_error_page", uint64(a.opt.errorPages.defaultCodeToRender)),
 		logger.Bool("send_same_http_code", a.opt.errorPages.sendSameHTTPCode),
 		logger.Bool("show_details", a.opt.errorPages.showDetails),
+		logger.String("source", sourceName(a.opt.errorPages.source)),
 		logger.Strings("proxy_headers", a.opt.errorPages.proxyHeaders...),
 		logger.String("homepage_url", a.opt.errorPages.homepageURL),
 		logger.Int("links_count", len(a.opt.errorPages.links)),
 		logger.Bool("l10n_disabled", a.opt.errorPages.l10nDisabled),
+		logger.Duration("drain_delay", a.opt.http.drainDelay),
+		logger.Uint64("ext_proc_port", uint64(a.opt.extProc.port)),
 	)
 
 	now := time.Now()
@@ -344,5 +282,15 @@ func (a *App) run(ctx context.Context, log *logger.Logger) error {
 	// since Serve() is blocking, we run it in the main goroutine and rely on context cancellation to stop it gracefully
 	// when needed - this way we don't need to handle signals and shutdown logic here, and the server will take care
 	// of it internally
-	return server.Serve(ctx, ln)
+	if extProcLn == nil {
+		return server.Serve(ctx, ln)
+	}
+
+	// otherwise, both servers are stopped when any of them fails
+	eg, _ := errgroup.New(ctx)
+
+	eg.Go(func(ctx context.Context) error { return server.Serve(ctx, ln) })
+	eg.Go(func(ctx context.Context) error { return a.serveExtProc(ctx, log, extProcLn, httpCodes, templater) })
+
+	return eg.Wait()
 }
@@ -0,0 +1,79 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+
+	"gh.tarampamp.am/error-pages/v4/internal/cli"
+	"gh.tarampamp.am/error-pages/v4/internal/codes"
+	"gh.tarampamp.am/error-pages/v4/internal/extproc"
+	"gh.tarampamp.am/error-pages/v4/internal/httpserver"
+	"gh.tarampamp.am/error-pages/v4/internal/logger"
+	tpl "gh.tarampamp.am/error-pages/v4/internal/template"
+)
+
+func newExtProcPortFlag() cli.Flag[uint] {
+	return cli.Flag[uint]{
+		Names: []string{"ext-proc-port"},
+		Usage: "TCP port number of the Envoy external processing (ext_proc) gRPC listener, on the same address as " +
+			"the HTTP server, which replaces the error responses with the error pages (zero disables it)",
+		EnvVars: []string{"EXT_PROC_PORT"},
+		Validator: func(_ *cli.Command, port uint) error {
+			if port > 65535 { //nolint:mnd
+				return fmt.Errorf("wrong TCP port number [%d]", port)
+			}
+
+			return nil
+		},
+	}
+}
+
+// listenExtProc opens the TCP listener of the external processing service.
+func (a *App) listenExtProc(ctx context.Context, log *logger.Logger) (net.Listener, error) {
+	network, address := listenAddress(a.opt.http.addr, a.opt.extProc.port)
+	if network != "tcp" {
+		return nil, errors.New("the ext_proc listener requires a TCP address, not a Unix socket")
+	}
+
+	log.Info("Opening ext_proc TCP port",
+		logger.String("addr", a.opt.http.addr),
+		logger.Uint64("port", uint64(a.opt.extProc.port)),
+	)
+
+	ln, err := (&net.ListenConfig{}).Listen(ctx, network, address)
+	if err != nil {
+		return nil, fmt.Errorf("listen ext_proc: %w", err)
+	}
+
+	return ln, nil
+}
+
+// serveExtProc starts the external processing service (gRPC over the unencrypted HTTP/2), and blocks until the
+// context is canceled or the server fails. No read and write timeouts are set, since the streams live as long as the
+// requests Envoy processes.
+func (a *App) serveExtProc(
+	ctx context.Context,
+	log *logger.Logger,
+	ln net.Listener,
+	httpCodes codes.Codes,
+	templater *tpl.Templates,
+) error {
+	var opt = a.opt.errorPages
+
+	opt.sendSameHTTPCode = true // the status code of the response is always kept
+	opt.redirects = nil         // so the browsers can not be redirected
+
+	server := httpserver.New(
+		extproc.New(log, a.opt.extProc.codes, opt.errorPageHandler(log, httpCodes, templater)),
+		httpserver.WithErrorLog(logger.NewStdLog(log, logger.ErrorLevel)),
+	)
+
+	log.Info("ext_proc server started",
+		logger.String("addr", ln.Addr().String()),
+		logger.String("method", extproc.ProcessMethod),
+	)
+
+	return server.Serve(ctx, ln)
+}
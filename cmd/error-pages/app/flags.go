@@ -6,10 +6,13 @@ import (
 	"io"
 	"net"
 	"slices"
+	"strconv"
 	"strings"
+	"time"
 	"unicode"
 
 	"gh.tarampamp.am/error-pages/v4/internal/cli"
+	"gh.tarampamp.am/error-pages/v4/internal/httpserver/handlers/error_page"
 	"gh.tarampamp.am/error-pages/v4/internal/logger"
 	tpl "gh.tarampamp.am/error-pages/v4/internal/template"
 	"gh.tarampamp.am/error-pages/v4/internal/template/tploader"
@@ -54,7 +57,7 @@ func newLogFormatFlag() cli.Flag[string] {
 func newHTTPAddrFlag(def string) cli.Flag[string] {
 	return cli.Flag[string]{
 		Names:   []string{"addr", "listen"},
-		Usage:   "HTTP server address to listen on (IPv4 or IPv6)",
+		Usage:   "HTTP server address to listen on (IPv4, IPv6, or '" + unixSocketPrefix + "/path/to/socket')",
 		EnvVars: []string{"HTTP_ADDR", "LISTEN_ADDR", "ADDR"},
 		Default: def,
 		Validator: func(_ *cli.Command, ip string) error {
@@ -62,6 +65,14 @@ func newHTTPAddrFlag(def string) cli.Flag[string] {
 				return errors.New("missing IP address for listening")
 			}
 
+			if socketPath, ok := strings.CutPrefix(ip, unixSocketPrefix); ok {
+				if socketPath == "" {
+					return errors.New("missing Unix socket path for listening")
+				}
+
+				return nil
+			}
+
 			if net.ParseIP(ip) == nil {
 				return fmt.Errorf("wrong IP address [%s] for listening", ip)
 			}
@@ -71,6 +82,20 @@ func newHTTPAddrFlag(def string) cli.Flag[string] {
 	}
 }
 
+// unixSocketPrefix is the prefix of the listen address that makes the server listen on a Unix socket instead of
+// a TCP port (e.g. "unix:/run/error-pages.sock").
+const unixSocketPrefix = "unix:"
+
+// listenAddress returns the network ("tcp" or "unix") and the address to listen on (or to connect to) for the given
+// address and port options. The port is ignored for Unix sockets.
+func listenAddress(addr string, port uint) (network, address string) {
+	if socketPath, ok := strings.CutPrefix(addr, unixSocketPrefix); ok {
+		return "unix", socketPath
+	}
+
+	return "tcp", net.JoinHostPort(addr, strconv.FormatUint(uint64(port), 10))
+}
+
 func newHTTPPortFlag(def uint) cli.Flag[uint] {
 	return cli.Flag[uint]{
 		Names:   []string{"port"},
@@ -87,6 +112,22 @@ func newHTTPPortFlag(def uint) cli.Flag[uint] {
 	}
 }
 
+func newDrainDelayFlag() cli.Flag[time.Duration] {
+	return cli.Flag[time.Duration]{
+		Names: []string{"drain-delay"},
+		Usage: "How long to keep serving after a shutdown signal while the readiness probe reports not ready " +
+			"(gives load balancers time to stop routing traffic before the listener closes, e.g. 5s)",
+		EnvVars: []string{"DRAIN_DELAY"},
+		Validator: func(_ *cli.Command, d time.Duration) error {
+			if d < 0 {
+				return fmt.Errorf("wrong drain delay [%s]: must not be negative", d)
+			}
+
+			return nil
+		},
+	}
+}
+
 func newDefaultCodeToRenderFlag(def uint) cli.Flag[uint] {
 	return cli.Flag[uint]{
 		Names:   []string{"default-error-page"},
@@ -112,6 +153,39 @@ func newSendSameHTTPCodeFlag() cli.Flag[bool] {
 	}
 }
 
+func newMapCodesFlag() cli.Flag[string] {
+	return cli.Flag[string]{
+		Names: []string{"map-code"},
+		Usage: "Remap the status codes before rendering the error page, and sending it with --send-same-http-code " +
+			"(format: 'FROM=TO[@PATH][||FROM=TO[@PATH]...]'; FROM is a comma-separated list of codes, wildcards " +
+			"like '5xx' or ranges like '500-504', and PATH limits the rule to the original URI path prefix; the " +
+			"first matching rule wins; separate multiple rules with '||', a newline, or a tab)",
+		EnvVars: []string{"MAP_CODE"},
+		Validator: func(_ *cli.Command, s string) error {
+			_, err := error_page.ParseCodeMapping(s)
+
+			return err
+		},
+	}
+}
+
+func newRedirectsFlag() cli.Flag[string] {
+	return cli.Flag[string]{
+		Names: []string{"redirect"},
+		Usage: "Redirect the browsers (the requests for HTML) to another URL instead of rendering the error page " +
+			"(format: 'CODES=URL[||CODES=URL...]'; CODES is a comma-separated list of codes, wildcards or ranges, " +
+			"and URL is a template with the same data and functions as the error pages, e.g. " +
+			"'401=https://sso.example.com/login?return_to={{ .OriginalURI | urlEncode }}'; the first matching rule " +
+			"wins; separate multiple rules with '||', a newline, or a tab)",
+		EnvVars: []string{"REDIRECT"},
+		Validator: func(_ *cli.Command, s string) error {
+			_, err := error_page.ParseRedirects(s)
+
+			return err
+		},
+	}
+}
+
 func newShowDetailsFlag() cli.Flag[bool] {
 	return cli.Flag[bool]{
 		Names:   []string{"show-details"},
@@ -121,6 +195,28 @@ func newShowDetailsFlag() cli.Flag[bool] {
 	}
 }
 
+func newSourceFlag() cli.Flag[string] {
+	all := append([]string{sourceAuto}, error_page.Sources()...)
+
+	return cli.Flag[string]{
+		Names: []string{"source"},
+		Usage: "The proxy (or gateway) in front of the error pages, which defines the headers the status code and the " +
+			"request details are taken from (" + strings.Join(all, "/") + ")",
+		Default: sourceAuto,
+		EnvVars: []string{"SOURCE"},
+		Validator: func(_ *cli.Command, s string) error {
+			if _, ok := error_page.SourceByName(s); !ok && s != sourceAuto {
+				return fmt.Errorf("unknown source %q (available sources: %s)", s, strings.Join(all, ", "))
+			}
+
+			return nil
+		},
+	}
+}
+
+// sourceAuto is the value of the source flag, which means the source is detected by the request headers.
+const sourceAuto = "auto"
+
 func newProxyHeadersListFlag(def []string) cli.Flag[string] {
 	return cli.Flag[string]{
 		Names: []string{"proxy-headers"},
@@ -253,6 +349,33 @@ func newPlainTextTemplateFlag() cli.Flag[string] {
 	}
 }
 
+func newYAMLTemplateFlag() cli.Flag[string] {
+	return cli.Flag[string]{
+		Names:     []string{"yaml-template"},
+		Usage:     "Custom YAML template for error page responses (template text/URL/file path)",
+		EnvVars:   []string{"YAML_TEMPLATE"},
+		Validator: validateCustomTemplate,
+	}
+}
+
+func newMarkdownTemplateFlag() cli.Flag[string] {
+	return cli.Flag[string]{
+		Names:     []string{"markdown-template"},
+		Usage:     "Custom Markdown template for error page responses (template text/URL/file path)",
+		EnvVars:   []string{"MARKDOWN_TEMPLATE"},
+		Validator: validateCustomTemplate,
+	}
+}
+
+func newSVGTemplateFlag() cli.Flag[string] {
+	return cli.Flag[string]{
+		Names:     []string{"svg-template"},
+		Usage:     "Custom SVG template for image error responses (template text/URL/file path)",
+		EnvVars:   []string{"SVG_TEMPLATE"},
+		Validator: validateCustomTemplate,
+	}
+}
+
 func validateCustomTemplate(_ *cli.Command, src string) error {
 	if tploader.IsURL(src) || tploader.IsFilePath(src) {
 		// if it's a URL or file path, we will attempt to load it later, so just skip validation for now
@@ -270,3 +393,12 @@ func validateCustomTemplate(_ *cli.Command, src string) error {
 
 	return nil
 }
+
+// sourceName returns the name of the source, or "auto" if it is detected by the request headers.
+func sourceName(s *error_page.Source) string {
+	if s == nil {
+		return sourceAuto
+	}
+
+	return s.Name
+}
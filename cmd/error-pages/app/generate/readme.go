@@ -19,18 +19,27 @@ func main() {
 	flag.Parse()
 
 	if stat, statErr := os.Stat(outFile); statErr == nil && stat.Mode().IsRegular() {
-		var help = app.NewApp("error-pages").Help()
+		var a = app.NewApp("error-pages")
 
-		if err := replaceWith(outFile, help); err != nil {
+		if err := replaceWith(outFile, "SERVER_CLI", a.Help()); err != nil {
 			panic(err)
 		}
+
+		// each subcommand is documented in its own section, e.g. <!--GENERATED:SERVER_CLI_HEALTHCHECK-->
+		for _, name := range a.CommandNames() {
+			tag := "SERVER_CLI_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+
+			if err := replaceWith(outFile, tag, a.CommandHelp(name)); err != nil {
+				fmt.Printf("⚠ cli docs for the %q command not updated: %s\n", name, err.Error())
+			}
+		}
 	} else if statErr != nil {
 		fmt.Println("⚠ readme file not found, cli docs not updated:", statErr.Error())
 	}
 }
 
-func replaceWith(filePath string, content string) error {
-	const start, end = "<!--GENERATED:SERVER_CLI-->", "<!--/GENERATED:SERVER_CLI-->"
+func replaceWith(filePath, tag, content string) error {
+	var start, end = "<!--GENERATED:" + tag + "-->", "<!--/GENERATED:" + tag + "-->"
 
 	// read original file content
 	original, err := os.ReadFile(filePath)
@@ -0,0 +1,85 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"gh.tarampamp.am/error-pages/v4/internal/cli"
+)
+
+// newHealthcheckCommand creates the "healthcheck" subcommand, which probes the liveness endpoint of the locally
+// running server and exits with a non-zero code if the server is not healthy. It is intended to be used in the
+// Docker HEALTHCHECK instruction, so no additional tools (like curl or wget) are required in the image.
+func newHealthcheckCommand(defaultAddr string, defaultPort uint) *cli.Command {
+	var (
+		httpAddrFlag = newHTTPAddrFlag(defaultAddr)
+		httpPortFlag = newHTTPPortFlag(defaultPort)
+	)
+
+	return &cli.Command{
+		Name:        "healthcheck",
+		Description: "Check the health of the locally running HTTP server (exit code 0 means healthy)",
+		Flags:       []cli.Flagger{&httpAddrFlag, &httpPortFlag},
+		Action: func(ctx context.Context, _ *cli.Command, _ []string) error {
+			var addr, port = defaultAddr, defaultPort
+
+			setIfFlagIsSet(&addr, httpAddrFlag)
+			setIfFlagIsSet(&port, httpPortFlag)
+
+			return checkHealth(ctx, addr, port)
+		},
+	}
+}
+
+// checkHealth sends a GET request to the liveness endpoint of the server listening on the given address and port
+// (or Unix socket), and returns an error if the server does not respond with 200 OK.
+func checkHealth(ctx context.Context, addr string, port uint) error {
+	const timeout = 5 * time.Second
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	network, address := listenAddress(addr, port)
+
+	// the server may listen on all interfaces (0.0.0.0 or ::), but we need a concrete address to connect to
+	if ip := net.ParseIP(addr); network == "tcp" && ip != nil && ip.IsUnspecified() {
+		loopback := "127.0.0.1"
+		if ip.To4() == nil {
+			loopback = net.IPv6loopback.String()
+		}
+
+		address = net.JoinHostPort(loopback, strconv.FormatUint(uint64(port), 10))
+	}
+
+	client := http.Client{
+		Transport: &http.Transport{
+			// the host part of the request URL is ignored, since we always dial the resolved address
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return (&net.Dialer{}).DialContext(ctx, network, address)
+			},
+			DisableKeepAlives: true,
+		},
+	}
+
+	req, reqErr := http.NewRequestWithContext(ctx, http.MethodGet, "http://localhost/healthz", http.NoBody)
+	if reqErr != nil {
+		return reqErr
+	}
+
+	resp, respErr := client.Do(req)
+	if respErr != nil {
+		return fmt.Errorf("health check failed: %w", respErr)
+	}
+
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("health check failed: unexpected status code %d", resp.StatusCode)
+	}
+
+	return nil
+}
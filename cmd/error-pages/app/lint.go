@@ -0,0 +1,279 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/url"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gh.tarampamp.am/error-pages/v4/internal/cli"
+	"gh.tarampamp.am/error-pages/v4/internal/codes"
+	"gh.tarampamp.am/error-pages/v4/internal/formats"
+	tpl "gh.tarampamp.am/error-pages/v4/internal/template"
+	"gh.tarampamp.am/error-pages/v4/internal/template/tploader"
+	"gh.tarampamp.am/error-pages/v4/templates"
+)
+
+// newLintCommand creates the "lint" subcommand, which checks the given templates (or the built-in ones, if none
+// given) for common problems, and reports them in a human-readable or JSON format.
+func newLintCommand() *cli.Command {
+	var (
+		formatFlag = newFormatFlag("Format of the templates, if it cannot be detected by the file extension",
+			formats.HTMLFormat,
+		)
+		codesFlag  = newLintCodesFlag()
+		outputFlag = newLintOutputFlag()
+	)
+
+	return &cli.Command{
+		Name:        "lint",
+		Description: "Check error page templates for common problems (exit code 1 means errors were found)",
+		Usage:       "[options] [<file|url>...]",
+		Flags:       []cli.Flagger{&formatFlag, &codesFlag, &outputFlag},
+		Action: func(ctx context.Context, c *cli.Command, args []string) error {
+			var fallback, _ = formats.FromString(*formatFlag.Value) // the flag validates itself
+
+			if fallback == formats.PNGFormat {
+				return errors.New("the png images are drawn without templates, so there is nothing to lint")
+			}
+
+			targets, err := lintTargets(ctx, args, fallback, formatFlag.IsSet())
+			if err != nil {
+				return err
+			}
+
+			codesList, _ := parseLintCodes(*codesFlag.Value) //nolint:errcheck // the flag validates itself
+
+			return lintTemplates(c.Output, targets, lintSamples(codesList), *outputFlag.Value == "json")
+		},
+	}
+}
+
+func newLintCodesFlag() cli.Flag[string] {
+	return cli.Flag[string]{
+		Names: []string{"codes"},
+		Usage: "Comma-separated list of HTTP codes to render the templates with (all built-in codes by default)",
+		Validator: func(_ *cli.Command, s string) error {
+			_, err := parseLintCodes(s)
+
+			return err
+		},
+	}
+}
+
+func newLintOutputFlag() cli.Flag[string] {
+	return cli.Flag[string]{
+		Names:   []string{"output-format"},
+		Usage:   "Report format (text/json)",
+		Default: "text",
+		Validator: func(_ *cli.Command, s string) error {
+			if s != "text" && s != "json" {
+				return fmt.Errorf("unknown report format %q (available formats: text, json)", s)
+			}
+
+			return nil
+		},
+	}
+}
+
+// parseLintCodes parses the comma-separated list of HTTP codes. An empty list means all built-in codes.
+func parseLintCodes(s string) ([]uint16, error) {
+	var result []uint16
+
+	for part := range strings.SplitSeq(s, ",") {
+		if part = strings.TrimSpace(part); part == "" {
+			continue
+		}
+
+		code, err := strconv.ParseUint(part, 10, 16)
+		if err != nil || code == 0 || code > 999 {
+			return nil, fmt.Errorf("wrong HTTP code %q (must be between 1 and 999)", part)
+		}
+
+		result = append(result, uint16(code))
+	}
+
+	if len(result) > 0 {
+		return result, nil
+	}
+
+	return numericCodes(codes.New(codes.BuiltInStandard)), nil
+}
+
+// lintSamples returns the template data for each of the given codes.
+func lintSamples(codesList []uint16) []tpl.Data {
+	var (
+		httpCodes = codes.New(codes.BuiltInExtended)
+		def       = newErrorPagesOptions()
+		samples   = make([]tpl.Data, 0, len(codesList))
+	)
+
+	for _, code := range codesList {
+		desc, _ := httpCodes.Find(code)
+
+		samples = append(samples, tpl.Data{
+			StatusCode:         code,
+			OriginalStatusCode: code,
+			Message:            desc.Short,
+			Description:        desc.Full,
+			HomepageURL:        def.homepageURL,
+			Links:              []tpl.Link{{Label: "Status page", URL: "https://status.example.com"}},
+			Image:              tpl.DefaultImage(),
+		})
+	}
+
+	return samples
+}
+
+// lintTarget is a single template to lint.
+type lintTarget struct {
+	source  string
+	format  formats.Format
+	content string
+}
+
+// lintTargets loads the templates from the given sources (file paths or URLs). If no sources given, the built-in
+// templates are returned. The format is detected by the file extension, unless forced.
+func lintTargets(ctx context.Context, sources []string, fallback formats.Format, force bool) ([]lintTarget, error) {
+	if len(sources) == 0 {
+		var result = []lintTarget{
+			{"built-in:json", formats.JSONFormat, templates.JSON},
+			{"built-in:xml", formats.XMLFormat, templates.XML},
+			{"built-in:text", formats.PlainTextFormat, templates.PlaintText},
+			{"built-in:yaml", formats.YAMLFormat, templates.YAML},
+			{"built-in:markdown", formats.MarkdownFormat, templates.Markdown},
+			{"built-in:svg", formats.SVGFormat, templates.SVG},
+		}
+
+		html := templates.BuiltInHTML()
+
+		for _, name := range slices.Sorted(maps.Keys(html)) {
+			result = append(result, lintTarget{"built-in:" + name, formats.HTMLFormat, html[name]})
+		}
+
+		return result, nil
+	}
+
+	var result = make([]lintTarget, 0, len(sources))
+
+	for _, src := range sources {
+		if !tploader.IsURL(src) && !tploader.IsFilePath(src) {
+			return nil, fmt.Errorf("%s: not a file path or URL", src)
+		}
+
+		content, err := tploader.LoadTemplateContent(ctx, src)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", src, err)
+		}
+
+		format := fallback
+
+		if !force {
+			if detected, ok := detectFormat(src); ok {
+				format = detected
+			}
+		}
+
+		result = append(result, lintTarget{src, format, content})
+	}
+
+	return result, nil
+}
+
+// detectFormat detects the template format by the file extension of the file path or URL. The PNG format has no
+// templates, so it is never detected.
+func detectFormat(src string) (formats.Format, bool) {
+	if u, err := url.Parse(src); err == nil && tploader.IsURL(src) {
+		src = u.Path
+	}
+
+	if f, ok := formats.FromString(strings.TrimPrefix(path.Ext(src), ".")); ok && f != formats.PNGFormat {
+		return f, true
+	}
+
+	return formats.Format(0), false
+}
+
+// lintTemplates lints the templates and writes the report to the output. It returns an error if any error-level
+// problem was found.
+func lintTemplates(out io.Writer, targets []lintTarget, samples []tpl.Data, asJSON bool) error {
+	type result struct {
+		Source      string           `json:"source"`
+		Format      string           `json:"format"`
+		Diagnostics []tpl.Diagnostic `json:"diagnostics"`
+	}
+
+	var report = struct {
+		Templates []result `json:"templates"`
+		Errors    int      `json:"errors"`
+		Warnings  int      `json:"warnings"`
+	}{Templates: make([]result, 0, len(targets))}
+
+	for _, t := range targets {
+		var diagnostics = tpl.Lint(t.content, t.format, samples...)
+
+		if diagnostics == nil {
+			diagnostics = []tpl.Diagnostic{} // to have an empty array instead of null in the JSON report
+		}
+
+		for _, d := range diagnostics {
+			if d.Severity == tpl.SeverityError {
+				report.Errors++
+			} else {
+				report.Warnings++
+			}
+		}
+
+		report.Templates = append(report.Templates, result{t.source, t.format.String(), diagnostics})
+	}
+
+	if asJSON {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+
+		if err := enc.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		var b strings.Builder
+
+		for _, r := range report.Templates {
+			for _, d := range r.Diagnostics {
+				b.WriteString(r.Source)
+
+				if d.Line > 0 {
+					b.WriteString(":" + strconv.Itoa(d.Line))
+				}
+
+				b.WriteString(": " + string(d.Severity) + ": " + d.Message + " [" + d.Rule + "]")
+
+				if d.Case != nil {
+					b.WriteString(" (" + d.Case.String() + ")")
+				}
+
+				b.WriteByte('\n')
+			}
+		}
+
+		_, _ = fmt.Fprintf(&b, "%d template(s) checked: %d error(s), %d warning(s)\n",
+			len(report.Templates), report.Errors, report.Warnings,
+		)
+
+		if _, err := io.WriteString(out, b.String()); err != nil {
+			return err
+		}
+	}
+
+	if report.Errors > 0 {
+		return fmt.Errorf("lint failed: %d error(s) found", report.Errors)
+	}
+
+	return nil
+}
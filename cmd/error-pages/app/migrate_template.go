@@ -0,0 +1,175 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gh.tarampamp.am/error-pages/v4/internal/cli"
+	tpl "gh.tarampamp.am/error-pages/v4/internal/template"
+	"gh.tarampamp.am/error-pages/v4/internal/template/tploader"
+)
+
+// newMigrateTemplateCommand creates the "migrate-template" subcommand, which converts a template from the v3 syntax
+// to the v4 one, prints the diff of every rewrite, and reports the constructs that must be fixed manually.
+func newMigrateTemplateCommand() *cli.Command {
+	var (
+		outFlag     = newMigrateOutFlag()
+		inPlaceFlag = newMigrateInPlaceFlag()
+	)
+
+	return &cli.Command{
+		Name: "migrate-template",
+		Description: "Convert a template from the v3 syntax to the v4 one (the migrated template is printed to stdout, " +
+			"and the report to stderr, unless the output file is specified)",
+		Usage: "[options] <file|url>",
+		Flags: []cli.Flagger{&outFlag, &inPlaceFlag},
+		Action: func(ctx context.Context, c *cli.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("exactly one template file path or URL is required")
+			}
+
+			var src, outPath = args[0], *outFlag.Value
+
+			if !tploader.IsURL(src) && !tploader.IsFilePath(src) {
+				return fmt.Errorf("%s: not a file path or URL", src)
+			}
+
+			if *inPlaceFlag.Value {
+				if tploader.IsURL(src) {
+					return errors.New("the template cannot be migrated in place, since it is loaded from a URL")
+				}
+
+				outPath = src
+			}
+
+			content, err := tploader.LoadTemplateContent(ctx, src)
+			if err != nil {
+				return fmt.Errorf("%s: %w", src, err)
+			}
+
+			return migrateTemplate(c.Output, os.Stderr, src, content, outPath)
+		},
+	}
+}
+
+func newMigrateOutFlag() cli.Flag[string] {
+	return cli.Flag[string]{
+		Names: []string{"out", "o"},
+		Usage: "Write the migrated template to the file (the report is printed to stdout in this case)",
+	}
+}
+
+func newMigrateInPlaceFlag() cli.Flag[bool] {
+	return cli.Flag[bool]{
+		Names: []string{"in-place", "w"},
+		Usage: "Overwrite the source template file with the migrated one",
+	}
+}
+
+// migrateTemplate migrates the template content and writes the result to the file (or stdout, if the path is
+// empty), and the report to stdout (or stderr, if the result is written to stdout).
+func migrateTemplate(stdout, stderr io.Writer, name, content, outPath string) error {
+	var (
+		m      = tpl.MigrateV3toV4(content)
+		report = stdout
+	)
+
+	if outPath == "" {
+		if _, err := io.WriteString(stdout, m.Result); err != nil {
+			return err
+		}
+
+		report = stderr
+	} else if m.Result != content || outPath != name { // do not touch the file if there is nothing to change
+		if err := writeFileAtomic(outPath, m.Result); err != nil {
+			return fmt.Errorf("write the migrated template: %w", err)
+		}
+	}
+
+	var b strings.Builder
+
+	writeLinesDiff(&b, name, content, m.Result)
+
+	for _, issue := range m.Issues {
+		b.WriteString(name)
+
+		if issue.Line > 0 {
+			b.WriteString(":" + strconv.Itoa(issue.Line))
+		}
+
+		b.WriteString(": manual action required: " + issue.Message + "\n")
+	}
+
+	_, _ = fmt.Fprintf(&b, "%d rewrite(s) made, %d construct(s) need manual attention\n",
+		len(m.Rewrites), len(m.Issues),
+	)
+
+	_, err := io.WriteString(report, b.String())
+
+	return err
+}
+
+// writeFileAtomic writes the content to the temporary file next to the path, and renames it to the path on success,
+// so the existing file (e.g. the migrated template) is never left half-written. The permissions of the existing file
+// are kept, and the symbolic link is followed (the file it points to is replaced, not the link).
+func writeFileAtomic(path, content string) error {
+	var mode os.FileMode = 0o664 //nolint:mnd
+
+	if target, err := filepath.EvalSymlinks(path); err == nil {
+		path = target
+	}
+
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = os.Remove(f.Name()) }() // no-op after the rename
+
+	if _, wErr := io.WriteString(f, content); wErr != nil {
+		return errors.Join(wErr, f.Close())
+	}
+
+	if cErr := f.Close(); cErr != nil {
+		return cErr
+	}
+
+	if chErr := os.Chmod(f.Name(), mode); chErr != nil {
+		return chErr
+	}
+
+	return os.Rename(f.Name(), path)
+}
+
+// writeLinesDiff writes a unified-like diff of the changed lines. The migration never adds or removes lines, so
+// the lines of both versions are compared one by one.
+func writeLinesDiff(b *strings.Builder, name, before, after string) {
+	var (
+		oldLines = strings.Split(before, "\n")
+		newLines = strings.Split(after, "\n")
+	)
+
+	if before == after || len(oldLines) != len(newLines) {
+		return
+	}
+
+	b.WriteString("--- " + name + "\n+++ " + name + " (v4)\n")
+
+	for i := range oldLines {
+		if oldLines[i] == newLines[i] {
+			continue
+		}
+
+		_, _ = fmt.Fprintf(b, "@@ -%[1]d +%[1]d @@\n-%s\n+%s\n", i+1, oldLines[i], newLines[i])
+	}
+}
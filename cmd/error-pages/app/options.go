@@ -0,0 +1,314 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"gh.tarampamp.am/error-pages/v4/internal/cli"
+	"gh.tarampamp.am/error-pages/v4/internal/cli/shared"
+	"gh.tarampamp.am/error-pages/v4/internal/codes"
+	"gh.tarampamp.am/error-pages/v4/internal/errgroup"
+	"gh.tarampamp.am/error-pages/v4/internal/httpserver/handlers/error_page"
+	"gh.tarampamp.am/error-pages/v4/internal/logger"
+	tpl "gh.tarampamp.am/error-pages/v4/internal/template"
+	"gh.tarampamp.am/error-pages/v4/internal/template/tploader"
+	"gh.tarampamp.am/error-pages/v4/templates"
+)
+
+// errorPagesOptions holds the options that affect how the error pages are rendered. They are shared between the
+// HTTP server and other commands that render error pages (so the output is always the same).
+type errorPagesOptions struct {
+	defaultCodeToRender uint
+	sendSameHTTPCode    bool
+	showDetails         bool
+	source              *error_page.Source // nil means it is detected by the request headers
+	proxyHeaders        []string
+	codeMapping         error_page.CodeMapping
+	redirects           error_page.Redirects
+	disableBuiltInCodes bool
+	builtInCodes        string // the set of the built-in codes
+	addHTTPCodes        map[string]codes.Description
+	codesFile           *codes.File // nil if not set
+	templateName        string
+	rotationMode        tpl.RotationMode
+	homepageURL         string
+	links               []tpl.Link
+	customTemplates     struct {
+		html, json, xml, text, yaml, markdown, svg string
+	}
+	l10nDisabled bool
+}
+
+// newErrorPagesOptions returns the error pages options with default values.
+func newErrorPagesOptions() errorPagesOptions {
+	return errorPagesOptions{
+		defaultCodeToRender: uint(http.StatusNotFound),
+		proxyHeaders:        []string{"X-Request-Id", "X-Trace-Id", "X-Correlation-Id", "X-Amzn-Trace-Id"},
+		templateName:        templates.HTMLTemplateNameAppDown,
+		rotationMode:        tpl.RotationModeDisabled,
+		homepageURL:         "/",
+		builtInCodes:        codes.BuiltInStandard,
+	}
+}
+
+// errorPagesFlags holds the flags for the [errorPagesOptions]. Commands register only the flags they need; the
+// values of flags that were not registered (or not set explicitly) are not applied to the options.
+type errorPagesFlags struct {
+	defaultCodeToRender cli.Flag[uint]
+	sendSameHTTPCode    cli.Flag[bool]
+	showDetails         cli.Flag[bool]
+	source              cli.Flag[string]
+	proxyHeadersList    cli.Flag[string]
+	mapCodes            cli.Flag[string]
+	redirects           cli.Flag[string]
+	disableBuiltInCodes cli.Flag[bool]
+	builtInCodes        cli.Flag[string]
+	addHTTPCodes        cli.Flag[string]
+	codesFile           cli.Flag[string]
+	templateName        cli.Flag[string]
+	rotationMode        cli.Flag[string]
+	homepageURL         cli.Flag[string]
+	addLinks            cli.Flag[string]
+	htmlTemplate        cli.Flag[string]
+	jsonTemplate        cli.Flag[string]
+	xmlTemplate         cli.Flag[string]
+	textTemplate        cli.Flag[string]
+	yamlTemplate        cli.Flag[string]
+	markdownTemplate    cli.Flag[string]
+	svgTemplate         cli.Flag[string]
+	disableL10n         cli.Flag[bool]
+}
+
+// newErrorPagesFlags creates the error pages flags, using the given options as defaults.
+func newErrorPagesFlags(def errorPagesOptions) errorPagesFlags {
+	allTemplateNames := slices.Collect(maps.Keys(templates.BuiltInHTML()))
+	slices.Sort(allTemplateNames)
+
+	return errorPagesFlags{
+		defaultCodeToRender: newDefaultCodeToRenderFlag(def.defaultCodeToRender),
+		sendSameHTTPCode:    newSendSameHTTPCodeFlag(),
+		showDetails:         newShowDetailsFlag(),
+		source:              newSourceFlag(),
+		proxyHeadersList:    newProxyHeadersListFlag(def.proxyHeaders),
+		mapCodes:            newMapCodesFlag(),
+		redirects:           newRedirectsFlag(),
+		disableBuiltInCodes: shared.NewDisableBuiltInCodesFlag(),
+		builtInCodes:        shared.NewBuiltInCodesFlag(),
+		addHTTPCodes:        shared.NewAddHTTPCodesFlag(),
+		codesFile:           shared.NewCodesFileFlag(),
+		templateName:        newTemplateNameFlag(allTemplateNames, def.templateName),
+		rotationMode:        newRotationModeFlag(def.rotationMode),
+		homepageURL:         shared.NewHomepageURLFlag(def.homepageURL),
+		addLinks:            shared.NewAddLinksFlag(),
+		htmlTemplate:        newHTMLTemplateFlag(),
+		jsonTemplate:        newJSONTemplateFlag(),
+		xmlTemplate:         newXMLTemplateFlag(),
+		textTemplate:        newPlainTextTemplateFlag(),
+		yamlTemplate:        newYAMLTemplateFlag(),
+		markdownTemplate:    newMarkdownTemplateFlag(),
+		svgTemplate:         newSVGTemplateFlag(),
+		disableL10n:         shared.NewDisableL10nFlag(),
+	}
+}
+
+// apply copies the values of explicitly set flags into the options.
+func (f *errorPagesFlags) apply(opt *errorPagesOptions) {
+	setIfFlagIsSet(&opt.defaultCodeToRender, f.defaultCodeToRender)
+	setIfFlagIsSet(&opt.sendSameHTTPCode, f.sendSameHTTPCode)
+	setIfFlagIsSet(&opt.showDetails, f.showDetails)
+
+	if f.source.Value != nil && f.source.IsSet() {
+		opt.source, _ = error_page.SourceByName(*f.source.Value) // nil for "auto"
+	}
+
+	setIfFlagIsSet(&opt.disableBuiltInCodes, f.disableBuiltInCodes)
+	setIfFlagIsSet(&opt.builtInCodes, f.builtInCodes)
+
+	if f.proxyHeadersList.Value != nil && f.proxyHeadersList.IsSet() {
+		opt.proxyHeaders = splitProxyHeadersList(*f.proxyHeadersList.Value)
+	}
+
+	slices.Sort(opt.proxyHeaders)
+
+	if f.mapCodes.Value != nil && f.mapCodes.IsSet() {
+		if parsed, err := error_page.ParseCodeMapping(*f.mapCodes.Value); err == nil {
+			opt.codeMapping = parsed
+		}
+	}
+
+	if f.redirects.Value != nil && f.redirects.IsSet() {
+		if parsed, err := error_page.ParseRedirects(*f.redirects.Value); err == nil {
+			opt.redirects = parsed
+		}
+	}
+
+	if f.addHTTPCodes.Value != nil && f.addHTTPCodes.IsSet() {
+		if parsed, err := shared.ParseAddHTTPCodes(*f.addHTTPCodes.Value); err == nil {
+			opt.addHTTPCodes = parsed
+		}
+	}
+
+	if f.codesFile.Value != nil && f.codesFile.IsSet() && *f.codesFile.Value != "" {
+		if file, err := codes.LoadFile(*f.codesFile.Value); err == nil {
+			opt.codesFile = file
+		}
+	}
+
+	setIfFlagIsSet(&opt.templateName, f.templateName)
+
+	if f.rotationMode.Value != nil && f.rotationMode.IsSet() {
+		opt.rotationMode = tpl.RotationMode(*f.rotationMode.Value)
+	}
+
+	setIfFlagIsSet(&opt.homepageURL, f.homepageURL)
+
+	if f.addLinks.Value != nil && f.addLinks.IsSet() {
+		if parsed, err := shared.ParseLinks(*f.addLinks.Value); err == nil {
+			opt.links = parsed
+		}
+	}
+
+	setIfFlagIsSet(&opt.customTemplates.html, f.htmlTemplate)
+	setIfFlagIsSet(&opt.customTemplates.json, f.jsonTemplate)
+	setIfFlagIsSet(&opt.customTemplates.xml, f.xmlTemplate)
+	setIfFlagIsSet(&opt.customTemplates.text, f.textTemplate)
+	setIfFlagIsSet(&opt.customTemplates.yaml, f.yamlTemplate)
+	setIfFlagIsSet(&opt.customTemplates.markdown, f.markdownTemplate)
+	setIfFlagIsSet(&opt.customTemplates.svg, f.svgTemplate)
+	setIfFlagIsSet(&opt.l10nDisabled, f.disableL10n)
+}
+
+// loadTemplates loads custom templates concurrently if they are specified in the options and appear to be from a
+// valid source (URL or file path), and does nothing otherwise.
+func (o *errorPagesOptions) loadTemplates(ctx context.Context) error {
+	ct := &o.customTemplates
+	eg, _ := errgroup.New(ctx)
+
+	for _, item := range []struct {
+		name string
+		src  *string
+	}{
+		{"HTML", &ct.html},
+		{"JSON", &ct.json},
+		{"XML", &ct.xml},
+		{"plain text", &ct.text},
+		{"YAML", &ct.yaml},
+		{"Markdown", &ct.markdown},
+		{"SVG", &ct.svg},
+	} {
+		if *item.src == "" {
+			continue
+		}
+
+		eg.Go(func(ctx context.Context) error {
+			t, err := tploader.LoadTemplateContent(ctx, *item.src)
+			if err != nil {
+				return fmt.Errorf("load %s template: %w", item.name, err)
+			}
+
+			*item.src = t
+
+			return nil
+		})
+	}
+
+	return eg.Wait()
+}
+
+// httpCodes returns the HTTP codes with their descriptions, according to the options. The codes from the file
+// override the built-in ones, and the codes from the command line override both.
+func (o *errorPagesOptions) httpCodes() codes.Codes {
+	set := o.builtInCodes
+	if o.disableBuiltInCodes || o.codesFile != nil && o.codesFile.Mode == codes.FileModeReplace {
+		set = codes.BuiltInNone
+	}
+
+	httpCodes := codes.New(set)
+
+	if o.codesFile != nil {
+		maps.Copy(httpCodes, o.codesFile.Codes)
+	}
+
+	maps.Copy(httpCodes, o.addHTTPCodes)
+
+	return httpCodes
+}
+
+// numericCodes returns the sorted list of the exact (non-wildcard) HTTP codes, which are not excluded.
+func numericCodes(httpCodes codes.Codes) []uint16 {
+	var result = make([]uint16, 0, len(httpCodes))
+
+	for _, key := range httpCodes.Codes() {
+		if code, err := strconv.ParseUint(key, 10, 16); err == nil && code > 0 && code <= 999 {
+			if _, found := httpCodes.Find(uint16(code)); found {
+				result = append(result, uint16(code))
+			}
+		}
+	}
+
+	return result
+}
+
+// templates initializes the templates according to the options. Custom templates must be already loaded
+// (see [errorPagesOptions.loadTemplates]).
+func (o *errorPagesOptions) templates() (*tpl.Templates, error) {
+	return tpl.NewTemplates(
+		tpl.WithCustomHTMLTemplate(o.customTemplates.html),
+		tpl.WithCustomJSONTemplate(o.customTemplates.json),
+		tpl.WithCustomXMLTemplate(o.customTemplates.xml),
+		tpl.WithCustomPlainTextTemplate(o.customTemplates.text),
+		tpl.WithCustomYAMLTemplate(o.customTemplates.yaml),
+		tpl.WithCustomMarkdownTemplate(o.customTemplates.markdown),
+		tpl.WithCustomSVGTemplate(o.customTemplates.svg),
+		tpl.WithHTMLTemplateName(o.templateName),
+		tpl.WithRotationMode(o.rotationMode),
+	)
+}
+
+// errorPageHandler creates the error page handler according to the options.
+func (o *errorPagesOptions) errorPageHandler(
+	log *logger.Logger,
+	httpCodes codes.Codes,
+	templater *tpl.Templates,
+) http.Handler {
+	return error_page.New(
+		log,
+		uint16(o.defaultCodeToRender), //nolint:gosec // validated to be in range 0-999
+		o.sendSameHTTPCode,
+		o.proxyHeaders,
+		o.codeMapping,
+		o.redirects,
+		httpCodes.Compile().Find,
+		templater.Get,
+		o.showDetails,
+		o.l10nDisabled,
+		o.homepageURL,
+		o.links,
+		o.source,
+	)
+}
+
+// testRenderData returns the template data for the default error page, used to test the templates rendering.
+func (o *errorPagesOptions) testRenderData(httpCodes codes.Codes) tpl.Data {
+	code := uint16(o.defaultCodeToRender) //nolint:gosec // validated to be in range 0-999
+
+	desc, _ := httpCodes.Find(code)
+
+	return tpl.Data{
+		StatusCode:         code,
+		OriginalStatusCode: code,
+		Message:            desc.Short,
+		Description:        desc.Full,
+		HomepageURL:        o.homepageURL,
+		Links:              o.links,
+		Image:              tpl.DefaultImage(),
+		Config: tpl.Config{
+			ShowRequestDetails: o.showDetails,
+			L10nDisabled:       o.l10nDisabled,
+		},
+	}
+}
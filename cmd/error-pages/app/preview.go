@@ -0,0 +1,198 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net"
+	"os"
+	"slices"
+	"time"
+
+	"gh.tarampamp.am/error-pages/v4/internal/cli"
+	"gh.tarampamp.am/error-pages/v4/internal/formats"
+	"gh.tarampamp.am/error-pages/v4/internal/httpserver"
+	"gh.tarampamp.am/error-pages/v4/internal/logger"
+	"gh.tarampamp.am/error-pages/v4/internal/preview"
+	tpl "gh.tarampamp.am/error-pages/v4/internal/template"
+)
+
+// newPreviewCommand creates the "preview" subcommand, which starts a local HTTP server with a gallery of the error
+// pages (every code in every format), rendered using the given template files. The files are watched for changes,
+// and the gallery is reloaded in the browser automatically.
+func newPreviewCommand(def errorPagesOptions) *cli.Command { //nolint:funlen
+	var (
+		httpAddrFlag = newHTTPAddrFlag("127.0.0.1") // the preview is not intended to be exposed
+		httpPortFlag = newHTTPPortFlag(8080)        //nolint:mnd
+		intervalFlag = newPreviewIntervalFlag()
+		epFlags      = newErrorPagesFlags(def)
+	)
+
+	return &cli.Command{
+		Name: "preview",
+		Description: "Start a live preview of the error pages for the given template files (the format of each file " +
+			"is detected by its extension, missing formats are taken from the built-in templates)",
+		Usage: "[options] [<file>...]",
+		Flags: []cli.Flagger{
+			&httpAddrFlag,
+			&httpPortFlag,
+			&intervalFlag,
+			&epFlags.disableBuiltInCodes,
+			&epFlags.builtInCodes,
+			&epFlags.addHTTPCodes,
+			&epFlags.codesFile,
+			&epFlags.templateName,
+			&epFlags.homepageURL,
+			&epFlags.addLinks,
+		},
+		Action: func(ctx context.Context, _ *cli.Command, args []string) error {
+			var (
+				opt        = def
+				addr, port = "127.0.0.1", uint(8080) //nolint:mnd
+			)
+
+			setIfFlagIsSet(&addr, httpAddrFlag)
+			setIfFlagIsSet(&port, httpPortFlag)
+			epFlags.apply(&opt)
+
+			files, err := previewFiles(args)
+			if err != nil {
+				return err
+			}
+
+			log, logErr := logger.New(logger.InfoLevel, logger.ConsoleFormat)
+			if logErr != nil {
+				return logErr
+			}
+
+			return runPreview(ctx, log, &opt, files, addr, port, *intervalFlag.Value)
+		},
+	}
+}
+
+func newPreviewIntervalFlag() cli.Flag[time.Duration] {
+	return cli.Flag[time.Duration]{
+		Names:   []string{"interval"},
+		Usage:   "How often to check the template files for changes",
+		Default: 500 * time.Millisecond, //nolint:mnd
+		Validator: func(_ *cli.Command, d time.Duration) error {
+			if d <= 0 {
+				return errors.New("the interval must be positive")
+			}
+
+			return nil
+		},
+	}
+}
+
+// previewFiles maps the template files to their formats (detected by the file extension). Only one file per format
+// is allowed.
+func previewFiles(args []string) (map[formats.Format]string, error) {
+	var result = make(map[formats.Format]string, len(args))
+
+	for _, path := range args {
+		if stat, err := os.Stat(path); err != nil {
+			return nil, err
+		} else if stat.IsDir() {
+			return nil, fmt.Errorf("%s: is a directory", path)
+		}
+
+		format, ok := detectFormat(path)
+		if !ok {
+			return nil, fmt.Errorf("%s: cannot detect the template format by the file extension", path)
+		}
+
+		if prev, dup := result[format]; dup {
+			return nil, fmt.Errorf("%s: the %s template is already given (%s)", path, format, prev)
+		}
+
+		result[format] = path
+	}
+
+	return result, nil
+}
+
+// runPreview starts the preview server and the files watcher, and blocks until the context is canceled.
+func runPreview(
+	ctx context.Context,
+	log *logger.Logger,
+	opt *errorPagesOptions,
+	files map[formats.Format]string,
+	addr string,
+	port uint,
+	interval time.Duration,
+) error {
+	var (
+		httpCodes = opt.httpCodes()
+		paths     = slices.Sorted(maps.Values(files))
+	)
+
+	// the files are re-read on every reload, the rest of the options stay the same
+	load := func() (*tpl.Templates, error) {
+		var o = *opt
+
+		for format, path := range files {
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return nil, err
+			}
+
+			switch format {
+			case formats.HTMLFormat:
+				o.customTemplates.html = string(content)
+			case formats.JSONFormat:
+				o.customTemplates.json = string(content)
+			case formats.XMLFormat:
+				o.customTemplates.xml = string(content)
+			case formats.PlainTextFormat:
+				o.customTemplates.text = string(content)
+			case formats.YAMLFormat:
+				o.customTemplates.yaml = string(content)
+			case formats.MarkdownFormat:
+				o.customTemplates.markdown = string(content)
+			case formats.SVGFormat:
+				o.customTemplates.svg = string(content)
+			}
+		}
+
+		templater, err := o.templates()
+		if err != nil {
+			return nil, err
+		}
+
+		if err = templater.TestRender(o.testRenderData(httpCodes)); err != nil {
+			return nil, err
+		}
+
+		return templater, nil
+	}
+
+	p := preview.New(log, load, httpCodes.Compile().Find, numericCodes(httpCodes), opt.homepageURL, opt.links)
+
+	// the event streams never end by themselves, so they must be closed before the graceful shutdown
+	stop := context.AfterFunc(ctx, p.Close)
+	defer stop()
+
+	go preview.Watch(ctx, interval, paths, func() {
+		log.Info("Template files changed, reloading")
+
+		p.Reload()
+	})
+
+	network, address := listenAddress(addr, port)
+
+	ln, lnErr := (&net.ListenConfig{}).Listen(ctx, network, address)
+	if lnErr != nil {
+		return fmt.Errorf("listen http: %w", lnErr)
+	}
+
+	defer func() { _ = ln.Close() }()
+
+	log.Info("Preview server started",
+		logger.String("url", "http://"+ln.Addr().String()+"/"),
+		logger.Strings("watching", paths...),
+	)
+
+	return httpserver.New(p, httpserver.WithErrorLog(logger.NewStdLog(log, logger.ErrorLevel))).Serve(ctx, ln)
+}
@@ -0,0 +1,241 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"gh.tarampamp.am/error-pages/v4/internal/cli"
+	"gh.tarampamp.am/error-pages/v4/internal/codes"
+	"gh.tarampamp.am/error-pages/v4/internal/httpserver"
+	"gh.tarampamp.am/error-pages/v4/internal/httpserver/handlers/proxy"
+	"gh.tarampamp.am/error-pages/v4/internal/httpserver/middleware"
+	"gh.tarampamp.am/error-pages/v4/internal/logger"
+)
+
+// newProxyCommand creates the "proxy" subcommand, which starts a reverse proxy in front of the upstream, replacing
+// its error responses with the error pages. It is useful for the platforms without an errors middleware (like the
+// one of Traefik, or the custom errors of ingress-nginx).
+func newProxyCommand(def errorPagesOptions) *cli.Command { //nolint:funlen
+	var (
+		logLevelFlag  = newLogLevelFlag()
+		logFormatFlag = newLogFormatFlag()
+		httpAddrFlag  = newHTTPAddrFlag("0.0.0.0")
+		httpPortFlag  = newHTTPPortFlag(8080) //nolint:mnd
+		upstreamFlag  = newUpstreamFlag()
+		timeoutFlag   = newUpstreamTimeoutFlag()
+		codesFlag     = newInterceptCodesFlag()
+		modeFlag      = newInterceptModeFlag()
+		epFlags       = newErrorPagesFlags(def)
+	)
+
+	return &cli.Command{
+		Name: "proxy",
+		Description: "Start a reverse proxy in front of the upstream, which replaces its error responses with the " +
+			"error pages (successful responses are passed through unchanged)",
+		Flags: []cli.Flagger{
+			&logLevelFlag,
+			&logFormatFlag,
+			&httpAddrFlag,
+			&httpPortFlag,
+			&upstreamFlag,
+			&timeoutFlag,
+			&codesFlag,
+			&modeFlag,
+			&epFlags.showDetails,
+			&epFlags.source,
+			&epFlags.proxyHeadersList,
+			&epFlags.mapCodes,
+			&epFlags.redirects,
+			&epFlags.disableBuiltInCodes,
+			&epFlags.builtInCodes,
+			&epFlags.addHTTPCodes,
+			&epFlags.codesFile,
+			&epFlags.templateName,
+			&epFlags.rotationMode,
+			&epFlags.homepageURL,
+			&epFlags.addLinks,
+			&epFlags.htmlTemplate,
+			&epFlags.jsonTemplate,
+			&epFlags.xmlTemplate,
+			&epFlags.textTemplate,
+			&epFlags.yamlTemplate,
+			&epFlags.markdownTemplate,
+			&epFlags.svgTemplate,
+			&epFlags.disableL10n,
+		},
+		Action: func(ctx context.Context, _ *cli.Command, _ []string) error {
+			var (
+				logLevel, _  = logger.ParseLevel(*logLevelFlag.Value)   //nolint:errcheck // the flag validates itself
+				logFormat, _ = logger.ParseFormat(*logFormatFlag.Value) //nolint:errcheck // the flag validates itself
+				opt          = def
+				addr, port   = "0.0.0.0", uint(8080) //nolint:mnd
+			)
+
+			log, logErr := logger.New(logLevel, logFormat)
+			if logErr != nil {
+				return logErr
+			}
+
+			setIfFlagIsSet(&addr, httpAddrFlag)
+			setIfFlagIsSet(&port, httpPortFlag)
+			epFlags.apply(&opt)
+
+			opt.sendSameHTTPCode = true // the status code of the upstream is always kept
+
+			if *upstreamFlag.Value == "" {
+				return errors.New("missing upstream URL (see --upstream)")
+			}
+
+			upstream, _ := url.Parse(*upstreamFlag.Value)    //nolint:errcheck // the flag validates itself
+			filter, _ := codes.ParseFilter(*codesFlag.Value) //nolint:errcheck // the flag validates itself
+			mode := proxy.ReplaceMode(*modeFlag.Value)
+
+			if err := opt.loadTemplates(ctx); err != nil {
+				return fmt.Errorf("failed to load custom templates: %w", err)
+			}
+
+			if err := runProxy(ctx, log, &opt, upstream, *timeoutFlag.Value, filter, mode, addr, port); err != nil {
+				log.Error("Proxy server failed", logger.Error(err))
+
+				return errors.New("proxy server failed")
+			}
+
+			return nil
+		},
+	}
+}
+
+func newUpstreamFlag() cli.Flag[string] {
+	return cli.Flag[string]{
+		Names:   []string{"upstream"},
+		Usage:   "URL of the upstream to forward the requests to (e.g. 'http://app:3000')",
+		EnvVars: []string{"UPSTREAM"},
+		Validator: func(_ *cli.Command, s string) error {
+			if s == "" {
+				return nil // checked by the command, since the flag has no default value
+			}
+
+			u, err := url.Parse(s)
+			if err != nil {
+				return fmt.Errorf("wrong upstream URL [%s]: %w", s, err)
+			}
+
+			if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return fmt.Errorf("wrong upstream URL [%s]: must be an absolute http(s) URL", s)
+			}
+
+			return nil
+		},
+	}
+}
+
+func newUpstreamTimeoutFlag() cli.Flag[time.Duration] {
+	return cli.Flag[time.Duration]{
+		Names: []string{"upstream-timeout"},
+		Usage: "How long to wait for the upstream response headers before responding with the 504 error page " +
+			"(zero means no timeout)",
+		Default: 60 * time.Second, //nolint:mnd
+		EnvVars: []string{"UPSTREAM_TIMEOUT"},
+		Validator: func(_ *cli.Command, d time.Duration) error {
+			if d < 0 {
+				return fmt.Errorf("wrong upstream timeout [%s]: must not be negative", d)
+			}
+
+			return nil
+		},
+	}
+}
+
+func newInterceptCodesFlag() cli.Flag[string] {
+	return cli.Flag[string]{
+		Names: []string{"intercept-codes"},
+		Usage: "Comma-separated list of the upstream response codes to replace with the error pages - exact codes, " +
+			"codes with wildcards, or ranges (only 4xx and 5xx codes are replaced)",
+		Default: "4xx,5xx",
+		EnvVars: []string{"INTERCEPT_CODES"},
+		Validator: func(_ *cli.Command, s string) error {
+			_, err := codes.ParseFilter(s)
+
+			return err
+		},
+	}
+}
+
+func newInterceptModeFlag() cli.Flag[string] {
+	all := make([]string, 0, len(proxy.ReplaceModes()))
+
+	for _, m := range proxy.ReplaceModes() {
+		all = append(all, string(m))
+	}
+
+	return cli.Flag[string]{
+		Names: []string{"intercept-mode"},
+		Usage: "Which upstream error responses to replace: always, only with an empty body, or only with an empty " +
+			"or non-JSON body (" + strings.Join(all, "/") + ")",
+		Default: string(proxy.ReplaceAlways),
+		EnvVars: []string{"INTERCEPT_MODE"},
+		Validator: func(_ *cli.Command, s string) error {
+			if !slices.Contains(proxy.ReplaceModes(), proxy.ReplaceMode(s)) {
+				return fmt.Errorf("unknown intercept mode %q (available modes: %s)", s, strings.Join(all, ", "))
+			}
+
+			return nil
+		},
+	}
+}
+
+// runProxy opens the listener and starts the reverse proxy, and blocks until the context is canceled or the server
+// fails.
+func runProxy(
+	ctx context.Context,
+	log *logger.Logger,
+	opt *errorPagesOptions,
+	upstream *url.URL,
+	timeout time.Duration,
+	filter codes.Filter,
+	mode proxy.ReplaceMode,
+	addr string,
+	port uint,
+) error {
+	httpCodes := opt.httpCodes()
+
+	templater, tErr := opt.templates()
+	if tErr != nil {
+		return fmt.Errorf("initialize templates: %w", tErr)
+	}
+
+	if err := templater.TestRender(opt.testRenderData(httpCodes)); err != nil {
+		return fmt.Errorf("test templates rendering: %w", err)
+	}
+
+	network, address := listenAddress(addr, port)
+
+	ln, lnErr := (&net.ListenConfig{}).Listen(ctx, network, address)
+	if lnErr != nil {
+		return fmt.Errorf("listen http: %w", lnErr)
+	}
+
+	defer func() { _ = ln.Close() }()
+
+	handler := middleware.Apply(
+		proxy.New(log, upstream, timeout, filter, mode, opt.errorPageHandler(log, httpCodes, templater)),
+		middleware.NewInjectLog(log),
+		middleware.NewAccessLog(logger.InfoLevel, nil),
+	)
+
+	log.Info("Proxy server started",
+		logger.String("addr", ln.Addr().String()),
+		logger.String("upstream", upstream.String()),
+		logger.Duration("upstream_timeout", timeout),
+		logger.String("intercept_mode", string(mode)),
+		logger.String("template_name", opt.templateName),
+	)
+
+	return httpserver.New(handler, httpserver.WithErrorLog(logger.NewStdLog(log, logger.ErrorLevel))).Serve(ctx, ln)
+}
@@ -0,0 +1,233 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"gh.tarampamp.am/error-pages/v4/internal/cli"
+	"gh.tarampamp.am/error-pages/v4/internal/formats"
+	"gh.tarampamp.am/error-pages/v4/internal/httpserver/handlers/error_page"
+	"gh.tarampamp.am/error-pages/v4/internal/logger"
+)
+
+// newRenderCommand creates the "render" subcommand, which renders a single error page using the same templates,
+// codes, and handler as the HTTP server, and prints the response body (optionally with the status line and
+// headers) to stdout. It is useful for designing templates and snapshot-testing them without starting the server.
+func newRenderCommand(def errorPagesOptions) *cli.Command { //nolint:funlen
+	var (
+		codeFlag           = newRenderCodeFlag(def.defaultCodeToRender)
+		formatFlag         = newFormatFlag("Format of the error page to render", formats.HTMLFormat)
+		headersFlag        = newRenderHeadersFlag()
+		includeHeadersFlag = newRenderIncludeHeadersFlag()
+		epFlags            = newErrorPagesFlags(def)
+	)
+
+	return &cli.Command{
+		Name:        "render",
+		Description: "Render a single error page and print it to stdout (exactly as the HTTP server would respond)",
+		Flags: []cli.Flagger{
+			&codeFlag,
+			&formatFlag,
+			&headersFlag,
+			&includeHeadersFlag,
+			&epFlags.sendSameHTTPCode,
+			&epFlags.showDetails,
+			&epFlags.source,
+			&epFlags.proxyHeadersList,
+			&epFlags.mapCodes,
+			&epFlags.disableBuiltInCodes,
+			&epFlags.builtInCodes,
+			&epFlags.addHTTPCodes,
+			&epFlags.codesFile,
+			&epFlags.templateName,
+			&epFlags.homepageURL,
+			&epFlags.addLinks,
+			&epFlags.htmlTemplate,
+			&epFlags.jsonTemplate,
+			&epFlags.xmlTemplate,
+			&epFlags.textTemplate,
+			&epFlags.yamlTemplate,
+			&epFlags.markdownTemplate,
+			&epFlags.svgTemplate,
+			&epFlags.disableL10n,
+		},
+		Action: func(ctx context.Context, c *cli.Command, _ []string) error {
+			var (
+				opt    = def
+				code   = def.defaultCodeToRender
+				format = formats.HTMLFormat
+				header http.Header
+			)
+
+			epFlags.apply(&opt)
+			setIfFlagIsSet(&code, codeFlag)
+
+			if formatFlag.Value != nil {
+				format, _ = formats.FromString(*formatFlag.Value) // the flag validates itself
+			}
+
+			if headersFlag.Value != nil && headersFlag.IsSet() {
+				header, _ = parseRequestHeaders(*headersFlag.Value) //nolint:errcheck // the flag validates itself
+			}
+
+			if err := opt.loadTemplates(ctx); err != nil {
+				return fmt.Errorf("failed to load custom templates: %w", err)
+			}
+
+			return renderErrorPage(ctx, c.Output, &opt, code, format, header, *includeHeadersFlag.Value)
+		},
+	}
+}
+
+func newRenderCodeFlag(def uint) cli.Flag[uint] {
+	return cli.Flag[uint]{
+		Names:   []string{"code"},
+		Usage:   "HTTP status code of the error page to render",
+		Default: def,
+		Validator: func(_ *cli.Command, code uint) error {
+			if code == 0 || code > 999 { //nolint:mnd
+				return fmt.Errorf("wrong HTTP code [%d] (must be between 1 and 999)", code)
+			}
+
+			return nil
+		},
+	}
+}
+
+func newFormatFlag(usage string, def formats.Format) cli.Flag[string] {
+	all := make([]string, 0, len(formats.All()))
+
+	for _, f := range formats.All() {
+		all = append(all, f.String())
+	}
+
+	return cli.Flag[string]{
+		Names:   []string{"format"},
+		Usage:   usage + " (" + strings.Join(all, "/") + ")",
+		Default: def.String(),
+		Validator: func(_ *cli.Command, s string) error {
+			if _, ok := formats.FromString(s); !ok {
+				return fmt.Errorf("unknown format %q (available formats: %s)", s, strings.Join(all, ", "))
+			}
+
+			return nil
+		},
+	}
+}
+
+func newRenderHeadersFlag() cli.Flag[string] {
+	return cli.Flag[string]{
+		Names: []string{"header"},
+		Usage: "Add request headers, as if they were sent by the client or proxy " +
+			"(format: 'NAME=VALUE[||NAME=VALUE...]'; separate multiple entries with '||', a newline, or a tab)",
+		Validator: func(_ *cli.Command, s string) error {
+			_, err := parseRequestHeaders(s)
+
+			return err
+		},
+	}
+}
+
+func newRenderIncludeHeadersFlag() cli.Flag[bool] {
+	return cli.Flag[bool]{
+		Names: []string{"include-headers", "i"},
+		Usage: "Include the response status line and headers in the output",
+	}
+}
+
+// parseRequestHeaders parses the --header flag value into HTTP headers. Entries are separated by '||', newline, or
+// tab; each entry has the format 'NAME=VALUE', where only the first '=' is used as the split point.
+func parseRequestHeaders(s string) (http.Header, error) {
+	s = strings.ReplaceAll(s, "\n", "||")
+	s = strings.ReplaceAll(s, "\t", "||")
+
+	result := make(http.Header)
+
+	for entry := range strings.SplitSeq(s, "||") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+
+		name, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("wrong header entry %q: missing '='", entry)
+		}
+
+		if name = strings.TrimSpace(name); name == "" {
+			return nil, fmt.Errorf("missing name in header entry %q", entry)
+		}
+
+		result.Add(name, strings.TrimSpace(value))
+	}
+
+	return result, nil
+}
+
+// renderErrorPage renders the error page with the given code and format by sending a synthetic request to the
+// error page handler (see [error_page.RenderFor]), and writes the response to the output.
+func renderErrorPage(
+	ctx context.Context,
+	out io.Writer,
+	opt *errorPagesOptions,
+	code uint,
+	format formats.Format,
+	header http.Header,
+	includeHeaders bool,
+) error {
+	log, logErr := logger.New(logger.ErrorLevel, logger.ConsoleFormat)
+	if logErr != nil {
+		return logErr
+	}
+
+	templater, tErr := opt.templates()
+	if tErr != nil {
+		return fmt.Errorf("initialize templates: %w", tErr)
+	}
+
+	// the format is selected by the file extension in the URL path, since it has priority over the request headers
+	req, reqErr := http.NewRequestWithContext(ctx,
+		http.MethodGet, "/"+strconv.FormatUint(uint64(code), 10)+format.Extension(), http.NoBody,
+	)
+	if reqErr != nil {
+		return reqErr
+	}
+
+	for name, values := range header {
+		if strings.EqualFold(name, "Host") {
+			req.Host = values[0] // the Host header is stored separately by the net/http package
+
+			continue
+		}
+
+		req.Header[name] = values
+	}
+
+	// the response body is written as-is, so it makes no sense to compress it
+	req.Header.Del("Accept-Encoding")
+
+	var handler = opt.errorPageHandler(log, opt.httpCodes(), templater)
+
+	status, respHeader, body := error_page.RenderFor(handler, req, int(code)) //nolint:gosec // validated to be 1-999
+
+	if includeHeaders {
+		if _, err := fmt.Fprintf(out, "HTTP/1.1 %d %s\r\n", status, http.StatusText(status)); err != nil {
+			return err
+		}
+
+		if err := respHeader.Write(out); err != nil {
+			return err
+		}
+
+		if _, err := io.WriteString(out, "\r\n"); err != nil {
+			return err
+		}
+	}
+
+	_, err := body.WriteTo(out)
+
+	return err
+}
@@ -0,0 +1,187 @@
+// Package archive packs a directory into a tar.gz or zip archive. The archives are reproducible: the entries are
+// sorted, and their modification times and permissions are fixed, so the same content always results in the same
+// archive (and the archives of two releases can be compared byte by byte).
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Format is an enumeration of the supported archive formats.
+type Format byte
+
+const (
+	TarGz Format = iota + 1 // gzip-compressed tarball
+	Zip                     // zip archive
+)
+
+// String returns the name of the format, which is also the file extension (without the leading dot).
+func (f Format) String() string {
+	switch f {
+	case TarGz:
+		return "tar.gz"
+	case Zip:
+		return "zip"
+	}
+
+	return "unknown"
+}
+
+// extensions maps the file extensions (in lowercase) to the formats.
+var extensions = []struct { //nolint:gochecknoglobals
+	ext    string
+	format Format
+}{
+	{".tar.gz", TarGz},
+	{".tgz", TarGz},
+	{".zip", Zip},
+}
+
+// FromPath returns the archive format for the file path, based on its extension (case-insensitive).
+func FromPath(path string) (Format, bool) {
+	for _, e := range extensions {
+		if strings.HasSuffix(strings.ToLower(path), e.ext) {
+			return e.format, true
+		}
+	}
+
+	return Format(0), false
+}
+
+// TrimExtension returns the file path without the archive extension (e.g. "dist/pages" for "dist/pages.tar.gz").
+// Paths without the archive extension are returned as is.
+func TrimExtension(path string) string {
+	for _, e := range extensions {
+		if strings.HasSuffix(strings.ToLower(path), e.ext) {
+			return path[:len(path)-len(e.ext)]
+		}
+	}
+
+	return path
+}
+
+const (
+	fileMode = 0o644
+	dirMode  = 0o755
+)
+
+// modTime is the modification time of every entry (the earliest one, which the zip format supports).
+var modTime = time.Date(1980, time.January, 1, 0, 0, 0, 0, time.UTC) //nolint:gochecknoglobals
+
+// Write packs the contents of the root directory (the root itself is not included) into the archive of the format.
+func Write(w io.Writer, f Format, root string) error {
+	switch f {
+	case TarGz:
+		return writeTarGz(w, root)
+	case Zip:
+		return writeZip(w, root)
+	}
+
+	return fmt.Errorf("unsupported archive format: %d", f)
+}
+
+// walk calls the function for every directory and regular file under the root, in lexical order, with the slash-
+// separated path relative to the root.
+func walk(root string, fn func(name string, d fs.DirEntry, path string) error) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if path == root {
+			return nil
+		}
+
+		if !d.IsDir() && !d.Type().IsRegular() {
+			return fmt.Errorf("%s is not a regular file", path)
+		}
+
+		rel, relErr := filepath.Rel(root, path)
+		if relErr != nil {
+			return relErr
+		}
+
+		return fn(filepath.ToSlash(rel), d, path)
+	})
+}
+
+func writeTarGz(w io.Writer, root string) error {
+	gz, err := gzip.NewWriterLevel(w, gzip.BestCompression)
+	if err != nil {
+		return err
+	}
+
+	tw := tar.NewWriter(gz)
+
+	if wErr := walk(root, func(name string, d fs.DirEntry, path string) error {
+		if d.IsDir() {
+			return tw.WriteHeader(&tar.Header{Typeflag: tar.TypeDir, Name: name + "/", Mode: dirMode, ModTime: modTime})
+		}
+
+		content, rErr := os.ReadFile(path)
+		if rErr != nil {
+			return rErr
+		}
+
+		if hErr := tw.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeReg, Name: name, Size: int64(len(content)), Mode: fileMode, ModTime: modTime,
+		}); hErr != nil {
+			return hErr
+		}
+
+		_, cErr := tw.Write(content)
+
+		return cErr
+	}); wErr != nil {
+		return wErr
+	}
+
+	return errors.Join(tw.Close(), gz.Close())
+}
+
+func writeZip(w io.Writer, root string) error {
+	zw := zip.NewWriter(w)
+
+	if wErr := walk(root, func(name string, d fs.DirEntry, path string) error {
+		var header = zip.FileHeader{Name: name, Method: zip.Deflate, Modified: modTime}
+
+		if d.IsDir() {
+			header.Name, header.Method = name+"/", zip.Store
+			header.SetMode(fs.ModeDir | dirMode)
+
+			_, err := zw.CreateHeader(&header)
+
+			return err
+		}
+
+		header.SetMode(fileMode)
+
+		content, rErr := os.ReadFile(path)
+		if rErr != nil {
+			return rErr
+		}
+
+		fw, cErr := zw.CreateHeader(&header)
+		if cErr != nil {
+			return cErr
+		}
+
+		_, err := fw.Write(content)
+
+		return err
+	}); wErr != nil {
+		return wErr
+	}
+
+	return zw.Close()
+}
@@ -0,0 +1,130 @@
+package archive_test
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gh.tarampamp.am/error-pages/v4/internal/archive"
+	"gh.tarampamp.am/error-pages/v4/internal/testutil/assert"
+)
+
+func TestFromPath(t *testing.T) {
+	t.Parallel()
+
+	for give, want := range map[string]struct {
+		format  archive.Format
+		trimmed string
+	}{
+		"out/pages.tar.gz": {archive.TarGz, "out/pages"},
+		"PAGES.TGZ":        {archive.TarGz, "PAGES"},
+		"/tmp/pages.zip":   {archive.Zip, "/tmp/pages"},
+		"./out":            {0, "./out"},
+		"pages.gz":         {0, "pages.gz"},
+	} {
+		got, ok := archive.FromPath(give)
+
+		assert.Equal(t, want.format != 0, ok)
+		assert.Equal(t, want.format, got)
+		assert.Equal(t, want.trimmed, archive.TrimExtension(give))
+	}
+}
+
+// testTree creates the directory with some files and returns its path.
+func testTree(t *testing.T) string {
+	t.Helper()
+
+	var root = t.TempDir()
+
+	assert.NoError(t, os.MkdirAll(filepath.Join(root, "ghost", "de"), 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "index.html"), []byte("<h1>index</h1>"), 0o600))
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "ghost", "404.html"), []byte("not found"), 0o600))
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "ghost", "de", "404.html"), []byte("nicht gefunden"), 0o600))
+
+	return root
+}
+
+var wantEntries = map[string]string{ //nolint:gochecknoglobals
+	"ghost/":            "",
+	"ghost/404.html":    "not found",
+	"ghost/de/":         "",
+	"ghost/de/404.html": "nicht gefunden",
+	"index.html":        "<h1>index</h1>",
+}
+
+func TestWrite_TarGz(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	assert.NoError(t, archive.Write(&buf, archive.TarGz, testTree(t)))
+
+	gz, err := gzip.NewReader(&buf)
+	assert.NoError(t, err)
+
+	var (
+		tr  = tar.NewReader(gz)
+		got = make(map[string]string)
+	)
+
+	for {
+		h, nErr := tr.Next()
+		if nErr == io.EOF {
+			break
+		}
+
+		assert.NoError(t, nErr)
+
+		content, rErr := io.ReadAll(tr)
+		assert.NoError(t, rErr)
+
+		got[h.Name] = string(content)
+	}
+
+	assert.DeepEqual(t, wantEntries, got)
+}
+
+func TestWrite_Zip(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	assert.NoError(t, archive.Write(&buf, archive.Zip, testTree(t)))
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.NoError(t, err)
+
+	var got = make(map[string]string)
+
+	for _, f := range zr.File {
+		r, oErr := f.Open()
+		assert.NoError(t, oErr)
+
+		content, rErr := io.ReadAll(r)
+		assert.NoError(t, rErr)
+
+		got[f.Name] = string(content)
+	}
+
+	assert.DeepEqual(t, wantEntries, got)
+}
+
+func TestWrite_Reproducible(t *testing.T) {
+	t.Parallel()
+
+	for _, f := range []archive.Format{archive.TarGz, archive.Zip} {
+		var first, second bytes.Buffer
+
+		assert.NoError(t, archive.Write(&first, f, testTree(t)))
+		assert.NoError(t, archive.Write(&second, f, testTree(t))) // another directory, with other timestamps
+
+		assert.True(t, bytes.Equal(first.Bytes(), second.Bytes()))
+	}
+
+	assert.ErrorContains(t, archive.Write(io.Discard, archive.Format(42), t.TempDir()), "unsupported archive format")
+}
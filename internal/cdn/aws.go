@@ -0,0 +1,189 @@
+package cdn
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+
+	"gh.tarampamp.am/error-pages/v4/internal/formats"
+	"gh.tarampamp.am/error-pages/v4/internal/minify"
+)
+
+// ALBMaxBodySize is the limit of the body of the ALB fixed-response action, in bytes.
+const ALBMaxBodySize = 1024
+
+// ALBContentType returns the content type of the ALB fixed-response action for the format (the action supports
+// only a few of them, and XML is not one of them).
+func ALBContentType(f formats.Format) (string, bool) {
+	switch f {
+	case formats.PlainTextFormat:
+		return "text/plain", true
+	case formats.HTMLFormat:
+		return "text/html", true
+	case formats.JSONFormat:
+		return "application/json", true
+	}
+
+	return "", false
+}
+
+// IsALBStatusCode reports whether the code may be returned by the ALB fixed-response action (2XX, 4XX, or 5XX).
+func IsALBStatusCode(code uint16) bool {
+	return (code >= 200 && code < 300) || (code >= 400 && code < 600) //nolint:mnd
+}
+
+// ALBResponse is the fixed response of the ALB listener rule.
+type ALBResponse struct {
+	StatusCode  uint16
+	ContentType string // see [ALBContentType]
+	Body        []byte
+}
+
+// ALBFixedResponses returns the JSON object with the actions of the ALB listener rules (as they are accepted by the
+// "aws elbv2 create-rule --actions" and "aws elbv2 modify-listener --default-actions"), keyed by the status code.
+func ALBFixedResponses(responses []ALBResponse) ([]byte, error) {
+	type (
+		config struct {
+			StatusCode  string `json:"StatusCode"`
+			ContentType string `json:"ContentType"`
+			MessageBody string `json:"MessageBody"`
+		}
+		action struct {
+			Type                string `json:"Type"`
+			FixedResponseConfig config `json:"FixedResponseConfig"`
+		}
+	)
+
+	var result = make(map[string][]action, len(responses))
+
+	for _, r := range responses {
+		if len(r.Body) > ALBMaxBodySize {
+			return nil, fmt.Errorf("the body of the %d response is %d bytes, which exceeds the limit of %d bytes",
+				r.StatusCode, len(r.Body), ALBMaxBodySize,
+			)
+		}
+
+		code := strconv.FormatUint(uint64(r.StatusCode), 10)
+
+		result[code] = []action{{
+			Type:                "fixed-response",
+			FixedResponseConfig: config{StatusCode: code, ContentType: r.ContentType, MessageBody: string(r.Body)},
+		}}
+	}
+
+	return marshalJSON(result)
+}
+
+// CloudFrontErrorCodes returns the codes the CloudFront custom error responses can be configured for.
+func CloudFrontErrorCodes() []uint16 {
+	return []uint16{400, 403, 404, 405, 414, 416, 500, 501, 502, 503, 504} //nolint:mnd
+}
+
+// CloudFrontErrorPage is the page of the CloudFront custom error response.
+type CloudFrontErrorPage struct {
+	Code uint16
+	Path string // the path of the page in the origin, e.g. "/ghost/404.html"
+}
+
+// CloudFrontErrorCachingMinTTL is the time (in seconds) CloudFront caches the error response for (the CloudFront
+// default one).
+const CloudFrontErrorCachingMinTTL = 10
+
+// CloudFrontErrorResponses returns the JSON object with the CustomErrorResponses of the CloudFront distribution
+// config for the pages (the codes CloudFront does not support are skipped).
+func CloudFrontErrorResponses(pages []CloudFrontErrorPage) ([]byte, error) {
+	type item struct {
+		ErrorCode          uint16 `json:"ErrorCode"`
+		ResponsePagePath   string `json:"ResponsePagePath"`
+		ResponseCode       string `json:"ResponseCode"`
+		ErrorCachingMinTTL int    `json:"ErrorCachingMinTTL"`
+	}
+
+	var items = make([]item, 0, len(pages))
+
+	for _, p := range pages {
+		if !slices.Contains(CloudFrontErrorCodes(), p.Code) {
+			continue
+		}
+
+		items = append(items, item{
+			ErrorCode:          p.Code,
+			ResponsePagePath:   p.Path,
+			ResponseCode:       strconv.FormatUint(uint64(p.Code), 10),
+			ErrorCachingMinTTL: CloudFrontErrorCachingMinTTL,
+		})
+	}
+
+	type responses struct {
+		Quantity int    `json:"Quantity"`
+		Items    []item `json:"Items"`
+	}
+
+	return marshalJSON(struct {
+		CustomErrorResponses responses `json:"CustomErrorResponses"`
+	}{responses{Quantity: len(items), Items: items}})
+}
+
+// marshalJSON returns the indented JSON (the HTML characters are not escaped, so the bodies remain readable).
+func marshalJSON(v any) ([]byte, error) {
+	var (
+		buf bytes.Buffer
+		enc = json.NewEncoder(&buf)
+	)
+
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// compactSteps are the parts of the page removed, one by one, until the page fits the limit (see [CompactHTML]).
+var compactSteps = []*regexp.Regexp{ //nolint:gochecknoglobals
+	regexp.MustCompile(`(?is)<script\b.*?</script\s*>`),                                  // scripts
+	regexp.MustCompile(`(?is)<meta\b[^>]*\b(?:name|property)\s*=[^>]*>|<link\b[^>]*>`),   // metadata
+	regexp.MustCompile(`(?is)<style\b.*?</style\s*>|<svg\b.*?</svg\s*>|\sstyle="[^"]*"`), // styles and images
+}
+
+// viewportMeta matches the viewport meta tag, which is kept by [CompactHTML].
+var viewportMeta = regexp.MustCompile(`(?i)^<meta\b[^>]*\bname\s*=\s*["']?viewport\b`) //nolint:gochecknoglobals
+
+// CompactHTML minifies the page and, if it still exceeds the limit, removes the scripts, then the metadata (the meta
+// tags, except the charset and viewport ones, and the links), and then the styles and inline images, until the page
+// fits the limit. It returns an error if the page does not fit even then.
+func CompactHTML(page []byte, limit int) ([]byte, error) {
+	page, err := minify.HTML(page)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, re := range compactSteps {
+		if len(page) <= limit {
+			return page, nil
+		}
+
+		if page, err = minify.HTML(re.ReplaceAllFunc(page, func(m []byte) []byte {
+			if viewportMeta.Match(m) {
+				return m
+			}
+
+			return nil
+		})); err != nil { // collapse the whitespace around the removed elements
+			return nil, err
+		}
+	}
+
+	if len(page) > limit {
+		return nil, fmt.Errorf("the page is %d bytes even without the scripts, metadata, styles and images, "+
+			"which exceeds the limit of %d bytes", len(page), limit,
+		)
+	}
+
+	return page, nil
+}
@@ -0,0 +1,144 @@
+package cdn_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"gh.tarampamp.am/error-pages/v4/internal/cdn"
+	"gh.tarampamp.am/error-pages/v4/internal/formats"
+	"gh.tarampamp.am/error-pages/v4/internal/testutil/assert"
+)
+
+func TestALBContentType(t *testing.T) {
+	t.Parallel()
+
+	for f, want := range map[formats.Format]string{
+		formats.PlainTextFormat: "text/plain",
+		formats.HTMLFormat:      "text/html",
+		formats.JSONFormat:      "application/json",
+		formats.XMLFormat:       "",
+	} {
+		got, ok := cdn.ALBContentType(f)
+
+		assert.Equal(t, want != "", ok)
+		assert.Equal(t, want, got)
+	}
+}
+
+func TestALBFixedResponses(t *testing.T) {
+	t.Parallel()
+
+	content, err := cdn.ALBFixedResponses([]cdn.ALBResponse{
+		{StatusCode: 404, ContentType: "text/html", Body: []byte("<h1>Not Found</h1>")},
+		{StatusCode: 503, ContentType: "text/plain", Body: []byte("Service Unavailable")},
+	})
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), `"MessageBody": "<h1>Not Found</h1>"`) // not escaped
+
+	var got map[string][]struct {
+		Type                string
+		FixedResponseConfig struct{ StatusCode, ContentType, MessageBody string }
+	}
+
+	assert.NoError(t, json.Unmarshal(content, &got))
+	assert.Equal(t, 2, len(got))
+	assert.Equal(t, "fixed-response", got["503"][0].Type)
+	assert.Equal(t, "503", got["503"][0].FixedResponseConfig.StatusCode)
+	assert.Equal(t, "text/plain", got["503"][0].FixedResponseConfig.ContentType)
+	assert.Equal(t, "Service Unavailable", got["503"][0].FixedResponseConfig.MessageBody)
+
+	_, err = cdn.ALBFixedResponses([]cdn.ALBResponse{
+		{StatusCode: 500, ContentType: "text/plain", Body: []byte(strings.Repeat("x", cdn.ALBMaxBodySize+1))},
+	})
+	assert.ErrorContains(t, err, "the body of the 500 response is 1025 bytes")
+}
+
+func TestCloudFrontErrorResponses(t *testing.T) {
+	t.Parallel()
+
+	content, err := cdn.CloudFrontErrorResponses([]cdn.CloudFrontErrorPage{
+		{Code: 404, Path: "/ghost/404.html"},
+		{Code: 418, Path: "/ghost/418.html"}, // not supported by CloudFront
+		{Code: 503, Path: "/ghost/503.html"},
+	})
+	assert.NoError(t, err)
+
+	var got struct {
+		CustomErrorResponses struct {
+			Quantity int
+			Items    []struct {
+				ErrorCode          uint16
+				ResponsePagePath   string
+				ResponseCode       string
+				ErrorCachingMinTTL int
+			}
+		}
+	}
+
+	assert.NoError(t, json.Unmarshal(content, &got))
+	assert.Equal(t, 2, got.CustomErrorResponses.Quantity)
+	assert.Equal(t, 2, len(got.CustomErrorResponses.Items))
+	assert.Equal(t, uint16(503), got.CustomErrorResponses.Items[1].ErrorCode)
+	assert.Equal(t, "/ghost/503.html", got.CustomErrorResponses.Items[1].ResponsePagePath)
+	assert.Equal(t, "503", got.CustomErrorResponses.Items[1].ResponseCode)
+}
+
+func TestCompactHTML(t *testing.T) {
+	t.Parallel()
+
+	const page = `<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <meta name="viewport" content="width=device-width">
+  <meta name="description" content="The server can not find the requested page">
+  <link rel="icon" href="data:,">
+  <style>h1 { color: red; }</style>
+</head>
+<body>
+  <h1 style="font-size: 2em">404</h1>
+  <svg><path d="M0 0"/></svg>
+  <script>console.log("hello, world")</script>
+</body>
+</html>`
+
+	for name, tc := range map[string]struct {
+		giveLimit int
+		want      string
+		wantErr   string
+	}{
+		"fits after the minification": {
+			giveLimit: 1024,
+			want: `<!DOCTYPE html> <html> <head> <meta charset="utf-8"> <meta name="viewport" ` +
+				`content="width=device-width"> <meta name="description" content="The server can not find the ` +
+				`requested page"> <link rel="icon" href="data:,"> <style>h1{color: red}</style> </head> <body> ` +
+				`<h1 style="font-size: 2em">404</h1> <svg><path d="M0 0"/></svg> ` +
+				`<script>console.log("hello, world")</script> </body> </html>`,
+		},
+		"without the styles": {
+			giveLimit: 150,
+			want: `<!DOCTYPE html> <html> <head> <meta charset="utf-8"> <meta name="viewport" ` +
+				`content="width=device-width"> </head> <body> <h1>404</h1> </body> </html>`,
+		},
+		"does not fit": {
+			giveLimit: 50,
+			wantErr:   "the page is 148 bytes even without the scripts, metadata, styles and images",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := cdn.CompactHTML([]byte(page), tc.giveLimit)
+
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, string(got))
+		})
+	}
+}
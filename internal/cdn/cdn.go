@@ -0,0 +1,115 @@
+// Package cdn renders the error pages for the CDNs and cloud load balancers, which serve the custom error pages
+// themselves and have their own requirements for them (e.g. the tokens the CDN replaces, or the size limits).
+package cdn
+
+import (
+	"bytes"
+	"errors"
+	"html"
+)
+
+// Target is the CDN (or cloud load balancer) to render the pages for.
+type Target string
+
+const (
+	Cloudflare Target = "cloudflare"
+	AWS        Target = "aws" // CloudFront and Application Load Balancer
+)
+
+// Targets returns all supported targets.
+func Targets() []Target { return []Target{Cloudflare, AWS} }
+
+// Placeholder is the description to render the page with, to be replaced with the token (see [InjectToken]).
+const Placeholder = "__ERROR_PAGES_CDN_PLACEHOLDER__"
+
+// InjectToken replaces the placeholder, rendered as the page description, with the token: the first occurrence in
+// the text of the page (not in the tag attributes or scripts) gets the token, others get the fallback text (in the
+// attributes, e.g. <meta name="description">) or nothing (in the scripts). If the description is not shown in the
+// text of the page, the token is placed at the end of the body.
+func InjectToken(page []byte, token, fallback string) ([]byte, error) {
+	var (
+		out      = make([]byte, 0, len(page)+len(token))
+		injected bool
+		rest     = page
+	)
+
+	for {
+		idx := bytes.Index(rest, []byte(Placeholder))
+		if idx < 0 {
+			out = append(out, rest...)
+
+			break
+		}
+
+		var before = rest[:idx]
+
+		switch {
+		case isInsideScript(out, before): // checked first, since the scripts may contain the "<" characters
+			out = append(out, before...)
+		case isInsideTag(out, before):
+			out = append(append(out, before...), html.EscapeString(fallback)...)
+		case !injected:
+			out, injected = append(append(out, dropL10nMarker(before)...), token...), true
+		default:
+			out = append(append(out, before...), html.EscapeString(fallback)...)
+		}
+
+		rest = rest[idx+len(Placeholder):]
+	}
+
+	if injected {
+		return out, nil
+	}
+
+	end := bytes.LastIndex(bytes.ToLower(out), []byte("</body>"))
+	if end < 0 {
+		return nil, errors.New("the page has no body to place the token into")
+	}
+
+	return append(out[:end:end], append([]byte("<div>"+token+"</div>"), out[end:]...)...), nil
+}
+
+// isInsideTag reports whether the end of the page (the written part and the one before the placeholder) is inside
+// a tag, e.g. in the value of the attribute.
+func isInsideTag(written, before []byte) bool {
+	lt, gt := lastIndex(written, before, '<'), lastIndex(written, before, '>')
+
+	return lt > gt
+}
+
+// isInsideScript reports whether the end of the page is inside the <script> element.
+func isInsideScript(written, before []byte) bool {
+	var page = bytes.ToLower(append(append([]byte{}, written...), before...))
+
+	return bytes.LastIndex(page, []byte("<script")) > bytes.LastIndex(page, []byte("</script"))
+}
+
+// lastIndex returns the index of the last occurrence of the byte in the concatenation of the slices, or -1.
+func lastIndex(a, b []byte, c byte) int {
+	if i := bytes.LastIndexByte(b, c); i >= 0 {
+		return len(a) + i
+	}
+
+	return bytes.LastIndexByte(a, c)
+}
+
+// dropL10nMarker removes the data-l10n attribute from the element the token is placed into (the page ends with its
+// opening tag), so that the localization script never touches the content Cloudflare puts there.
+func dropL10nMarker(before []byte) []byte {
+	lt := bytes.LastIndexByte(before, '<')
+	if lt < 0 {
+		return before
+	}
+
+	var (
+		tag    = before[lt:]
+		marker = []byte(" data-l10n")
+		i      = bytes.Index(tag, marker)
+	)
+
+	if i < 0 || i+len(marker) >= len(tag) || (tag[i+len(marker)] != '>' && tag[i+len(marker)] != ' ') {
+		return before // no marker, or the one with a value
+	}
+
+	return append(append(append([]byte{}, before[:lt]...), tag[:i]...), tag[i+len(marker):]...)
+}
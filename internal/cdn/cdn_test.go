@@ -0,0 +1,56 @@
+package cdn_test
+
+import (
+	"testing"
+
+	"gh.tarampamp.am/error-pages/v4/internal/cdn"
+	"gh.tarampamp.am/error-pages/v4/internal/testutil/assert"
+)
+
+func TestInjectToken(t *testing.T) {
+	t.Parallel()
+
+	const p = cdn.Placeholder
+
+	for name, tc := range map[string]struct {
+		give, want string
+		wantErr    string
+	}{
+		"text": {
+			give: `<html><body><p class="d">` + p + `</p></body></html>`,
+			want: `<html><body><p class="d">TOKEN</p></body></html>`,
+		},
+		"attributes, scripts and the second occurrence": {
+			give: `<meta content="` + p + `"><body><p>` + p + `</p><script>if (a < b) x = "` + p + `"</script>` +
+				`<i>` + p + `</i></body>`,
+			want: `<meta content="A &amp; B"><body><p>TOKEN</p><script>if (a < b) x = ""</script><i>A &amp; B</i></body>`,
+		},
+		"localizable element": {
+			give: `<body><p class="d" data-l10n>` + p + `</p><span data-l10n="x">` + p + `</span></body>`,
+			want: `<body><p class="d">TOKEN</p><span data-l10n="x">A &amp; B</span></body>`,
+		},
+		"no text occurrence": {
+			give: `<head><meta content="` + p + `"></head><BODY><h1>Oops</h1></BODY>`,
+			want: `<head><meta content="A &amp; B"></head><BODY><h1>Oops</h1><div>TOKEN</div></BODY>`,
+		},
+		"no body": {
+			give:    `<meta content="` + p + `">`,
+			wantErr: "the page has no body to place the token into",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := cdn.InjectToken([]byte(tc.give), "TOKEN", "A & B")
+
+			if tc.wantErr != "" {
+				assert.ErrorEqual(t, err, tc.wantErr)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, string(got))
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package cdn
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// CloudflarePage is a custom error page of Cloudflare (see the "Custom Errors" section of the dashboard). Cloudflare
+// replaces the token on the page with the details of the error, so the page must contain it.
+type CloudflarePage struct {
+	Name       string // the file name, without the extension
+	Title      string // the page type, as it is named in the dashboard
+	StatusCode uint16 // the code the page is rendered with
+	Message    string // the message the page is rendered with
+	Token      string
+}
+
+// The Cloudflare tokens, replaced with the details of the error (or the challenge widget).
+const (
+	Cloudflare500sToken   = "::CLOUDFLARE_ERROR_500S_BOX::"
+	Cloudflare1000sToken  = "::CLOUDFLARE_ERROR_1000S_BOX::"
+	CloudflareWidgetToken = "::CF_WIDGET_BOX::"
+)
+
+// The size limits of the Cloudflare custom error page, in bytes.
+const (
+	CloudflareMinSize = 100
+	CloudflareMaxSize = 1_430_000 // 1.43 MB
+)
+
+// CloudflarePages returns the custom error pages Cloudflare supports.
+func CloudflarePages() []CloudflarePage {
+	return []CloudflarePage{
+		{"500s", "500 class errors", 500, "Server Error", Cloudflare500sToken},                  //nolint:mnd
+		{"1000s", "1000 class errors", 530, "Site Error", Cloudflare1000sToken},                 //nolint:mnd
+		{"waf-block", "WAF block", 403, "Access Denied", Cloudflare1000sToken},                  //nolint:mnd
+		{"rate-limit", "Rate limiting block", 429, "Too Many Requests", Cloudflare1000sToken},   //nolint:mnd
+		{"challenge", "Managed challenge", 403, "Checking Your Browser", CloudflareWidgetToken}, //nolint:mnd
+	}
+}
+
+// ValidateCloudflare checks the rendered page against the Cloudflare requirements.
+func ValidateCloudflare(p CloudflarePage, page []byte) error {
+	if n := bytes.Count(page, []byte(p.Token)); n != 1 {
+		return fmt.Errorf("the page must contain the %s token exactly once, but it is found %d times", p.Token, n)
+	}
+
+	if len(page) < CloudflareMinSize {
+		return fmt.Errorf("the page is %d bytes, but at least %d bytes are required", len(page), CloudflareMinSize)
+	}
+
+	if len(page) > CloudflareMaxSize {
+		return fmt.Errorf("the page is %d bytes, which exceeds the limit of %d bytes", len(page), CloudflareMaxSize)
+	}
+
+	return nil
+}
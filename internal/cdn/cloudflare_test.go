@@ -0,0 +1,44 @@
+package cdn_test
+
+import (
+	"strings"
+	"testing"
+
+	"gh.tarampamp.am/error-pages/v4/internal/cdn"
+	"gh.tarampamp.am/error-pages/v4/internal/testutil/assert"
+)
+
+func TestCloudflarePages(t *testing.T) {
+	t.Parallel()
+
+	var seen = make(map[string]struct{})
+
+	for _, p := range cdn.CloudflarePages() {
+		_, dup := seen[p.Name]
+		assert.False(t, dup)
+
+		seen[p.Name] = struct{}{}
+
+		assert.True(t, p.StatusCode >= 400 && p.StatusCode < 600)
+		assert.True(t, strings.HasPrefix(p.Token, "::") && strings.HasSuffix(p.Token, "::"))
+	}
+}
+
+func TestValidateCloudflare(t *testing.T) {
+	t.Parallel()
+
+	var (
+		page    = cdn.CloudflarePages()[0]
+		padding = strings.Repeat("x", cdn.CloudflareMinSize)
+	)
+
+	assert.NoError(t, cdn.ValidateCloudflare(page, []byte(padding+page.Token)))
+
+	assert.ErrorContains(t, cdn.ValidateCloudflare(page, []byte(padding)), "found 0 times")
+	assert.ErrorContains(t, cdn.ValidateCloudflare(page, []byte(padding+page.Token+page.Token)), "found 2 times")
+	assert.ErrorContains(t, cdn.ValidateCloudflare(page, []byte(page.Token)), "at least 100 bytes are required")
+	assert.ErrorContains(t,
+		cdn.ValidateCloudflare(page, []byte(strings.Repeat("x", cdn.CloudflareMaxSize)+page.Token)),
+		"exceeds the limit",
+	)
+}
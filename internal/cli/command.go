@@ -12,19 +12,21 @@ import (
 	"unicode/utf8"
 )
 
-// Command represents a CLI command with flags, description, usage, and an action function.
+// Command represents a CLI command with flags, description, usage, subcommands, and an action function.
 type Command struct {
-	Name        string    // Name of the command.
-	Description string    // Brief description of the command.
-	Usage       string    // Usage example of the command.
-	Version     string    // Version of the command.
-	Flags       []Flagger // Collection of flags associated with the command.
-	Output      io.Writer // Output writer, defaults to os.Stdout if not set.
+	Name        string     // Name of the command.
+	Description string     // Brief description of the command.
+	Usage       string     // Usage example of the command.
+	Version     string     // Version of the command.
+	Flags       []Flagger  // Collection of flags associated with the command.
+	Commands    []*Command // Subcommands, selected by the first positional argument (e.g. `app sub --flag`).
+	Output      io.Writer  // Output writer, defaults to os.Stdout if not set.
 
 	Action func(_ context.Context, _ *Command, args []string) error // Action function executed when the command runs.
 
 	initOnce              sync.Once // to ensure initialization is done only once
 	showHelp, showVersion bool      // built-in flags for displaying help and version
+	parent                *Command  // parent command (set for subcommands only), used to build the full name
 }
 
 func (c *Command) init() {
@@ -33,9 +35,43 @@ func (c *Command) init() {
 			&Flag[bool]{Names: []string{"help", "h"}, Usage: "Show help", Value: &c.showHelp},
 			&Flag[bool]{Names: []string{"version", "v"}, Usage: "Print the version", Value: &c.showVersion},
 		)
+
+		for _, sub := range c.Commands {
+			sub.parent = c
+
+			if sub.Version == "" {
+				sub.Version = c.Version // subcommands inherit the version of the parent
+			}
+		}
 	})
 }
 
+// fullName returns the command name prefixed with the names of all its parents (e.g. "app sub").
+func (c *Command) fullName() string {
+	if c.parent == nil {
+		return c.Name
+	}
+
+	if parentName := c.parent.fullName(); parentName != "" {
+		return parentName + " " + c.Name
+	}
+
+	return c.Name
+}
+
+// Subcommand returns the subcommand with the given name, or nil if there is no such subcommand.
+func (c *Command) Subcommand(name string) *Command {
+	c.init()
+
+	for _, sub := range c.Commands {
+		if sub != nil && sub.Name == name {
+			return sub
+		}
+	}
+
+	return nil
+}
+
 // Help generates and returns a formatted help message for the command.
 func (c *Command) Help() string {
 	c.init()
@@ -61,11 +97,13 @@ func (c *Command) Help() string {
 
 		b.WriteString("Usage:\n")
 		b.WriteString(offset)
-		b.WriteString(c.Name)
+		b.WriteString(c.fullName())
 
 		if c.Usage != "" {
 			b.WriteRune(' ')
 			b.WriteString(c.Usage)
+		} else if len(c.Commands) > 0 {
+			b.WriteString(" [command]")
 		}
 	}
 
@@ -80,6 +118,40 @@ func (c *Command) Help() string {
 		b.WriteString(c.Version)
 	}
 
+	// append subcommands if any exist
+	if len(c.Commands) > 0 {
+		if b.Len() > 0 {
+			b.WriteString("\n\n")
+		}
+
+		b.WriteString("Commands:\n")
+
+		var longest int // stores the length of the longest command name for alignment
+
+		for _, sub := range c.Commands {
+			if l := utf8.RuneCountInString(sub.Name); l > longest {
+				longest = l
+			}
+		}
+
+		for i, sub := range c.Commands {
+			if i > 0 {
+				b.WriteRune('\n')
+			}
+
+			b.WriteString(offset)
+			b.WriteString(sub.Name)
+
+			// align command descriptions
+			for j := utf8.RuneCountInString(sub.Name); j < longest; j++ {
+				b.WriteRune(' ')
+			}
+
+			b.WriteString("  ")
+			b.WriteString(sub.Description)
+		}
+	}
+
 	// append flags if any exist
 	if len(c.Flags) > 0 {
 		if b.Len() > 0 {
@@ -134,17 +206,28 @@ func (c *Command) Run(ctx context.Context, args []string) error { //nolint:conte
 
 	c.init()
 
+	// set default output if not defined
+	if c.Output == nil {
+		c.Output = os.Stdout
+	}
+
+	// delegate to the subcommand if the first argument is its name (flags of the parent are not parsed in this case)
+	if len(args) > 0 {
+		if sub := c.Subcommand(args[0]); sub != nil {
+			if sub.Output == nil {
+				sub.Output = c.Output
+			}
+
+			return sub.Run(ctx, args[1:])
+		}
+	}
+
 	// create a new flag set for parsing command-line flags
 	var set = flag.NewFlagSet(c.Name, flag.ContinueOnError)
 
 	// suppress output from the standard flag library to avoid unnecessary messages
 	set.SetOutput(io.Discard)
 
-	// set default output if not defined
-	if c.Output == nil {
-		c.Output = os.Stdout
-	}
-
 	// register flags in the flag set
 	for _, f := range c.Flags {
 		f.Apply(set)
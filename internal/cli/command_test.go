@@ -74,6 +74,23 @@ Options:
    --help, -h                 Show help
    --version, -v              Print the version`,
 		},
+		"with subcommands": {
+			giveCommand: &cli.Command{
+				Name: "some-name",
+				Commands: []*cli.Command{
+					{Name: "foo", Description: "Foo description"},
+					{Name: "foobar", Description: "Foobar description"},
+				},
+			},
+			wantHelp: `Usage:
+   some-name [command]
+
+Commands:
+   foo     Foo description
+   foobar  Foobar description
+
+` + builtInFlagsHelp,
+		},
 	} {
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
@@ -297,4 +314,53 @@ func TestCommand_Run(t *testing.T) {
 		assert.NoError(t, c.Run(ctx, nil))
 		assert.Equal(t, true, executed)
 	})
+
+	t.Run("subcommand", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			out          strings.Builder
+			value        string
+			gotArgs      []string
+			rootExecuted bool
+			subExecuted  bool
+			subcommand   = &cli.Command{
+				Name: "sub",
+				Flags: []cli.Flagger{
+					&cli.Flag[string]{Names: []string{"foo"}, Value: &value},
+				},
+				Action: func(_ context.Context, _ *cli.Command, args []string) error {
+					subExecuted, gotArgs = true, args
+
+					return nil
+				},
+			}
+
+			c = &cli.Command{
+				Name:     "some-name",
+				Version:  "some-version",
+				Output:   &out,
+				Commands: []*cli.Command{subcommand},
+				Action:   func(context.Context, *cli.Command, []string) error { rootExecuted = true; return nil },
+			}
+		)
+
+		assert.NoError(t, c.Run(ctx, []string{"sub", "--foo", "bar", "baz"}))
+		assert.Equal(t, true, subExecuted)
+		assert.Equal(t, false, rootExecuted)
+		assert.Equal(t, "bar", value)
+		assert.DeepEqual(t, []string{"baz"}, gotArgs)
+
+		// the subcommand inherits the output and version of the parent
+		assert.NoError(t, c.Run(ctx, []string{"sub", "--help"}))
+		assert.Equal(t, subcommand.Help()+"\n", out.String())
+		assert.Contains(t, out.String(), "some-name sub", "some-version")
+
+		subExecuted = false
+
+		// unknown positional arguments are passed to the root action
+		assert.NoError(t, c.Run(ctx, []string{"unknown"}))
+		assert.Equal(t, true, rootExecuted)
+		assert.Equal(t, false, subExecuted)
+	})
 }
@@ -2,6 +2,7 @@ package shared
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
 	"gh.tarampamp.am/error-pages/v4/internal/cli"
@@ -19,13 +20,34 @@ func NewDisableBuiltInCodesFlag() cli.Flag[bool] {
 	}
 }
 
+// NewBuiltInCodesFlag returns a flag that selects the set of the built-in HTTP status code descriptions.
+func NewBuiltInCodesFlag() cli.Flag[string] {
+	return cli.Flag[string]{
+		Names: []string{"builtin-codes"},
+		Usage: "The set of the built-in HTTP status codes (" + strings.Join(codes.BuiltInSets(), "/") + "; the " +
+			"extended one adds the non-standard codes of proxies and vendors, like nginx 499 or Cloudflare 52x)",
+		EnvVars: []string{"BUILTIN_CODES"},
+		Default: codes.BuiltInStandard,
+		Validator: func(_ *cli.Command, set string) error {
+			if slices.Contains(codes.BuiltInSets(), set) {
+				return nil
+			}
+
+			return fmt.Errorf("unknown set of built-in codes %q (available sets: %s)",
+				set, strings.Join(codes.BuiltInSets(), ", "),
+			)
+		},
+	}
+}
+
 // NewAddHTTPCodesFlag returns a flag for adding or overriding HTTP status codes and their descriptions.
 func NewAddHTTPCodesFlag() cli.Flag[string] {
 	return cli.Flag[string]{
 		Names: []string{"add-code"},
 		Usage: "Add or override HTTP status codes and their messages/descriptions " +
 			"(format: 'CODE=MESSAGE[|DESCRIPTION][||CODE=MESSAGE[|DESCRIPTION]...]'; CODE may contain wildcards like " +
-			"'4**'; separate multiple entries with '||', a newline, or a tab)",
+			"'4**' or be a range like '400-451', and '!CODE' excludes the codes; separate multiple entries with '||', " +
+			"a newline, or a tab)",
 		EnvVars: []string{"ADD_CODE"},
 		Validator: func(_ *cli.Command, s string) error {
 			_, err := ParseAddHTTPCodes(s)
@@ -35,9 +57,28 @@ func NewAddHTTPCodesFlag() cli.Flag[string] {
 	}
 }
 
+// NewCodesFileFlag returns a flag for loading HTTP status codes and their descriptions from a file.
+func NewCodesFileFlag() cli.Flag[string] {
+	return cli.Flag[string]{
+		Names: []string{"codes-file"},
+		Usage: "Path to a JSON or YAML file with HTTP status codes, their messages/descriptions, links and " +
+			"translations, which are merged with (or replace) the built-in ones (--add-code entries take precedence)",
+		EnvVars: []string{"CODES_FILE"},
+		Validator: func(_ *cli.Command, path string) error {
+			if path == "" {
+				return nil
+			}
+
+			_, err := codes.LoadFile(path)
+
+			return err
+		},
+	}
+}
+
 // ParseAddHTTPCodes parses the --add-code flag value into a map of HTTP codes to their descriptions.
 // Entries are separated by '||', newline, or tab; each entry has the format 'CODE=MESSAGE' or
-// 'CODE=MESSAGE|DESCRIPTION'. Returns an error if any entry is malformed.
+// 'CODE=MESSAGE|DESCRIPTION', or '!CODE' for the exclusions. Returns an error if any entry is malformed.
 // Should be used together with [newAddHTTPCodesFlag].
 func ParseAddHTTPCodes(s string) (map[string]codes.Description, error) {
 	s = strings.ReplaceAll(s, "\n", "||")
@@ -52,6 +93,20 @@ func ParseAddHTTPCodes(s string) (map[string]codes.Description, error) {
 		}
 
 		before, after, ok := strings.Cut(entry, "=")
+		if code := strings.TrimSpace(before); strings.HasPrefix(code, "!") { // exclusion, e.g. '!404'
+			if err := codes.ValidateCode(code); err != nil {
+				return nil, err
+			}
+
+			if strings.TrimSpace(after) != "" {
+				return nil, fmt.Errorf("the exclusion %q must not have a message", code)
+			}
+
+			result[code] = codes.Description{}
+
+			continue
+		}
+
 		if !ok {
 			return nil, fmt.Errorf("wrong HTTP code entry %q: missing '='", entry)
 		}
@@ -61,14 +116,8 @@ func ParseAddHTTPCodes(s string) (map[string]codes.Description, error) {
 			return nil, fmt.Errorf("missing HTTP code in entry %q", entry)
 		}
 
-		if len(code) != 3 { //nolint:mnd
-			return nil, fmt.Errorf("wrong HTTP code %q: must be 3 characters long", code)
-		}
-
-		for i := range len(code) {
-			if b := code[i]; (b < '0' || b > '9') && b != '*' && b != 'x' && b != 'X' {
-				return nil, fmt.Errorf("wrong HTTP code %q: allowed characters are digits and wildcards (*xX)", code)
-			}
+		if err := codes.ValidateCode(code); err != nil {
+			return nil, err
 		}
 
 		rest := after
@@ -142,6 +142,14 @@ func TestParseAddHTTPCodes(t *testing.T) {
 			give: "4XX=Client Error",
 			want: map[string]codes.Description{"4XX": {Short: "Client Error"}},
 		},
+		"range": {
+			give: "400-451=Client Error",
+			want: map[string]codes.Description{"400-451": {Short: "Client Error"}},
+		},
+		"exclusions": {
+			give: "4xx=Client Error||!404||!500-504=",
+			want: map[string]codes.Description{"4xx": {Short: "Client Error"}, "!404": {}, "!500-504": {}},
+		},
 		"override same code": {
 			give: "404=First||404=Second",
 			want: map[string]codes.Description{"404": {Short: "Second"}},
@@ -168,6 +176,18 @@ func TestParseAddHTTPCodes(t *testing.T) {
 				assert.ErrorContains(t, err, "allowed characters are digits and wildcards")
 			},
 		},
+		"reversed range": {
+			give:     "451-400=Client Error",
+			checkErr: func(t *testing.T, err error) { assert.ErrorContains(t, err, "the start is greater than the end") },
+		},
+		"wildcards in range": {
+			give:     "4xx-5xx=Error",
+			checkErr: func(t *testing.T, err error) { assert.ErrorContains(t, err, "the bounds must be 3-digit codes") },
+		},
+		"exclusion with message": {
+			give:     "!404=Not Found",
+			checkErr: func(t *testing.T, err error) { assert.ErrorContains(t, err, "must not have a message") },
+		},
 		"empty message": {
 			give:     "404=",
 			checkErr: func(t *testing.T, err error) { assert.ErrorContains(t, err, "missing message") },
@@ -4,6 +4,9 @@ import (
 	"maps"
 	"slices"
 	"strconv"
+	"strings"
+
+	tpl "gh.tarampamp.am/error-pages/v4/internal/template"
 )
 
 // Description holds HTTP error information.
@@ -13,52 +16,215 @@ type Description struct {
 
 	// Full is a longer description of the HTTP error.
 	Full string
+
+	// Links are the additional links to display on the error page of the code (optional).
+	Links []tpl.Link
+
+	// Translations of the short and full descriptions, keyed by the lowercase language tags (e.g. "de" or "pt-br").
+	Translations map[string]Translation
+
+	// Category of the built-in code (empty for the user-defined ones).
+	Category Category
+}
+
+// Translation is the translation of the [Description] to a language. Empty fields are not translated.
+type Translation struct {
+	Short, Full string
+}
+
+// Translate returns the description translated to the first language it has the translation to. The languages
+// are the BCP 47 tags in the order of preference, and the base language (e.g. "fr" for "fr-CA") is tried when
+// there is no translation to the exact one.
+func (d Description) Translate(langs ...string) Description {
+	if len(d.Translations) == 0 {
+		return d
+	}
+
+	for _, lang := range langs {
+		lang = strings.ToLower(lang)
+
+		tr, ok := d.Translations[lang]
+		if !ok {
+			if base, _, cut := strings.Cut(lang, "-"); cut {
+				tr, ok = d.Translations[base]
+			}
+		}
+
+		if ok {
+			if tr.Short != "" {
+				d.Short = tr.Short
+			}
+
+			if tr.Full != "" {
+				d.Full = tr.Full
+			}
+
+			return d
+		}
+	}
+
+	return d
 }
 
 // Codes is a map of HTTP codes to their descriptions.
 //
-// The codes may be written in a non-strict manner. For example, they may be "4xx", "4XX", or "4**".
-// If the map contains both "404" and "4xx" keys, and we search via [Codes.Find] for "404", the "404" key will
-// be returned.
-// However, if we search for "405", "400", or any non-existing code that starts with "4" and its length is 3,
-// the value under the key "4xx" will be retrieved.
+// The keys may be exact codes ("404"), codes with wildcards ("4xx", "4XX", or "4**"), inclusive ranges
+// ("400-451"), or any of them prefixed with "!" to exclude the codes ("!404", "!5xx"). The description of the
+// exclusion is not used. When we search via [Codes.Find], the precedence is:
+//
+//  1. exclusions - the excluded code is not found, even if there is the exact key for it;
+//  2. the exact key - "404" for the code 404;
+//  3. the range or the key with wildcards covering the fewest codes - "40x" (10 codes) wins over "400-451"
+//     (52 codes), which wins over "4xx" (100 codes). On a tie, the key that sorts first wins.
 //
-// The length of the code (in string format) is matter.
+// For example, if the map contains "404" and "4xx" keys, the "404" key is returned for the code 404, and the "4xx"
+// key for 405, 400, or any other code that starts with "4" and its length is 3.
+//
+// The length of the code (in string format) is matter for the keys with wildcards.
 type Codes map[string]Description
 
-var builtInCodes = Codes{ //nolint:gochecknoglobals
-	"400": {"Bad Request", "The server did not understand the request"},
-	"401": {"Unauthorized", "The requested page needs a username and a password"},
-	"403": {"Forbidden", "Access is forbidden to the requested page"},
-	"404": {"Not Found", "The server can not find the requested page"},
-	"405": {"Method Not Allowed", "The method specified in the request is not allowed"},
-	"407": {"Proxy Authentication Required", "You must authenticate with a proxy server before this request can be served"}, //nolint:lll
-	"408": {"Request Timeout", "The request took longer than the server was prepared to wait"},
-	"409": {"Conflict", "The request could not be completed because of a conflict"},
-	"410": {"Gone", "The requested page is no longer available"},
-	"411": {"Length Required", "The \"Content-Length\" is not defined. The server will not accept the request without it"},
-	"412": {"Precondition Failed", "The pre condition given in the request evaluated to false by the server"},
-	"413": {"Payload Too Large", "The server will not accept the request, because the request entity is too large"},
-	"416": {"Requested Range Not Satisfiable", "The requested byte range is not available and is out of bounds"},
-	"418": {"I'm a teapot", "Attempt to brew coffee with a teapot is not supported"},
-	"429": {"Too Many Requests", "Too many requests in a given amount of time"},
-	"500": {"Internal Server Error", "The server met an unexpected condition"},
-	"502": {"Bad Gateway", "The server received an invalid response from the upstream server"},
-	"503": {"Service Unavailable", "The server is temporarily overloading or down"},
-	"504": {"Gateway Timeout", "The gateway has timed out"},
-	"505": {"HTTP Version Not Supported", "The server does not support the \"http protocol\" version"},
+// Category is the category of the built-in HTTP code.
+type Category string
+
+const (
+	CategoryStandard Category = "standard" // registered by IANA (RFC 9110 and others)
+	CategoryProxy    Category = "proxy"    // used by the proxies for the conditions of their own (e.g. nginx 444 or 499)
+	CategoryVendor   Category = "vendor"   // used by the vendors of CDNs and load balancers (e.g. Cloudflare 52x)
+)
+
+// The sets of the built-in codes (see [New]).
+const (
+	BuiltInNone     = ""         // no built-in codes
+	BuiltInStandard = "standard" // the standard codes only
+	BuiltInExtended = "extended" // the standard, proxy-specific and vendor codes
+)
+
+// BuiltInSets returns the names of the sets of the built-in codes.
+func BuiltInSets() []string { return []string{BuiltInStandard, BuiltInExtended} }
+
+var builtInCodes = map[Category]Codes{ //nolint:gochecknoglobals
+	CategoryStandard: {
+		"400": {Short: "Bad Request", Full: "The server did not understand the request"},
+		"401": {Short: "Unauthorized", Full: "The requested page needs a username and a password"},
+		"402": {Short: "Payment Required", Full: "Payment is required to access the requested page"},
+		"403": {Short: "Forbidden", Full: "Access is forbidden to the requested page"},
+		"404": {Short: "Not Found", Full: "The server can not find the requested page"},
+		"405": {Short: "Method Not Allowed", Full: "The method specified in the request is not allowed"},
+		"406": {Short: "Not Acceptable", Full: "The server can not produce a response in any of the accepted formats"},
+		"407": {
+			Short: "Proxy Authentication Required",
+			Full:  "You must authenticate with a proxy server before this request can be served",
+		},
+		"408": {Short: "Request Timeout", Full: "The request took longer than the server was prepared to wait"},
+		"409": {Short: "Conflict", Full: "The request could not be completed because of a conflict"},
+		"410": {Short: "Gone", Full: "The requested page is no longer available"},
+		"411": {
+			Short: "Length Required",
+			Full:  "The \"Content-Length\" is not defined. The server will not accept the request without it",
+		},
+		"412": {
+			Short: "Precondition Failed",
+			Full:  "The pre condition given in the request evaluated to false by the server",
+		},
+		"413": {
+			Short: "Payload Too Large",
+			Full:  "The server will not accept the request, because the request entity is too large",
+		},
+		"414": {Short: "URI Too Long", Full: "The requested URL is longer than the server is willing to process"},
+		"415": {Short: "Unsupported Media Type", Full: "The media type of the request payload is not supported"},
+		"416": {
+			Short: "Requested Range Not Satisfiable",
+			Full:  "The requested byte range is not available and is out of bounds",
+		},
+		"417": {Short: "Expectation Failed", Full: "The server can not meet the requirements of the \"Expect\" header"},
+		"418": {Short: "I'm a teapot", Full: "Attempt to brew coffee with a teapot is not supported"},
+		"421": {Short: "Misdirected Request", Full: "The request was sent to a server that can not respond to it"},
+		"422": {Short: "Unprocessable Entity", Full: "The request is well-formed, but could not be processed"},
+		"423": {Short: "Locked", Full: "The requested resource is locked"},
+		"424": {Short: "Failed Dependency", Full: "The request failed because the request it depends on failed"},
+		"425": {Short: "Too Early", Full: "The server is unwilling to process a request that might be replayed"},
+		"426": {Short: "Upgrade Required", Full: "The client must switch to a different protocol"},
+		"428": {Short: "Precondition Required", Full: "The server requires the request to be conditional"},
+		"429": {Short: "Too Many Requests", Full: "Too many requests in a given amount of time"},
+		"431": {
+			Short: "Request Header Fields Too Large",
+			Full:  "The request headers are too large for the server to process",
+		},
+		"451": {Short: "Unavailable For Legal Reasons", Full: "The requested page is unavailable for legal reasons"},
+		"500": {Short: "Internal Server Error", Full: "The server met an unexpected condition"},
+		"501": {Short: "Not Implemented", Full: "The server does not support the functionality required by the request"},
+		"502": {Short: "Bad Gateway", Full: "The server received an invalid response from the upstream server"},
+		"503": {Short: "Service Unavailable", Full: "The server is temporarily overloading or down"},
+		"504": {Short: "Gateway Timeout", Full: "The gateway has timed out"},
+		"505": {Short: "HTTP Version Not Supported", Full: "The server does not support the \"http protocol\" version"},
+		"506": {Short: "Variant Also Negotiates", Full: "The server has a configuration error in the content negotiation"},
+		"507": {Short: "Insufficient Storage", Full: "The server is unable to store the data needed to complete the request"},
+		"508": {Short: "Loop Detected", Full: "The server detected an infinite loop while processing the request"},
+		"510": {Short: "Not Extended", Full: "Further extensions to the request are required to fulfill it"},
+		"511": {Short: "Network Authentication Required", Full: "You must authenticate to gain network access"},
+	},
+	CategoryProxy: { // nginx
+		"444": {Short: "No Response", Full: "The server closed the connection without sending a response"},
+		"494": {Short: "Request Header Too Large", Full: "The request headers are too large for the server to process"},
+		"495": {Short: "SSL Certificate Error", Full: "The client SSL certificate is invalid"},
+		"496": {Short: "SSL Certificate Required", Full: "The client SSL certificate is required, but was not provided"},
+		"497": {Short: "HTTP Request Sent to HTTPS Port", Full: "A plain HTTP request was sent to the HTTPS port"},
+		"499": {Short: "Client Closed Request", Full: "The client closed the connection before the server responded"},
+	},
+	CategoryVendor: {
+		// AWS Elastic Load Balancing
+		"460": {
+			Short: "Client Closed Connection",
+			Full:  "The client closed the connection before the load balancer responded",
+		},
+		"561": {
+			Short: "Unauthorized",
+			Full:  "The load balancer could not authenticate the user with the identity provider",
+		},
+		// Esri ArcGIS
+		"498": {Short: "Invalid Token", Full: "The access token is expired or otherwise invalid"},
+		// Apache (mod_bw) and cPanel
+		"509": {Short: "Bandwidth Limit Exceeded", Full: "The site has used up the bandwidth allowed by its hosting plan"},
+		// Cloudflare
+		"520": {
+			Short: "Web Server Returned an Unknown Error",
+			Full:  "The origin server returned an empty, unknown, or unexpected response",
+		},
+		"521": {Short: "Web Server Is Down", Full: "The origin server refused the connection"},
+		"522": {Short: "Connection Timed Out", Full: "The connection to the origin server timed out"},
+		"523": {Short: "Origin Is Unreachable", Full: "The origin server could not be reached"},
+		"524": {Short: "A Timeout Occurred", Full: "The origin server did not respond in time"},
+		"525": {Short: "SSL Handshake Failed", Full: "The SSL handshake with the origin server failed"},
+		"526": {Short: "Invalid SSL Certificate", Full: "The SSL certificate of the origin server is invalid"},
+		"527": {Short: "Railgun Error", Full: "The connection between the CDN and the origin server was interrupted"},
+		"529": {Short: "Site Is Overloaded", Full: "The site can not process the request, because it is overloaded"},
+		"530": {Short: "Origin DNS Error", Full: "The address of the origin server could not be resolved"},
+	},
 }
 
-// New returns a new Codes map with the built-in HTTP codes and their descriptions.
-func New(empty bool) Codes {
-	if empty {
-		return make(Codes)
+// New returns a new Codes map with the built-in HTTP codes of the set ([BuiltInStandard] or [BuiltInExtended])
+// and their descriptions. The map is empty for [BuiltInNone].
+func New(set string) Codes {
+	var result = make(Codes)
+
+	for category, entries := range builtInCodes {
+		if set != BuiltInExtended && (set != BuiltInStandard || category != CategoryStandard) {
+			continue
+		}
+
+		for key, desc := range entries {
+			desc.Category = category
+			result[key] = desc
+		}
 	}
 
-	return maps.Clone(builtInCodes)
+	return result
 }
 
 // Find returns the description of the given HTTP code. If the code is not found, it returns false.
+//
+// All the entries are checked on every call (with no allocations), so use [Codes.Compile] to find the codes many
+// times with a lot of entries (e.g. on every request).
 func (c Codes) Find(code uint16) (Description, bool) {
 	if len(c) == 0 { // happiest path ;)
 		return Description{}, false
@@ -69,42 +235,107 @@ func (c Codes) Find(code uint16) (Description, bool) {
 
 	str := strconv.AppendUint(buf[:0], uint64(code), 10) //nolint:mnd
 
+	var (
+		best     string
+		bestSize uint32
+	)
+
+	for key := range c { // the same precedence as the one of resolve, but without sorting the keys
+		raw, exclude := strings.CutPrefix(key, "!")
+
+		if !exclude && isDigits(raw) {
+			continue // exact codes are found by the key
+		}
+
+		p, err := parsePattern(raw)
+		if err != nil || !p.match(code, str) {
+			continue
+		}
+
+		if exclude {
+			return Description{}, false
+		}
+
+		if size := p.size(); best == "" || size < bestSize || (size == bestSize && key < best) {
+			best, bestSize = key, size
+		}
+	}
+
 	if desc, ok := c[string(str)]; ok { // exact match
 		return desc, true
 	}
 
-	var (
-		bestKey string
-		bestWC  = -1
-	)
+	if best == "" {
+		return Description{}, false
+	}
 
-	for key := range c {
-		if len(key) != len(str) || (!isWildcard(key[0]) && key[0] != str[0]) {
-			continue // skip keys that are of different length or don't start with the same character or a wildcard
-		}
+	return c[best], true
+}
 
-		wc, matched := 0, true
+// keyPattern is the parsed key of the [Codes] that is not an exact code.
+type keyPattern struct {
+	pattern
 
-		for i := range str {
-			if kb := key[i]; isWildcard(kb) {
-				wc++
-			} else if kb != str[i] {
-				matched = false
+	key     string
+	exclude bool // the key starts with "!"
+}
 
-				break
-			}
+// patterns returns the parsed keys with wildcards, ranges and exclusions, sorted by the key. Exact codes and keys
+// that can not be parsed are skipped.
+func (c Codes) patterns() []keyPattern {
+	var result []keyPattern
+
+	for _, key := range c.Codes() {
+		var (
+			exclude = strings.HasPrefix(key, "!")
+			raw     = strings.TrimPrefix(key, "!")
+		)
+
+		if !exclude && isDigits(raw) {
+			continue // exact codes are found by the key
 		}
 
-		if matched && (bestWC < 0 || wc < bestWC) {
-			bestKey, bestWC = key, wc
+		if p, err := parsePattern(raw); err == nil {
+			result = append(result, keyPattern{pattern: p, key: key, exclude: exclude})
 		}
 	}
 
-	if bestWC < 0 {
-		return Description{}, false
+	return result
+}
+
+// resolve returns the key of the entry describing the code, following the precedence described in [Codes].
+func (c Codes) resolve(code uint16, patterns []keyPattern) (string, bool) {
+	// stack-allocated buffer, uint16 max = 65535 (5 digits)
+	var buf [5]byte
+
+	str := strconv.AppendUint(buf[:0], uint64(code), 10) //nolint:mnd
+
+	for _, p := range patterns {
+		if p.exclude && p.match(code, str) {
+			return "", false
+		}
+	}
+
+	if _, ok := c[string(str)]; ok { // exact match
+		return string(str), true
+	}
+
+	var (
+		best     string
+		bestSize uint32
+	)
+
+	for _, p := range patterns { // sorted by the key, so the ties are resolved by the key order
+		if p.exclude || !p.match(code, str) {
+			continue
+		}
+
+		if size := p.size(); best == "" || size < bestSize {
+			best, bestSize = p.key, size
+		}
 	}
 
-	return c[bestKey], true
+	return best, best != ""
 }
 
 func isWildcard(b byte) bool { return b == '*' || b == 'x' || b == 'X' }
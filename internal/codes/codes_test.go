@@ -1,10 +1,12 @@
 package codes_test
 
 import (
+	"strconv"
 	"testing"
 
 	"gh.tarampamp.am/error-pages/v4/internal/codes"
 	"gh.tarampamp.am/error-pages/v4/internal/testutil/assert"
+	"gh.tarampamp.am/error-pages/v4/l10n"
 )
 
 func TestNew(t *testing.T) {
@@ -13,14 +15,15 @@ func TestNew(t *testing.T) {
 	t.Run("not empty", func(t *testing.T) {
 		t.Parallel()
 
-		assert.False(t, len(codes.New(false)) == 0)
-		assert.True(t, len(codes.New(true)) == 0)
+		assert.False(t, len(codes.New(codes.BuiltInStandard)) == 0)
+		assert.True(t, len(codes.New(codes.BuiltInExtended)) > len(codes.New(codes.BuiltInStandard)))
+		assert.True(t, len(codes.New(codes.BuiltInNone)) == 0)
 	})
 
 	t.Run("contains known codes", func(t *testing.T) {
 		t.Parallel()
 
-		c := codes.New(false)
+		c := codes.New(codes.BuiltInStandard)
 
 		for code, wantShort := range map[string]string{
 			"400": "Bad Request",
@@ -57,12 +60,46 @@ func TestNew(t *testing.T) {
 	t.Run("returns independent clone", func(t *testing.T) {
 		t.Parallel()
 
-		c := codes.New(false)
+		c := codes.New(codes.BuiltInStandard)
 		delete(c, "404")
 
-		_, ok := codes.New(false)["404"]
+		_, ok := codes.New(codes.BuiltInStandard)["404"]
 		assert.True(t, ok)
 	})
+
+	t.Run("categories", func(t *testing.T) {
+		t.Parallel()
+
+		for _, desc := range codes.New(codes.BuiltInStandard) {
+			assert.Equal(t, codes.CategoryStandard, desc.Category)
+		}
+
+		extended := codes.New(codes.BuiltInExtended)
+
+		assert.Equal(t, codes.CategoryProxy, extended["499"].Category)
+		assert.Equal(t, codes.CategoryVendor, extended["522"].Category)
+		assert.Equal(t, codes.CategoryVendor, extended["509"].Category)
+	})
+
+	t.Run("translated", func(t *testing.T) {
+		t.Parallel()
+
+		for code, desc := range codes.New(codes.BuiltInExtended) {
+			assert.True(t, desc.Short != "" && desc.Full != "")
+
+			for _, lang := range l10n.Languages() {
+				if lang == "en" {
+					continue
+				}
+
+				for _, text := range []string{desc.Short, desc.Full} {
+					if _, ok := l10n.Translate(text, lang); !ok {
+						t.Errorf("code %s: no %s translation for %q", code, lang, text)
+					}
+				}
+			}
+		}
+	})
 }
 
 func TestCodes_Find(t *testing.T) {
@@ -93,6 +130,22 @@ func TestCodes_Find(t *testing.T) {
 		"*":   {Short: "Single"},
 	}
 
+	ranges := codes.Codes{
+		"400-451": {Short: "Client Error"},
+		"420-429": {Short: "Rate Limited"},
+		"40x":     {Short: "Auth"},
+		"418":     {Short: "Teapot"},
+		"!404":    {Short: "Ignored"},
+		"4xx":     {Short: "Other"},
+		"500":     {Short: "Internal Server Error"},
+		"!500":    {},
+		"!50x":    {},
+		"5xx":     {Short: "Server Error"},
+		"6x0":     {Short: "Six hundred"},
+		"600-609": {Short: "Six hundreds"},
+		"60x":     {Short: "Six hundreds, but..."},
+	}
+
 	for name, tt := range map[string]struct {
 		giveCodes codes.Codes
 		giveCode  uint16
@@ -138,23 +191,147 @@ func TestCodes_Find(t *testing.T) {
 		"ladder - strict single match": {giveCodes: ladder, giveCode: 1, wantShort: "Full single"},
 		"ladder - single wildcard":     {giveCodes: ladder, giveCode: 2, wantShort: "Single"},
 
+		"range - inside":                 {giveCodes: ranges, giveCode: 430, wantShort: "Client Error"},
+		"range - bounds":                 {giveCodes: ranges, giveCode: 451, wantShort: "Client Error"},
+		"range - outside":                {giveCodes: ranges, giveCode: 452, wantShort: "Other"},
+		"range - narrower wins":          {giveCodes: ranges, giveCode: 429, wantShort: "Rate Limited"},
+		"range - wildcard is narrower":   {giveCodes: ranges, giveCode: 401, wantShort: "Auth"},
+		"range - exact wins":             {giveCodes: ranges, giveCode: 418, wantShort: "Teapot"},
+		"range - excluded":               {giveCodes: ranges, giveCode: 404, wantNotFound: true},
+		"range - excluded exact":         {giveCodes: ranges, giveCode: 500, wantNotFound: true},
+		"range - excluded by wildcard":   {giveCodes: ranges, giveCode: 503, wantNotFound: true},
+		"range - wildcard after exclude": {giveCodes: ranges, giveCode: 510, wantShort: "Server Error"},
+		"range - tie resolved by key":    {giveCodes: ranges, giveCode: 600, wantShort: "Six hundreds"},
+
 		"empty map": {giveCodes: codes.Codes{}, giveCode: 404, wantNotFound: true},
 		"zero code": {giveCodes: common, giveCode: 0, wantNotFound: true},
 	} {
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
 
-			for range 10 { // repeat to ensure the function is idempotent
-				desc, found := tt.giveCodes.Find(tt.giveCode)
+			for _, find := range []func(uint16) (codes.Description, bool){
+				tt.giveCodes.Find,
+				tt.giveCodes.Compile().Find,
+			} {
+				for range 10 { // repeat to ensure the function is idempotent
+					desc, found := find(tt.giveCode)
 
-				if !tt.wantNotFound {
-					assert.True(t, found)
-					assert.Equal(t, tt.wantShort, desc.Short)
-				} else {
-					assert.False(t, found)
-					assert.Empty(t, desc)
+					if !tt.wantNotFound {
+						assert.True(t, found)
+						assert.Equal(t, tt.wantShort, desc.Short)
+					} else {
+						assert.False(t, found)
+						assert.DeepEqual(t, codes.Description{}, desc)
+					}
 				}
 			}
 		})
 	}
 }
+
+func TestDescription_Translate(t *testing.T) {
+	t.Parallel()
+
+	desc := codes.Description{
+		Short: "Not Found",
+		Full:  "The page does not exist",
+		Translations: map[string]codes.Translation{
+			"de":    {Short: "Nicht gefunden"},
+			"pt-br": {Short: "Não encontrado", Full: "A página não existe"},
+		},
+	}
+
+	for name, tc := range map[string]struct {
+		giveLangs           []string
+		wantShort, wantFull string
+	}{
+		"no languages":       {wantShort: "Not Found", wantFull: "The page does not exist"},
+		"unknown":            {giveLangs: []string{"fr", "es"}, wantShort: "Not Found", wantFull: "The page does not exist"},
+		"partial":            {giveLangs: []string{"de"}, wantShort: "Nicht gefunden", wantFull: "The page does not exist"},
+		"exact":              {giveLangs: []string{"pt-BR"}, wantShort: "Não encontrado", wantFull: "A página não existe"},
+		"base language":      {giveLangs: []string{"de-AT"}, wantShort: "Nicht gefunden", wantFull: "The page does not exist"},
+		"first of preferred": {giveLangs: []string{"fr", "pt-br", "de"}, wantShort: "Não encontrado", wantFull: "A página não existe"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got := desc.Translate(tc.giveLangs...)
+			assert.Equal(t, tc.wantShort, got.Short)
+			assert.Equal(t, tc.wantFull, got.Full)
+		})
+	}
+}
+
+func TestCodes_Compile(t *testing.T) {
+	t.Parallel()
+
+	httpCodes := codes.New(codes.BuiltInExtended)
+
+	for key, desc := range map[string]codes.Description{
+		"4xx": {Short: "Client Error"}, "420-429": {Short: "Rate Limited"}, "!404": {}, "!51x": {},
+		"5**": {Short: "Server Error"}, "1234": {Short: "Out of the index"}, "12xx": {Short: "Out of the index, too"},
+	} {
+		httpCodes[key] = desc
+	}
+
+	matcher := httpCodes.Compile()
+
+	for code := range uint16(2000) {
+		want, wantFound := httpCodes.Find(code)
+		got, gotFound := matcher.Find(code)
+
+		assert.Equal(t, wantFound, gotFound)
+		assert.DeepEqual(t, want, got)
+	}
+
+	got, _ := matcher.Find(1234)
+	assert.Equal(t, "Out of the index", got.Short)
+
+	got, _ = matcher.Find(1299)
+	assert.Equal(t, "Out of the index, too", got.Short)
+}
+
+func TestCodes_Find_NoAllocs(t *testing.T) { //nolint:paralleltest // the allocations of the other tests are counted too
+	httpCodes := codes.New(codes.BuiltInExtended)
+	httpCodes["4xx"] = codes.Description{Short: "Client Error"}
+	httpCodes["500-599"] = codes.Description{Short: "Server Error"}
+	httpCodes["!418"] = codes.Description{}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		for _, code := range []uint16{404, 418, 450, 509, 599, 999} {
+			_, _ = httpCodes.Find(code)
+		}
+	})
+
+	assert.Equal(t, float64(0), allocs)
+}
+
+func BenchmarkCodes_Find(b *testing.B) {
+	httpCodes := codes.New(codes.BuiltInExtended)
+	httpCodes["4xx"] = codes.Description{Short: "Client Error"}
+	httpCodes["500-599"] = codes.Description{Short: "Server Error"}
+	httpCodes["!418"] = codes.Description{}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := range b.N {
+		_, _ = httpCodes.Find(uint16(100 + i%900)) //nolint:gosec
+	}
+}
+
+func BenchmarkMatcher_Find(b *testing.B) {
+	httpCodes := codes.New(codes.BuiltInExtended)
+
+	for i := range 500 { // lots of the custom entries
+		httpCodes[strconv.Itoa(100+i%400)+"-"+strconv.Itoa(500+i%400)] = codes.Description{Short: "Range"}
+	}
+
+	matcher := httpCodes.Compile()
+
+	b.ResetTimer()
+
+	for i := range b.N {
+		_, _ = matcher.Find(uint16(100 + i%900)) //nolint:gosec
+	}
+}
@@ -0,0 +1,359 @@
+package codes
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	tpl "gh.tarampamp.am/error-pages/v4/internal/template"
+	"gh.tarampamp.am/error-pages/v4/internal/yaml"
+)
+
+// The modes of combining the codes from the file with the built-in ones.
+const (
+	FileModeMerge   = "merge"   // the codes from the file add to (or override) the built-in ones
+	FileModeReplace = "replace" // only the codes from the file are used
+)
+
+// File is the file with the HTTP codes and their descriptions (the format is described by the codes.schema.json).
+type File struct {
+	Mode  string // [FileModeMerge] or [FileModeReplace]
+	Codes Codes
+}
+
+// LoadFile reads and parses the codes file. See [ParseFile] for details.
+func LoadFile(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseFile(path, data)
+}
+
+// ParseFile parses the codes file content. The format is detected by the file name extension (".json", ".yaml" or
+// ".yml"), and the name is used in the errors along with the line numbers (e.g. "codes.yml:12: ...").
+func ParseFile(name string, data []byte) (*File, error) {
+	var (
+		root *yaml.Node
+		err  error
+	)
+
+	switch ext := strings.ToLower(filepath.Ext(name)); ext {
+	case ".json":
+		root, err = yaml.ParseJSON(data)
+	case ".yaml", ".yml":
+		root, err = yaml.Parse(data)
+	default:
+		return nil, fmt.Errorf("%s: unsupported codes file format %q (use .json, .yaml or .yml)", name, ext)
+	}
+
+	if err != nil {
+		if yErr := (*yaml.Error)(nil); errors.As(err, &yErr) {
+			return nil, fmt.Errorf("%s:%d: %s", name, yErr.Line, yErr.Msg)
+		}
+
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	return fileDecoder{name: name}.decode(root)
+}
+
+// fileDecoder decodes the document tree of the codes file.
+type fileDecoder struct{ name string }
+
+// errorf returns the error for the node, with the file name and the line number.
+func (d fileDecoder) errorf(n *yaml.Node, format string, args ...any) error {
+	return fmt.Errorf("%s:%d: %s", d.name, n.Line, fmt.Sprintf(format, args...))
+}
+
+func (d fileDecoder) decode(root *yaml.Node) (*File, error) {
+	fields, err := d.fields(root, "the file", []string{"codes"}, "$schema", "mode", "codes")
+	if err != nil {
+		return nil, err
+	}
+
+	var file = File{Mode: FileModeMerge}
+
+	if n, ok := fields["mode"]; ok {
+		if file.Mode, err = d.string(n, "the mode"); err != nil {
+			return nil, err
+		}
+
+		if file.Mode != FileModeMerge && file.Mode != FileModeReplace {
+			return nil, d.errorf(n, "unknown mode %q (allowed modes: %s, %s)", file.Mode, FileModeMerge, FileModeReplace)
+		}
+	}
+
+	codesNode := fields["codes"]
+
+	if codesNode.Kind != yaml.MappingNode {
+		return nil, d.errorf(codesNode, "the codes must be a mapping of the HTTP codes, not a %s", kindOf(codesNode))
+	}
+
+	file.Codes = make(Codes, len(codesNode.Items)/2) //nolint:mnd
+
+	for i := 0; i < len(codesNode.Items); i += 2 {
+		key, value := codesNode.Items[i], codesNode.Items[i+1]
+
+		if err = ValidateCode(key.Value); err != nil {
+			return nil, d.errorf(key, "%s", err.Error())
+		}
+
+		if strings.HasPrefix(key.Value, "!") {
+			if !value.IsNull() {
+				return nil, d.errorf(value, "the exclusion %s must have no value (null)", key.Value)
+			}
+
+			file.Codes[key.Value] = Description{}
+
+			continue
+		}
+
+		desc, dErr := d.description(value, key.Value)
+		if dErr != nil {
+			return nil, dErr
+		}
+
+		file.Codes[key.Value] = desc
+	}
+
+	return &file, nil
+}
+
+// description decodes the description of the code.
+func (d fileDecoder) description(n *yaml.Node, code string) (desc Description, err error) {
+	var what = fmt.Sprintf("the code %s", code)
+
+	fields, err := d.fields(n, what, []string{"message"}, "message", "description", "links", "translations")
+	if err != nil {
+		return desc, err
+	}
+
+	if desc.Short, err = d.nonEmptyString(fields["message"], "the message of "+what); err != nil {
+		return desc, err
+	}
+
+	if f, ok := fields["description"]; ok {
+		if desc.Full, err = d.string(f, "the description of "+what); err != nil {
+			return desc, err
+		}
+	}
+
+	if f, ok := fields["links"]; ok {
+		if desc.Links, err = d.links(f, what); err != nil {
+			return desc, err
+		}
+	}
+
+	if f, ok := fields["translations"]; ok {
+		if desc.Translations, err = d.translations(f, what); err != nil {
+			return desc, err
+		}
+	}
+
+	return desc, nil
+}
+
+// links decodes the links of the code.
+func (d fileDecoder) links(n *yaml.Node, what string) ([]tpl.Link, error) {
+	if n.Kind != yaml.SequenceNode {
+		return nil, d.errorf(n, "the links of %s must be a sequence, not a %s", what, kindOf(n))
+	}
+
+	var links = make([]tpl.Link, 0, len(n.Items))
+
+	for _, item := range n.Items {
+		fields, err := d.fields(item, "the link of "+what, []string{"label", "url"}, "label", "url")
+		if err != nil {
+			return nil, err
+		}
+
+		var link tpl.Link
+
+		if link.Label, err = d.nonEmptyString(fields["label"], "the link label"); err != nil {
+			return nil, err
+		}
+
+		if link.URL, err = d.nonEmptyString(fields["url"], "the link URL"); err != nil {
+			return nil, err
+		}
+
+		links = append(links, link)
+	}
+
+	return links, nil
+}
+
+// translations decodes the translations of the code.
+func (d fileDecoder) translations(n *yaml.Node, what string) (map[string]Translation, error) {
+	if n.Kind != yaml.MappingNode {
+		return nil, d.errorf(n, "the translations of %s must be a mapping of the languages, not a %s", what, kindOf(n))
+	}
+
+	var result = make(map[string]Translation, len(n.Items)/2) //nolint:mnd
+
+	for i := 0; i < len(n.Items); i += 2 {
+		key, value := n.Items[i], n.Items[i+1]
+
+		if !isLanguageTag(key.Value) {
+			return nil, d.errorf(key, "wrong language tag %q (expected a BCP 47 tag like \"de\" or \"pt-BR\")", key.Value)
+		}
+
+		var (
+			lang = strings.ToLower(key.Value)
+			tr   Translation
+		)
+
+		if _, dup := result[lang]; dup {
+			return nil, d.errorf(key, "duplicate language %q", key.Value)
+		}
+
+		fields, err := d.fields(value, "the "+key.Value+" translation of "+what, nil, "message", "description")
+		if err != nil {
+			return nil, err
+		}
+
+		if len(fields) == 0 {
+			return nil, d.errorf(value, "the %s translation of %s has neither a message nor a description", key.Value, what)
+		}
+
+		if f, ok := fields["message"]; ok {
+			if tr.Short, err = d.nonEmptyString(f, "the translated message"); err != nil {
+				return nil, err
+			}
+		}
+
+		if f, ok := fields["description"]; ok {
+			if tr.Full, err = d.nonEmptyString(f, "the translated description"); err != nil {
+				return nil, err
+			}
+		}
+
+		result[lang] = tr
+	}
+
+	return result, nil
+}
+
+// fields checks the node is a mapping with the allowed and required fields only, and returns its values.
+func (d fileDecoder) fields(
+	n *yaml.Node, what string, required []string, allowed ...string,
+) (map[string]*yaml.Node, error) {
+	if n.Kind != yaml.MappingNode {
+		return nil, d.errorf(n, "%s must be a mapping, not a %s", what, kindOf(n))
+	}
+
+	var fields = make(map[string]*yaml.Node, len(n.Items)/2) //nolint:mnd
+
+	for i := 0; i < len(n.Items); i += 2 {
+		key := n.Items[i]
+
+		if !slices.Contains(allowed, key.Value) {
+			return nil, d.errorf(key, "unknown field %q in %s (allowed fields: %s)",
+				key.Value, what, strings.Join(allowed, ", "))
+		}
+
+		fields[key.Value] = n.Items[i+1]
+	}
+
+	for _, name := range required {
+		if _, ok := fields[name]; !ok {
+			return nil, d.errorf(n, "missing field %q in %s", name, what)
+		}
+	}
+
+	return fields, nil
+}
+
+// string returns the value of the string scalar node (the null one is empty). The unquoted numbers and booleans are
+// rejected, as the schema requires the strings (they must be quoted, e.g. message: "404").
+func (d fileDecoder) string(n *yaml.Node, what string) (string, error) {
+	if n.Kind != yaml.ScalarNode || n.IsBool() || n.IsNumber() {
+		return "", d.errorf(n, "%s must be a string, not a %s", what, kindOf(n))
+	}
+
+	if n.IsNull() {
+		return "", nil
+	}
+
+	return n.Value, nil
+}
+
+// nonEmptyString returns the value of the scalar node, which must not be empty.
+func (d fileDecoder) nonEmptyString(n *yaml.Node, what string) (string, error) {
+	s, err := d.string(n, what)
+	if err != nil {
+		return "", err
+	}
+
+	if strings.TrimSpace(s) == "" {
+		return "", d.errorf(n, "%s must not be empty", what)
+	}
+
+	return s, nil
+}
+
+// kindOf returns the name of the node kind for the errors.
+func kindOf(n *yaml.Node) string {
+	switch {
+	case n.IsNull():
+		return "null"
+	case n.IsBool():
+		return "boolean"
+	case n.IsNumber():
+		return "number"
+	}
+
+	return n.Kind.String()
+}
+
+// isLanguageTag reports whether the string looks like the BCP 47 language tag (e.g. "de", "pt-BR", "zh-Hant").
+func isLanguageTag(s string) bool {
+	for i, part := range strings.Split(s, "-") {
+		if n := len(part); (i == 0 && (n < 2 || n > 3)) || n < 2 || n > 8 { //nolint:mnd
+			return false
+		}
+
+		for _, c := range part {
+			if (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') && (i == 0 || c < '0' || c > '9') {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// ValidateCode checks the key of the HTTP code (see [Codes]): the exact code ("404"), the code with wildcards
+// ("4xx", "5**"), the inclusive range ("400-451"), or any of them prefixed with "!" to exclude the codes ("!404").
+func ValidateCode(code string) error {
+	var key = strings.TrimPrefix(code, "!")
+
+	if from, to, isRange := strings.Cut(key, "-"); isRange {
+		if len(from) != 3 || len(to) != 3 || !isDigits(from) || !isDigits(to) { //nolint:mnd
+			return fmt.Errorf("wrong HTTP code range %q: the bounds must be 3-digit codes", code)
+		}
+
+		if from > to {
+			return fmt.Errorf("wrong HTTP code range %q: the start is greater than the end", code)
+		}
+
+		return nil
+	}
+
+	if len(key) != 3 { //nolint:mnd
+		return fmt.Errorf("wrong HTTP code %q: must be 3 characters long", code)
+	}
+
+	for i := range len(key) {
+		if b := key[i]; (b < '0' || b > '9') && !isWildcard(b) {
+			return fmt.Errorf("wrong HTTP code %q: allowed characters are digits and wildcards (*xX)", code)
+		}
+	}
+
+	return nil
+}
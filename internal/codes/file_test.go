@@ -0,0 +1,303 @@
+package codes_test
+
+import (
+	"encoding/json"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"gh.tarampamp.am/error-pages/v4/internal/codes"
+	tpl "gh.tarampamp.am/error-pages/v4/internal/template"
+	"gh.tarampamp.am/error-pages/v4/internal/testutil/assert"
+)
+
+const yamlFile = `# yaml-language-server: $schema=codes.schema.json
+mode: replace
+codes:
+  404:
+    message: Not Found
+    description: >
+      The page you are looking for
+      does not exist
+    links:
+      - label: Search
+        url: https://example.com/search
+    translations:
+      de: {message: Nicht gefunden}
+      pt-BR:
+        message: Não encontrado
+        description: "A página não existe"
+  5xx:
+    message: Server Error
+  500-504: {message: Gateway Error}
+  '!503':
+`
+
+const jsonFile = `{
+  "$schema": "codes.schema.json",
+  "mode": "replace",
+  "codes": {
+    "404": {
+      "message": "Not Found",
+      "description": "The page you are looking for does not exist\n",
+      "links": [{"label": "Search", "url": "https://example.com/search"}],
+      "translations": {
+        "de": {"message": "Nicht gefunden"},
+        "pt-BR": {"message": "Não encontrado", "description": "A página não existe"}
+      }
+    },
+    "5xx": {"message": "Server Error"},
+    "500-504": {"message": "Gateway Error"},
+    "!503": null
+  }
+}`
+
+func TestParseFile(t *testing.T) {
+	t.Parallel()
+
+	var want = &codes.File{
+		Mode: codes.FileModeReplace,
+		Codes: codes.Codes{
+			"404": {
+				Short: "Not Found",
+				Full:  "The page you are looking for does not exist\n",
+				Links: []tpl.Link{{Label: "Search", URL: "https://example.com/search"}},
+				Translations: map[string]codes.Translation{
+					"de":    {Short: "Nicht gefunden"},
+					"pt-br": {Short: "Não encontrado", Full: "A página não existe"},
+				},
+			},
+			"5xx":     {Short: "Server Error"},
+			"500-504": {Short: "Gateway Error"},
+			"!503":    {},
+		},
+	}
+
+	for name, content := range map[string]string{"codes.yml": yamlFile, "codes.YAML": yamlFile, "codes.json": jsonFile} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := codes.ParseFile(name, []byte(content))
+			assert.NoError(t, err)
+			assert.DeepEqual(t, want, got)
+		})
+	}
+
+	t.Run("merge by default", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := codes.ParseFile("codes.yaml", []byte("codes:\n  '418': {message: Teapot}\n"))
+		assert.NoError(t, err)
+		assert.Equal(t, codes.FileModeMerge, got.Mode)
+		assert.Equal(t, "Teapot", got.Codes["418"].Short)
+	})
+}
+
+func TestParseFile_Errors(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		giveName, giveContent string
+		wantError             string
+	}{
+		"format": {
+			giveName:  "codes.toml",
+			wantError: `codes.toml: unsupported codes file format ".toml"`,
+		},
+		"syntax": {
+			giveName:    "codes.yml",
+			giveContent: "codes:\n  404:\n\tmessage: x\n",
+			wantError:   "codes.yml:3: tabs are not allowed for indentation",
+		},
+		"json syntax": {
+			giveName:    "codes.json",
+			giveContent: "{\n  \"codes\": {\n    \"404\": {\"message\": \"x\",}\n  }\n}",
+			wantError:   "codes.json:3: invalid character",
+		},
+		"missing codes": {
+			giveName:    "codes.yml",
+			giveContent: "mode: merge\n",
+			wantError:   `codes.yml:1: missing field "codes" in the file`,
+		},
+		"unknown mode": {
+			giveName:    "codes.yml",
+			giveContent: "mode: append\ncodes: {}\n",
+			wantError:   `codes.yml:1: unknown mode "append" (allowed modes: merge, replace)`,
+		},
+		"unknown field": {
+			giveName:    "codes.yml",
+			giveContent: "codes:\n  404:\n    message: x\n    title: y\n",
+			wantError: `codes.yml:4: unknown field "title" in the code 404 ` +
+				`(allowed fields: message, description, links, translations)`,
+		},
+		"wrong code": {
+			giveName:    "codes.json",
+			giveContent: "{\"codes\": {\n\"4040\": {\"message\": \"x\"}}}",
+			wantError:   `codes.json:2: wrong HTTP code "4040": must be 3 characters long`,
+		},
+		"wrong range": {
+			giveName:    "codes.yml",
+			giveContent: "codes:\n  500-4xx: {message: x}\n",
+			wantError:   `codes.yml:2: wrong HTTP code range "500-4xx": the bounds must be 3-digit codes`,
+		},
+		"exclusion with value": {
+			giveName:    "codes.yml",
+			giveContent: "codes:\n  '!404': {message: x}\n",
+			wantError:   "codes.yml:2: the exclusion !404 must have no value (null)",
+		},
+		"missing message": {
+			giveName:    "codes.yml",
+			giveContent: "codes:\n  404:\n    description: x\n",
+			wantError:   `codes.yml:3: missing field "message" in the code 404`,
+		},
+		"empty message": {
+			giveName:    "codes.yml",
+			giveContent: "codes:\n  404:\n    message: ''\n",
+			wantError:   "codes.yml:3: the message of the code 404 must not be empty",
+		},
+		"not a string": {
+			giveName:    "codes.yml",
+			giveContent: "codes:\n  404:\n    message: [a, b]\n",
+			wantError:   "codes.yml:3: the message of the code 404 must be a string, not a sequence",
+		},
+		"number": {
+			giveName:    "codes.json",
+			giveContent: "{\"codes\": {\n\"404\": {\"message\": 1}}}",
+			wantError:   "codes.json:2: the message of the code 404 must be a string, not a number",
+		},
+		"boolean": {
+			giveName:    "codes.json",
+			giveContent: "{\"codes\": {\"404\": {\n\"message\": \"x\",\n\"description\": false}}}",
+			wantError:   "codes.json:3: the description of the code 404 must be a string, not a boolean",
+		},
+		"yaml number": {
+			giveName:    "codes.yml",
+			giveContent: "codes:\n  404:\n    message: x\n    translations:\n      de: {message: 1.5e3}\n",
+			wantError:   "codes.yml:5: the translated message must be a string, not a number",
+		},
+		"links": {
+			giveName:    "codes.yml",
+			giveContent: "codes:\n  404:\n    message: x\n    links:\n      - label: y\n",
+			wantError:   `codes.yml:5: missing field "url" in the link of the code 404`,
+		},
+		"language": {
+			giveName:    "codes.yml",
+			giveContent: "codes:\n  404:\n    message: x\n    translations:\n      german: {message: y}\n",
+			wantError:   `codes.yml:5: wrong language tag "german"`,
+		},
+		"empty translation": {
+			giveName:    "codes.yml",
+			giveContent: "codes:\n  404:\n    message: x\n    translations:\n      de: {}\n",
+			wantError:   "codes.yml:5: the de translation of the code 404 has neither a message nor a description",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := codes.ParseFile(tc.giveName, []byte(tc.giveContent))
+			assert.ErrorContains(t, err, tc.wantError)
+		})
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	t.Parallel()
+
+	var path = filepath.Join(t.TempDir(), "codes.yaml")
+
+	assert.NoError(t, os.WriteFile(path, []byte(yamlFile), 0o600))
+
+	file, err := codes.LoadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, len(file.Codes))
+
+	_, err = codes.LoadFile(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.Error(t, err)
+}
+
+// TestSchema checks the JSON schema of the codes file describes the same fields the parser supports.
+func TestSchema(t *testing.T) {
+	t.Parallel()
+
+	type object struct {
+		Required   []string                   `json:"required"`
+		Properties map[string]json.RawMessage `json:"properties"`
+	}
+
+	var schema struct {
+		object
+
+		Definitions map[string]object `json:"definitions"`
+	}
+
+	data, err := os.ReadFile("codes.schema.json")
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(data, &schema))
+
+	for name, tc := range map[string]struct {
+		schema object
+		doc    func(fields string) string // wraps the fields into the document
+		values map[string]string
+	}{
+		"root": {
+			schema: schema.object,
+			doc:    func(fields string) string { return "{" + fields + "}" },
+			values: map[string]string{"$schema": `"x"`, "mode": `"merge"`, "codes": `{}`},
+		},
+		"code": {
+			schema: schema.Definitions["code"],
+			doc:    func(fields string) string { return `{"codes": {"404": {` + fields + `}}}` },
+			values: map[string]string{
+				"message": `"x"`, "description": `"x"`, "links": `[]`, "translations": `{"de": {"message": "x"}}`,
+			},
+		},
+		"link": {
+			schema: schema.Definitions["link"],
+			doc: func(fields string) string {
+				return `{"codes": {"404": {"message": "x", "links": [{` + fields + `}]}}}`
+			},
+			values: map[string]string{"label": `"x"`, "url": `"x"`},
+		},
+		"translation": {
+			schema: schema.Definitions["translation"],
+			doc: func(fields string) string {
+				return `{"codes": {"404": {"message": "x", "translations": {"de": {` + fields + `}}}}}`
+			},
+			values: map[string]string{"message": `"x"`, "description": `"x"`},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.DeepEqual(t, slices.Sorted(maps.Keys(tc.values)), slices.Sorted(maps.Keys(tc.schema.Properties)))
+
+			var fields []string
+
+			for _, key := range slices.Sorted(maps.Keys(tc.values)) {
+				fields = append(fields, `"`+key+`": `+tc.values[key])
+			}
+
+			_, err := codes.ParseFile("all.json", []byte(tc.doc(strings.Join(fields, ", "))))
+			assert.NoError(t, err) // all the fields of the schema are supported
+
+			_, err = codes.ParseFile("extra.json", []byte(tc.doc(strings.Join(append(fields, `"extra": "x"`), ", "))))
+			assert.ErrorContains(t, err, `unknown field "extra"`) // and no other fields
+
+			for _, required := range tc.schema.Required {
+				var without []string
+
+				for _, f := range fields {
+					if !strings.HasPrefix(f, `"`+required+`"`) {
+						without = append(without, f)
+					}
+				}
+
+				_, err = codes.ParseFile("required.json", []byte(tc.doc(strings.Join(without, ", "))))
+				assert.ErrorContains(t, err, `missing field "`+required+`"`)
+			}
+		})
+	}
+}
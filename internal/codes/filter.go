@@ -0,0 +1,196 @@
+package codes
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Filter selects the HTTP codes by the patterns: exact codes ("404"), codes with wildcards ("5xx", "4**"), and
+// inclusive ranges ("500-504"). An empty filter selects every code.
+type Filter []pattern
+
+type pattern struct {
+	lo, hi uint16 // the inclusive range (for the exact codes and ranges)
+	mask   string // the code with wildcards (the range is not used then)
+}
+
+// ParseFilter parses the comma-separated list of patterns.
+func ParseFilter(s string) (Filter, error) {
+	var result Filter
+
+	for part := range strings.SplitSeq(s, ",") {
+		if part = strings.TrimSpace(part); part == "" {
+			continue
+		}
+
+		p, err := parsePattern(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid code pattern %q: %w", part, err)
+		}
+
+		result = append(result, p)
+	}
+
+	return result, nil
+}
+
+func parsePattern(s string) (pattern, error) {
+	if from, to, isRange := strings.Cut(s, "-"); isRange {
+		lo, loErr := strconv.ParseUint(strings.TrimSpace(from), 10, 16)
+		hi, hiErr := strconv.ParseUint(strings.TrimSpace(to), 10, 16)
+
+		if loErr != nil || hiErr != nil {
+			return pattern{}, errors.New("the range bounds must be numbers")
+		}
+
+		if lo > hi {
+			return pattern{}, errors.New("the range start is greater than its end")
+		}
+
+		return pattern{lo: uint16(lo), hi: uint16(hi)}, nil
+	}
+
+	if strings.ContainsFunc(s, func(r rune) bool { return r <= 0x7f && isWildcard(byte(r)) }) {
+		if !isCodeWithWildcards(s) {
+			return pattern{}, errors.New("only digits and wildcards (x, X, *) are allowed")
+		}
+
+		return pattern{mask: s}, nil
+	}
+
+	code, err := strconv.ParseUint(s, 10, 16)
+	if err != nil {
+		return pattern{}, errors.New("not a number")
+	}
+
+	return pattern{lo: uint16(code), hi: uint16(code)}, nil
+}
+
+// Match reports whether the code is selected by the filter.
+func (f Filter) Match(code uint16) bool {
+	if len(f) == 0 {
+		return true
+	}
+
+	var (
+		buf [5]byte                                         // uint16 max = 65535 (5 digits)
+		str = strconv.AppendUint(buf[:0], uint64(code), 10) //nolint:mnd
+	)
+
+	for _, p := range f {
+		if p.match(code, str) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// match reports whether the code (in both numeric and string forms) matches the pattern.
+func (p pattern) match(code uint16, str []byte) bool {
+	if p.mask == "" {
+		return code >= p.lo && code <= p.hi
+	}
+
+	return matchMask(p.mask, str)
+}
+
+// size returns the number of codes the pattern covers (for the keys with wildcards, every wildcard is counted as
+// 10 digits, so "4xx" covers 100 codes).
+func (p pattern) size() uint32 {
+	if p.mask == "" {
+		return uint32(p.hi-p.lo) + 1
+	}
+
+	var size uint32 = 1
+
+	for i := range len(p.mask) {
+		if isWildcard(p.mask[i]) {
+			size *= 10 //nolint:mnd
+		}
+	}
+
+	return size
+}
+
+// matchMask reports whether the code (in the string form) matches the code with wildcards.
+func matchMask(mask string, code []byte) bool {
+	if len(mask) != len(code) {
+		return false
+	}
+
+	for i := range code {
+		if !isWildcard(mask[i]) && mask[i] != code[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isDigits reports whether the string consists of digits only.
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for i := range len(s) {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isCodeWithWildcards reports whether the string consists of digits and wildcards only.
+func isCodeWithWildcards(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for i := range len(s) {
+		if !isWildcard(s[i]) && (s[i] < '0' || s[i] > '9') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// LowestCode returns the lowest code the key covers: the code itself for the exact keys, the start of the range
+// (e.g. 400 for "400-451"), or the code with the wildcards replaced by zeros (e.g. 400 for "4xx", or 100 for "xxx",
+// since the code keeps its length). It returns false for the exclusions and the keys that are not codes.
+func LowestCode(key string) (uint16, bool) {
+	if strings.Contains(key, "-") {
+		if p, err := parsePattern(key); err == nil {
+			return p.lo, true
+		}
+
+		return 0, false
+	}
+
+	if !isCodeWithWildcards(key) {
+		return 0, false
+	}
+
+	var digits = []byte(key)
+
+	for i, b := range digits {
+		switch {
+		case isWildcard(b) && i == 0 && len(digits) > 1:
+			digits[i] = '1'
+		case isWildcard(b):
+			digits[i] = '0'
+		}
+	}
+
+	code, err := strconv.ParseUint(string(digits), 10, 16)
+	if err != nil {
+		return 0, false
+	}
+
+	return uint16(code), true
+}
@@ -0,0 +1,70 @@
+package codes_test
+
+import (
+	"testing"
+
+	"gh.tarampamp.am/error-pages/v4/internal/codes"
+	"gh.tarampamp.am/error-pages/v4/internal/testutil/assert"
+)
+
+func TestParseFilter(t *testing.T) {
+	t.Parallel()
+
+	for name, tt := range map[string]struct {
+		give      string
+		wantMatch []uint16
+		wantSkip  []uint16
+	}{
+		"empty":     {give: "", wantMatch: []uint16{100, 404, 599}},
+		"exact":     {give: "404", wantMatch: []uint16{404}, wantSkip: []uint16{400, 4040, 40}},
+		"wildcards": {give: "5xx, 4*3", wantMatch: []uint16{500, 599, 403, 413}, wantSkip: []uint16{404, 5000, 50}},
+		"range":     {give: "400 - 410", wantMatch: []uint16{400, 404, 410}, wantSkip: []uint16{399, 411}},
+		"mixed":     {give: "401,,503-504,4XX", wantMatch: []uint16{401, 418, 503, 504}, wantSkip: []uint16{502}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			f, err := codes.ParseFilter(tt.give)
+			assert.NoError(t, err)
+
+			for _, code := range tt.wantMatch {
+				assert.True(t, f.Match(code))
+			}
+
+			for _, code := range tt.wantSkip {
+				assert.False(t, f.Match(code))
+			}
+		})
+	}
+
+	for give, wantErr := range map[string]string{
+		"foo":     "not a number",
+		"70000":   "not a number",
+		"4x4y":    "only digits and wildcards",
+		"500-400": "greater than its end",
+		"500-":    "must be numbers",
+	} {
+		_, err := codes.ParseFilter(give)
+
+		assert.ErrorContains(t, err, wantErr)
+	}
+}
+
+func TestLowestCode(t *testing.T) {
+	t.Parallel()
+
+	for give, want := range map[string]uint16{
+		"404": 404, "4xx": 400, "5*3": 503, "xxx": 100, "x": 0, "4XX": 400, "420-429": 420,
+	} {
+		got, ok := codes.LowestCode(give)
+
+		assert.True(t, ok)
+		assert.Equal(t, want, got)
+	}
+
+	for _, give := range []string{"", "foo", "4x4y", "99999x", "!404", "!4xx", "429-420", "4xx-5xx"} {
+		_, ok := codes.LowestCode(give)
+
+		assert.False(t, ok)
+	}
+}
@@ -0,0 +1,57 @@
+package codes
+
+// maxIndexedCode is the greatest code indexed by the [Matcher].
+const maxIndexedCode = 999
+
+// Matcher is the compiled [Codes] for the fast lookups: the entries describing the codes up to 999 are resolved
+// once, so [Matcher.Find] takes the same time regardless of the number of entries. Create it using
+// [Codes.Compile]; the codes must not be modified after that.
+type Matcher struct {
+	codes    Codes
+	patterns []keyPattern               // for the codes out of the index
+	index    [maxIndexedCode + 1]uint16 // the index of the description in descs, plus 1 (0 means "not found")
+	descs    []Description
+}
+
+// Compile resolves the descriptions of the codes (following the precedence described in [Codes]) into the index.
+func (c Codes) Compile() *Matcher {
+	var (
+		m    = Matcher{codes: c, patterns: c.patterns()}
+		keys = make(map[string]uint16, len(c)) // the key to its index in descs
+	)
+
+	for code := range uint16(maxIndexedCode + 1) {
+		key, ok := c.resolve(code, m.patterns)
+		if !ok {
+			continue
+		}
+
+		idx, seen := keys[key]
+		if !seen {
+			m.descs = append(m.descs, c[key])
+			idx = uint16(len(m.descs)) //nolint:gosec // limited by the index size
+			keys[key] = idx
+		}
+
+		m.index[code] = idx
+	}
+
+	return &m
+}
+
+// Find returns the description of the given HTTP code. If the code is not found, it returns false.
+func (m *Matcher) Find(code uint16) (Description, bool) {
+	if code > maxIndexedCode { // not a valid HTTP code, but the entries may be written for it anyway
+		if key, ok := m.codes.resolve(code, m.patterns); ok {
+			return m.codes[key], true
+		}
+
+		return Description{}, false
+	}
+
+	if idx := m.index[code]; idx > 0 {
+		return m.descs[idx-1], true
+	}
+
+	return Description{}, false
+}
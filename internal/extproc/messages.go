@@ -0,0 +1,300 @@
+package extproc
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Phase is the phase of the HTTP stream processing, which the ext_proc request is sent for. The values are the
+// numbers of the "request" oneof fields of the envoy.service.ext_proc.v3.ProcessingRequest message.
+type Phase int
+
+const (
+	RequestHeaders   Phase = 2
+	ResponseHeaders  Phase = 3
+	RequestBody      Phase = 4
+	ResponseBody     Phase = 5
+	RequestTrailers  Phase = 6
+	ResponseTrailers Phase = 7
+)
+
+// String returns the name of the phase.
+func (p Phase) String() string {
+	switch p {
+	case RequestHeaders:
+		return "request_headers"
+	case ResponseHeaders:
+		return "response_headers"
+	case RequestBody:
+		return "request_body"
+	case ResponseBody:
+		return "response_body"
+	case RequestTrailers:
+		return "request_trailers"
+	case ResponseTrailers:
+		return "response_trailers"
+	}
+
+	return fmt.Sprintf("phase(%d)", int(p))
+}
+
+// responseField returns the number of the "response" oneof field of the ProcessingResponse message for the phase
+// (the fields go in the same order, one less than the request ones).
+func (p Phase) responseField() int { return int(p) - 1 }
+
+// isHeaders reports whether the phase is one of the headers phases.
+func (p Phase) isHeaders() bool { return p == RequestHeaders || p == ResponseHeaders }
+
+// isKnown reports whether the phase is supported.
+func (p Phase) isKnown() bool { return p >= RequestHeaders && p <= ResponseTrailers }
+
+// Header is the HTTP header (the envoy.config.core.v3.HeaderValue message). The HTTP/2 pseudo-headers (like
+// ":status" or ":path") are the headers too, and the keys are always lowercase.
+type Header struct {
+	Key, Value string
+}
+
+// ProcessingRequest is the envoy.service.ext_proc.v3.ProcessingRequest message, limited to the fields the error
+// pages need.
+type ProcessingRequest struct {
+	Phase       Phase
+	Headers     []Header // for the headers phases only
+	EndOfStream bool     // for the headers phases only
+}
+
+// Marshal encodes the request.
+func (r ProcessingRequest) Marshal() []byte {
+	var msg []byte
+
+	if r.Phase.isHeaders() {
+		var headers []byte // the envoy.config.core.v3.HeaderMap message
+
+		for _, h := range r.Headers {
+			headers = appendBytes(headers, 1, marshalHeader(h))
+		}
+
+		msg = appendBytes(msg, 1, headers)
+
+		if r.EndOfStream {
+			msg = appendVarint(msg, 3, 1) //nolint:mnd
+		}
+	}
+
+	return appendBytes(nil, int(r.Phase), msg)
+}
+
+// Unmarshal decodes the request. The fields the error pages do not need are skipped.
+func (r *ProcessingRequest) Unmarshal(b []byte) error {
+	*r = ProcessingRequest{}
+
+	if err := consumeFields(b, func(f field) error {
+		if p := Phase(f.num); p.isKnown() && f.typ == wireBytes {
+			r.Phase = p
+
+			if p.isHeaders() {
+				return r.unmarshalHeaders(f.bytes)
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if r.Phase == 0 {
+		return errors.New("the request has no supported phase")
+	}
+
+	return nil
+}
+
+// unmarshalHeaders decodes the envoy.service.ext_proc.v3.HttpHeaders message.
+func (r *ProcessingRequest) unmarshalHeaders(b []byte) error {
+	return consumeFields(b, func(f field) error {
+		switch {
+		case f.num == 1 && f.typ == wireBytes: // headers (HeaderMap)
+			return consumeFields(f.bytes, func(hf field) error {
+				if hf.num != 1 || hf.typ != wireBytes {
+					return nil
+				}
+
+				h, err := unmarshalHeader(hf.bytes)
+				if err != nil {
+					return fmt.Errorf("header: %w", err)
+				}
+
+				r.Headers = append(r.Headers, h)
+
+				return nil
+			})
+		case f.num == 3 && f.typ == wireVarint: // end_of_stream
+			r.EndOfStream = f.varint != 0
+		}
+
+		return nil
+	})
+}
+
+// ProcessingResponse is the envoy.service.ext_proc.v3.ProcessingResponse message, limited to the fields the error
+// pages need. Without the replacement, the processing continues unchanged.
+type ProcessingResponse struct {
+	Phase   Phase        // the phase of the request it responds to
+	Replace *Replacement // for the headers phases only
+}
+
+// Replacement replaces the headers and the body of the HTTP stream (the CommonResponse message with the
+// CONTINUE_AND_REPLACE status).
+type Replacement struct {
+	SetHeaders    []Header // overwrite the existing ones
+	RemoveHeaders []string
+	Body          []byte
+}
+
+// The values of the enums used by the responses.
+const (
+	statusContinueAndReplace   = 1 // envoy.service.ext_proc.v3.CommonResponse.ResponseStatus
+	appendOverwriteIfExistsAdd = 2 // envoy.config.core.v3.HeaderValueOption.HeaderAppendAction
+)
+
+// Marshal encodes the response.
+func (r ProcessingResponse) Marshal() []byte {
+	var msg []byte // the HeadersResponse, BodyResponse or TrailersResponse message
+
+	if r.Replace != nil && r.Phase.isHeaders() {
+		var mutation []byte // the HeaderMutation message
+
+		for _, h := range r.Replace.SetHeaders {
+			var option = appendBytes(nil, 1, marshalHeader(h))
+
+			mutation = appendBytes(mutation, 1, appendVarint(option, 3, appendOverwriteIfExistsAdd)) //nolint:mnd
+		}
+
+		for _, key := range r.Replace.RemoveHeaders {
+			mutation = appendBytes(mutation, 2, []byte(key)) //nolint:mnd
+		}
+
+		var common = appendVarint(nil, 1, statusContinueAndReplace)
+
+		common = appendBytes(common, 2, mutation)                            //nolint:mnd
+		common = appendBytes(common, 3, appendBytes(nil, 1, r.Replace.Body)) //nolint:mnd // the BodyMutation message
+
+		msg = appendBytes(msg, 1, common)
+	}
+
+	return appendBytes(nil, r.Phase.responseField(), msg)
+}
+
+// Unmarshal decodes the response. The fields the error pages do not set are skipped.
+func (r *ProcessingResponse) Unmarshal(b []byte) error {
+	*r = ProcessingResponse{}
+
+	if err := consumeFields(b, func(f field) error {
+		if p := Phase(f.num + 1); p.isKnown() && f.typ == wireBytes {
+			r.Phase = p
+
+			if p.isHeaders() {
+				return consumeFields(f.bytes, func(hf field) error {
+					if hf.num == 1 && hf.typ == wireBytes {
+						return r.unmarshalCommon(hf.bytes)
+					}
+
+					return nil
+				})
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if r.Phase == 0 {
+		return errors.New("the response has no supported phase")
+	}
+
+	return nil
+}
+
+// unmarshalCommon decodes the envoy.service.ext_proc.v3.CommonResponse message.
+func (r *ProcessingResponse) unmarshalCommon(b []byte) error {
+	var replace Replacement
+
+	return consumeFields(b, func(f field) error {
+		switch {
+		case f.num == 1 && f.typ == wireVarint: // status
+			if f.varint == statusContinueAndReplace {
+				r.Replace = &replace
+			}
+		case f.num == 2 && f.typ == wireBytes: // header_mutation
+			return consumeFields(f.bytes, func(mf field) error {
+				switch {
+				case mf.num == 1 && mf.typ == wireBytes: // set_headers (HeaderValueOption)
+					return consumeFields(mf.bytes, func(of field) error {
+						if of.num == 1 && of.typ == wireBytes {
+							h, err := unmarshalHeader(of.bytes)
+							replace.SetHeaders = append(replace.SetHeaders, h)
+
+							return err
+						}
+
+						return nil
+					})
+				case mf.num == 2 && mf.typ == wireBytes: //nolint:mnd // remove_headers
+					replace.RemoveHeaders = append(replace.RemoveHeaders, string(mf.bytes))
+				}
+
+				return nil
+			})
+		case f.num == 3 && f.typ == wireBytes: // body_mutation
+			return consumeFields(f.bytes, func(bf field) error {
+				if bf.num == 1 && bf.typ == wireBytes {
+					replace.Body = bf.bytes
+				}
+
+				return nil
+			})
+		}
+
+		return nil
+	})
+}
+
+// marshalHeader encodes the envoy.config.core.v3.HeaderValue message. The value is set as the raw one, since Envoy
+// ignores the string value when the raw one is present, and sends the raw values only.
+func marshalHeader(h Header) []byte {
+	return appendBytes(appendBytes(nil, 1, []byte(h.Key)), 3, []byte(h.Value)) //nolint:mnd
+}
+
+// unmarshalHeader decodes the envoy.config.core.v3.HeaderValue message (the raw value takes precedence).
+func unmarshalHeader(b []byte) (Header, error) {
+	var (
+		h      Header
+		raw    []byte
+		hasRaw bool
+	)
+
+	if err := consumeFields(b, func(f field) error {
+		if f.typ != wireBytes {
+			return nil
+		}
+
+		switch f.num {
+		case 1:
+			h.Key = string(f.bytes)
+		case 2: //nolint:mnd
+			h.Value = string(f.bytes)
+		case 3: //nolint:mnd
+			raw, hasRaw = f.bytes, true
+		}
+
+		return nil
+	}); err != nil {
+		return h, err
+	}
+
+	if hasRaw {
+		h.Value = string(raw)
+	}
+
+	return h, nil
+}
@@ -0,0 +1,132 @@
+package extproc_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"gh.tarampamp.am/error-pages/v4/internal/extproc"
+	"gh.tarampamp.am/error-pages/v4/internal/testutil/assert"
+)
+
+func mustDecodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+
+	b, err := hex.DecodeString(s)
+	assert.NoError(t, err)
+
+	return b
+}
+
+func TestProcessingRequest_Unmarshal(t *testing.T) {
+	t.Parallel()
+
+	for name, tt := range map[string]struct {
+		giveHex     string
+		wantRequest extproc.ProcessingRequest
+		wantErr     string
+	}{
+		"response headers (raw and string values)": { // encoded by the generated Envoy code
+			giveHex: "1a1e0a1a0a0e0a073a7374617475731a033430340a080a03782d611201621801",
+			wantRequest: extproc.ProcessingRequest{
+				Phase:       extproc.ResponseHeaders,
+				Headers:     []extproc.Header{{Key: ":status", Value: "404"}, {Key: "x-a", Value: "b"}},
+				EndOfStream: true,
+			},
+		},
+		"response body": {
+			giveHex:     "2a050a03616263",
+			wantRequest: extproc.ProcessingRequest{Phase: extproc.ResponseBody},
+		},
+		"unknown fields are skipped": {
+			giveHex:     "50011200",
+			wantRequest: extproc.ProcessingRequest{Phase: extproc.RequestHeaders},
+		},
+		"no phase": {
+			giveHex: "5001",
+			wantErr: "the request has no supported phase",
+		},
+		"truncated": {
+			giveHex: "1a1e0a1a",
+			wantErr: "malformed length of field 3",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var req extproc.ProcessingRequest
+
+			err := req.Unmarshal(mustDecodeHex(t, tt.giveHex))
+
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.DeepEqual(t, tt.wantRequest, req)
+		})
+	}
+}
+
+func TestProcessingRequest_Marshal(t *testing.T) {
+	t.Parallel()
+
+	var (
+		req = extproc.ProcessingRequest{
+			Phase:   extproc.RequestHeaders,
+			Headers: []extproc.Header{{Key: ":path", Value: "/foo"}, {Key: "accept", Value: "application/json"}},
+		}
+		got extproc.ProcessingRequest
+	)
+
+	assert.NoError(t, got.Unmarshal(req.Marshal()))
+	assert.DeepEqual(t, req, got)
+}
+
+func TestProcessingResponse_Marshal(t *testing.T) {
+	t.Parallel()
+
+	for name, tt := range map[string]struct {
+		give    extproc.ProcessingResponse
+		wantHex string // decoded by the generated Envoy code as expected
+	}{
+		"continue request headers": {
+			give:    extproc.ProcessingResponse{Phase: extproc.RequestHeaders},
+			wantHex: "0a00",
+		},
+		"continue response body": {
+			give:    extproc.ProcessingResponse{Phase: extproc.ResponseBody},
+			wantHex: "2200",
+		},
+		"replace response headers": {
+			give: extproc.ProcessingResponse{Phase: extproc.ResponseHeaders, Replace: &extproc.Replacement{
+				SetHeaders:    []extproc.Header{{Key: "content-type", Value: "text/html"}},
+				RemoveHeaders: []string{"content-encoding"},
+				Body:          []byte("<p>hi</p>"),
+			}},
+			wantHex: "12440a42080112310a1d0a190a0c636f6e74656e742d747970651a09746578742f68746d6c18021210636f6e74656e74" +
+				"2d656e636f64696e671a0b0a093c703e68693c2f703e",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got := tt.give.Marshal()
+
+			assert.Equal(t, tt.wantHex, hex.EncodeToString(got))
+
+			var back extproc.ProcessingResponse
+
+			assert.NoError(t, back.Unmarshal(got))
+			assert.DeepEqual(t, tt.give, back)
+		})
+	}
+}
+
+func TestPhase_String(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "response_headers", extproc.ResponseHeaders.String())
+	assert.Equal(t, "phase(42)", extproc.Phase(42).String())
+}
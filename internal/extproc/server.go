@@ -0,0 +1,261 @@
+// Package extproc implements the Envoy external processing service (envoy.service.ext_proc.v3.ExternalProcessor),
+// which replaces the bodies of the error responses with the error pages.
+//
+// To keep the error pages free of third-party dependencies, the gRPC is served by the HTTP/2 server of the standard
+// library (over h2c), and the few protobuf messages it needs are encoded by hand.
+package extproc
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"gh.tarampamp.am/error-pages/v4/internal/codes"
+	"gh.tarampamp.am/error-pages/v4/internal/httpserver/handlers/error_page"
+	"gh.tarampamp.am/error-pages/v4/internal/logger"
+)
+
+// ProcessMethod is the gRPC method of the external processing stream.
+const ProcessMethod = "/envoy.service.ext_proc.v3.ExternalProcessor/Process"
+
+// MaxMessageSize is the limit of the incoming gRPC message (the gRPC default one).
+const MaxMessageSize = 4 << 20
+
+// The gRPC status codes used by the server.
+const (
+	grpcOK                = 0
+	grpcInvalidArgument   = 3
+	grpcResourceExhausted = 8
+	grpcUnimplemented     = 12
+	grpcInternal          = 13
+)
+
+// grpcError is the error which ends the stream with the gRPC status.
+type grpcError struct {
+	code int
+	msg  string
+}
+
+func (e *grpcError) Error() string { return e.msg }
+
+// New creates a new external processing service handler. It continues every request unchanged, except the
+// responses with the error codes (the codes matching the filter, 4xx and 5xx only) - their headers and body are
+// replaced with the error pages rendered by the errorPage handler (which should respond with the same status code,
+// or the remapped one - then the ":status" is replaced too).
+//
+// The error pages are rendered for the request of the stream (see [error_page.RenderFor]), whose headers are mapped
+// the same way as for the error pages server: the ":path" becomes the X-Original-Uri (unless it is set), and the
+// ":authority" becomes the Host. So, the format negotiation and the details (like X-Request-Id) work as usual.
+func New(log *logger.Logger, filter codes.Filter, errorPage http.Handler) http.Handler {
+	return &server{log: log, filter: filter, errorPage: errorPage}
+}
+
+type server struct {
+	log       *logger.Logger
+	filter    codes.Filter
+	errorPage http.Handler
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || !strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc") {
+		http.Error(w, "gRPC requests only", http.StatusUnsupportedMediaType)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/grpc")
+	w.Header().Set("Trailer", "Grpc-Status, Grpc-Message")
+	w.WriteHeader(http.StatusOK)
+
+	var err error
+
+	if r.URL.Path == ProcessMethod {
+		err = s.process(r.Context(), r.Body, w)
+	} else {
+		err = &grpcError{code: grpcUnimplemented, msg: "unknown method " + r.URL.Path}
+	}
+
+	var status, msg = grpcOK, ""
+
+	if err != nil {
+		var gErr *grpcError
+		if !errors.As(err, &gErr) {
+			gErr = &grpcError{code: grpcInternal, msg: err.Error()}
+		}
+
+		if r.Context().Err() == nil {
+			s.log.Warn("External processing stream failed", logger.Int("status", gErr.code), logger.Error(err))
+		}
+
+		status, msg = gErr.code, gErr.msg
+	}
+
+	w.Header().Set("Grpc-Status", strconv.Itoa(status))
+	w.Header().Set("Grpc-Message", url.PathEscape(msg)) // the message is percent-encoded
+}
+
+// process handles the messages of the stream until the client closes it.
+func (s *server) process(ctx context.Context, in io.Reader, w http.ResponseWriter) error {
+	var (
+		rc      = http.NewResponseController(w)
+		request []Header // the request headers of the stream
+	)
+
+	if err := rc.Flush(); err != nil { // send the response headers, so the client starts streaming
+		return err
+	}
+
+	for {
+		msg, err := readMessage(in)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil // the stream is closed by the client
+			}
+
+			return err
+		}
+
+		var req ProcessingRequest
+
+		if uErr := req.Unmarshal(msg); uErr != nil {
+			return &grpcError{code: grpcInvalidArgument, msg: "wrong processing request: " + uErr.Error()}
+		}
+
+		var resp = ProcessingResponse{Phase: req.Phase}
+
+		switch req.Phase { //nolint:exhaustive // the other phases are continued unchanged
+		case RequestHeaders:
+			request = req.Headers
+		case ResponseHeaders:
+			resp.Replace = s.replacement(ctx, request, req.Headers)
+		}
+
+		if _, wErr := w.Write(frameMessage(resp.Marshal())); wErr != nil {
+			return wErr
+		}
+
+		if fErr := rc.Flush(); fErr != nil {
+			return fErr
+		}
+	}
+}
+
+// replacement returns the replacement of the response with the error page, or nil if the response is kept.
+func (s *server) replacement(ctx context.Context, request, response []Header) *Replacement {
+	var status string
+
+	for _, h := range response {
+		if h.Key == ":status" {
+			status = h.Value
+		}
+	}
+
+	code, err := strconv.ParseUint(status, 10, 16)
+	if err != nil || code < http.StatusBadRequest || !s.filter.Match(uint16(code)) {
+		return nil
+	}
+
+	sent, header, body := error_page.RenderFor(s.errorPage, newRequest(ctx, request), int(code))
+
+	var replace = Replacement{
+		RemoveHeaders: []string{"content-encoding"}, // the page is not compressed (Envoy may compress it on its own)
+		Body:          body.Bytes(),
+	}
+
+	if sent != int(code) { // the code is remapped (see the --map-code), so the status is replaced too
+		replace.SetHeaders = append(replace.SetHeaders, Header{Key: ":status", Value: strconv.Itoa(sent)})
+	}
+
+	for name, values := range header {
+		if len(values) > 0 {
+			replace.SetHeaders = append(replace.SetHeaders, Header{Key: strings.ToLower(name), Value: values[0]})
+		}
+	}
+
+	s.log.Debug("Replacing the error response", logger.Int("code", int(code)))
+
+	return &replace
+}
+
+// newRequest creates the request to render the error page for, from the request headers of the stream.
+func newRequest(ctx context.Context, headers []Header) *http.Request {
+	var r = (&http.Request{
+		Method:     http.MethodGet,
+		URL:        &url.URL{Path: "/"},
+		Proto:      "HTTP/2.0",
+		ProtoMajor: 2, //nolint:mnd
+		Header:     make(http.Header, len(headers)),
+		Body:       http.NoBody,
+	}).WithContext(ctx)
+
+	for _, h := range headers {
+		switch h.Key {
+		case ":method":
+			r.Method = h.Value
+		case ":authority":
+			r.Host = h.Value
+		case ":path":
+			if u, err := url.ParseRequestURI(h.Value); err == nil {
+				r.URL = u
+			}
+
+			r.Header.Set("X-Original-Uri", h.Value) // unless set by the headers (see below)
+		default:
+			if !strings.HasPrefix(h.Key, ":") {
+				r.Header.Add(h.Key, h.Value)
+			}
+		}
+	}
+
+	if r.Host == "" {
+		r.Host = r.Header.Get("Host")
+	}
+
+	r.Header.Del("Accept-Encoding") // the replaced body must not be compressed
+
+	return r
+}
+
+// readMessage reads the length-prefixed gRPC message.
+func readMessage(r io.Reader) ([]byte, error) {
+	var prefix [5]byte
+
+	if _, err := io.ReadFull(r, prefix[:]); err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, &grpcError{code: grpcInvalidArgument, msg: "truncated message"}
+		}
+
+		return nil, err
+	}
+
+	if prefix[0] != 0 {
+		return nil, &grpcError{code: grpcUnimplemented, msg: "compressed messages are not supported"}
+	}
+
+	size := binary.BigEndian.Uint32(prefix[1:])
+	if size > MaxMessageSize {
+		return nil, &grpcError{
+			code: grpcResourceExhausted,
+			msg:  fmt.Sprintf("the message is %d bytes, which exceeds the limit of %d bytes", size, MaxMessageSize),
+		}
+	}
+
+	var msg = make([]byte, size)
+
+	if _, err := io.ReadFull(r, msg); err != nil {
+		return nil, &grpcError{code: grpcInvalidArgument, msg: "truncated message"}
+	}
+
+	return msg, nil
+}
+
+// frameMessage returns the length-prefixed (uncompressed) gRPC message.
+func frameMessage(msg []byte) []byte {
+	return append(binary.BigEndian.AppendUint32([]byte{0}, uint32(len(msg))), msg...) //nolint:gosec
+}
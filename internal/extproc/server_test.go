@@ -0,0 +1,231 @@
+package extproc_test
+
+import (
+	"encoding/binary"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gh.tarampamp.am/error-pages/v4/internal/codes"
+	"gh.tarampamp.am/error-pages/v4/internal/extproc"
+	"gh.tarampamp.am/error-pages/v4/internal/formats"
+	"gh.tarampamp.am/error-pages/v4/internal/httpserver/handlers/error_page"
+	"gh.tarampamp.am/error-pages/v4/internal/logger"
+	tpl "gh.tarampamp.am/error-pages/v4/internal/template"
+	"gh.tarampamp.am/error-pages/v4/internal/testutil/assert"
+)
+
+// stream is the client side of the external processing stream.
+type stream struct {
+	t    *testing.T
+	in   *io.PipeWriter
+	resp *http.Response
+}
+
+// newStream starts the external processing server (the 4xx and 5xx codes are replaced with the pages rendered
+// as "{format} {code} {original uri} {host} {request id}", and 403 is remapped to 404 under /admin) and opens the
+// stream to it.
+func newStream(t *testing.T, method string) *stream {
+	t.Helper()
+
+	mapping, mErr := error_page.ParseCodeMapping("403=404@/admin")
+	assert.NoError(t, mErr)
+
+	errorPage := error_page.New(
+		logger.NewNop(),
+		404,
+		true,
+		nil,
+		mapping,
+		nil,
+		func(uint16) (codes.Description, bool) { return codes.Description{}, false },
+		func(f formats.Format) (*tpl.Template, error) {
+			return tpl.New(f.String() + " {{ .StatusCode }} {{ .OriginalURI }} {{ .Host }} {{ .RequestID }}")
+		},
+		true,
+		false,
+		"",
+		nil,
+		nil,
+	)
+
+	filter, fErr := codes.ParseFilter("4xx,5xx")
+	assert.NoError(t, fErr)
+
+	srv := httptest.NewUnstartedServer(extproc.New(logger.NewNop(), filter, errorPage))
+	srv.Config.Protocols = new(http.Protocols)
+	srv.Config.Protocols.SetUnencryptedHTTP2(true)
+	srv.Start()
+
+	t.Cleanup(srv.Close)
+
+	var (
+		protocols = new(http.Protocols)
+		pr, pw    = io.Pipe()
+	)
+
+	protocols.SetUnencryptedHTTP2(true)
+
+	req, rErr := http.NewRequestWithContext(t.Context(), http.MethodPost, srv.URL+method, pr)
+	assert.NoError(t, rErr)
+
+	req.Header.Set("Content-Type", "application/grpc")
+
+	resp, err := (&http.Client{Transport: &http.Transport{Protocols: protocols}}).Do(req)
+	assert.NoError(t, err)
+
+	t.Cleanup(func() { _ = resp.Body.Close() })
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/grpc", resp.Header.Get("Content-Type"))
+
+	return &stream{t: t, in: pw, resp: resp}
+}
+
+// send sends the request and returns the response.
+func (s *stream) send(req extproc.ProcessingRequest) extproc.ProcessingResponse {
+	s.t.Helper()
+
+	msg := req.Marshal()
+
+	_, err := s.in.Write(append(binary.BigEndian.AppendUint32([]byte{0}, uint32(len(msg))), msg...)) //nolint:gosec
+	assert.NoError(s.t, err)
+
+	var prefix [5]byte
+
+	_, err = io.ReadFull(s.resp.Body, prefix[:])
+	assert.NoError(s.t, err)
+
+	var body = make([]byte, binary.BigEndian.Uint32(prefix[1:]))
+
+	_, err = io.ReadFull(s.resp.Body, body)
+	assert.NoError(s.t, err)
+
+	var resp extproc.ProcessingResponse
+
+	assert.NoError(s.t, resp.Unmarshal(body))
+
+	return resp
+}
+
+// close closes the stream and returns the gRPC status.
+func (s *stream) close() string {
+	s.t.Helper()
+
+	assert.NoError(s.t, s.in.Close())
+
+	_, err := io.Copy(io.Discard, s.resp.Body)
+	assert.NoError(s.t, err)
+
+	return s.resp.Trailer.Get("Grpc-Status")
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	var s = newStream(t, extproc.ProcessMethod)
+
+	// the request headers are continued unchanged, and kept to render the page for
+	assert.DeepEqual(t, extproc.ProcessingResponse{Phase: extproc.RequestHeaders}, s.send(extproc.ProcessingRequest{
+		Phase: extproc.RequestHeaders,
+		Headers: []extproc.Header{
+			{Key: ":method", Value: http.MethodPost},
+			{Key: ":path", Value: "/api/users.json?id=1"},
+			{Key: ":authority", Value: "example.com"},
+			{Key: "x-request-id", Value: "abc123"},
+			{Key: "accept-encoding", Value: "gzip"},
+		},
+	}))
+
+	// successful responses are kept
+	assert.DeepEqual(t, extproc.ProcessingResponse{Phase: extproc.ResponseHeaders}, s.send(extproc.ProcessingRequest{
+		Phase:   extproc.ResponseHeaders,
+		Headers: []extproc.Header{{Key: ":status", Value: "200"}},
+	}))
+
+	// the error ones are replaced, the format is negotiated by the request path
+	var resp = s.send(extproc.ProcessingRequest{
+		Phase:   extproc.ResponseHeaders,
+		Headers: []extproc.Header{{Key: ":status", Value: "503"}, {Key: "content-type", Value: "text/plain"}},
+	})
+
+	assert.Equal(t, extproc.ResponseHeaders, resp.Phase)
+	assert.NotNil(t, resp.Replace)
+	assert.Equal(t, "json 503 /api/users.json?id=1 example.com abc123", string(resp.Replace.Body))
+	assert.DeepEqual(t, []string{"content-encoding"}, resp.Replace.RemoveHeaders)
+
+	var headers = make(map[string]string, len(resp.Replace.SetHeaders))
+
+	for _, h := range resp.Replace.SetHeaders {
+		headers[h.Key] = h.Value
+	}
+
+	assert.Equal(t, "application/json; charset=utf-8", headers["content-type"])
+	assert.Equal(t, "48", headers["content-length"])
+	assert.Equal(t, "120", headers["retry-after"])
+	assert.Equal(t, "", headers[":status"]) // the status is kept
+
+	// other phases are continued unchanged
+	assert.DeepEqual(t, extproc.ProcessingResponse{Phase: extproc.ResponseBody}, s.send(extproc.ProcessingRequest{
+		Phase: extproc.ResponseBody,
+	}))
+
+	assert.Equal(t, "0", s.close())
+}
+
+func TestNew_WithoutRequestHeaders(t *testing.T) {
+	t.Parallel()
+
+	var s = newStream(t, extproc.ProcessMethod)
+
+	resp := s.send(extproc.ProcessingRequest{
+		Phase:   extproc.ResponseHeaders,
+		Headers: []extproc.Header{{Key: ":status", Value: "404"}},
+	})
+
+	assert.NotNil(t, resp.Replace)
+	assert.True(t, strings.HasPrefix(string(resp.Replace.Body), "text 404"))
+	assert.Equal(t, "0", s.close())
+}
+
+func TestNew_RemappedCode(t *testing.T) {
+	t.Parallel()
+
+	var s = newStream(t, extproc.ProcessMethod)
+
+	s.send(extproc.ProcessingRequest{
+		Phase:   extproc.RequestHeaders,
+		Headers: []extproc.Header{{Key: ":path", Value: "/admin/users"}, {Key: ":authority", Value: "example.com"}},
+	})
+
+	resp := s.send(extproc.ProcessingRequest{
+		Phase:   extproc.ResponseHeaders,
+		Headers: []extproc.Header{{Key: ":status", Value: "403"}},
+	})
+
+	assert.NotNil(t, resp.Replace)
+	assert.Equal(t, "text 404 /admin/users example.com ", string(resp.Replace.Body))
+	assert.DeepEqual(t, extproc.Header{Key: ":status", Value: "404"}, resp.Replace.SetHeaders[0])
+	assert.Equal(t, "0", s.close())
+}
+
+func TestNew_UnknownMethod(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "12", newStream(t, "/foo.Bar/Baz").close()) // UNIMPLEMENTED
+}
+
+func TestNew_NotGRPC(t *testing.T) {
+	t.Parallel()
+
+	var (
+		rec = httptest.NewRecorder()
+		req = httptest.NewRequest(http.MethodGet, extproc.ProcessMethod, http.NoBody)
+	)
+
+	extproc.New(logger.NewNop(), codes.Filter{}, http.NotFoundHandler()).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+}
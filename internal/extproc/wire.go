@@ -0,0 +1,89 @@
+package extproc
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// The protobuf wire types used by the ext_proc messages (the fixed-size ones are skipped only).
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// field is the decoded protobuf field.
+type field struct {
+	num    int
+	typ    int
+	varint uint64 // for the varint fields
+	bytes  []byte // for the length-delimited fields (strings, bytes and messages)
+}
+
+// consumeFields calls the fn for every field of the protobuf message, in the wire order.
+func consumeFields(b []byte, fn func(f field) error) error {
+	for len(b) > 0 {
+		tag, n := binary.Uvarint(b)
+		if n <= 0 {
+			return errors.New("malformed field tag")
+		}
+
+		b = b[n:]
+
+		var f = field{num: int(tag >> 3), typ: int(tag & 7)} //nolint:mnd,gosec
+
+		if f.num <= 0 {
+			return fmt.Errorf("wrong field number %d", f.num)
+		}
+
+		switch f.typ {
+		case wireVarint:
+			if f.varint, n = binary.Uvarint(b); n <= 0 {
+				return fmt.Errorf("malformed varint of field %d", f.num)
+			}
+
+			b = b[n:]
+		case wireBytes:
+			size, sn := binary.Uvarint(b)
+			if sn <= 0 || size > uint64(len(b)-sn) {
+				return fmt.Errorf("malformed length of field %d", f.num)
+			}
+
+			f.bytes, b = b[sn:sn+int(size)], b[sn+int(size):] //nolint:gosec // checked above
+		case wireFixed64, wireFixed32:
+			size := 8 //nolint:mnd
+			if f.typ == wireFixed32 {
+				size = 4
+			}
+
+			if len(b) < size {
+				return fmt.Errorf("malformed value of field %d", f.num)
+			}
+
+			b = b[size:]
+		default:
+			return fmt.Errorf("unsupported wire type %d of field %d", f.typ, f.num)
+		}
+
+		if err := fn(f); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// appendTag appends the tag of the field.
+func appendTag(b []byte, num, typ int) []byte { return binary.AppendUvarint(b, uint64(num<<3|typ)) } //nolint:gosec,mnd
+
+// appendVarint appends the varint field.
+func appendVarint(b []byte, num int, v uint64) []byte {
+	return binary.AppendUvarint(appendTag(b, num, wireVarint), v)
+}
+
+// appendBytes appends the length-delimited field (a string, bytes or an encoded message).
+func appendBytes(b []byte, num int, v []byte) []byte {
+	return append(binary.AppendUvarint(appendTag(b, num, wireBytes), uint64(len(v))), v...)
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"encoding/xml"
 	"html"
+	"strings"
 )
 
 // Format is an enumeration of supported content formats for error page responses.
@@ -15,8 +16,93 @@ const (
 	JSONFormat                    // json
 	XMLFormat                     // xml
 	HTMLFormat                    // html
+	YAMLFormat                    // yaml
+	MarkdownFormat                // markdown
+	SVGFormat                     // svg image
+	PNGFormat                     // png image (rendered without a template)
 )
 
+// All returns all supported formats.
+func All() []Format {
+	return []Format{PlainTextFormat, JSONFormat, XMLFormat, HTMLFormat, YAMLFormat, MarkdownFormat, SVGFormat, PNGFormat}
+}
+
+// IsImage reports whether the format is an image one.
+func (f Format) IsImage() bool { return f == SVGFormat || f == PNGFormat }
+
+// String returns the short name of the format (e.g. "json"), which can be parsed back using [FromString].
+func (f Format) String() string {
+	switch f {
+	case PlainTextFormat:
+		return "text"
+	case JSONFormat:
+		return "json"
+	case XMLFormat:
+		return "xml"
+	case HTMLFormat:
+		return "html"
+	case YAMLFormat:
+		return "yaml"
+	case MarkdownFormat:
+		return "markdown"
+	case SVGFormat:
+		return "svg"
+	case PNGFormat:
+		return "png"
+	}
+
+	return "unknown"
+}
+
+// FromString returns the format for the given short name (case-insensitive). Besides the names returned by
+// [Format.String], the common file extensions ("txt", "htm", "yml", "md") are accepted too.
+func FromString(s string) (Format, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "text", "txt", "plain", "plaintext":
+		return PlainTextFormat, true
+	case "json":
+		return JSONFormat, true
+	case "xml":
+		return XMLFormat, true
+	case "html", "htm":
+		return HTMLFormat, true
+	case "yaml", "yml":
+		return YAMLFormat, true
+	case "markdown", "md":
+		return MarkdownFormat, true
+	case "svg":
+		return SVGFormat, true
+	case "png":
+		return PNGFormat, true
+	}
+
+	return Format(0), false
+}
+
+// Extension returns the file extension (with the leading dot) for the format, or an empty string for unknown formats.
+func (f Format) Extension() string {
+	switch f {
+	case PlainTextFormat:
+		return ".txt"
+	case JSONFormat:
+		return ".json"
+	case XMLFormat:
+		return ".xml"
+	case HTMLFormat:
+		return ".html"
+	case YAMLFormat:
+		return ".yaml"
+	case MarkdownFormat:
+		return ".md"
+	case SVGFormat:
+		return ".svg"
+	case PNGFormat:
+		return ".png"
+	}
+
+	return ""
+}
+
 // ContentType returns the MIME content type string for the format.
 func (f Format) ContentType() string {
 	switch f {
@@ -28,6 +114,14 @@ func (f Format) ContentType() string {
 		return "application/json; charset=utf-8"
 	case XMLFormat:
 		return "application/xml; charset=utf-8"
+	case YAMLFormat:
+		return "application/yaml; charset=utf-8"
+	case MarkdownFormat:
+		return "text/markdown; charset=utf-8"
+	case SVGFormat:
+		return "image/svg+xml; charset=utf-8"
+	case PNGFormat:
+		return "image/png"
 	}
 
 	return ""
@@ -52,7 +146,19 @@ func (f Format) FormatError(errStr string) []byte {
 	case HTMLFormat:
 		return []byte("<!DOCTYPE html>\n" +
 			"<html><head><meta charset=\"UTF-8\"></head><body>\n" + html.EscapeString(errStr) + "\n</body></html>")
-	case PlainTextFormat:
+	case YAMLFormat:
+		b, _ := json.Marshal(errStr) //nolint:errcheck,errchkjson // the JSON string is a valid YAML scalar
+
+		return append([]byte("error: "), b...)
+	case SVGFormat:
+		var buf bytes.Buffer
+		buf.WriteString("<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"400\" height=\"300\">" +
+			"<text x=\"8\" y=\"24\" font-family=\"sans-serif\" font-size=\"14\">")
+		_ = xml.EscapeText(&buf, []byte(errStr)) //nolint:errcheck
+		buf.WriteString("</text></svg>")
+
+		return buf.Bytes()
+	case PlainTextFormat, MarkdownFormat, PNGFormat: // the PNG images are not rendered from the templates
 		return []byte(errStr)
 	}
 
@@ -7,6 +7,110 @@ import (
 	"gh.tarampamp.am/error-pages/v4/internal/testutil/assert"
 )
 
+func TestAll(t *testing.T) {
+	t.Parallel()
+
+	var seen = make(map[formats.Format]struct{})
+
+	for _, f := range formats.All() {
+		_, dup := seen[f]
+		assert.False(t, dup)
+
+		seen[f] = struct{}{}
+
+		assert.True(t, f.ContentType() != "")
+	}
+
+	assert.Equal(t, 8, len(seen))
+}
+
+func TestFormat_String(t *testing.T) {
+	t.Parallel()
+
+	for name, tt := range map[string]struct {
+		give formats.Format
+		want string
+	}{
+		"plain text": {give: formats.PlainTextFormat, want: "text"},
+		"html":       {give: formats.HTMLFormat, want: "html"},
+		"json":       {give: formats.JSONFormat, want: "json"},
+		"xml":        {give: formats.XMLFormat, want: "xml"},
+		"yaml":       {give: formats.YAMLFormat, want: "yaml"},
+		"markdown":   {give: formats.MarkdownFormat, want: "markdown"},
+		"svg":        {give: formats.SVGFormat, want: "svg"},
+		"png":        {give: formats.PNGFormat, want: "png"},
+		"unknown":    {give: formats.Format(255), want: "unknown"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, tt.give.String())
+		})
+	}
+}
+
+func TestFromString(t *testing.T) {
+	t.Parallel()
+
+	for name, tt := range map[string]struct {
+		give     string
+		want     formats.Format
+		wantOkay bool
+	}{
+		"text":           {give: "text", want: formats.PlainTextFormat, wantOkay: true},
+		"txt":            {give: "txt", want: formats.PlainTextFormat, wantOkay: true},
+		"json":           {give: "json", want: formats.JSONFormat, wantOkay: true},
+		"xml upper-case": {give: "XML", want: formats.XMLFormat, wantOkay: true},
+		"html":           {give: " html ", want: formats.HTMLFormat, wantOkay: true},
+		"htm":            {give: "htm", want: formats.HTMLFormat, wantOkay: true},
+		"yml":            {give: "yml", want: formats.YAMLFormat, wantOkay: true},
+		"md":             {give: "md", want: formats.MarkdownFormat, wantOkay: true},
+		"svg":            {give: "svg", want: formats.SVGFormat, wantOkay: true},
+		"png upper-case": {give: "PNG", want: formats.PNGFormat, wantOkay: true},
+		"empty":          {give: ""},
+		"unknown":        {give: "foo"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := formats.FromString(tt.give)
+			assert.Equal(t, tt.wantOkay, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	for _, f := range formats.All() { // round-trip
+		got, ok := formats.FromString(f.String())
+		assert.True(t, ok)
+		assert.Equal(t, f, got)
+	}
+}
+
+func TestFormat_Extension(t *testing.T) {
+	t.Parallel()
+
+	for name, tt := range map[string]struct {
+		give formats.Format
+		want string
+	}{
+		"plain text": {give: formats.PlainTextFormat, want: ".txt"},
+		"html":       {give: formats.HTMLFormat, want: ".html"},
+		"json":       {give: formats.JSONFormat, want: ".json"},
+		"xml":        {give: formats.XMLFormat, want: ".xml"},
+		"yaml":       {give: formats.YAMLFormat, want: ".yaml"},
+		"markdown":   {give: formats.MarkdownFormat, want: ".md"},
+		"svg":        {give: formats.SVGFormat, want: ".svg"},
+		"png":        {give: formats.PNGFormat, want: ".png"},
+		"unknown":    {give: formats.Format(255), want: ""},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, tt.give.Extension())
+		})
+	}
+}
+
 func TestFormat_ContentType(t *testing.T) {
 	t.Parallel()
 
@@ -18,6 +122,10 @@ func TestFormat_ContentType(t *testing.T) {
 		"html":       {give: formats.HTMLFormat, want: "text/html; charset=utf-8"},
 		"json":       {give: formats.JSONFormat, want: "application/json; charset=utf-8"},
 		"xml":        {give: formats.XMLFormat, want: "application/xml; charset=utf-8"},
+		"yaml":       {give: formats.YAMLFormat, want: "application/yaml; charset=utf-8"},
+		"markdown":   {give: formats.MarkdownFormat, want: "text/markdown; charset=utf-8"},
+		"svg":        {give: formats.SVGFormat, want: "image/svg+xml; charset=utf-8"},
+		"png":        {give: formats.PNGFormat, want: "image/png"},
 		"unknown":    {give: formats.Format(255), want: ""},
 	} {
 		t.Run(name, func(t *testing.T) {
@@ -98,6 +206,22 @@ func TestFormat_FormatError(t *testing.T) {
 			want: "<!DOCTYPE html>\n<html><head><meta charset=\"UTF-8\"></head><body>\n" +
 				"&lt;script&gt;alert(&#34;xss&#34;)&lt;/script&gt;\n</body></html>",
 		},
+		"yaml/special chars escaped": {
+			giveFormat: formats.YAMLFormat,
+			giveErr:    "oops: \"quoted\"\nnew line",
+			want:       `error: "oops: \"quoted\"\nnew line"`,
+		},
+		"markdown/simple": {
+			giveFormat: formats.MarkdownFormat,
+			giveErr:    "page not found",
+			want:       "page not found",
+		},
+		"svg/special chars escaped": {
+			giveFormat: formats.SVGFormat,
+			giveErr:    "<b>oops</b>",
+			want: `<svg xmlns="http://www.w3.org/2000/svg" width="400" height="300">` +
+				`<text x="8" y="24" font-family="sans-serif" font-size="14">&lt;b&gt;oops&lt;/b&gt;</text></svg>`,
+		},
 		"unknown format": {
 			giveFormat: formats.Format(255),
 			giveErr:    "something went wrong",
@@ -111,3 +235,11 @@ func TestFormat_FormatError(t *testing.T) {
 		})
 	}
 }
+
+func TestFormat_IsImage(t *testing.T) {
+	t.Parallel()
+
+	for _, f := range formats.All() {
+		assert.Equal(t, f == formats.SVGFormat || f == formats.PNGFormat, f.IsImage())
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"gh.tarampamp.am/error-pages/v4/internal/httpserver/handlers/error_page"
 	"gh.tarampamp.am/error-pages/v4/internal/httpserver/handlers/favicon"
 	"gh.tarampamp.am/error-pages/v4/internal/httpserver/handlers/live"
+	"gh.tarampamp.am/error-pages/v4/internal/httpserver/handlers/ready"
 	"gh.tarampamp.am/error-pages/v4/internal/httpserver/handlers/version"
 	"gh.tarampamp.am/error-pages/v4/internal/httpserver/middleware"
 	"gh.tarampamp.am/error-pages/v4/internal/logger"
@@ -20,12 +21,16 @@ func NewHandler(
 	defaultCode uint16,
 	respondSameStatus bool,
 	proxyHeaders []string,
+	codeMapping error_page.CodeMapping,
+	redirects error_page.Redirects,
 	describer error_page.CodeDescriber,
 	templater error_page.Templater,
 	showDetails bool,
 	l10nDisabled bool,
 	homepageURL string,
 	links []tpl.Link,
+	source *error_page.Source,
+	isReady func() bool,
 ) http.Handler {
 	const (
 		healthzEndpoint    = "/healthz"
@@ -33,11 +38,15 @@ func NewHandler(
 		healthLiveEndpoint = "/health/live"
 		liveEndpoint       = "/live"
 
+		readyEndpoint       = "/ready"
+		healthReadyEndpoint = "/health/ready"
+
 		versionEndpoint = "/version"
 		faviconEndpoint = "/favicon.ico"
 	)
 
 	liveHandler := live.New()
+	readyHandler := ready.New(isReady)
 	versionHandler := version.New(appmeta.Version())
 	faviconHandler := favicon.New()
 	errorPagesHandler := error_page.New(
@@ -45,12 +54,15 @@ func NewHandler(
 		defaultCode,
 		respondSameStatus,
 		proxyHeaders,
+		codeMapping,
+		redirects,
 		describer,
 		templater,
 		showDetails,
 		l10nDisabled,
 		homepageURL,
 		links,
+		source,
 	)
 
 	return middleware.Apply(
@@ -61,6 +73,11 @@ func NewHandler(
 
 				return
 
+			case readyEndpoint, healthReadyEndpoint:
+				readyHandler.ServeHTTP(w, r)
+
+				return
+
 			case versionEndpoint:
 				versionHandler.ServeHTTP(w, r)
 
@@ -77,11 +94,13 @@ func NewHandler(
 		}),
 		middleware.NewInjectLog(log),
 		middleware.NewAccessLog(logger.InfoLevel, func(r *http.Request) bool {
-			// skip logging for the healthz endpoint
+			// skip logging for the healthz and readiness endpoints
 			return r.URL.Path == healthzEndpoint ||
 				r.URL.Path == healthEndpoint ||
 				r.URL.Path == healthLiveEndpoint ||
-				r.URL.Path == liveEndpoint
+				r.URL.Path == liveEndpoint ||
+				r.URL.Path == readyEndpoint ||
+				r.URL.Path == healthReadyEndpoint
 		}),
 	)
 }
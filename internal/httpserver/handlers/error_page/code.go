@@ -19,18 +19,26 @@ import (
 // and the headers:
 //   - X-Code: {code}					-> {code}, true
 //
-// The code must be a number between 1 and 999 (inclusive). URL path takes priority over headers.
-func getCodeFromRequest(r *http.Request) (uint16, bool) {
-	// try the first URL path segment first: "/404/page" -> "404", "/404.json" -> "404"
-	segment, _, _ := strings.Cut(strings.TrimLeft(r.URL.Path, "/"), "/")
-
-	// strip extension without case-folding: numeric codes have no case
-	if i := strings.LastIndexByte(segment, '.'); i >= 0 {
-		segment = segment[:i]
+// The code must be a number between 1 and 999 (inclusive). URL path takes priority over headers, unless the path is
+// the original one (see [Source]) - then only the headers are checked. The code set by [RenderFor] takes priority
+// over both.
+func getCodeFromRequest(r *http.Request, originalPath bool) (uint16, bool) {
+	if code, ok := r.Context().Value(codeKey{}).(int); ok && code > 0 && code <= 999 {
+		return uint16(code), true
 	}
 
-	if code, err := strconv.ParseUint(segment, 10, 16); err == nil && code > 0 && code <= 999 {
-		return uint16(code), true
+	if !originalPath {
+		// try the first URL path segment first: "/404/page" -> "404", "/404.json" -> "404"
+		segment, _, _ := strings.Cut(strings.TrimLeft(r.URL.Path, "/"), "/")
+
+		// strip extension without case-folding: numeric codes have no case
+		if i := strings.LastIndexByte(segment, '.'); i >= 0 {
+			segment = segment[:i]
+		}
+
+		if code, err := strconv.ParseUint(segment, 10, 16); err == nil && code > 0 && code <= 999 {
+			return uint16(code), true
+		}
 	}
 
 	// fall back to the X-Code header (ingress-nginx sets this on error responses)
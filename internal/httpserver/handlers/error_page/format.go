@@ -33,6 +33,14 @@ func getFormatFromRequest(r *http.Request) (formats.Format, bool) {
 			return formats.HTMLFormat, true
 		case strings.EqualFold(ext, ".txt"):
 			return formats.PlainTextFormat, true
+		case strings.EqualFold(ext, ".yaml"), strings.EqualFold(ext, ".yml"):
+			return formats.YAMLFormat, true
+		case strings.EqualFold(ext, ".md"), strings.EqualFold(ext, ".markdown"):
+			return formats.MarkdownFormat, true
+		case strings.EqualFold(ext, ".svg"):
+			return formats.SVGFormat, true
+		case strings.EqualFold(ext, ".png"):
+			return formats.PNGFormat, true
 		}
 	}
 
@@ -159,9 +167,10 @@ func parseQWeight(params string) int {
 }
 
 // mimeTypeToFormat maps a bare MIME type (params already stripped by callers) to a Format constant.
-// MIME types follow the "type/subtype" or "type/subtype+suffix" structure (RFC 6838).
+// MIME types follow the "type/subtype" or "type/subtype+suffix" structure (RFC 6838). Any image type is mapped to
+// the SVG format, except PNG, since the SVG placeholder can be displayed wherever an image is expected.
 func mimeTypeToFormat(mimeType string) (formats.Format, bool) {
-	_, sub, ok := strings.Cut(mimeType, "/")
+	typ, sub, ok := strings.Cut(mimeType, "/")
 	if !ok {
 		return formats.Format(0), false
 	}
@@ -169,6 +178,10 @@ func mimeTypeToFormat(mimeType string) (formats.Format, bool) {
 	name, suffix, _ := strings.Cut(sub, "+")
 
 	switch {
+	case strings.EqualFold(typ, "image") && (strings.EqualFold(name, "png") || strings.EqualFold(name, "apng")):
+		return formats.PNGFormat, true
+	case strings.EqualFold(typ, "image"): // image/svg+xml, image/webp, image/*
+		return formats.SVGFormat, true
 	case strings.EqualFold(name, "json"): // application/json, text/json
 		return formats.JSONFormat, true
 	case strings.EqualFold(name, "xml") || strings.EqualFold(suffix, "xml"): // application/xml, application/xhtml+xml
@@ -177,6 +190,10 @@ func mimeTypeToFormat(mimeType string) (formats.Format, bool) {
 		return formats.HTMLFormat, true
 	case strings.EqualFold(name, "plain"): // text/plain
 		return formats.PlainTextFormat, true
+	case strings.EqualFold(name, "yaml") || strings.EqualFold(name, "x-yaml") || strings.EqualFold(suffix, "yaml"):
+		return formats.YAMLFormat, true // application/yaml, application/x-yaml, text/yaml
+	case strings.EqualFold(name, "markdown") || strings.EqualFold(name, "x-markdown"): // text/markdown
+		return formats.MarkdownFormat, true
 	}
 
 	return formats.Format(0), false
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"compress/gzip"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -11,6 +12,7 @@ import (
 	"gh.tarampamp.am/error-pages/v4/internal/codes"
 	"gh.tarampamp.am/error-pages/v4/internal/formats"
 	"gh.tarampamp.am/error-pages/v4/internal/logger"
+	"gh.tarampamp.am/error-pages/v4/internal/raster"
 	tpl "gh.tarampamp.am/error-pages/v4/internal/template"
 )
 
@@ -31,12 +33,15 @@ func New( //nolint:funlen
 	defaultCode uint16,
 	respondSameStatus bool,
 	proxyHeaders []string,
+	codeMapping CodeMapping,
+	redirects Redirects,
 	codeDescriber CodeDescriber,
 	templater Templater,
 	showDetails bool,
 	l10nDisabled bool,
 	homepageURL string,
 	links []tpl.Link,
+	source *Source, // nil means the source is detected by the request headers
 ) http.Handler {
 	// bufPool reuses the render buffer across requests to avoid per-request heap allocation for the response body
 	bufPool := sync.Pool{New: func() any { return new(bytes.Buffer) }}
@@ -49,11 +54,22 @@ func New( //nolint:funlen
 			return
 		}
 
-		code, codeOk := getCodeFromRequest(r)
+		src := source
+		if src == nil {
+			src = detectSource(r.Header)
+		}
+
+		code, codeOk := getCodeFromRequest(r, src.original)
 		if !codeOk {
 			code = defaultCode
 		}
 
+		originalCode := code
+
+		if len(codeMapping) > 0 {
+			code = codeMapping.Map(code, src.requestURI(r))
+		}
+
 		contentFormat, formatOk := getFormatFromRequest(r)
 		if !formatOk {
 			contentFormat = formats.PlainTextFormat // as default, curl-like clients prefer plain text over HTML
@@ -70,6 +86,25 @@ func New( //nolint:funlen
 			}
 		}
 
+		if target := redirects.find(code); target != nil {
+			w.Header().Add("Vary", "Accept") // the API clients get the error page, the browsers are redirected
+
+			if contentFormat == formats.HTMLFormat {
+				var data = tpl.Data{StatusCode: code, OriginalStatusCode: originalCode, HomepageURL: homepageURL}
+
+				src.fill(r, &data)
+
+				location, err := target.Render(data)
+				if err == nil {
+					http.Redirect(w, r, strings.TrimSpace(string(location)), http.StatusFound)
+
+					return
+				}
+
+				log.Error("Failed to render the redirect URL", logger.Error(err)) // render the error page instead
+			}
+		}
+
 		w.Header().Set("Content-Type", contentFormat.ContentType())
 		w.Header().Set("X-Robots-Tag", "noindex, nofollow, nosnippet, noarchive")
 
@@ -91,28 +126,37 @@ func New( //nolint:funlen
 			}
 		}
 
+		// the PNG images are drawn with the built-in font, which has no glyphs for most of the translations
+		if len(codeDesc.Translations) > 0 && !l10nDisabled && contentFormat != formats.PNGFormat {
+			codeDesc = codeDesc.Translate(getLanguagesFromRequest(r)...)
+
+			w.Header().Add("Vary", "Accept-Language")
+		}
+
 		tplData := tpl.Data{
-			StatusCode:  code,
-			Message:     codeDesc.Short,
-			Description: codeDesc.Full,
-			HomepageURL: homepageURL,
-			Links:       links,
+			StatusCode:         code,
+			OriginalStatusCode: originalCode,
+			Message:            codeDesc.Short,
+			Description:        codeDesc.Full,
+			HomepageURL:        homepageURL,
+			Links:              links,
+			Image:              tpl.DefaultImage(),
 			Config: tpl.Config{
 				ShowRequestDetails: showDetails,
 				L10nDisabled:       l10nDisabled,
 			},
 		}
 
-		//nolint:lll
-		if showDetails { // ingress-nginx: https://kubernetes.github.io/ingress-nginx/user-guide/custom-errors/
-			tplData.OriginalURI = r.Header.Get("X-Original-Uri")   // (ingress-nginx) URI that caused the error
-			tplData.Namespace = r.Header.Get("X-Namespace")        // (ingress-nginx) namespace where the backend Service is located
-			tplData.IngressName = r.Header.Get("X-Ingress-Name")   // (ingress-nginx) name of the Ingress where the backend is defined
-			tplData.ServiceName = r.Header.Get("X-Service-Name")   // (ingress-nginx) name of the Service backing the backend
-			tplData.ServicePort = r.Header.Get("X-Service-Port")   // (ingress-nginx) port number of the Service backing the backend
-			tplData.RequestID = r.Header.Get("X-Request-Id")       // unique ID that identifies the request - same as for backend service
-			tplData.ForwardedFor = r.Header.Get("X-Forwarded-For") // the value of the `X-Forwarded-For` header
-			tplData.Host = r.Host                                  // the value of the `Host` header
+		if len(codeDesc.Links) > 0 { // the links of the code follow the common ones
+			tplData.Links = append(slices.Clip(links), codeDesc.Links...)
+		}
+
+		if showDetails {
+			src.fill(r, &tplData)
+		}
+
+		if contentFormat.IsImage() {
+			tplData.Image = getImageFromRequest(r)
 		}
 
 		buf, ok := bufPool.Get().(*bytes.Buffer)
@@ -122,8 +166,13 @@ func New( //nolint:funlen
 
 		buf.Reset()
 
-		tmpl, tErr := templater(contentFormat)
-		if tErr != nil {
+		if contentFormat == formats.PNGFormat { // the PNG images are drawn without a template
+			if err := raster.PNG(buf, tplData.Image.Width, tplData.Image.Height,
+				strconv.Itoa(int(code)), tplData.Message,
+			); err != nil {
+				log.Error("Failed to draw the PNG image", logger.Error(err))
+			}
+		} else if tmpl, tErr := templater(contentFormat); tErr != nil {
 			buf.Write(contentFormat.FormatError("Failed to get the template for the requested content format: " + tErr.Error()))
 		} else if tmpl == nil {
 			buf.Write(contentFormat.FormatError("No template available for the requested content format"))
@@ -131,7 +180,9 @@ func New( //nolint:funlen
 			buf.Write(contentFormat.FormatError("Failed to render the error page template: " + renderErr.Error()))
 		}
 
-		buf = gzipCompress(r, w, buf, &bufPool)
+		if contentFormat != formats.PNGFormat { // PNG is already compressed
+			buf = gzipCompress(r, w, buf, &bufPool)
+		}
 
 		w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
 		w.WriteHeader(httpStatus)
@@ -177,7 +228,7 @@ func gzipCompress(r *http.Request, w http.ResponseWriter, src *bytes.Buffer, poo
 	}
 
 	w.Header().Set("Content-Encoding", "gzip")
-	w.Header().Set("Vary", "Accept-Encoding")
+	w.Header().Add("Vary", "Accept-Encoding")
 
 	if src.Cap() <= maxPooledBuf {
 		pool.Put(src)
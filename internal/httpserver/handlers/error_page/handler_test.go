@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"compress/gzip"
 	"errors"
+	"image/png"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -42,12 +43,15 @@ func TestNew(t *testing.T) {
 			404,
 			false,
 			nil,
+			nil,
+			nil,
 			noDesc,
 			func(_ formats.Format) (*tpl.Template, error) { return mustTemplate(t, ""), nil },
 			false,
 			false,
 			"",
 			nil,
+			nil,
 		)
 
 		for name, tc := range map[string]struct {
@@ -90,12 +94,15 @@ func TestNew(t *testing.T) {
 				defaultCode,
 				false,
 				nil,
+				nil,
+				nil,
 				noDesc,
 				func(_ formats.Format) (*tpl.Template, error) { return tmpl, nil },
 				false,
 				false,
 				"",
 				nil,
+				nil,
 			)
 		}
 
@@ -159,12 +166,17 @@ func TestNew(t *testing.T) {
 		xmlTmpl := mustTemplate(t, `xml-body`)
 		htmlTmpl := mustTemplate(t, `html-body`)
 		textTmpl := mustTemplate(t, `text-body`)
+		yamlTmpl := mustTemplate(t, `yaml-body`)
+		mdTmpl := mustTemplate(t, `md-body`)
+		svgTmpl := mustTemplate(t, `svg-body`)
 
 		h := error_page.New(
 			logger.NewNop(),
 			404,
 			false,
 			nil,
+			nil,
+			nil,
 			noDesc,
 			func(f formats.Format) (*tpl.Template, error) {
 				switch f {
@@ -176,6 +188,12 @@ func TestNew(t *testing.T) {
 					return htmlTmpl, nil
 				case formats.PlainTextFormat:
 					return textTmpl, nil
+				case formats.YAMLFormat:
+					return yamlTmpl, nil
+				case formats.MarkdownFormat:
+					return mdTmpl, nil
+				case formats.SVGFormat:
+					return svgTmpl, nil
 				}
 
 				return textTmpl, nil
@@ -184,6 +202,7 @@ func TestNew(t *testing.T) {
 			false,
 			"",
 			nil,
+			nil,
 		)
 
 		for name, tc := range map[string]struct {
@@ -210,6 +229,18 @@ func TestNew(t *testing.T) {
 			"extension .txt": {
 				givePath: "/404.txt", wantContentType: "text/plain; charset=utf-8", wantBody: "text-body",
 			},
+			"extension .yaml": {
+				givePath: "/404.yaml", wantContentType: "application/yaml; charset=utf-8", wantBody: "yaml-body",
+			},
+			"extension .yml": {
+				givePath: "/404.yml", wantContentType: "application/yaml; charset=utf-8", wantBody: "yaml-body",
+			},
+			"extension .md": {
+				givePath: "/404.md", wantContentType: "text/markdown; charset=utf-8", wantBody: "md-body",
+			},
+			"extension .svg": {
+				givePath: "/404.svg", wantContentType: "image/svg+xml; charset=utf-8", wantBody: "svg-body",
+			},
 			"extension beats Accept header": {
 				givePath:        "/404.json",
 				giveAccept:      "text/html",
@@ -297,6 +328,42 @@ func TestNew(t *testing.T) {
 				wantContentType: "application/xml; charset=utf-8",
 				wantBody:        "xml-body",
 			},
+			"Accept application/yaml": {
+				givePath:        "/404",
+				giveAccept:      "application/yaml",
+				wantContentType: "application/yaml; charset=utf-8",
+				wantBody:        "yaml-body",
+			},
+			"Accept application/x-yaml": {
+				givePath:        "/404",
+				giveAccept:      "application/x-yaml",
+				wantContentType: "application/yaml; charset=utf-8",
+				wantBody:        "yaml-body",
+			},
+			"Accept text/markdown": {
+				givePath:        "/404",
+				giveAccept:      "text/markdown, text/plain;q=0.9",
+				wantContentType: "text/markdown; charset=utf-8",
+				wantBody:        "md-body",
+			},
+			"Accept image/svg+xml is not XML": {
+				givePath:        "/404",
+				giveAccept:      "image/svg+xml",
+				wantContentType: "image/svg+xml; charset=utf-8",
+				wantBody:        "svg-body",
+			},
+			"Accept image/* maps to SVG": {
+				givePath:        "/404",
+				giveAccept:      "image/*",
+				wantContentType: "image/svg+xml; charset=utf-8",
+				wantBody:        "svg-body",
+			},
+			"Accept of the browser img element maps to SVG": {
+				givePath:        "/404",
+				giveAccept:      "image/avif,image/webp,image/apng,image/svg+xml,image/*,*/*;q=0.8",
+				wantContentType: "image/svg+xml; charset=utf-8",
+				wantBody:        "svg-body",
+			},
 			"Accept q-weight: JSON higher weight wins": {
 				givePath:        "/404",
 				giveAccept:      "text/html;q=0.5,application/json;q=0.9",
@@ -379,12 +446,15 @@ func TestNew(t *testing.T) {
 					tc.giveDefaultCode,
 					tc.giveRespondSameStatus,
 					nil,
+					nil,
+					nil,
 					noDesc,
 					func(_ formats.Format) (*tpl.Template, error) { return tmpl, nil },
 					false,
 					false,
 					"",
 					nil,
+					nil,
 				)
 
 				req := httptest.NewRequest(http.MethodGet, tc.givePath, nil)
@@ -406,12 +476,15 @@ func TestNew(t *testing.T) {
 			404,
 			false,
 			nil,
+			nil,
+			nil,
 			noDesc,
 			func(_ formats.Format) (*tpl.Template, error) { return tmpl, nil },
 			false,
 			false,
 			"",
 			nil,
+			nil,
 		)
 
 		for name, tc := range map[string]struct {
@@ -485,12 +558,15 @@ func TestNew(t *testing.T) {
 					404,
 					false,
 					tc.giveProxyHeaders,
+					nil,
+					nil,
 					noDesc,
 					func(_ formats.Format) (*tpl.Template, error) { return tmpl, nil },
 					false,
 					false,
 					"",
 					nil,
+					nil,
 				)
 
 				req := httptest.NewRequest(http.MethodGet, "/404", nil)
@@ -550,12 +626,15 @@ func TestNew(t *testing.T) {
 					404,
 					false,
 					nil,
+					nil,
+					nil,
 					tc.giveDescriber,
 					func(_ formats.Format) (*tpl.Template, error) { return tmpl, nil },
 					false,
 					false,
 					"",
 					nil,
+					nil,
 				)
 
 				req := httptest.NewRequest(http.MethodGet, tc.givePath, nil)
@@ -575,6 +654,8 @@ func TestNew(t *testing.T) {
 			404,
 			false,
 			nil,
+			nil,
+			nil,
 			noDesc,
 			func(_ formats.Format) (*tpl.Template, error) {
 				return nil, nil //nolint:nilnil // no template is the scenario under test
@@ -583,6 +664,7 @@ func TestNew(t *testing.T) {
 			false,
 			"",
 			nil,
+			nil,
 		)
 
 		for name, tc := range map[string]struct {
@@ -617,12 +699,15 @@ func TestNew(t *testing.T) {
 			404,
 			false,
 			nil,
+			nil,
+			nil,
 			noDesc,
 			func(_ formats.Format) (*tpl.Template, error) { return nil, templaterErr },
 			false,
 			false,
 			"",
 			nil,
+			nil,
 		)
 
 		for name, tc := range map[string]struct {
@@ -658,12 +743,15 @@ func TestNew(t *testing.T) {
 			404,
 			false,
 			nil,
+			nil,
+			nil,
 			noDesc,
 			func(_ formats.Format) (*tpl.Template, error) { return badTmpl, nil },
 			false,
 			false,
 			"",
 			nil,
+			nil,
 		)
 
 		for name, tc := range map[string]struct {
@@ -705,12 +793,15 @@ func TestNew(t *testing.T) {
 				404,
 				false,
 				nil,
+				nil,
+				nil,
 				noDesc,
 				func(_ formats.Format) (*tpl.Template, error) { return tmpl, nil },
 				true,
 				false,
 				"",
 				nil,
+				nil,
 			)
 
 			req := httptest.NewRequest(http.MethodGet, "/500", nil)
@@ -741,12 +832,15 @@ func TestNew(t *testing.T) {
 				404,
 				false,
 				nil,
+				nil,
+				nil,
 				noDesc,
 				func(_ formats.Format) (*tpl.Template, error) { return tmpl, nil },
 				false,
 				false,
 				"",
 				nil,
+				nil,
 			)
 
 			req := httptest.NewRequest(http.MethodGet, "/500", nil)
@@ -761,6 +855,468 @@ func TestNew(t *testing.T) {
 		})
 	})
 
+	t.Run("source profiles", func(t *testing.T) {
+		t.Parallel()
+
+		tmpl := mustTemplate(t, `{{.StatusCode}} {{.OriginalURI}} {{.Host}} {{.Namespace}} {{.IngressName}} {{.ForwardedFor}}`)
+
+		for name, tc := range map[string]struct {
+			giveSource  string // empty means auto
+			givePath    string
+			giveHeaders map[string]string
+			wantBody    string
+		}{
+			"auto, no known headers (ingress-nginx)": {
+				givePath: "/500",
+				wantBody: "500  example.com   ",
+			},
+			"auto, ingress-nginx": {
+				givePath: "/",
+				giveHeaders: map[string]string{
+					"X-Code": "503", "X-Original-Uri": "/app", "X-Namespace": "prod", "X-Ingress-Name": "web",
+				},
+				wantBody: "503 /app example.com prod web ",
+			},
+			"auto, traefik": {
+				givePath: "/502",
+				giveHeaders: map[string]string{
+					"X-Forwarded-Server": "traefik-1", "X-Forwarded-Uri": "/api", "X-Forwarded-Host": "app.io",
+					"X-Forwarded-For": "1.2.3.4",
+				},
+				wantBody: "502 /api app.io   1.2.3.4",
+			},
+			"auto, envoy": {
+				givePath: "/404",
+				giveHeaders: map[string]string{
+					"X-Envoy-Original-Path": "/status/404", "X-Envoy-External-Address": "5.6.7.8",
+					"X-Forwarded-Uri": "/ignored",
+				},
+				wantBody: "404 /status/404 example.com   5.6.7.8",
+			},
+			"auto, caddy": {
+				givePath:    "/503",
+				giveHeaders: map[string]string{"Via": "1.1 Caddy", "X-Forwarded-Host": "site.io"},
+				wantBody:    "503  site.io   ",
+			},
+			"haproxy, the path is the original one": {
+				giveSource: "haproxy",
+				givePath:   "/404/page?x=1",
+				wantBody:   "418 /404/page?x=1 example.com   ",
+			},
+			"haproxy, the code header": {
+				giveSource:  "haproxy",
+				givePath:    "/status/503",
+				giveHeaders: map[string]string{"X-Code": "503"},
+				wantBody:    "503 /status/503 example.com   ",
+			},
+			"gateway-api": {
+				giveSource:  "gateway-api",
+				givePath:    "/503",
+				giveHeaders: map[string]string{"X-Route-Name": "web", "X-Route-Namespace": "prod"},
+				wantBody:    "503  example.com prod web ",
+			},
+			"selected source ignores the others": {
+				giveSource:  "traefik",
+				givePath:    "/404",
+				giveHeaders: map[string]string{"X-Envoy-Original-Path": "/ignored", "X-Original-Uri": "/fallback"},
+				wantBody:    "404 /fallback example.com   ",
+			},
+		} {
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+
+				var source *error_page.Source
+
+				if tc.giveSource != "" {
+					var ok bool
+
+					source, ok = error_page.SourceByName(tc.giveSource)
+					assert.True(t, ok)
+				}
+
+				h := error_page.New(
+					logger.NewNop(),
+					418,
+					false,
+					nil,
+					nil,
+					nil,
+					noDesc,
+					func(_ formats.Format) (*tpl.Template, error) { return tmpl, nil },
+					true,
+					false,
+					"",
+					nil,
+					source,
+				)
+
+				req := httptest.NewRequest(http.MethodGet, tc.givePath, nil)
+				req.Host = "example.com"
+
+				for k, v := range tc.giveHeaders {
+					req.Header.Set(k, v)
+				}
+
+				rec := httptest.NewRecorder()
+				h.ServeHTTP(rec, req)
+
+				assert.Equal(t, tc.wantBody, rec.Body.String())
+			})
+		}
+
+		_, ok := error_page.SourceByName("unknown")
+		assert.False(t, ok)
+		assert.Equal(t, 6, len(error_page.Sources()))
+	})
+
+	t.Run("code links and translations", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			tmpl = mustTemplate(t, `{{.Message}}|{{.Description}}|{{range .Links}}{{.Label}};{{end}}`)
+			desc = codes.Description{
+				Short: "Not Found",
+				Full:  "No such page",
+				Links: []tpl.Link{{Label: "Search", URL: "/search"}},
+				Translations: map[string]codes.Translation{
+					"de":    {Short: "Nicht gefunden"},
+					"pt-br": {Short: "Não encontrado", Full: "Página inexistente"},
+				},
+			}
+		)
+
+		for name, tc := range map[string]struct {
+			giveAcceptLanguage string
+			giveL10nDisabled   bool
+			wantBody           string
+			wantVary           bool
+		}{
+			"no header": {wantBody: "Not Found|No such page|Home;Search;", wantVary: true},
+			"base language": {
+				giveAcceptLanguage: "de-AT",
+				wantBody:           "Nicht gefunden|No such page|Home;Search;",
+				wantVary:           true,
+			},
+			"by weight": {
+				giveAcceptLanguage: "de;q=0.5, pt-BR",
+				wantBody:           "Não encontrado|Página inexistente|Home;Search;",
+				wantVary:           true,
+			},
+			"unknown": {
+				giveAcceptLanguage: "fr, *;q=0.1",
+				wantBody:           "Not Found|No such page|Home;Search;",
+				wantVary:           true,
+			},
+			"zero weight": {
+				giveAcceptLanguage: "de;q=0, fr",
+				wantBody:           "Not Found|No such page|Home;Search;",
+				wantVary:           true,
+			},
+			"l10n disabled": {
+				giveAcceptLanguage: "de",
+				giveL10nDisabled:   true,
+				wantBody:           "Not Found|No such page|Home;Search;",
+			},
+		} {
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+
+				h := error_page.New(
+					logger.NewNop(),
+					404,
+					false,
+					nil,
+					nil,
+					nil,
+					func(uint16) (codes.Description, bool) { return desc, true },
+					func(_ formats.Format) (*tpl.Template, error) { return tmpl, nil },
+					false,
+					tc.giveL10nDisabled,
+					"",
+					[]tpl.Link{{Label: "Home", URL: "/"}},
+					nil,
+				)
+
+				req := httptest.NewRequest(http.MethodGet, "/404", nil)
+				req.Header.Set("Accept-Language", tc.giveAcceptLanguage)
+
+				rec := httptest.NewRecorder()
+				h.ServeHTTP(rec, req)
+
+				assert.Equal(t, tc.wantBody, rec.Body.String())
+				assert.Equal(t, tc.wantVary, rec.Header().Get("Vary") == "Accept-Language")
+			})
+		}
+	})
+
+	t.Run("images", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			tmpl = mustTemplate(t, `{{.Image.Width}}x{{.Image.Height}}|{{.Message}}`)
+			desc = codes.Description{
+				Short:        "Not Found",
+				Translations: map[string]codes.Translation{"de": {Short: "Nicht gefunden"}},
+			}
+		)
+
+		h := error_page.New(
+			logger.NewNop(),
+			404,
+			false,
+			nil,
+			nil,
+			nil,
+			func(uint16) (codes.Description, bool) { return desc, true },
+			func(_ formats.Format) (*tpl.Template, error) { return tmpl, nil },
+			false,
+			false,
+			"",
+			nil,
+			nil,
+		)
+
+		t.Run("svg size", func(t *testing.T) {
+			t.Parallel()
+
+			for giveQuery, wantBody := range map[string]string{
+				"":                        "400x300|Nicht gefunden",
+				"?width=640&height=480":   "640x480|Nicht gefunden",
+				"?width=100":              "100x300|Nicht gefunden",
+				"?width=1&height=100000":  "16x2048|Nicht gefunden",
+				"?width=-5&height=foo":    "400x300|Nicht gefunden",
+				"?height=120&width=1e3px": "400x120|Nicht gefunden",
+			} {
+				t.Run(giveQuery, func(t *testing.T) {
+					t.Parallel()
+
+					req := httptest.NewRequest(http.MethodGet, "/404.svg"+giveQuery, nil)
+					req.Header.Set("Accept-Language", "de")
+
+					rec := httptest.NewRecorder()
+					h.ServeHTTP(rec, req)
+
+					assert.Equal(t, wantBody, rec.Body.String())
+				})
+			}
+		})
+
+		t.Run("the size is ignored for other formats", func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "/404.json?width=640&height=480", nil)
+
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			assert.Equal(t, "400x300|Not Found", rec.Body.String())
+		})
+
+		for name, tc := range map[string]struct {
+			givePath   string
+			giveAccept string
+			wantWidth  int
+			wantHeight int
+		}{
+			"png by extension":  {givePath: "/404.png", wantWidth: 400, wantHeight: 300},
+			"png by Accept":     {givePath: "/404", giveAccept: "image/png", wantWidth: 400, wantHeight: 300},
+			"apng by Accept":    {givePath: "/404", giveAccept: "image/apng", wantWidth: 400, wantHeight: 300},
+			"png of given size": {givePath: "/404.png?width=64&height=32", wantWidth: 64, wantHeight: 32},
+		} {
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+
+				req := httptest.NewRequest(http.MethodGet, tc.givePath, nil)
+				req.Header.Set("Accept", tc.giveAccept)
+				req.Header.Set("Accept-Encoding", "gzip") // PNG is never compressed
+				req.Header.Set("Accept-Language", "de")   // and never translated
+
+				rec := httptest.NewRecorder()
+				h.ServeHTTP(rec, req)
+
+				assert.Equal(t, "image/png", rec.Header().Get("Content-Type"))
+				assert.Equal(t, "", rec.Header().Get("Content-Encoding"))
+				assert.Equal(t, "", rec.Header().Get("Vary"))
+
+				cfg, err := png.DecodeConfig(rec.Body)
+				assert.NoError(t, err)
+				assert.Equal(t, tc.wantWidth, cfg.Width)
+				assert.Equal(t, tc.wantHeight, cfg.Height)
+			})
+		}
+	})
+
+	t.Run("code mapping", func(t *testing.T) {
+		t.Parallel()
+
+		mapping, err := error_page.ParseCodeMapping("502,504=503||403=404@/admin||4xx=400@/api")
+		assert.NoError(t, err)
+
+		var (
+			tmpl = mustTemplate(t, `{{.StatusCode}}/{{.OriginalStatusCode}}/{{.Message}}`)
+			desc = func(code uint16) (codes.Description, bool) {
+				return codes.Description{Short: "desc of " + strconv.Itoa(int(code))}, true
+			}
+		)
+
+		for name, tc := range map[string]struct {
+			givePath    string
+			giveHeaders map[string]string
+			wantBody    string
+			wantStatus  int
+		}{
+			"remapped":      {givePath: "/502", wantBody: "503/502/desc of 503", wantStatus: 503},
+			"not mapped":    {givePath: "/500", wantBody: "500/500/desc of 500", wantStatus: 500},
+			"path - no uri": {givePath: "/403", wantBody: "403/403/desc of 403", wantStatus: 403},
+			"path - matched": {
+				givePath:    "/403",
+				giveHeaders: map[string]string{"X-Original-Uri": "/admin/users?page=2"},
+				wantBody:    "404/403/desc of 404",
+				wantStatus:  404,
+			},
+			"path - exact": {
+				givePath:    "/403",
+				giveHeaders: map[string]string{"X-Original-Uri": "/admin"},
+				wantBody:    "404/403/desc of 404",
+				wantStatus:  404,
+			},
+			"path - another segment": {
+				givePath:    "/403",
+				giveHeaders: map[string]string{"X-Original-Uri": "/administrator"},
+				wantBody:    "403/403/desc of 403",
+				wantStatus:  403,
+			},
+			"path - source header": {
+				givePath:    "/403",
+				giveHeaders: map[string]string{"X-Forwarded-Server": "traefik", "X-Forwarded-Uri": "/admin/"},
+				wantBody:    "404/403/desc of 404",
+				wantStatus:  404,
+			},
+			"first rule wins": {
+				givePath:    "/403",
+				giveHeaders: map[string]string{"X-Original-Uri": "/api"},
+				wantBody:    "400/403/desc of 400",
+				wantStatus:  400,
+			},
+		} {
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+
+				h := error_page.New(
+					logger.NewNop(),
+					404,
+					true,
+					nil,
+					mapping,
+					nil,
+					desc,
+					func(_ formats.Format) (*tpl.Template, error) { return tmpl, nil },
+					false,
+					false,
+					"",
+					nil,
+					nil,
+				)
+
+				req := httptest.NewRequest(http.MethodGet, tc.givePath, nil)
+
+				for k, v := range tc.giveHeaders {
+					req.Header.Set(k, v)
+				}
+
+				rec := httptest.NewRecorder()
+				h.ServeHTTP(rec, req)
+
+				assert.Equal(t, tc.wantBody, rec.Body.String())
+				assert.Equal(t, tc.wantStatus, rec.Code)
+			})
+		}
+	})
+
+	t.Run("redirects", func(t *testing.T) {
+		t.Parallel()
+
+		redirects, err := error_page.ParseRedirects(
+			"401=https://sso.example.com/login?return_to={{ .OriginalURI | urlEncode }}||410=/archive",
+		)
+		assert.NoError(t, err)
+
+		tmpl := mustTemplate(t, `page {{.StatusCode}}`)
+
+		for name, tc := range map[string]struct {
+			givePath     string
+			giveHeaders  map[string]string
+			wantStatus   int
+			wantLocation string
+			wantBody     string
+		}{
+			"browser": {
+				givePath:     "/401",
+				giveHeaders:  map[string]string{"Accept": "text/html", "X-Original-Uri": "/app?x=1"},
+				wantStatus:   http.StatusFound,
+				wantLocation: "https://sso.example.com/login?return_to=%2Fapp%3Fx%3D1",
+			},
+			"browser - static target": {
+				givePath:     "/410",
+				giveHeaders:  map[string]string{"Accept": "text/html"},
+				wantStatus:   http.StatusFound,
+				wantLocation: "/archive",
+			},
+			"api client": {
+				givePath:    "/401",
+				giveHeaders: map[string]string{"Accept": "application/json"},
+				wantStatus:  http.StatusUnauthorized,
+				wantBody:    "page 401",
+			},
+			"not matched": {
+				givePath:    "/404",
+				giveHeaders: map[string]string{"Accept": "text/html"},
+				wantStatus:  http.StatusNotFound,
+				wantBody:    "page 404",
+			},
+		} {
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+
+				h := error_page.New(
+					logger.NewNop(),
+					404,
+					true,
+					nil,
+					nil,
+					redirects,
+					noDesc,
+					func(_ formats.Format) (*tpl.Template, error) { return tmpl, nil },
+					false,
+					false,
+					"",
+					nil,
+					nil,
+				)
+
+				req := httptest.NewRequest(http.MethodGet, tc.givePath, nil)
+
+				for k, v := range tc.giveHeaders {
+					req.Header.Set(k, v)
+				}
+
+				rec := httptest.NewRecorder()
+				h.ServeHTTP(rec, req)
+
+				assert.Equal(t, tc.wantStatus, rec.Code)
+				assert.Equal(t, tc.wantLocation, rec.Header().Get("Location"))
+
+				if tc.wantBody != "" {
+					assert.Equal(t, tc.wantBody, rec.Body.String())
+				}
+
+				if name != "not matched" {
+					assert.Equal(t, "Accept", rec.Header().Get("Vary"))
+				}
+			})
+		}
+	})
+
 	t.Run("GET writes body HEAD omits it", func(t *testing.T) {
 		t.Parallel()
 
@@ -772,12 +1328,15 @@ func TestNew(t *testing.T) {
 			404,
 			false,
 			nil,
+			nil,
+			nil,
 			noDesc,
 			func(_ formats.Format) (*tpl.Template, error) { return tmpl, nil },
 			false,
 			false,
 			"",
 			nil,
+			nil,
 		)
 
 		wantLen := strconv.Itoa(len(tplBody))
@@ -826,12 +1385,15 @@ func TestNew(t *testing.T) {
 			404,
 			false,
 			nil,
+			nil,
+			nil,
 			noDesc,
 			func(_ formats.Format) (*tpl.Template, error) { return tmpl, nil },
 			false,
 			false,
 			"",
 			nil,
+			nil,
 		)
 
 		for name, tc := range map[string]struct {
@@ -911,12 +1473,15 @@ func TestNew(t *testing.T) {
 				404,
 				false,
 				nil,
+				nil,
+				nil,
 				noDesc,
 				func(_ formats.Format) (*tpl.Template, error) { return emptyTmpl, nil },
 				false,
 				false,
 				"",
 				nil,
+				nil,
 			)
 
 			req := httptest.NewRequest(http.MethodGet, "/404", nil)
@@ -932,3 +1497,92 @@ func TestNew(t *testing.T) {
 		})
 	})
 }
+
+func TestRenderFor(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range error_page.Sources() {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			source, _ := error_page.SourceByName(name)
+
+			var (
+				h = error_page.New(logger.NewNop(), 404, true, nil, nil, nil, noDesc,
+					func(f formats.Format) (*tpl.Template, error) {
+						return tpl.New(f.String() + " {{ .StatusCode }} {{ .OriginalURI }}")
+					},
+					true, false, "", nil, source,
+				)
+				req = httptest.NewRequest(http.MethodPost, "/api/users.json?page=2", nil)
+			)
+
+			req.Header.Set("X-Code", "418") // ignored, the code is passed explicitly
+			req.Header.Set("X-Original-Uri", "/api/users.json?page=2")
+
+			status, header, body := error_page.RenderFor(h, req, http.StatusServiceUnavailable)
+
+			assert.Equal(t, http.StatusServiceUnavailable, status)
+			assert.Equal(t, "application/json; charset=utf-8", header.Get("Content-Type"))
+			assert.Equal(t, "json 503 /api/users.json?page=2", body.String())
+		})
+	}
+}
+
+func TestParseCodeMapping(t *testing.T) {
+	t.Parallel()
+
+	mapping, err := error_page.ParseCodeMapping("502, 504=503 || 403=404@/admin/\n\n5xx = 500 @ /api")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(mapping))
+	assert.Equal(t, "/admin", mapping[1].PathPrefix)
+	assert.Equal(t, "/api", mapping[2].PathPrefix)
+
+	for code, want := range map[uint16]uint16{502: 503, 504: 503, 503: 500, 599: 500, 501: 500, 404: 404} {
+		assert.Equal(t, want, mapping.Map(code, "/api/v1?x=1"))
+	}
+
+	empty, err := error_page.ParseCodeMapping("")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(empty))
+
+	for give, wantErr := range map[string]string{
+		"502":           "missing '='",
+		"=503":          "missing the codes to remap",
+		"foo=503":       "not a number",
+		"502=5xx":       "the target must be a code between 1 and 999",
+		"502=1000":      "the target must be a code between 1 and 999",
+		"403=404@admin": "the path must start with '/'",
+	} {
+		_, err = error_page.ParseCodeMapping(give)
+		assert.ErrorContains(t, err, wantErr)
+	}
+}
+
+func TestParseRedirects(t *testing.T) {
+	t.Parallel()
+
+	redirects, err := error_page.ParseRedirects("401, 403 = /login?a=b&c={{ .StatusCode }}\n\n410=/archive")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(redirects))
+
+	location, err := redirects[0].Target.Render(tpl.Data{StatusCode: 403})
+	assert.NoError(t, err)
+	assert.Equal(t, "/login?a=b&c=403", string(location))
+
+	empty, err := error_page.ParseRedirects("")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(empty))
+
+	for give, wantErr := range map[string]string{
+		"401":                      "missing '='",
+		"=/login":                  "missing the codes to redirect",
+		"foo=/login":               "not a number",
+		"401= ":                    "missing the target URL",
+		"401=/login?{{ .Foo }}":    "can't evaluate field Foo",
+		"401=/login?{{ unknown }}": "function \"unknown\" not defined",
+	} {
+		_, err = error_page.ParseRedirects(give)
+		assert.ErrorContains(t, err, wantErr)
+	}
+}
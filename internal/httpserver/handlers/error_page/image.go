@@ -0,0 +1,31 @@
+package error_page
+
+import (
+	"net/http"
+	"strconv"
+
+	tpl "gh.tarampamp.am/error-pages/v4/internal/template"
+)
+
+// getImageFromRequest returns the size of the image, requested using the "width" and "height" query parameters.
+// The size is clamped to the [tpl.MinImageSize, tpl.MaxImageSize] range, and the missing or wrong values are
+// replaced with the default ones.
+//
+//	/404.svg?width=640&height=480	-> 640x480
+//	/404.png?width=10000					-> 2048x300
+func getImageFromRequest(r *http.Request) tpl.Image {
+	var (
+		image = tpl.DefaultImage()
+		query = r.URL.Query()
+	)
+
+	if w, err := strconv.Atoi(query.Get("width")); err == nil && w > 0 {
+		image.Width = min(max(w, tpl.MinImageSize), tpl.MaxImageSize)
+	}
+
+	if h, err := strconv.Atoi(query.Get("height")); err == nil && h > 0 {
+		image.Height = min(max(h, tpl.MinImageSize), tpl.MaxImageSize)
+	}
+
+	return image
+}
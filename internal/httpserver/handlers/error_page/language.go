@@ -0,0 +1,48 @@
+package error_page
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// getLanguagesFromRequest returns the language tags of the Accept-Language header, from the most preferred to the
+// least one. The wildcard and the languages with q=0 are skipped.
+//
+//	de-CH, de;q=0.9, en;q=0.8, *;q=0.5	-> [de-CH de en]
+func getLanguagesFromRequest(r *http.Request) []string {
+	type weighted struct {
+		tag    string
+		weight int
+	}
+
+	var (
+		accept = r.Header.Get("Accept-Language")
+		list   []weighted
+	)
+
+	for accept != "" {
+		segment, rest, _ := strings.Cut(accept, ",")
+		accept = rest
+
+		tag, params, _ := strings.Cut(segment, ";")
+
+		if tag = strings.TrimSpace(tag); tag == "" || tag == "*" {
+			continue
+		}
+
+		if weight := parseQWeight(params); weight > 0 {
+			list = append(list, weighted{tag: tag, weight: weight})
+		}
+	}
+
+	slices.SortStableFunc(list, func(a, b weighted) int { return b.weight - a.weight })
+
+	var tags = make([]string, len(list))
+
+	for i, w := range list {
+		tags[i] = w.tag
+	}
+
+	return tags
+}
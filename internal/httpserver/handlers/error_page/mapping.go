@@ -0,0 +1,95 @@
+package error_page
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gh.tarampamp.am/error-pages/v4/internal/codes"
+)
+
+// CodeMapping is the list of rules remapping the status codes (e.g. 502 and 504 to 503, to show the maintenance page
+// instead of them). The rules are checked in order, and the first one matching the code wins; the remapped code is
+// not checked again.
+type CodeMapping []CodeMappingRule
+
+// CodeMappingRule remaps the status codes matching the filter to another one.
+type CodeMappingRule struct {
+	From codes.Filter // the codes to remap (not empty)
+	To   uint16       // the code to use instead
+
+	// PathPrefix limits the rule to the original requests with the URI path under the prefix (e.g. "/admin" matches
+	// "/admin" and "/admin/users", but not "/administrator"). Empty means any path.
+	PathPrefix string
+}
+
+// ParseCodeMapping parses the rules separated by '||', newline, or tab. Each rule has the format 'FROM=TO' or
+// 'FROM=TO@PATH', where FROM is a comma-separated list of the codes, codes with wildcards, or ranges (e.g.
+// '502,504=503' or '4xx=404@/admin').
+func ParseCodeMapping(s string) (CodeMapping, error) {
+	s = strings.ReplaceAll(s, "\n", "||")
+	s = strings.ReplaceAll(s, "\t", "||")
+
+	var result CodeMapping
+
+	for entry := range strings.SplitSeq(s, "||") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+
+		from, to, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("wrong code mapping %q: missing '='", entry)
+		}
+
+		to, path, hasPath := strings.Cut(to, "@")
+
+		filter, err := codes.ParseFilter(from)
+		if err != nil {
+			return nil, fmt.Errorf("wrong code mapping %q: %w", entry, err)
+		}
+
+		if len(filter) == 0 {
+			return nil, fmt.Errorf("wrong code mapping %q: missing the codes to remap", entry)
+		}
+
+		code, err := strconv.ParseUint(strings.TrimSpace(to), 10, 16)
+		if err != nil || code == 0 || code > 999 {
+			return nil, fmt.Errorf("wrong code mapping %q: the target must be a code between 1 and 999", entry)
+		}
+
+		var rule = CodeMappingRule{From: filter, To: uint16(code)}
+
+		if hasPath {
+			if path = strings.TrimSpace(path); !strings.HasPrefix(path, "/") {
+				return nil, fmt.Errorf("wrong code mapping %q: the path must start with '/'", entry)
+			}
+
+			rule.PathPrefix = strings.TrimRight(path, "/")
+		}
+
+		result = append(result, rule)
+	}
+
+	return result, nil
+}
+
+// Map returns the code the given one is remapped to, or the code itself if none of the rules matches. The uri is
+// the URI of the original request, used by the rules limited to the path.
+func (m CodeMapping) Map(code uint16, uri string) uint16 {
+	path, _, _ := strings.Cut(uri, "?")
+
+	for _, rule := range m {
+		if !rule.From.Match(code) {
+			continue
+		}
+
+		if p := rule.PathPrefix; p != "" && path != p && !strings.HasPrefix(path, p+"/") {
+			continue
+		}
+
+		return rule.To
+	}
+
+	return code
+}
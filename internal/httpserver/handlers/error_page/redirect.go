@@ -0,0 +1,80 @@
+package error_page
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"gh.tarampamp.am/error-pages/v4/internal/codes"
+	tpl "gh.tarampamp.am/error-pages/v4/internal/template"
+)
+
+// Redirects is the list of rules redirecting the browsers to another URL instead of rendering the error page (e.g.
+// to the login page for 401). The rules are checked in order, and the first one matching the code wins.
+type Redirects []RedirectRule
+
+// RedirectRule redirects the requests with the status codes matching the filter.
+type RedirectRule struct {
+	Codes  codes.Filter  // the codes to redirect (not empty)
+	Target *tpl.Template // the target URL, rendered with the same data as the error page
+}
+
+// ParseRedirects parses the rules separated by '||', newline, or tab. Each rule has the format 'CODES=URL', where
+// CODES is a comma-separated list of the codes, codes with wildcards, or ranges, and URL is a template (e.g.
+// '401=https://sso.example.com/login?return_to={{ .OriginalURI | urlEncode }}'). The URL may contain '=' signs -
+// only the first one is used as the separator.
+func ParseRedirects(s string) (Redirects, error) {
+	s = strings.ReplaceAll(s, "\n", "||")
+	s = strings.ReplaceAll(s, "\t", "||")
+
+	var result Redirects
+
+	for entry := range strings.SplitSeq(s, "||") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+
+		from, target, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("wrong redirect %q: missing '='", entry)
+		}
+
+		filter, err := codes.ParseFilter(from)
+		if err != nil {
+			return nil, fmt.Errorf("wrong redirect %q: %w", entry, err)
+		}
+
+		if len(filter) == 0 {
+			return nil, fmt.Errorf("wrong redirect %q: missing the codes to redirect", entry)
+		}
+
+		if target = strings.TrimSpace(target); target == "" {
+			return nil, fmt.Errorf("wrong redirect %q: missing the target URL", entry)
+		}
+
+		t, err := tpl.New(target)
+		if err != nil {
+			return nil, fmt.Errorf("wrong redirect %q: %w", entry, err)
+		}
+
+		// the fields and functions are checked on execution only
+		if err = t.RenderTo(tpl.Data{}, io.Discard); err != nil {
+			return nil, fmt.Errorf("wrong redirect %q: %w", entry, err)
+		}
+
+		result = append(result, RedirectRule{Codes: filter, Target: t})
+	}
+
+	return result, nil
+}
+
+// find returns the target URL template of the first rule matching the code, or nil if none of them does.
+func (r Redirects) find(code uint16) *tpl.Template {
+	for _, rule := range r {
+		if rule.Codes.Match(code) {
+			return rule.Target
+		}
+	}
+
+	return nil
+}
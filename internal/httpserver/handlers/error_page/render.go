@@ -0,0 +1,63 @@
+package error_page
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+)
+
+// codeKey is the context key of the code the error page is rendered for by [RenderFor].
+type codeKey struct{}
+
+// RenderFor renders the error page of the code for the request using the error page handler (see [New]), as if
+// the request was sent to the error pages server, and returns the response status, headers and body. The handler
+// gets a GET (or HEAD) request for the "/{code}{ext}" path, where the extension is the one of the request path, with
+// the headers of the request, so the format is negotiated the same way. The code is passed with the request context,
+// so it is used with any source (even the one, which takes the path for the original URI).
+func RenderFor(errorPage http.Handler, r *http.Request, code int) (int, http.Header, *bytes.Buffer) {
+	req := r.Clone(context.WithValue(r.Context(), codeKey{}, code))
+
+	req.Method, req.Body, req.ContentLength = http.MethodGet, http.NoBody, 0
+	req.URL = &url.URL{Path: "/" + strconv.Itoa(code) + path.Ext(r.URL.Path)}
+	req.Header.Del("X-Code") // the code is set by the context
+
+	if r.Method == http.MethodHead {
+		req.Method = http.MethodHead
+	}
+
+	var resp = responseBuffer{header: make(http.Header)}
+
+	errorPage.ServeHTTP(&resp, req)
+
+	if resp.status == 0 {
+		resp.status = http.StatusOK
+	}
+
+	return resp.status, resp.header, &resp.body
+}
+
+// responseBuffer is a minimal [http.ResponseWriter] that keeps the response in memory.
+type responseBuffer struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+var _ http.ResponseWriter = (*responseBuffer)(nil) // ensure the interface is implemented
+
+func (r *responseBuffer) Header() http.Header { return r.header }
+
+func (r *responseBuffer) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+}
+
+func (r *responseBuffer) Write(b []byte) (int, error) {
+	r.WriteHeader(http.StatusOK)
+
+	return r.body.Write(b)
+}
@@ -0,0 +1,173 @@
+package error_page
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	tpl "gh.tarampamp.am/error-pages/v4/internal/template"
+)
+
+// Source is the profile of the proxy (or gateway) the requests to the error pages come from. It knows how the proxy
+// passes the status code and the details of the original request, and maps them into the template data.
+//
+// The ingress-nginx headers (X-Code, X-Original-Uri, X-Namespace and so on) are the fallback for every source, so
+// they can always be set by hand (e.g. with the "proxy_set_header" of nginx).
+type Source struct {
+	Name string
+
+	detect   func(http.Header) bool // nil means the source can not be detected, only selected
+	original bool                   // the request is the original one, so the path is not the code, but the original URI
+
+	// the headers of the source, checked before the ingress-nginx ones
+	originalURI, host, namespace, ingressName, forwardedFor []string
+}
+
+// sources are the known sources, in the order of detection.
+var sources = []*Source{ //nolint:gochecknoglobals
+	{ // https://kubernetes.github.io/ingress-nginx/user-guide/custom-errors/
+		Name:   "ingress-nginx",
+		detect: hasAnyHeader("X-Code", "X-Ingress-Name", "X-Namespace", "X-Service-Name"),
+	},
+	{ // Envoy and Envoy Gateway, the path of the error page is set with the "replaceFullPath" (e.g. "/404")
+		Name: "envoy",
+		detect: hasAnyHeader(
+			"X-Envoy-Original-Path", "X-Envoy-External-Address", "X-Envoy-Expected-Rq-Timeout-Ms",
+		),
+		originalURI:  []string{"X-Envoy-Original-Path"},
+		host:         []string{"X-Forwarded-Host"},
+		forwardedFor: []string{"X-Forwarded-For", "X-Envoy-External-Address"},
+	},
+	{ // the errors middleware, the path of the error page is set with the "query" (e.g. "/{status}")
+		Name:        "traefik",
+		detect:      hasAnyHeader("X-Forwarded-Server"),
+		originalURI: []string{"X-Forwarded-Uri"},
+		host:        []string{"X-Forwarded-Host"},
+	},
+	{ // the "handle_errors" with the "rewrite * /{err.status_code}"
+		Name: "caddy",
+		detect: func(h http.Header) bool {
+			return strings.Contains(strings.ToLower(h.Get("Via")), "caddy")
+		},
+		originalURI: []string{"X-Forwarded-Uri"},
+		host:        []string{"X-Forwarded-Host"},
+	},
+	{ // the default backend gets the unmatched requests as they are, and sends no headers of its own
+		Name:     "haproxy",
+		original: true,
+	},
+	{ // the implementations add no headers of their own, so the "RequestHeaderModifier" filter sets them
+		Name:        "gateway-api",
+		originalURI: []string{"X-Envoy-Original-Path", "X-Forwarded-Uri"},
+		host:        []string{"X-Forwarded-Host"},
+		namespace:   []string{"X-Route-Namespace"},
+		ingressName: []string{"X-Route-Name"},
+	},
+}
+
+// Sources returns the names of all known sources.
+func Sources() []string {
+	var names = make([]string, len(sources))
+
+	for i, s := range sources {
+		names[i] = s.Name
+	}
+
+	return names
+}
+
+// SourceByName returns the source with the name.
+func SourceByName(name string) (*Source, bool) {
+	for _, s := range sources {
+		if s.Name == name {
+			return s, true
+		}
+	}
+
+	return nil, false
+}
+
+// SourceHeaders returns the headers the sources pass the status code and the details of the original request with
+// (the ingress-nginx ones, and the ones of every other source). The headers of the client, like X-Forwarded-For or
+// X-Request-Id, are not included.
+func SourceHeaders() []string {
+	var names = []string{
+		"X-Code", "X-Original-Uri", "X-Namespace", "X-Ingress-Name", "X-Service-Name", "X-Service-Port",
+	}
+
+	for _, s := range sources {
+		for _, list := range [][]string{s.originalURI, s.namespace, s.ingressName} {
+			for _, name := range list {
+				if !slices.Contains(names, name) {
+					names = append(names, name)
+				}
+			}
+		}
+	}
+
+	return names
+}
+
+// detectSource returns the first source detected by the request headers, or ingress-nginx if none of them is.
+func detectSource(h http.Header) *Source {
+	for _, s := range sources {
+		if s.detect != nil && s.detect(h) {
+			return s
+		}
+	}
+
+	return sources[0]
+}
+
+// hasAnyHeader returns the detection function, which reports whether any of the headers is set.
+func hasAnyHeader(names ...string) func(http.Header) bool {
+	return func(h http.Header) bool {
+		for _, name := range names {
+			if h.Get(name) != "" {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// fill sets the details of the original request in the template data.
+func (s *Source) fill(r *http.Request, data *tpl.Data) {
+	data.OriginalURI = s.requestURI(r)
+	data.Namespace = firstHeader(r.Header, s.namespace, "X-Namespace")
+	data.IngressName = firstHeader(r.Header, s.ingressName, "X-Ingress-Name")
+	data.ServiceName = r.Header.Get("X-Service-Name")
+	data.ServicePort = r.Header.Get("X-Service-Port")
+	data.RequestID = r.Header.Get("X-Request-Id")
+	data.ForwardedFor = firstHeader(r.Header, s.forwardedFor, "X-Forwarded-For")
+	data.Host = firstHeader(r.Header, s.host, "")
+
+	if data.Host == "" {
+		data.Host = r.Host
+	}
+}
+
+// requestURI returns the URI of the original request that caused the error (empty if unknown).
+func (s *Source) requestURI(r *http.Request) string {
+	if uri := firstHeader(r.Header, s.originalURI, "X-Original-Uri"); uri != "" || !s.original {
+		return uri
+	}
+
+	return r.URL.RequestURI()
+}
+
+// firstHeader returns the first non-empty value of the headers, or the value of the fallback header.
+func firstHeader(h http.Header, names []string, fallback string) string {
+	for _, name := range names {
+		if v := h.Get(name); v != "" {
+			return v
+		}
+	}
+
+	if fallback == "" {
+		return ""
+	}
+
+	return h.Get(fallback)
+}
@@ -0,0 +1,197 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"gh.tarampamp.am/error-pages/v4/internal/codes"
+	"gh.tarampamp.am/error-pages/v4/internal/httpserver/handlers/error_page"
+	"gh.tarampamp.am/error-pages/v4/internal/logger"
+)
+
+// ReplaceMode defines which error responses of the upstream are replaced with the error pages.
+type ReplaceMode string
+
+const (
+	ReplaceAlways  ReplaceMode = "always"   // every error response matching the codes
+	ReplaceEmpty   ReplaceMode = "empty"    // only the responses with an empty body
+	ReplaceNonJSON ReplaceMode = "non-json" // only the responses with an empty or non-JSON body (keeps the API errors)
+)
+
+// ReplaceModes returns all supported replace modes.
+func ReplaceModes() []ReplaceMode { return []ReplaceMode{ReplaceAlways, ReplaceEmpty, ReplaceNonJSON} }
+
+// inboundKey is the context key of the original (inbound) request, used to render the error page for it.
+type inboundKey struct{}
+
+// New creates a new reverse proxy handler, which forwards the requests to the upstream and passes its responses
+// through unchanged, except the error ones (the codes matching the filter, 4xx and 5xx only) - they are replaced
+// with the error pages rendered by the errorPage handler, and its response status is sent (which should be the same
+// status code, the remapped one, or the redirect). If the upstream is unreachable, or does not respond within the
+// timeout (zero means no timeout), the 502 or 504 error page is returned.
+//
+// The error pages are rendered for the original request (see [error_page.RenderFor]), so the format negotiation
+// works the same way as for the error pages server.
+func New(
+	log *logger.Logger,
+	upstream *url.URL,
+	timeout time.Duration,
+	filter codes.Filter,
+	mode ReplaceMode,
+	errorPage http.Handler,
+) http.Handler {
+	transport := http.DefaultTransport.(*http.Transport).Clone() //nolint:forcetypeassert // it is always the case
+	transport.ResponseHeaderTimeout = timeout
+
+	return &httputil.ReverseProxy{
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			pr.SetURL(upstream)
+			pr.SetXForwarded()
+			pr.Out.Host = pr.In.Host // keep the original host, as most of the proxies do
+
+			pr.Out = pr.Out.WithContext(context.WithValue(pr.Out.Context(), inboundKey{}, pr.In))
+		},
+		Transport: transport,
+		ModifyResponse: func(resp *http.Response) error {
+			in, ok := resp.Request.Context().Value(inboundKey{}).(*http.Request)
+			if !ok || resp.StatusCode < http.StatusBadRequest || !filter.Match(uint16(resp.StatusCode)) { //nolint:gosec
+				return nil
+			}
+
+			if replace, err := shouldReplace(resp, mode); err != nil || !replace {
+				return err
+			}
+
+			_ = resp.Body.Close()
+
+			status, header, body := renderFor(errorPage, in, resp.StatusCode)
+
+			// the status may differ from the upstream one (the code is remapped, or the browser is redirected)
+			resp.StatusCode, resp.Status = status, strconv.Itoa(status)+" "+http.StatusText(status)
+			replaceHeaders(resp.Header, header)
+			resp.Trailer = nil
+			resp.Body, resp.ContentLength = io.NopCloser(body), int64(body.Len())
+
+			return nil
+		},
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			if errors.Is(err, context.Canceled) && r.Context().Err() != nil {
+				return // the client has gone away
+			}
+
+			var (
+				code = http.StatusBadGateway
+				nErr net.Error
+			)
+
+			if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &nErr) && nErr.Timeout()) {
+				code = http.StatusGatewayTimeout
+			}
+
+			log.Warn("Upstream request failed",
+				logger.String("url", r.URL.String()),
+				logger.Int("code", code),
+				logger.Error(err),
+			)
+
+			status, header, body := renderFor(errorPage, r, code)
+
+			for name, values := range header {
+				w.Header()[name] = values
+			}
+
+			w.WriteHeader(status) // the code may be remapped, or the browser redirected
+			_, _ = body.WriteTo(w)
+		},
+	}
+}
+
+// renderFor renders the error page for the inbound request (see [error_page.RenderFor]). The URI of the request is
+// passed as the X-Original-Uri header, so the code mapping rules limited to a path and the request details work the
+// same way as behind ingress-nginx. The source headers sent by the client are removed, since the request comes from
+// the client, not from a proxy - otherwise the client could fake the URI (and bypass the code mapping rules).
+func renderFor(errorPage http.Handler, r *http.Request, code int) (int, http.Header, *bytes.Buffer) {
+	r = r.Clone(r.Context())
+
+	for _, name := range error_page.SourceHeaders() {
+		r.Header.Del(name)
+	}
+
+	r.Header.Set("X-Original-Uri", r.URL.RequestURI())
+
+	return error_page.RenderFor(errorPage, r, code)
+}
+
+// contentHeaders are the headers of the upstream response, which describe its body (and are replaced with the ones
+// of the error page, or removed). The Location is replaced too, since the browsers may be redirected.
+var contentHeaders = []string{ //nolint:gochecknoglobals
+	"Content-Type", "Content-Length", "Content-Encoding", "Content-Language", "Content-Disposition", "Content-Range",
+	"Vary", "ETag", "Last-Modified", "Location",
+}
+
+// replaceHeaders replaces the content headers of the upstream response with the ones of the error page. The rest of
+// the upstream headers are kept (e.g. the WWW-Authenticate of 401, the Allow of 405, the Retry-After, or the cookies),
+// and the other headers of the error page are added only if the upstream has not set them.
+func replaceHeaders(upstream, page http.Header) {
+	for _, name := range contentHeaders {
+		upstream.Del(name)
+	}
+
+	for name, values := range page {
+		if _, ok := upstream[name]; !ok {
+			upstream[name] = values
+		}
+	}
+}
+
+// shouldReplace reports whether the error response must be replaced according to the mode. The body is checked
+// for emptiness only (the JSON body is detected by the content type), and the read part of it is put back.
+func shouldReplace(resp *http.Response, mode ReplaceMode) (bool, error) {
+	switch {
+	case mode != ReplaceEmpty && mode != ReplaceNonJSON:
+		return true, nil
+	case mode == ReplaceNonJSON && !isJSONContentType(resp.Header.Get("Content-Type")):
+		return true, nil
+	case resp.ContentLength > 0:
+		return false, nil
+	}
+
+	var peek [1]byte
+
+	n, err := io.ReadFull(resp.Body, peek[:])
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return false, err
+	}
+
+	resp.Body = readCloser{io.MultiReader(bytes.NewReader(peek[:n]), resp.Body), resp.Body}
+
+	return n == 0, nil
+}
+
+// isJSONContentType reports whether the content type is JSON (e.g. "application/json" or "application/problem+json").
+func isJSONContentType(ct string) bool {
+	mediaType, _, err := mime.ParseMediaType(ct)
+	if err != nil {
+		return false
+	}
+
+	_, sub, _ := strings.Cut(mediaType, "/")
+
+	return sub == "json" || strings.HasSuffix(sub, "+json")
+}
+
+// readCloser reads from the reader and closes the closer.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,275 @@
+package proxy_test
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"gh.tarampamp.am/error-pages/v4/internal/codes"
+	"gh.tarampamp.am/error-pages/v4/internal/formats"
+	"gh.tarampamp.am/error-pages/v4/internal/httpserver/handlers/error_page"
+	"gh.tarampamp.am/error-pages/v4/internal/httpserver/handlers/proxy"
+	"gh.tarampamp.am/error-pages/v4/internal/logger"
+	tpl "gh.tarampamp.am/error-pages/v4/internal/template"
+	"gh.tarampamp.am/error-pages/v4/internal/testutil/assert"
+)
+
+// errorPage returns the error page handler, which renders the pages as "{format} {code}", remaps 403 to 404 under
+// /admin and 504 to 503 under /slow, and redirects the browsers to the login page for 401.
+func errorPage(t *testing.T) http.Handler {
+	t.Helper()
+
+	mapping, err := error_page.ParseCodeMapping("403=404@/admin||504=503@/slow")
+	assert.NoError(t, err)
+
+	redirects, err := error_page.ParseRedirects("401=/login?code={{ .StatusCode }}")
+	assert.NoError(t, err)
+
+	return error_page.New(
+		logger.NewNop(),
+		404,
+		true,
+		[]string{"X-Request-Id"},
+		mapping,
+		redirects,
+		func(uint16) (codes.Description, bool) { return codes.Description{}, false },
+		func(f formats.Format) (*tpl.Template, error) { return tpl.New(f.String() + " {{ .StatusCode }}") },
+		false,
+		false,
+		"",
+		nil,
+		nil,
+	)
+}
+
+// upstream starts the upstream server, which responds with the code, content type, authentication challenge and body
+// from the request query.
+func upstream(t *testing.T) *url.URL {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var q = r.URL.Query()
+
+		if d, err := time.ParseDuration(q.Get("sleep")); err == nil {
+			time.Sleep(d)
+		}
+
+		if ct := q.Get("type"); ct != "" {
+			w.Header().Set("Content-Type", ct)
+		}
+
+		if auth := q.Get("auth"); auth != "" {
+			w.Header().Set("WWW-Authenticate", auth)
+		}
+
+		w.Header().Set("X-Upstream", "yes")
+		w.Header().Set("ETag", `"v1"`)
+
+		code := http.StatusOK
+
+		if c := q.Get("code"); c != "" {
+			code = map[string]int{"401": 401, "403": 403, "404": 404, "500": 500, "503": 503}[c]
+		}
+
+		w.WriteHeader(code)
+		_, _ = io.WriteString(w, q.Get("body"))
+	}))
+
+	t.Cleanup(srv.Close)
+
+	u, err := url.Parse(srv.URL)
+	assert.NoError(t, err)
+
+	return u
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	var up = upstream(t)
+
+	for name, tc := range map[string]struct {
+		giveFilter string
+		giveMode   proxy.ReplaceMode
+		giveURL    string
+		giveHeader map[string]string
+		wantCode   int
+		wantBody   string
+		wantHeader map[string]string
+	}{
+		"success is passed through": {
+			giveURL:    "/?body=hello",
+			wantCode:   http.StatusOK,
+			wantBody:   "hello",
+			wantHeader: map[string]string{"X-Upstream": "yes", "ETag": `"v1"`},
+		},
+		"error is replaced": {
+			giveURL:    "/foo?code=404&body=oops",
+			giveHeader: map[string]string{"X-Request-Id": "req-1"},
+			wantCode:   http.StatusNotFound,
+			wantBody:   "text 404",
+			wantHeader: map[string]string{"X-Upstream": "yes", "ETag": "", "X-Request-Id": "req-1"},
+		},
+		"format by the extension": {
+			giveURL:  "/foo.html?code=503",
+			wantCode: http.StatusServiceUnavailable,
+			wantBody: "html 503",
+		},
+		"format by the header": {
+			giveURL:    "/foo?code=500",
+			giveHeader: map[string]string{"Accept": "application/json"},
+			wantCode:   http.StatusInternalServerError,
+			wantBody:   "json 500",
+		},
+		"browser is redirected": {
+			giveURL:    "/foo?code=401",
+			giveHeader: map[string]string{"Accept": "text/html"},
+			wantCode:   http.StatusFound,
+			wantBody:   "<a href=\"/login?code=401\">Found</a>.\n\n",
+			wantHeader: map[string]string{"Location": "/login?code=401", "X-Upstream": "yes"},
+		},
+		"api client is not redirected": {
+			giveURL:    "/foo?code=401",
+			giveHeader: map[string]string{"Accept": "application/json"},
+			wantCode:   http.StatusUnauthorized,
+			wantBody:   "json 401",
+			wantHeader: map[string]string{"Location": ""},
+		},
+		"authentication challenge is kept": {
+			giveURL:    "/foo?code=401&auth=Basic+realm%3D%22app%22",
+			giveHeader: map[string]string{"Accept": "application/json"},
+			wantCode:   http.StatusUnauthorized,
+			wantBody:   "json 401",
+			wantHeader: map[string]string{
+				"WWW-Authenticate": `Basic realm="app"`,
+				"Content-Type":     "application/json; charset=utf-8",
+				"Content-Length":   "8",
+			},
+		},
+		"code is remapped": {
+			giveURL:  "/admin/users?code=403",
+			wantCode: http.StatusNotFound,
+			wantBody: "text 404",
+		},
+		"spoofed original URI is ignored": {
+			giveURL:    "/admin/users?code=403",
+			giveHeader: map[string]string{"X-Original-Uri": "/public"},
+			wantCode:   http.StatusNotFound,
+			wantBody:   "text 404",
+		},
+		"spoofed envoy original path is ignored": {
+			giveURL:    "/admin/users?code=403",
+			giveHeader: map[string]string{"X-Envoy-Original-Path": "/public"},
+			wantCode:   http.StatusNotFound,
+			wantBody:   "text 404",
+		},
+		"spoofed forwarded URI is ignored": {
+			giveURL:    "/admin/users?code=403",
+			giveHeader: map[string]string{"X-Forwarded-Server": "traefik", "X-Forwarded-Uri": "/public"},
+			wantCode:   http.StatusNotFound,
+			wantBody:   "text 404",
+		},
+		"code is not remapped out of the path": {
+			giveURL:  "/users?code=403",
+			wantCode: http.StatusForbidden,
+			wantBody: "text 403",
+		},
+		"code is not selected": {
+			giveFilter: "5xx",
+			giveURL:    "/?code=404&body=oops",
+			wantCode:   http.StatusNotFound,
+			wantBody:   "oops",
+		},
+		"empty mode, empty body": {
+			giveMode: proxy.ReplaceEmpty,
+			giveURL:  "/?code=500",
+			wantCode: http.StatusInternalServerError,
+			wantBody: "text 500",
+		},
+		"empty mode, non-empty body": {
+			giveMode: proxy.ReplaceEmpty,
+			giveURL:  "/?code=500&body=oops",
+			wantCode: http.StatusInternalServerError,
+			wantBody: "oops",
+		},
+		"non-json mode, JSON body": {
+			giveMode: proxy.ReplaceNonJSON,
+			giveURL:  "/?code=404&type=application/problem%2Bjson&body=%7B%7D",
+			wantCode: http.StatusNotFound,
+			wantBody: "{}",
+		},
+		"non-json mode, empty JSON body": {
+			giveMode: proxy.ReplaceNonJSON,
+			giveURL:  "/?code=404&type=application/json",
+			wantCode: http.StatusNotFound,
+			wantBody: "text 404",
+		},
+		"non-json mode, HTML body": {
+			giveMode: proxy.ReplaceNonJSON,
+			giveURL:  "/?code=404&type=text/html&body=oops",
+			wantCode: http.StatusNotFound,
+			wantBody: "text 404",
+		},
+		"timeout": {
+			giveURL:  "/?sleep=500ms",
+			wantCode: http.StatusGatewayTimeout,
+			wantBody: "text 504",
+		},
+		"timeout code is remapped": {
+			giveURL:  "/slow?sleep=500ms",
+			wantCode: http.StatusServiceUnavailable,
+			wantBody: "text 503",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			filter, err := codes.ParseFilter(tc.giveFilter)
+			assert.NoError(t, err)
+
+			var (
+				h   = proxy.New(logger.NewNop(), up, 100*time.Millisecond, filter, tc.giveMode, errorPage(t))
+				req = httptest.NewRequest(http.MethodGet, tc.giveURL, nil)
+				rec = httptest.NewRecorder()
+			)
+
+			for k, v := range tc.giveHeader {
+				req.Header.Set(k, v)
+			}
+
+			h.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.wantCode, rec.Code)
+			assert.Equal(t, tc.wantBody, rec.Body.String())
+
+			for k, v := range tc.wantHeader {
+				assert.Equal(t, v, rec.Header().Get(k))
+			}
+		})
+	}
+}
+
+func TestNew_Unreachable(t *testing.T) {
+	t.Parallel()
+
+	// take a free port, and release it, so nothing listens on it
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	assert.NoError(t, ln.Close())
+
+	var (
+		h   = proxy.New(logger.NewNop(), &url.URL{Scheme: "http", Host: ln.Addr().String()}, 0, nil, "", errorPage(t))
+		req = httptest.NewRequest(http.MethodPost, "/api.json", nil)
+		rec = httptest.NewRecorder()
+	)
+
+	h.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadGateway, rec.Code)
+	assert.Equal(t, "json 502", rec.Body.String())
+	assert.Equal(t, "application/json; charset=utf-8", rec.Header().Get("Content-Type"))
+}
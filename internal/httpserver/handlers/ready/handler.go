@@ -0,0 +1,39 @@
+package ready
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// New creates a new handler that returns "OK" for GET and HEAD requests when isReady reports true, "NOT READY"
+// with 503 status code otherwise, and 405 for other methods. It is intended to be used as a readiness probe for
+// Kubernetes, load balancers, and other orchestrators.
+func New(isReady func() bool) http.Handler {
+	var (
+		okBody, notReadyBody     = []byte("OK\n"), []byte("NOT READY\n")
+		okLength, notReadyLength = strconv.Itoa(len(okBody)), strconv.Itoa(len(notReadyBody))
+	)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch m := r.Method; m {
+		case http.MethodGet, http.MethodHead:
+			status, body, length := http.StatusOK, okBody, okLength
+
+			if isReady == nil || !isReady() {
+				status, body, length = http.StatusServiceUnavailable, notReadyBody, notReadyLength
+			}
+
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			w.Header().Set("Content-Length", length)
+			w.WriteHeader(status)
+
+			if m == http.MethodGet {
+				_, _ = w.Write(body) //nolint:errcheck
+			}
+
+		default:
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		}
+	})
+}
@@ -0,0 +1,100 @@
+package ready_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"gh.tarampamp.am/error-pages/v4/internal/httpserver/handlers/ready"
+	"gh.tarampamp.am/error-pages/v4/internal/testutil/assert"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	var (
+		okBody, notReadyBody = []byte("OK\n"), []byte("NOT READY\n")
+		isReady              = func() bool { return true }
+		isNotReady           = func() bool { return false }
+	)
+
+	for name, tc := range map[string]struct {
+		giveIsReady func() bool
+		giveMethod  string
+		wantStatus  int
+		wantHeaders map[string]string
+		wantBody    []byte
+	}{
+		"GET when ready": {
+			giveIsReady: isReady,
+			giveMethod:  http.MethodGet,
+			wantStatus:  http.StatusOK,
+			wantHeaders: map[string]string{
+				"Content-Type":   "text/plain; charset=utf-8",
+				"Content-Length": strconv.Itoa(len(okBody)),
+			},
+			wantBody: okBody,
+		},
+		"HEAD when ready": {
+			giveIsReady: isReady,
+			giveMethod:  http.MethodHead,
+			wantStatus:  http.StatusOK,
+			wantHeaders: map[string]string{"Content-Length": strconv.Itoa(len(okBody))},
+			wantBody:    []byte{},
+		},
+		"GET when not ready": {
+			giveIsReady: isNotReady,
+			giveMethod:  http.MethodGet,
+			wantStatus:  http.StatusServiceUnavailable,
+			wantHeaders: map[string]string{
+				"Content-Type":   "text/plain; charset=utf-8",
+				"Content-Length": strconv.Itoa(len(notReadyBody)),
+			},
+			wantBody: notReadyBody,
+		},
+		"HEAD when not ready": {
+			giveIsReady: isNotReady,
+			giveMethod:  http.MethodHead,
+			wantStatus:  http.StatusServiceUnavailable,
+			wantHeaders: map[string]string{"Content-Length": strconv.Itoa(len(notReadyBody))},
+			wantBody:    []byte{},
+		},
+		"nil checker means not ready": {
+			giveMethod: http.MethodGet,
+			wantStatus: http.StatusServiceUnavailable,
+			wantBody:   notReadyBody,
+		},
+		"POST is not allowed": {
+			giveIsReady: isReady,
+			giveMethod:  http.MethodPost,
+			wantStatus:  http.StatusMethodNotAllowed,
+			wantHeaders: map[string]string{"Allow": "GET, HEAD"},
+			wantBody:    []byte("Method Not Allowed\n"),
+		},
+		"DELETE is not allowed": {
+			giveIsReady: isNotReady,
+			giveMethod:  http.MethodDelete,
+			wantStatus:  http.StatusMethodNotAllowed,
+			wantHeaders: map[string]string{"Allow": "GET, HEAD"},
+			wantBody:    []byte("Method Not Allowed\n"),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(tc.giveMethod, "/ready", nil)
+			rec := httptest.NewRecorder()
+
+			ready.New(tc.giveIsReady).ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.wantStatus, rec.Code)
+
+			for header, want := range tc.wantHeaders {
+				assert.Equal(t, want, rec.Header().Get(header))
+			}
+
+			assert.Equal(t, string(tc.wantBody), rec.Body.String())
+		})
+	}
+}
@@ -20,6 +20,8 @@ var ErrServerAlreadyStarted = errors.New("httpserver: server already started")
 type Server struct {
 	srv             *http.Server
 	shutdownTimeout time.Duration
+	drainDelay      time.Duration
+	readiness       *atomic.Bool
 	started         atomic.Bool
 }
 
@@ -95,6 +97,34 @@ func WithShutdownTimeout(d time.Duration) Option {
 	}
 }
 
+// WithReadiness sets the flag that reflects the server readiness: it is set to true once the server starts serving,
+// and back to false as soon as the graceful shutdown begins (before the listener is closed). A nil flag is ignored.
+//
+// The same flag is expected to be read by the readiness probe handler, so load balancers stop routing new traffic
+// to the server before it stops accepting connections.
+func WithReadiness(flag *atomic.Bool) Option {
+	return func(s *Server) {
+		if flag == nil {
+			return
+		}
+
+		s.readiness = flag
+	}
+}
+
+// WithDrainDelay sets the amount of time to wait after the server is marked as not ready (see [WithReadiness]) and
+// before it stops accepting new connections, giving load balancers time to notice the readiness change and drain
+// the traffic. Default is zero (no delay). Negative values are normalized to zero.
+func WithDrainDelay(d time.Duration) Option {
+	return func(s *Server) {
+		if d < 0 {
+			d = 0
+		}
+
+		s.drainDelay = d
+	}
+}
+
 // WithErrorLog sets the logger for errors accepting connections and unexpected behavior from handlers.
 // A nil logger is ignored, leaving the previously configured (or default) logger in place.
 //
@@ -145,6 +175,9 @@ func New(handler http.Handler, opts ...Option) *Server {
 //
 // The provided listener will be closed when the server stops.
 //
+// When the context is canceled, the readiness flag (see [WithReadiness]) is reset first, then the server keeps
+// serving for the drain delay (see [WithDrainDelay]), and only after that the graceful shutdown begins.
+//
 // Serve must not be called more than once per [Server] instance. Subsequent calls return [ErrServerAlreadyStarted]
 // immediately without affecting the running server (if any).
 func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
@@ -157,8 +190,27 @@ func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
 	// closing buffered channel is not required here - GC will take care of it, but prefer it as a good practice
 	go func() { defer close(errCh); errCh <- s.srv.Serve(ln) }()
 
+	s.setReady(true)
+	defer s.setReady(false)
+
 	select {
 	case <-ctx.Done():
+		// report "not ready" first, and keep serving for a while (if configured) - so load balancers have a chance
+		// to stop routing new requests to this server before the listener gets closed
+		s.setReady(false)
+
+		if s.drainDelay > 0 {
+			select {
+			case <-time.After(s.drainDelay):
+			case err := <-errCh: // the server stopped by itself during the drain delay
+				if err != nil && !errors.Is(err, http.ErrServerClosed) {
+					return err
+				}
+
+				return nil
+			}
+		}
+
 		// the parent ctx is already canceled here, so we use [context.WithoutCancel] to detach from it before applying
 		// WithTimeout - otherwise shutdownCtx would be canceled immediately and Shutdown would return without waiting
 		// for in-flight requests. This ctx serves only as Shutdown's own drain deadline; it is not propagated to handlers
@@ -201,3 +253,10 @@ func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
 
 	return nil
 }
+
+// setReady updates the readiness flag, if it was set using [WithReadiness].
+func (s *Server) setReady(v bool) {
+	if s.readiness != nil {
+		s.readiness.Store(v)
+	}
+}
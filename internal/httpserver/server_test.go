@@ -6,6 +6,7 @@ import (
 	"log"
 	"net"
 	"net/http"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -38,6 +39,10 @@ func TestNew_WithOptions(t *testing.T) {
 		"shutdown timeout/positive":    {httpserver.WithShutdownTimeout(10 * time.Second)},
 		"shutdown timeout/zero":        {httpserver.WithShutdownTimeout(0)},
 		"shutdown timeout/negative":    {httpserver.WithShutdownTimeout(-1)},
+		"readiness/nil":                {httpserver.WithReadiness(nil)},
+		"readiness/non-nil":            {httpserver.WithReadiness(new(atomic.Bool))},
+		"drain delay/positive":         {httpserver.WithDrainDelay(10 * time.Second)},
+		"drain delay/negative":         {httpserver.WithDrainDelay(-1)},
 		"error log/nil":                {httpserver.WithErrorLog(nil)},
 		"error log/non-nil":            {httpserver.WithErrorLog(log.New(io.Discard, "", 0))},
 	} {
@@ -89,6 +94,64 @@ func TestServe_ContextCancel(t *testing.T) {
 	assert.NoError(t, <-serveDone)
 }
 
+func TestServe_Readiness(t *testing.T) {
+	t.Parallel()
+
+	const drainDelay = 200 * time.Millisecond
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	addr := ln.Addr().String()
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	var (
+		readiness atomic.Bool
+		handler   = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) })
+		srv       = httpserver.New(handler, httpserver.WithReadiness(&readiness), httpserver.WithDrainDelay(drainDelay))
+	)
+
+	assert.False(t, readiness.Load()) // not ready before the server starts
+
+	serveDone := make(chan error, 1)
+
+	go func() { serveDone <- srv.Serve(ctx, ln) }()
+
+	// wait for the server to become ready
+	for deadline := time.Now().Add(time.Second); !readiness.Load(); {
+		if time.Now().After(deadline) {
+			t.Fatal("server did not become ready in time")
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	cancelledAt := time.Now()
+
+	cancel()
+
+	// wait for the server to become not ready (right after the shutdown begins)
+	for deadline := time.Now().Add(time.Second); readiness.Load(); {
+		if time.Now().After(deadline) {
+			t.Fatal("server did not become not ready in time")
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	// during the drain delay the server still accepts new requests
+	resp, httpErr := http.Get("http://" + addr) //nolint:gosec
+	assert.NoError(t, httpErr)
+	assert.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	assert.NoError(t, <-serveDone)
+	assert.True(t, time.Since(cancelledAt) >= drainDelay)
+	assert.False(t, readiness.Load())
+}
+
 func TestServe_ErrAlreadyStarted(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,263 @@
+// Package minify makes the rendered pages smaller: the comments are removed, the whitespace is collapsed, and the
+// inline styles and scripts are minified too. The transformations are conservative - the minified page must look
+// and behave exactly like the original one, so the whitespace is never removed entirely between the text and the
+// elements.
+package minify
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gh.tarampamp.am/error-pages/v4/l10n/generate/jsmin"
+)
+
+// HTML minifies the HTML document. The contents of the <pre> and <textarea> elements are kept as is, the contents
+// of the <style> and <script> elements are minified as CSS and JavaScript (the scripts of other types, e.g. JSON-LD
+// or templates, are kept as is). Conditional comments are kept too.
+func HTML(src []byte) ([]byte, error) {
+	var out = make([]byte, 0, len(src))
+
+	for i := 0; i < len(src); {
+		switch c := src[i]; {
+		case bytes.HasPrefix(src[i:], []byte("<!--")):
+			end := bytes.Index(src[i+4:], []byte("-->"))
+			if end < 0 {
+				return append(out, src[i:]...), nil // unterminated, keep as is
+			}
+
+			comment := src[i : i+4+end+3]
+
+			if bytes.HasPrefix(comment, []byte("<!--[")) {
+				out = append(out, comment...)
+			}
+
+			i += len(comment)
+		case c == '<' && i+1 < len(src) && isTagStart(src[i+1]):
+			end := tagEnd(src, i)
+			tag := src[i:end]
+			out = appendTag(out, tag)
+			i = end
+
+			name := tagName(tag)
+			if tag[1] == '/' || !isRawText(name) {
+				continue
+			}
+
+			// the contents of the element is not HTML, it continues until the closing tag
+			closing := indexFold(src[i:], "</"+name)
+			if closing < 0 {
+				closing = len(src) - i
+			}
+
+			content := string(src[i : i+closing])
+
+			switch name {
+			case "style":
+				content = CSS(content)
+			case "script":
+				if isJavaScript(tag) {
+					minified, err := jsmin.MinifyString(content)
+					if err != nil {
+						return nil, fmt.Errorf("minify script: %w", err)
+					}
+
+					content = strings.TrimSpace(minified)
+				}
+			}
+
+			out = append(out, content...)
+			i += closing
+		case isSpace(c):
+			for i < len(src) && isSpace(src[i]) {
+				i++
+			}
+
+			if len(out) > 0 && i < len(src) && out[len(out)-1] != ' ' { // a removed comment may be in between
+				out = append(out, ' ')
+			}
+		default:
+			out = append(out, c)
+			i++
+		}
+	}
+
+	return out, nil
+}
+
+// CSS minifies the stylesheet: the comments are removed, and the whitespace is collapsed (or removed around the
+// braces, semicolons, commas and child combinators). The strings are kept as is.
+func CSS(src string) string {
+	const separators = "{};,>"
+
+	var (
+		out   = make([]byte, 0, len(src))
+		space bool // pending whitespace
+	)
+
+	for i := 0; i < len(src); {
+		c := src[i]
+
+		switch {
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				end = len(src) - i - 2
+			}
+
+			i += 2 + end + 2
+			space = true
+
+			continue
+		case isSpace(c):
+			i++
+			space = true
+
+			continue
+		}
+
+		if space && len(out) > 0 && !strings.ContainsRune(separators, rune(out[len(out)-1])) &&
+			!strings.ContainsRune(separators, rune(c)) {
+			out = append(out, ' ')
+		}
+
+		space = false
+
+		switch {
+		case c == '"' || c == '\'':
+			end := i + 1
+
+			for end < len(src) && src[end] != c {
+				if src[end] == '\\' {
+					end++
+				}
+
+				end++
+			}
+
+			end = min(end+1, len(src))
+			out = append(out, src[i:end]...)
+			i = end
+		case c == '}' && len(out) > 0 && out[len(out)-1] == ';':
+			out[len(out)-1] = c // the last semicolon in the block is optional
+			i++
+		default:
+			out = append(out, c)
+			i++
+		}
+	}
+
+	return string(out)
+}
+
+// rawTextElements are the elements, which contents is not an HTML markup (or must be kept as is).
+var rawTextElements = map[string]struct{}{ //nolint:gochecknoglobals
+	"script": {}, "style": {}, "pre": {}, "textarea": {},
+}
+
+func isRawText(name string) bool {
+	_, ok := rawTextElements[name]
+
+	return ok
+}
+
+func isSpace(c byte) bool { return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' }
+
+func isTagStart(c byte) bool {
+	return c == '/' || c == '!' || c == '?' || (c|0x20 >= 'a' && c|0x20 <= 'z') //nolint:mnd // lowercase
+}
+
+// tagEnd returns the position after the end of the tag (the quoted attribute values may contain the ">").
+func tagEnd(src []byte, start int) int {
+	var quote byte
+
+	for i := start + 1; i < len(src); i++ {
+		switch c := src[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '>':
+			return i + 1
+		}
+	}
+
+	return len(src)
+}
+
+// appendTag appends the tag with the whitespace collapsed (outside of the quoted attribute values), and removed
+// around the "=" and before the closing ">".
+func appendTag(out, tag []byte) []byte {
+	var (
+		quote byte
+		space bool
+	)
+
+	for _, c := range tag {
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case isSpace(c):
+			space = true
+
+			continue
+		case c == '"' || c == '\'':
+			quote = c
+		}
+
+		if space && c != '>' && c != '=' && out[len(out)-1] != '=' {
+			out = append(out, ' ')
+		}
+
+		space = false
+		out = append(out, c)
+	}
+
+	return out
+}
+
+// tagName returns the lowercase name of the (opening or closing) tag.
+func tagName(tag []byte) string {
+	name := bytes.TrimPrefix(tag[1:], []byte("/"))
+
+	if end := bytes.IndexFunc(name, func(r rune) bool { return r == '>' || r == '/' || isSpace(byte(r)) }); end >= 0 {
+		name = name[:end]
+	}
+
+	return strings.ToLower(string(name))
+}
+
+// indexFold returns the index of the first case-insensitive occurrence of the ASCII substring, or -1.
+func indexFold(s []byte, substr string) int {
+	var sub = []byte(substr)
+
+	for i := 0; i+len(sub) <= len(s); i++ {
+		if bytes.EqualFold(s[i:i+len(sub)], sub) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+var scriptType = regexp.MustCompile(`(?i)\stype\s*=\s*["']?([^"'\s>]*)`) //nolint:gochecknoglobals
+
+// isJavaScript reports whether the script tag has no type, or one of the JavaScript types.
+func isJavaScript(tag []byte) bool {
+	m := scriptType.FindSubmatch(tag)
+	if m == nil {
+		return true
+	}
+
+	switch strings.ToLower(string(m[1])) {
+	case "", "module", "text/javascript", "application/javascript":
+		return true
+	}
+
+	return false
+}
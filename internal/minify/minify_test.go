@@ -0,0 +1,97 @@
+package minify_test
+
+import (
+	"testing"
+
+	"gh.tarampamp.am/error-pages/v4/internal/minify"
+	"gh.tarampamp.am/error-pages/v4/internal/testutil/assert"
+)
+
+func TestHTML(t *testing.T) {
+	t.Parallel()
+
+	for name, tt := range map[string]struct {
+		give, want string
+	}{
+		"empty": {give: "", want: ""},
+		"whitespace": {
+			give: "\n  <!DOCTYPE html>\n<html>\n  <body>\n    <h1>Not   Found</h1>\n  </body>\n</html>\n",
+			want: "<!DOCTYPE html> <html> <body> <h1>Not Found</h1> </body> </html>",
+		},
+		"comments": {
+			give: "<p>foo</p>\n<!-- comment -->\n<p>bar</p><!--[if IE]><p>IE</p><![endif]-->",
+			want: "<p>foo</p> <p>bar</p><!--[if IE]><p>IE</p><![endif]-->",
+		},
+		"tags": {
+			give: "<a\n  href = \"/foo  bar\"\n  title='a > b'  >link</a ><br />",
+			want: "<a href=\"/foo  bar\" title='a > b'>link</a><br />",
+		},
+		"pre and textarea": {
+			give: "<pre>  keep\n  me </pre> <TEXTAREA>  and\n  me</TEXTAREA>",
+			want: "<pre>  keep\n  me </pre> <TEXTAREA>  and\n  me</TEXTAREA>",
+		},
+		"style": {
+			give: "<style>\n  /* comment */\n  a > b,\n  c { color: red; }\n</style>",
+			want: "<style>a>b,c{color: red}</style>",
+		},
+		"script": {
+			give: "<script>\n  // comment\n  var x = 1;\n</script>",
+			want: "<script>var x=1;</script>",
+		},
+		"script of other type": {
+			give: "<script type=\"application/ld+json\">\n  {\"a\":  1}\n</script>",
+			want: "<script type=\"application/ld+json\">\n  {\"a\":  1}\n</script>",
+		},
+		"module script": {
+			give: "<script type='module'>\n  const  y = 2 ;\n</script>",
+			want: "<script type='module'>const y=2;</script>",
+		},
+		"text with less-than sign": {
+			give: "<p>1 < 2</p>",
+			want: "<p>1 < 2</p>",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := minify.HTML([]byte(tt.give))
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+
+	t.Run("broken script", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := minify.HTML([]byte("<script>var s = 'unterminated</script>"))
+
+		assert.ErrorContains(t, err, "minify script")
+	})
+}
+
+func TestCSS(t *testing.T) {
+	t.Parallel()
+
+	for name, tt := range map[string]struct {
+		give, want string
+	}{
+		"empty":       {give: "", want: ""},
+		"declaration": {give: "a {\n  color : red ;\n  margin: 0 auto;\n}\n", want: "a{color : red;margin: 0 auto}"},
+		"descendants": {give: "article  ul li:hover ,  a :not(.b) { x: y }", want: "article ul li:hover,a :not(.b){x: y}"},
+		"media": {
+			give: "@media screen and (min-width: 2000px) {\n  html, body { font-size: 20px; }\n}",
+			want: "@media screen and (min-width: 2000px){html,body{font-size: 20px}}",
+		},
+		"strings":       {give: `a::before { content: "  /* not a comment */  " }`, want: `a::before{content: "  /* not a comment */  "}`},
+		"escaped quote": {give: `a { content: 'it\'s  fine' }`, want: `a{content: 'it\'s  fine'}`},
+		"comments":      {give: "a/**/b{}/* unterminated", want: "a b{}"},
+		"calc":          {give: "a { width: calc(100% - 2px) }", want: "a{width: calc(100% - 2px)}"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, minify.CSS(tt.give))
+		})
+	}
+}
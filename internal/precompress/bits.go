@@ -0,0 +1,36 @@
+package precompress
+
+import "math/bits"
+
+// bitWriter packs the bits starting from the least significant bit of every byte, which is the bit order of both
+// brotli and zstd streams.
+type bitWriter struct {
+	buf []byte
+	acc uint64 // pending bits, the oldest ones are the least significant
+	n   uint   // number of the pending bits
+}
+
+// write appends the lowest nBits (up to 32) of the value.
+func (w *bitWriter) write(value uint64, nBits uint) {
+	w.acc |= (value & (1<<nBits - 1)) << w.n
+	w.n += nBits
+
+	for w.n >= 8 { //nolint:mnd
+		w.buf = append(w.buf, byte(w.acc))
+		w.acc >>= 8
+		w.n -= 8
+	}
+}
+
+// bytes pads the pending bits with zeros up to the byte boundary and returns the written data.
+func (w *bitWriter) bytes() []byte {
+	if w.n > 0 {
+		w.buf = append(w.buf, byte(w.acc))
+		w.acc, w.n = 0, 0
+	}
+
+	return w.buf
+}
+
+// highBit returns the position of the highest set bit (the value must be positive).
+func highBit(v uint32) uint { return uint(bits.Len32(v)) - 1 }
@@ -0,0 +1,426 @@
+package precompress
+
+import (
+	"cmp"
+	"slices"
+)
+
+// The brotli encoder (RFC 7932) is intentionally simple: a single block type for every category, no context
+// modeling and no static dictionary. Every meta-block uses three prefix codes (literals, insert-and-copy lengths
+// and distances), built for the LZ77 sequences found by the matcher.
+
+const (
+	brotliWindowBits   = 22
+	brotliMaxDistance  = 1<<brotliWindowBits - 16
+	brotliMaxMetaBlock = 1 << 24
+
+	brotliLiteralAlphabet  = 256
+	brotliCommandAlphabet  = 704
+	brotliDistanceAlphabet = 64 // 16 + NDIRECT + (48 << NPOSTFIX), with NDIRECT = NPOSTFIX = 0
+	brotliMaxCodeLength    = 15
+)
+
+var (
+	brotliInsertBase  = [24]uint32{0, 1, 2, 3, 4, 5, 6, 8, 10, 14, 18, 26, 34, 50, 66, 98, 130, 194, 322, 578, 1090, 2114, 6210, 22594} //nolint:lll
+	brotliInsertExtra = [24]uint8{0, 0, 0, 0, 0, 0, 1, 1, 2, 2, 3, 3, 4, 4, 5, 5, 6, 7, 8, 9, 10, 12, 14, 24}
+	brotliCopyBase    = [24]uint32{2, 3, 4, 5, 6, 7, 8, 9, 10, 12, 14, 18, 22, 30, 38, 54, 70, 102, 134, 198, 326, 582, 1094, 2118} //nolint:lll
+	brotliCopyExtra   = [24]uint8{0, 0, 0, 0, 0, 0, 0, 0, 1, 1, 2, 2, 3, 3, 4, 4, 5, 5, 6, 7, 8, 9, 10, 24}
+)
+
+// brotliCompress compresses the data into a brotli stream.
+func brotliCompress(src []byte) []byte {
+	var w bitWriter
+
+	w.write(1, 1)                   // WBITS is not 16...
+	w.write(brotliWindowBits-17, 3) // ...but 17 + this value
+
+	if len(src) == 0 {
+		w.write(1, 1) // ISLAST
+		w.write(1, 1) // ISLASTEMPTY
+
+		return w.bytes()
+	}
+
+	m := newMatcher(src, brotliMaxDistance)
+
+	for start := 0; start < len(src); start += brotliMaxMetaBlock {
+		end := min(start+brotliMaxMetaBlock, len(src))
+
+		brotliMetaBlock(&w, src, m.parse(start, end), start, end)
+	}
+
+	return w.bytes()
+}
+
+// brotliCommand is a sequence, converted to the brotli symbols.
+type brotliCommand struct {
+	sequence
+
+	cmd            uint16 // insert-and-copy length symbol
+	insert, copy   uint8  // insert and copy length codes
+	dist, distBits uint8  // distance symbol and the number of its extra bits
+	distExtra      uint32
+}
+
+func newBrotliCommand(s sequence) brotliCommand {
+	var c = brotliCommand{sequence: s}
+
+	c.insert = brotliLengthCode(&brotliInsertBase, uint32(s.litLen)) //nolint:gosec // limited by the block size
+
+	if s.matchLen > 0 {
+		c.copy = brotliLengthCode(&brotliCopyBase, uint32(s.matchLen)) //nolint:gosec // limited by the block size
+
+		// with NPOSTFIX = NDIRECT = 0, distance symbol 16 + d encodes the distances
+		// ((2 + (d & 1)) << (1 + d >> 1)) - 3 and up, using 1 + d >> 1 extra bits
+		x := uint32(s.dist) + 3 //nolint:gosec,mnd // limited by the window size
+		n := highBit(x) - 1
+		hi := x>>n - 2 //nolint:mnd
+
+		c.dist = uint8(16 + 2*(n-1) + uint(hi)) //nolint:gosec,mnd // up to 63
+		c.distBits = uint8(n)                   //nolint:gosec // up to 24
+		c.distExtra = x - (2+hi)<<n             //nolint:mnd
+	}
+
+	// the explicit distance cells of the insert-and-copy lengths table (the trailing literals use them too, but the
+	// decoder stops before the copy, since the meta-block is complete)
+	var cell uint16
+
+	switch ins, cp := c.insert>>3, c.copy>>3; {
+	case ins == 0 && cp == 0:
+		cell = 128
+	case ins == 0 && cp == 1:
+		cell = 192
+	case ins == 1 && cp == 0:
+		cell = 256
+	case ins == 1 && cp == 1:
+		cell = 320
+	case ins == 0 && cp == 2:
+		cell = 384
+	case ins == 2 && cp == 0:
+		cell = 448
+	case ins == 1 && cp == 2:
+		cell = 512
+	case ins == 2 && cp == 1:
+		cell = 576
+	default:
+		cell = 640
+	}
+
+	c.cmd = cell + uint16(c.insert&7)<<3 | uint16(c.copy&7) //nolint:mnd
+
+	return c
+}
+
+// brotliLengthCode returns the code for the insert or copy length.
+func brotliLengthCode(base *[24]uint32, n uint32) uint8 {
+	code := len(base) - 1
+
+	for base[code] > n {
+		code--
+	}
+
+	return uint8(code) //nolint:gosec // up to 23
+}
+
+// brotliMetaBlock writes a compressed meta-block with the data between start and end (the last meta-block is
+// the one, that reaches the end of the data).
+func brotliMetaBlock(w *bitWriter, src []byte, seqs []sequence, start, end int) {
+	var (
+		isLast   = end == len(src)
+		commands = make([]brotliCommand, 0, len(seqs))
+
+		litFreq  = make([]int, brotliLiteralAlphabet)
+		cmdFreq  = make([]int, brotliCommandAlphabet)
+		distFreq = make([]int, brotliDistanceAlphabet)
+	)
+
+	for pos, s := start, seqs; len(s) > 0; s = s[1:] {
+		if s[0].litLen == 0 && s[0].matchLen == 0 {
+			continue // no trailing literals
+		}
+
+		c := newBrotliCommand(s[0])
+
+		for _, b := range src[pos : pos+c.litLen] {
+			litFreq[b]++
+		}
+
+		cmdFreq[c.cmd]++
+
+		if c.matchLen > 0 {
+			distFreq[c.dist]++
+		}
+
+		pos += c.litLen + c.matchLen
+		commands = append(commands, c)
+	}
+
+	// the header
+	var (
+		mLen    = uint64(end - start - 1) //nolint:gosec // positive
+		nibbles = uint(4)                 //nolint:mnd
+	)
+
+	for mLen >= 1<<(4*nibbles) {
+		nibbles++
+	}
+
+	w.write(boolBit(isLast), 1)
+
+	if isLast {
+		w.write(0, 1) // ISLASTEMPTY
+	}
+
+	w.write(uint64(nibbles-4), 2) //nolint:mnd // MNIBBLES
+	w.write(mLen, 4*nibbles)      //nolint:mnd // MLEN - 1
+
+	if !isLast {
+		w.write(0, 1) // ISUNCOMPRESSED
+	}
+
+	w.write(0, 1) // NBLTYPESL = 1
+	w.write(0, 1) // NBLTYPESI = 1
+	w.write(0, 1) // NBLTYPESD = 1
+	w.write(0, 2) // NPOSTFIX = 0
+	w.write(0, 4) // NDIRECT = 0
+	w.write(0, 2) // the context mode for the literals (does not matter for a single prefix code)
+	w.write(0, 1) // NTREESL = 1
+	w.write(0, 1) // NTREESD = 1
+
+	var (
+		litLengths, litCodes   = brotliWritePrefixCode(w, litFreq, 8)  //nolint:mnd // alphabet bits
+		cmdLengths, cmdCodes   = brotliWritePrefixCode(w, cmdFreq, 10) //nolint:mnd
+		distLengths, distCodes = brotliWritePrefixCode(w, distFreq, 6) //nolint:mnd
+	)
+
+	// the data
+	for pos, cmds := start, commands; len(cmds) > 0; cmds = cmds[1:] {
+		c := &cmds[0]
+
+		w.write(uint64(cmdCodes[c.cmd]), uint(cmdLengths[c.cmd]))
+		w.write(uint64(uint32(c.litLen)-brotliInsertBase[c.insert]), uint(brotliInsertExtra[c.insert])) //nolint:gosec
+
+		var copyLen = uint32(2) //nolint:mnd // any, the copy of the trailing literals is ignored
+
+		if c.matchLen > 0 {
+			copyLen = uint32(c.matchLen) //nolint:gosec // limited by the block size
+		}
+
+		w.write(uint64(copyLen-brotliCopyBase[c.copy]), uint(brotliCopyExtra[c.copy]))
+
+		for _, b := range src[pos : pos+c.litLen] {
+			w.write(uint64(litCodes[b]), uint(litLengths[b]))
+		}
+
+		if c.matchLen > 0 {
+			w.write(uint64(distCodes[c.dist]), uint(distLengths[c.dist]))
+			w.write(uint64(c.distExtra), uint(c.distBits))
+		}
+
+		pos += c.litLen + c.matchLen
+	}
+}
+
+// brotliWritePrefixCode builds the prefix code for the symbol frequencies, writes its description and returns the
+// code lengths with the (bit-reversed) codes.
+func brotliWritePrefixCode(w *bitWriter, freq []int, alphabetBits uint) ([]uint8, []uint32) {
+	var (
+		lengths = huffmanLengths(freq, brotliMaxCodeLength)
+		used    []int
+	)
+
+	for sym, l := range lengths {
+		if l > 0 {
+			used = append(used, sym)
+		}
+	}
+
+	if len(used) > 4 { //nolint:mnd
+		brotliWriteComplexPrefixCode(w, lengths)
+
+		return lengths, canonicalCodes(lengths)
+	}
+
+	// the simple prefix code, the symbols are sorted by the code length
+	if len(used) == 0 {
+		used = []int{0} // nothing to encode, but the code must be described anyway
+	}
+
+	if len(used) == 1 {
+		lengths[used[0]] = 0 // a single symbol takes no bits at all
+	}
+
+	slices.SortStableFunc(used, func(a, b int) int { return cmp.Compare(lengths[a], lengths[b]) })
+
+	w.write(1, 2)                   // HSKIP = 1 means the simple prefix code
+	w.write(uint64(len(used)-1), 2) // NSYM - 1
+
+	for _, sym := range used {
+		w.write(uint64(sym), alphabetBits) //nolint:gosec // positive
+	}
+
+	if len(used) == 4 { //nolint:mnd
+		w.write(uint64(boolBit(lengths[used[0]] == 1)), 1) // tree-select: 1, 2, 3, 3 instead of 2, 2, 2, 2
+	}
+
+	return lengths, canonicalCodes(lengths)
+}
+
+// brotliCodeLengthOrder is the order, in which the code length code lengths are stored.
+var brotliCodeLengthOrder = [18]uint8{1, 2, 3, 4, 0, 5, 17, 6, 16, 7, 8, 9, 10, 11, 12, 13, 14, 15} //nolint:gochecknoglobals,lll
+
+// brotliWriteComplexPrefixCode writes the code lengths (which form a complete code), compressed with the run-length
+// encoding and the code length prefix code.
+func brotliWriteComplexPrefixCode(w *bitWriter, lengths []uint8) {
+	const repeatPrevious, repeatZero = 16, 17
+
+	var (
+		symbols, extra []uint8
+		clFreq         = make([]int, len(brotliCodeLengthOrder))
+		previous       = uint8(8) //nolint:mnd // the initial "previous non-zero code length"
+		last           = len(lengths)
+	)
+
+	for last > 0 && lengths[last-1] == 0 {
+		last-- // the trailing zeros are implied
+	}
+
+	// the repeat codes, that follow each other, form the digits of a single (bigger) repeat count, so the digits
+	// are produced in the reversed order
+	emitRepeats := func(code uint8, reps int, bits uint) {
+		from := len(symbols)
+
+		for {
+			symbols, extra = append(symbols, code), append(extra, uint8(reps&(1<<bits-1))) //nolint:gosec
+			reps >>= bits
+
+			if reps == 0 {
+				break
+			}
+
+			reps--
+		}
+
+		slices.Reverse(symbols[from:])
+		slices.Reverse(extra[from:])
+	}
+
+	for i := 0; i < last; {
+		value, reps := lengths[i], 1
+
+		for i+reps < last && lengths[i+reps] == value {
+			reps++
+		}
+
+		i += reps
+
+		if value == 0 {
+			if reps == 11 { //nolint:mnd // cannot be encoded with the repeat codes
+				symbols, extra = append(symbols, 0), append(extra, 0)
+				reps--
+			}
+
+			if reps < 3 { //nolint:mnd
+				for range reps {
+					symbols, extra = append(symbols, 0), append(extra, 0)
+				}
+			} else {
+				emitRepeats(repeatZero, reps-3, 3) //nolint:mnd
+			}
+
+			continue
+		}
+
+		if value != previous {
+			symbols, extra = append(symbols, value), append(extra, 0)
+			reps--
+		}
+
+		if reps == 7 { //nolint:mnd // cannot be encoded with the repeat codes
+			symbols, extra = append(symbols, value), append(extra, 0)
+			reps--
+		}
+
+		if reps < 3 { //nolint:mnd
+			for range reps {
+				symbols, extra = append(symbols, value), append(extra, 0)
+			}
+		} else {
+			emitRepeats(repeatPrevious, reps-3, 2) //nolint:mnd
+		}
+
+		previous = value
+	}
+
+	for _, s := range symbols {
+		clFreq[s]++
+	}
+
+	var (
+		clLengths = huffmanLengths(clFreq, 5) //nolint:mnd
+		numCodes  int
+	)
+
+	for _, l := range clLengths {
+		if l > 0 {
+			numCodes++
+		}
+	}
+
+	// the code length code lengths, with the static prefix code for them
+	var (
+		toStore = len(brotliCodeLengthOrder)
+		skip    int
+	)
+
+	if numCodes > 1 {
+		for toStore > 0 && clLengths[brotliCodeLengthOrder[toStore-1]] == 0 {
+			toStore--
+		}
+	}
+
+	if clLengths[brotliCodeLengthOrder[0]] == 0 && clLengths[brotliCodeLengthOrder[1]] == 0 {
+		skip = 2
+
+		if clLengths[brotliCodeLengthOrder[2]] == 0 {
+			skip = 3
+		}
+	}
+
+	var (
+		staticCodes   = [6]uint8{0, 7, 3, 2, 1, 15}
+		staticLengths = [6]uint8{2, 4, 3, 2, 2, 4}
+	)
+
+	w.write(uint64(skip), 2) //nolint:gosec // HSKIP
+
+	for _, sym := range brotliCodeLengthOrder[skip:toStore] {
+		l := clLengths[sym]
+		w.write(uint64(staticCodes[l]), uint(staticLengths[l]))
+	}
+
+	clCodes := canonicalCodes(clLengths)
+
+	if numCodes == 1 {
+		clear(clLengths) // a single code takes no bits at all
+	}
+
+	for i, s := range symbols {
+		w.write(uint64(clCodes[s]), uint(clLengths[s]))
+
+		switch s {
+		case repeatPrevious:
+			w.write(uint64(extra[i]), 2) //nolint:mnd
+		case repeatZero:
+			w.write(uint64(extra[i]), 3) //nolint:mnd
+		}
+	}
+}
+
+func boolBit(b bool) uint64 {
+	if b {
+		return 1
+	}
+
+	return 0
+}
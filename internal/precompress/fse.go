@@ -0,0 +1,210 @@
+package precompress
+
+import (
+	"cmp"
+	"slices"
+)
+
+// fseTable is the FSE (tANS) encoding table, built the same way as the decoder builds its decoding table from the
+// normalized distribution.
+type fseTable struct {
+	log            uint8
+	states         []uint16 // the next states, grouped by symbol
+	deltaNbBits    []uint32 // per symbol
+	deltaFindState []int32  // per symbol
+}
+
+// newFSETable builds the table for the normalized distribution (where -1 stands for the "less than one"
+// probability).
+func newFSETable(norm []int16, log uint8) *fseTable {
+	var (
+		size    = 1 << log
+		mask    = size - 1
+		high    = size - 1
+		symbols = make([]uint8, size)
+		cumul   = make([]int, len(norm)+1)
+		t       = fseTable{
+			log:            log,
+			states:         make([]uint16, size),
+			deltaNbBits:    make([]uint32, len(norm)),
+			deltaFindState: make([]int32, len(norm)),
+		}
+	)
+
+	// the "less than one" probability symbols take the last cells
+	for sym, c := range norm {
+		if c == -1 {
+			cumul[sym+1] = cumul[sym] + 1
+			symbols[high] = uint8(sym) //nolint:gosec // up to 255
+			high--
+		} else {
+			cumul[sym+1] = cumul[sym] + int(c)
+		}
+	}
+
+	// the rest are spread over the table
+	var step, pos = size>>1 + size>>3 + 3, 0 //nolint:mnd
+
+	for sym, c := range norm {
+		for range max(c, 0) {
+			symbols[pos] = uint8(sym) //nolint:gosec // up to 255
+
+			pos = (pos + step) & mask
+
+			for pos > high {
+				pos = (pos + step) & mask
+			}
+		}
+	}
+
+	for u, sym := range symbols {
+		t.states[cumul[sym]] = uint16(size + u) //nolint:gosec // up to 2^13
+		cumul[sym]++
+	}
+
+	var total int32
+
+	for sym, c := range norm {
+		switch {
+		case c == 0:
+			// never encoded
+		case c == -1 || c == 1:
+			t.deltaNbBits[sym] = uint32(log)<<16 - uint32(size) //nolint:gosec,mnd
+			t.deltaFindState[sym] = total - 1
+			total++
+		default:
+			maxBitsOut := uint32(log) - uint32(highBit(uint32(c-1))) //nolint:gosec // positive
+			minStatePlus := uint32(c) << maxBitsOut                  //nolint:gosec // positive
+
+			t.deltaNbBits[sym] = maxBitsOut<<16 - minStatePlus //nolint:mnd
+			t.deltaFindState[sym] = total - int32(c)
+			total += int32(c)
+		}
+	}
+
+	return &t
+}
+
+// fseState is the encoder state; the symbols are encoded in the reverse order.
+type fseState struct {
+	table *fseTable
+	value uint32
+}
+
+// init sets the initial state for the (last) symbol, without writing anything.
+func (s *fseState) init(sym uint8) {
+	var (
+		nbBits = (s.table.deltaNbBits[sym] + 1<<15) >> 16 //nolint:mnd
+		value  = nbBits<<16 - s.table.deltaNbBits[sym]    //nolint:mnd
+	)
+
+	s.value = uint32(s.table.states[int32(value>>nbBits)+s.table.deltaFindState[sym]]) //nolint:gosec
+}
+
+// encode writes the bits of the current state and moves to the state for the symbol.
+func (s *fseState) encode(w *bitWriter, sym uint8) {
+	nbBits := (s.value + s.table.deltaNbBits[sym]) >> 16 //nolint:mnd
+
+	w.write(uint64(s.value), uint(nbBits))
+	s.value = uint32(s.table.states[int32(s.value>>nbBits)+s.table.deltaFindState[sym]]) //nolint:gosec
+}
+
+// flush writes the final state.
+func (s *fseState) flush(w *bitWriter) { w.write(uint64(s.value), uint(s.table.log)) }
+
+// fseNormalize scales the frequencies to the sum of 2^log, so that every used symbol gets at least one cell.
+func fseNormalize(freq []int, log uint8) []int16 {
+	type remainder struct{ sym, value int }
+
+	var (
+		norm              = make([]int16, len(freq))
+		total, used, left int
+		remainders        []remainder
+	)
+
+	for _, f := range freq {
+		if f > 0 {
+			total += f
+			used++
+		}
+	}
+
+	left = 1<<log - used
+
+	for sym, f := range freq {
+		if f > 0 {
+			share := f * (1<<log - used) / total
+
+			norm[sym] = int16(1 + share) //nolint:gosec // up to 2^log
+			left -= share
+			remainders = append(remainders, remainder{sym, f * (1<<log - used) % total})
+		}
+	}
+
+	// the cells left after the rounding go to the symbols with the largest remainders
+	slices.SortStableFunc(remainders, func(a, b remainder) int { return cmp.Compare(b.value, a.value) })
+
+	for _, r := range remainders[:left] {
+		norm[r.sym]++
+	}
+
+	return norm
+}
+
+// fseWriteNormalized writes the FSE table description (the accuracy log and the normalized distribution).
+func fseWriteNormalized(w *bitWriter, norm []int16, log uint8) {
+	var (
+		remaining = 1<<log + 1
+		threshold = 1 << log
+		nbBits    = uint(log) + 1
+		prevZero  bool
+	)
+
+	w.write(uint64(log-5), 4) //nolint:mnd
+
+	for sym := 0; sym < len(norm) && remaining > 1; {
+		if prevZero {
+			start := sym
+
+			for sym < len(norm) && norm[sym] == 0 {
+				sym++
+			}
+
+			for ; sym >= start+24; start += 24 {
+				w.write(0xFFFF, 16) //nolint:mnd // eight times "three more zeros"
+			}
+
+			for ; sym >= start+3; start += 3 {
+				w.write(3, 2) //nolint:mnd
+			}
+
+			w.write(uint64(sym-start), 2) //nolint:gosec,mnd
+		}
+
+		var (
+			count = int(norm[sym])
+			limit = 2*threshold - 1 - remaining
+		)
+
+		sym++
+		remaining -= max(count, -count)
+		count++ // -1 becomes zero
+
+		if count >= threshold {
+			count += limit
+		}
+
+		if count < limit {
+			w.write(uint64(count), nbBits-1) //nolint:gosec // positive
+		} else {
+			w.write(uint64(count), nbBits) //nolint:gosec // positive
+		}
+
+		prevZero = count == 1
+
+		for remaining < threshold {
+			nbBits--
+			threshold >>= 1
+		}
+	}
+}
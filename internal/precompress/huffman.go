@@ -0,0 +1,151 @@
+package precompress
+
+import (
+	"cmp"
+	"slices"
+)
+
+// huffmanLengths returns the prefix code lengths for the symbol frequencies, limited to maxBits. Unused symbols
+// get the zero length. When at least two symbols are used, the code is complete (the Kraft sum is exactly one);
+// a single used symbol gets the length of one.
+func huffmanLengths(freq []int, maxBits uint8) []uint8 {
+	type node struct {
+		freq        int
+		left, right int // children indexes, -1 for leaves
+	}
+
+	var (
+		lengths = make([]uint8, len(freq))
+		used    []int // symbols sorted by frequency, ascending
+	)
+
+	for sym, f := range freq {
+		if f > 0 {
+			used = append(used, sym)
+		}
+	}
+
+	switch len(used) {
+	case 0:
+		return lengths
+	case 1:
+		lengths[used[0]] = 1
+
+		return lengths
+	}
+
+	slices.SortStableFunc(used, func(a, b int) int { return cmp.Compare(freq[a], freq[b]) })
+
+	// build the tree using two queues: the sorted leaves, and the internal nodes (which are created in
+	// non-decreasing frequency order)
+	var nodes = make([]node, 0, 2*len(used))
+
+	for _, sym := range used {
+		nodes = append(nodes, node{freq: freq[sym], left: -1, right: -1})
+	}
+
+	var leaf, inner = 0, len(used)
+
+	pick := func() int {
+		if leaf < len(used) && (inner >= len(nodes) || nodes[leaf].freq <= nodes[inner].freq) {
+			leaf++
+
+			return leaf - 1
+		}
+
+		inner++
+
+		return inner - 1
+	}
+
+	for range len(used) - 1 {
+		a, b := pick(), pick()
+		nodes = append(nodes, node{freq: nodes[a].freq + nodes[b].freq, left: a, right: b})
+	}
+
+	// count the leaves on every depth
+	var (
+		count = make([]int, len(used)+1)
+		walk  func(i, depth int)
+	)
+
+	walk = func(i, depth int) {
+		if nodes[i].left < 0 {
+			count[depth]++
+
+			return
+		}
+
+		walk(nodes[i].left, depth+1)
+		walk(nodes[i].right, depth+1)
+	}
+
+	walk(len(nodes)-1, 0)
+
+	// move the too deep leaves up, keeping the code complete (JPEG, Annex K.3)
+	for depth := len(count) - 1; depth > int(maxBits); depth-- {
+		for count[depth] > 0 {
+			j := depth - 2
+			for count[j] == 0 {
+				j--
+			}
+
+			count[depth] -= 2
+			count[depth-1]++
+			count[j+1] += 2
+			count[j]--
+		}
+	}
+
+	// the least frequent symbols get the longest codes
+	var i int
+
+	for depth := min(len(count)-1, int(maxBits)); depth > 0; depth-- {
+		for range count[depth] {
+			lengths[used[i]] = uint8(depth) //nolint:gosec // limited by maxBits
+			i++
+		}
+	}
+
+	return lengths
+}
+
+// reverseBits reverses the lowest n bits of the value.
+func reverseBits(v uint32, n uint8) uint32 {
+	var r uint32
+
+	for range n {
+		r = r<<1 | v&1
+		v >>= 1
+	}
+
+	return r
+}
+
+// canonicalCodes assigns the canonical prefix codes (as in deflate) to the code lengths. The codes are bit-reversed,
+// since the prefix codes are packed starting from the most significant bit.
+func canonicalCodes(lengths []uint8) []uint32 {
+	var count, next [16]uint32
+
+	for _, l := range lengths {
+		count[l]++
+	}
+
+	count[0] = 0
+
+	for bits, code := 1, uint32(0); bits < len(next); bits++ {
+		code = (code + count[bits-1]) << 1
+		next[bits] = code
+	}
+
+	codes := make([]uint32, len(lengths))
+
+	for sym, l := range lengths {
+		if l > 0 {
+			codes[sym] = reverseBits(next[l], l)
+			next[l]++
+		}
+	}
+
+	return codes
+}
@@ -0,0 +1,135 @@
+package precompress
+
+import "encoding/binary"
+
+const (
+	minMatch  = 4       // shorter matches are not worth it for both formats
+	hashLog   = 16      // size of the hash table (in bits)
+	maxChain  = 64      // how many candidates to check at most for every position
+	maxLength = 1 << 16 // longest match to look for
+)
+
+// sequence is a run of literals followed by a match (the match is empty for the trailing literals).
+type sequence struct {
+	litLen, matchLen, dist int
+}
+
+// matcher finds the LZ77 matches using hash chains. The chains are kept between the calls, so the matches may
+// reference any data before the current position (within the maximal distance).
+type matcher struct {
+	src     []byte
+	maxDist int
+	head    []int32 // the last position for every hash
+	prev    []int32 // the previous position with the same hash, for every position
+	next    int     // the first position, that is not inserted yet
+}
+
+func newMatcher(src []byte, maxDist int) *matcher {
+	m := matcher{
+		src:     src,
+		maxDist: maxDist,
+		head:    make([]int32, 1<<hashLog),
+		prev:    make([]int32, len(src)),
+	}
+
+	for i := range m.head {
+		m.head[i] = -1
+	}
+
+	return &m
+}
+
+func (m *matcher) hash(i int) uint32 {
+	return (binary.LittleEndian.Uint32(m.src[i:]) * 2654435761) >> (32 - hashLog) //nolint:mnd
+}
+
+// insertUpTo adds all positions before the given one to the hash chains.
+func (m *matcher) insertUpTo(end int) {
+	for ; m.next < end; m.next++ {
+		if m.next+minMatch > len(m.src) {
+			continue
+		}
+
+		h := m.hash(m.next)
+		m.prev[m.next], m.head[h] = m.head[h], int32(m.next) //nolint:gosec // the input size is limited
+	}
+}
+
+// find returns the longest match for the position, that does not cross the end.
+func (m *matcher) find(pos, end int) (length, dist int) {
+	limit := min(end-pos, maxLength)
+	if limit < minMatch {
+		return 0, 0
+	}
+
+	m.insertUpTo(pos)
+
+	for cand, depth := m.head[m.hash(pos)], 0; cand >= 0 && depth < maxChain; cand, depth = m.prev[cand], depth+1 {
+		c := int(cand)
+
+		if pos-c > m.maxDist {
+			break
+		}
+
+		if length > 0 && m.src[c+length] != m.src[pos+length] {
+			continue // cannot be longer than the current best
+		}
+
+		var n int
+
+		for n < limit && m.src[c+n] == m.src[pos+n] {
+			n++
+		}
+
+		if n > length {
+			length, dist = n, pos-c
+
+			if n == limit {
+				break
+			}
+		}
+	}
+
+	if length < minMatch {
+		return 0, 0
+	}
+
+	return length, dist
+}
+
+// parse splits the data between start and end into the sequences (using the lazy matching). The last sequence
+// holds the trailing literals only.
+func (m *matcher) parse(start, end int) []sequence {
+	var (
+		result []sequence
+		anchor = start
+	)
+
+	for pos := start; pos < end; {
+		length, dist := m.find(pos, end)
+		if length == 0 {
+			pos++
+
+			continue
+		}
+
+		// maybe the match at the next position is longer
+		for pos+1 < end {
+			nextLength, nextDist := m.find(pos+1, end)
+			if nextLength <= length {
+				break
+			}
+
+			pos, length, dist = pos+1, nextLength, nextDist
+		}
+
+		result = append(result, sequence{litLen: pos - anchor, matchLen: length, dist: dist})
+
+		pos += length
+		anchor = pos
+	}
+
+	m.insertUpTo(end)
+
+	return append(result, sequence{litLen: end - anchor})
+}
@@ -0,0 +1,100 @@
+// Package precompress compresses the static files ahead of time, so that the web servers can serve the compressed
+// copies (e.g. nginx with "gzip_static", or Caddy with "precompressed") instead of compressing them on the fly.
+//
+// The brotli and zstd encoders are implemented here, since there is no standard library support for them (and the
+// project has no third-party runtime dependencies). They are simple and fast rather than optimal - the output is
+// usually close to the gzip one, but it is produced for a handful of small files only.
+package precompress
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"strings"
+)
+
+// Encoding is an enumeration of the supported content encodings.
+type Encoding byte
+
+const (
+	Gzip   Encoding = iota // gzip
+	Brotli                 // br
+	Zstd                   // zstd
+)
+
+// Encodings returns all supported encodings.
+func Encodings() []Encoding { return []Encoding{Gzip, Brotli, Zstd} }
+
+// String returns the name of the encoding, as used in the Content-Encoding header (e.g. "br"). The name can be
+// parsed back using [FromString].
+func (e Encoding) String() string {
+	switch e {
+	case Gzip:
+		return "gzip"
+	case Brotli:
+		return "br"
+	case Zstd:
+		return "zstd"
+	}
+
+	return "unknown"
+}
+
+// FromString returns the encoding for the given name (case-insensitive). Besides the names returned by
+// [Encoding.String], the full names and the file extensions ("gz", "brotli", "zst") are accepted too.
+func FromString(s string) (Encoding, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "gzip", "gz":
+		return Gzip, true
+	case "br", "brotli":
+		return Brotli, true
+	case "zstd", "zst":
+		return Zstd, true
+	}
+
+	return Encoding(0), false
+}
+
+// Extension returns the file extension (with the leading dot) for the encoding, or an empty string for unknown
+// encodings.
+func (e Encoding) Extension() string {
+	switch e {
+	case Gzip:
+		return ".gz"
+	case Brotli:
+		return ".br"
+	case Zstd:
+		return ".zst"
+	}
+
+	return ""
+}
+
+// Compress compresses the data using the encoding.
+func (e Encoding) Compress(data []byte) ([]byte, error) {
+	switch e {
+	case Gzip:
+		var buf bytes.Buffer
+
+		w, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err = w.Write(data); err != nil {
+			return nil, err
+		}
+
+		if err = w.Close(); err != nil {
+			return nil, err
+		}
+
+		return buf.Bytes(), nil
+	case Brotli:
+		return brotliCompress(data), nil
+	case Zstd:
+		return zstdCompress(data), nil
+	}
+
+	return nil, fmt.Errorf("unsupported encoding: %d", e)
+}
@@ -0,0 +1,197 @@
+package precompress_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"math/rand/v2"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gh.tarampamp.am/error-pages/v4/internal/precompress"
+	"gh.tarampamp.am/error-pages/v4/internal/testutil/assert"
+)
+
+func TestEncoding_String(t *testing.T) {
+	t.Parallel()
+
+	for giveEncoding, want := range map[precompress.Encoding]struct{ name, ext string }{
+		precompress.Gzip:         {"gzip", ".gz"},
+		precompress.Brotli:       {"br", ".br"},
+		precompress.Zstd:         {"zstd", ".zst"},
+		precompress.Encoding(42): {"unknown", ""},
+	} {
+		assert.Equal(t, want.name, giveEncoding.String())
+		assert.Equal(t, want.ext, giveEncoding.Extension())
+	}
+}
+
+func TestFromString(t *testing.T) {
+	t.Parallel()
+
+	for give, want := range map[string]precompress.Encoding{
+		"gzip": precompress.Gzip, "GZ": precompress.Gzip,
+		"br": precompress.Brotli, " brotli ": precompress.Brotli,
+		"zstd": precompress.Zstd, "zst": precompress.Zstd,
+	} {
+		got, ok := precompress.FromString(give)
+
+		assert.True(t, ok)
+		assert.Equal(t, want, got)
+	}
+
+	for _, e := range precompress.Encodings() {
+		got, ok := precompress.FromString(e.String())
+
+		assert.True(t, ok)
+		assert.Equal(t, e, got)
+	}
+
+	_, ok := precompress.FromString("deflate")
+	assert.False(t, ok)
+}
+
+// testInputs returns the inputs of different kinds: empty, tiny, highly repetitive, incompressible and real ones.
+func testInputs(t *testing.T) map[string][]byte {
+	t.Helper()
+
+	var (
+		rnd    = rand.New(rand.NewPCG(1, 2)) //nolint:gosec
+		noise  = make([]byte, 100_000)
+		skewed = make([]byte, 200_000)
+	)
+
+	for i := range noise {
+		noise[i] = byte(rnd.Uint32())
+	}
+
+	for i := range skewed {
+		skewed[i] = "aaaabbbcde  \n<>xyz"[rnd.IntN(18)]
+	}
+
+	page, err := os.ReadFile(filepath.Join("testdata", "page.html"))
+	assert.NoError(t, err)
+
+	return map[string][]byte{
+		"empty":     {},
+		"one byte":  []byte("a"),
+		"short":     []byte("hello, hello, hello world"),
+		"zeros":     make([]byte, 300_000), // more than one block
+		"noise":     noise,
+		"skewed":    skewed,
+		"real text": page,
+	}
+}
+
+func TestEncoding_Compress_Gzip(t *testing.T) {
+	t.Parallel()
+
+	for name, in := range testInputs(t) {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			compressed, err := precompress.Gzip.Compress(in)
+			assert.NoError(t, err)
+
+			r, err := gzip.NewReader(bytes.NewReader(compressed))
+			assert.NoError(t, err)
+
+			got, err := io.ReadAll(r)
+			assert.NoError(t, err)
+			assert.True(t, bytes.Equal(in, got))
+		})
+	}
+}
+
+// TestEncoding_Compress checks the output of the brotli and zstd encoders with the reference decoders, if they are
+// installed.
+func TestEncoding_Compress(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		encoding precompress.Encoding
+		decoder  string
+	}{
+		{precompress.Brotli, "brotli"},
+		{precompress.Zstd, "zstd"},
+	} {
+		for name, in := range testInputs(t) {
+			t.Run(tt.encoding.String()+", "+name, func(t *testing.T) {
+				t.Parallel()
+
+				compressed, err := tt.encoding.Compress(in)
+				assert.NoError(t, err)
+
+				if len(in) > 1000 && !strings.Contains(name, "noise") {
+					assert.True(t, len(compressed) < len(in)*3/4)
+				}
+
+				bin, lookErr := exec.LookPath(tt.decoder)
+				if lookErr != nil {
+					t.Skipf("%s is not installed", tt.decoder)
+				}
+
+				var out bytes.Buffer
+
+				cmd := exec.CommandContext(t.Context(), bin, "-d", "-c")
+				cmd.Stdin, cmd.Stdout = bytes.NewReader(compressed), &out
+
+				assert.NoError(t, cmd.Run())
+				assert.True(t, bytes.Equal(in, out.Bytes()))
+			})
+		}
+	}
+}
+
+// TestEncoding_Compress_Golden compares the output of the brotli and zstd encoders with the golden files in the
+// testdata directory, which were checked with the reference decoders. The encoders are deterministic, so any change
+// of the output must be checked with the reference decoders again (see [TestEncoding_Compress]).
+func TestEncoding_Compress_Golden(t *testing.T) {
+	t.Parallel()
+
+	var inputs = map[string][]byte{"zeros": make([]byte, 300_000)} // more than one block
+
+	for _, name := range []string{"page.html", "short.txt"} {
+		content, err := os.ReadFile(filepath.Join("testdata", name))
+		assert.NoError(t, err)
+
+		inputs[name] = content
+	}
+
+	for _, e := range []precompress.Encoding{precompress.Brotli, precompress.Zstd} {
+		for name, in := range inputs {
+			t.Run(e.String()+", "+name, func(t *testing.T) {
+				t.Parallel()
+
+				want, err := os.ReadFile(filepath.Join("testdata", name+e.Extension()))
+				assert.NoError(t, err)
+
+				got, err := e.Compress(in)
+				assert.NoError(t, err)
+				assert.True(t, bytes.Equal(want, got))
+			})
+		}
+	}
+}
+
+func TestEncoding_Compress_Empty(t *testing.T) {
+	t.Parallel()
+
+	got, err := precompress.Zstd.Compress(nil)
+	assert.NoError(t, err)
+
+	// magic, single segment frame with the zero content size, and an empty raw last block
+	assert.DeepEqual(t, []byte{0x28, 0xB5, 0x2F, 0xFD, 0x20, 0x00, 0x01, 0x00, 0x00}, got)
+
+	got, err = precompress.Brotli.Compress(nil)
+	assert.NoError(t, err)
+
+	// window bits (22), then the last and empty meta-block
+	assert.DeepEqual(t, []byte{0x3B}, got)
+
+	_, err = precompress.Encoding(42).Compress(nil)
+	assert.ErrorContains(t, err, "unsupported encoding")
+}
@@ -0,0 +1,489 @@
+package precompress
+
+import (
+	"encoding/binary"
+	"slices"
+)
+
+// The zstd encoder (RFC 8878) writes a single-segment frame. The literals are compressed with a Huffman code, and
+// the sequences use the predefined FSE distributions (so there are no tables to describe). Blocks, that do not
+// benefit from the compression, are stored as is.
+
+const (
+	zstdMagic        = 0xFD2FB528
+	zstdMaxBlockSize = 1 << 17
+
+	zstdBlockRaw        = 0
+	zstdBlockCompressed = 2
+
+	zstdLiteralsRaw        = 0
+	zstdLiteralsRLE        = 1
+	zstdLiteralsCompressed = 2
+
+	zstdModeCompressed = 2
+
+	zstdMaxHuffmanBits = 11
+	zstdMinLiterals    = 64 // do not bother compressing fewer literals
+)
+
+// zstdCompress compresses the data into a zstd frame.
+func zstdCompress(src []byte) []byte {
+	var out = binary.LittleEndian.AppendUint32(nil, zstdMagic)
+
+	// the frame header: the single segment flag is set, so the window size is the content size
+	switch n := uint64(len(src)); {
+	case n < 1<<8:
+		out = append(out, 0b00_1_0_0_0_00, byte(n))
+	case n < 1<<16+1<<8:
+		out = binary.LittleEndian.AppendUint16(append(out, 0b01_1_0_0_0_00), uint16(n-1<<8))
+	case n < 1<<32:
+		out = binary.LittleEndian.AppendUint32(append(out, 0b10_1_0_0_0_00), uint32(n))
+	default:
+		out = binary.LittleEndian.AppendUint64(append(out, 0b11_1_0_0_0_00), n)
+	}
+
+	m := newMatcher(src, len(src))
+
+	for start := 0; ; start += zstdMaxBlockSize {
+		var (
+			end    = min(start+zstdMaxBlockSize, len(src))
+			last   = uint32(boolBit(end == len(src))) //nolint:gosec // 0 or 1
+			size   = uint32(end - start)              //nolint:gosec // up to the block size
+			header = last | zstdBlockRaw<<1 | size<<3
+		)
+
+		if content := zstdBlock(src, m.parse(start, end), start); len(content) < end-start {
+			header = last | zstdBlockCompressed<<1 | uint32(len(content))<<3 //nolint:gosec
+
+			out = append(append(out, byte(header), byte(header>>8), byte(header>>16)), content...)
+		} else {
+			out = append(append(out, byte(header), byte(header>>8), byte(header>>16)), src[start:end]...)
+		}
+
+		if last == 1 {
+			return out
+		}
+	}
+}
+
+// zstdBlock returns the content of the compressed block.
+func zstdBlock(src []byte, seqs []sequence, start int) []byte {
+	var lits []byte
+
+	for pos, s := start, seqs; len(s) > 0; s = s[1:] {
+		lits = append(lits, src[pos:pos+s[0].litLen]...)
+		pos += s[0].litLen + s[0].matchLen
+	}
+
+	out := zstdLiterals(lits)
+
+	seqs = seqs[:len(seqs)-1] // the trailing literals are implied
+
+	// the number of sequences
+	switch n := len(seqs); {
+	case n == 0:
+		return append(out, 0)
+	case n < 128: //nolint:mnd
+		out = append(out, byte(n))
+	case n < 0x7F00: //nolint:mnd
+		out = append(out, byte(n>>8+128), byte(n)) //nolint:mnd
+	default:
+		out = append(out, 255, byte(n-0x7F00), byte((n-0x7F00)>>8)) //nolint:mnd
+	}
+
+	return append(out, zstdSequences(seqs)...)
+}
+
+// zstdLiterals returns the literals section.
+func zstdLiterals(lits []byte) []byte {
+	var freq = make([]int, 256) //nolint:mnd
+
+	for _, b := range lits {
+		freq[b]++
+	}
+
+	if len(lits) > 1 && freq[lits[0]] == len(lits) {
+		return append(zstdLiteralsHeader(zstdLiteralsRLE, len(lits)), lits[0])
+	}
+
+	raw := append(zstdLiteralsHeader(zstdLiteralsRaw, len(lits)), lits...)
+
+	if len(lits) < zstdMinLiterals {
+		return raw
+	}
+
+	var (
+		lengths = huffmanLengths(freq, zstdMaxHuffmanBits)
+		maxBits = slices.Max(lengths)
+		weights = make([]uint8, 0, len(lengths))
+	)
+
+	for _, l := range lengths {
+		var w uint8
+
+		if l > 0 {
+			w = maxBits + 1 - l
+		}
+
+		weights = append(weights, w)
+	}
+
+	for weights[len(weights)-1] == 0 {
+		weights = weights[:len(weights)-1]
+	}
+
+	// the weight of the last symbol is implied
+	desc := zstdHuffmanDescription(weights[:len(weights)-1])
+	if desc == nil {
+		return raw
+	}
+
+	var (
+		codes   = zstdHuffmanCodes(weights)
+		streams [][]byte
+	)
+
+	if len(lits) < 1<<10 {
+		streams = [][]byte{zstdHuffmanStream(lits, codes, lengths)}
+	} else {
+		segment := (len(lits) + 3) / 4 //nolint:mnd
+
+		for i := range 4 {
+			streams = append(streams, zstdHuffmanStream(lits[min(i*segment, len(lits)):min((i+1)*segment, len(lits))],
+				codes, lengths,
+			))
+		}
+	}
+
+	var body = desc
+
+	if len(streams) > 1 {
+		for _, s := range streams[:3] { // the jump table
+			if len(s) > 0xFFFF { //nolint:mnd
+				return raw
+			}
+
+			body = binary.LittleEndian.AppendUint16(body, uint16(len(s))) //nolint:gosec // checked above
+		}
+	}
+
+	for _, s := range streams {
+		body = append(body, s...)
+	}
+
+	var (
+		regen, comp = uint64(len(lits)), uint64(len(body))
+		header      []byte
+	)
+
+	switch size := max(regen, comp); {
+	case len(streams) == 1:
+		if size >= 1<<10 {
+			return raw // does not fit the single stream header
+		}
+
+		header = binary.LittleEndian.AppendUint32(nil, uint32(zstdLiteralsCompressed|regen<<4|comp<<14))[:3]
+	case size < 1<<10:
+		header = binary.LittleEndian.AppendUint32(nil, uint32(zstdLiteralsCompressed|1<<2|regen<<4|comp<<14))[:3]
+	case size < 1<<14:
+		header = binary.LittleEndian.AppendUint32(nil, uint32(zstdLiteralsCompressed|2<<2|regen<<4|comp<<18))
+	case size < 1<<18:
+		header = binary.LittleEndian.AppendUint64(nil, zstdLiteralsCompressed|3<<2|regen<<4|comp<<22)[:5]
+	default:
+		return raw
+	}
+
+	if len(header)+len(body) >= len(raw) {
+		return raw
+	}
+
+	return append(header, body...)
+}
+
+// zstdLiteralsHeader returns the header of the raw or RLE literals section.
+func zstdLiteralsHeader(kind byte, size int) []byte {
+	switch {
+	case size < 1<<5:
+		return []byte{kind | byte(size)<<3}
+	case size < 1<<12:
+		return []byte{kind | 1<<2 | byte(size)<<4, byte(size >> 4)}
+	default:
+		return []byte{kind | 3<<2 | byte(size)<<4, byte(size >> 4), byte(size >> 12)}
+	}
+}
+
+// zstdHuffmanCodes assigns the prefix codes to the symbols with the given weights (including the last symbol): the
+// symbols with the lowest weights (the longest codes) get the lowest codes, in the symbol order.
+func zstdHuffmanCodes(weights []uint8) []uint32 {
+	var (
+		count [zstdMaxHuffmanBits + 2]uint32
+		next  [zstdMaxHuffmanBits + 2]uint32
+		codes = make([]uint32, 256) //nolint:mnd
+	)
+
+	for _, w := range weights {
+		count[w]++
+	}
+
+	for w, start := 1, uint32(0); w < len(count); w++ {
+		next[w] = start
+		start += count[w] << (w - 1)
+	}
+
+	for sym, w := range weights {
+		if w > 0 {
+			codes[sym] = next[w] >> (w - 1)
+			next[w] += 1 << (w - 1)
+		}
+	}
+
+	return codes
+}
+
+// zstdHuffmanStream encodes the literals into a single Huffman stream, which is read backwards.
+func zstdHuffmanStream(lits []byte, codes []uint32, lengths []uint8) []byte {
+	var w bitWriter
+
+	for i := len(lits) - 1; i >= 0; i-- {
+		w.write(uint64(codes[lits[i]]), uint(lengths[lits[i]]))
+	}
+
+	w.write(1, 1) // the end mark
+
+	return w.bytes()
+}
+
+// zstdHuffmanDescription returns the Huffman tree description for the weights (without the last one), either FSE
+// compressed or stored directly, whichever is shorter. It returns nil if the weights cannot be described.
+func zstdHuffmanDescription(weights []uint8) []byte {
+	var direct []byte
+
+	if len(weights) <= 128 { //nolint:mnd
+		direct = append(direct, byte(127+len(weights))) //nolint:mnd
+
+		for i := 0; i < len(weights); i += 2 {
+			var b = weights[i] << 4 //nolint:mnd
+
+			if i+1 < len(weights) {
+				b |= weights[i+1]
+			}
+
+			direct = append(direct, b)
+		}
+	}
+
+	if compressed := zstdCompressWeights(weights); compressed != nil && len(compressed) < 128 { //nolint:mnd
+		if direct == nil || len(compressed)+1 < len(direct) {
+			return append([]byte{byte(len(compressed))}, compressed...)
+		}
+	}
+
+	return direct
+}
+
+// zstdCompressWeights compresses the Huffman weights using FSE with two interleaved states. It returns nil when the
+// weights cannot be compressed.
+func zstdCompressWeights(weights []uint8) []byte {
+	const tableLog = 6 // the maximum for the weights
+
+	var (
+		freq     = make([]int, zstdMaxHuffmanBits+2)
+		distinct int
+	)
+
+	for _, w := range weights {
+		if freq[w] == 0 {
+			distinct++
+		}
+
+		freq[w]++
+	}
+
+	if len(weights) <= 2 || distinct < 2 { //nolint:mnd
+		return nil
+	}
+
+	for freq[len(freq)-1] == 0 {
+		freq = freq[:len(freq)-1]
+	}
+
+	var (
+		norm = fseNormalize(freq, tableLog)
+		w    bitWriter
+	)
+
+	fseWriteNormalized(&w, norm, tableLog)
+
+	var (
+		table  = newFSETable(norm, tableLog)
+		header = w.bytes()
+		s1, s2 = fseState{table: table}, fseState{table: table}
+		bw     bitWriter
+		i      = len(weights) - 1
+	)
+
+	// the even symbols go to the first state, the odd ones to the second; the first state is read first
+	if len(weights)%2 == 1 {
+		s1.init(weights[i])
+		s2.init(weights[i-1])
+		s1.encode(&bw, weights[i-2])
+
+		i -= 3
+	} else {
+		s2.init(weights[i])
+		s1.init(weights[i-1])
+
+		i -= 2
+	}
+
+	for ; i > 0; i -= 2 {
+		s2.encode(&bw, weights[i])
+		s1.encode(&bw, weights[i-1])
+	}
+
+	s2.flush(&bw)
+	s1.flush(&bw)
+	bw.write(1, 1) // the end mark
+
+	return append(header, bw.bytes()...)
+}
+
+// zstdCodes are the codes of a sequence, with their extra bits.
+type zstdCodes struct {
+	ll, ml, of                uint8
+	llExtra, mlExtra, ofExtra uint32
+}
+
+// zstdSequences returns the symbol compression modes, the FSE table descriptions and the sequences bitstream. The
+// sequences are encoded using both the predefined and the custom FSE tables, and the shorter result is returned.
+func zstdSequences(seqs []sequence) []byte {
+	var (
+		codes   = make([]zstdCodes, len(seqs))
+		symbols [3][]uint8 // literal lengths, offsets and match lengths codes (the order of the table descriptions)
+	)
+
+	for i, s := range seqs {
+		var (
+			ll = uint32(s.litLen)   //nolint:gosec // limited by the block size
+			ml = uint32(s.matchLen) //nolint:gosec // limited by the block size
+			of = uint32(s.dist) + 3 //nolint:gosec,mnd // the offset value, 1-3 are the repeat codes
+			c  zstdCodes
+		)
+
+		c.ll = zstdLengthCode(zstdLiteralLengthBase[:], ll)
+		c.ml = zstdLengthCode(zstdMatchLengthBase[:], ml)
+		c.of = uint8(highBit(of)) //nolint:gosec // up to 31
+		c.llExtra = ll - zstdLiteralLengthBase[c.ll]
+		c.mlExtra = ml - zstdMatchLengthBase[c.ml]
+		c.ofExtra = of - 1<<c.of
+
+		codes[i] = c
+		symbols[0], symbols[1], symbols[2] = append(symbols[0], c.ll), append(symbols[1], c.of), append(symbols[2], c.ml)
+	}
+
+	var (
+		predefined = append([]byte{0}, zstdEncodeSequences(codes, zstdDefaultTables)...)
+		tables     = zstdDefaultTables
+		custom     = []byte{0}
+	)
+
+	for i, maxLog := range [3]uint8{9, 8, 9} { //nolint:mnd // the maximal accuracy logs
+		var freq = make([]int, 0, 64) //nolint:mnd
+
+		for _, sym := range symbols[i] {
+			if int(sym) >= len(freq) {
+				freq = append(freq, make([]int, int(sym)+1-len(freq))...)
+			}
+
+			freq[sym]++
+		}
+
+		if slices.Max(freq) == len(symbols[i]) {
+			continue // a single symbol, the predefined table is good enough
+		}
+
+		var (
+			norm = fseNormalize(freq, maxLog)
+			w    bitWriter
+		)
+
+		fseWriteNormalized(&w, norm, maxLog)
+
+		custom = append(custom, w.bytes()...)
+		custom[0] |= zstdModeCompressed << (6 - 2*i) //nolint:mnd
+		tables[i] = newFSETable(norm, maxLog)
+	}
+
+	if custom = append(custom, zstdEncodeSequences(codes, tables)...); len(custom) < len(predefined) {
+		return custom
+	}
+
+	return predefined
+}
+
+// zstdEncodeSequences returns the sequences bitstream, encoded using the tables for the literal lengths, offsets
+// and match lengths.
+func zstdEncodeSequences(codes []zstdCodes, tables [3]*fseTable) []byte {
+	var (
+		ll = fseState{table: tables[0]}
+		of = fseState{table: tables[1]}
+		ml = fseState{table: tables[2]}
+		w  bitWriter
+	)
+
+	writeExtra := func(c zstdCodes) {
+		w.write(uint64(c.llExtra), uint(zstdLiteralLengthBits[c.ll]))
+		w.write(uint64(c.mlExtra), uint(zstdMatchLengthBits[c.ml]))
+		w.write(uint64(c.ofExtra), uint(c.of))
+	}
+
+	// the sequences are encoded backwards, since the decoder reads the stream from the end
+	last := codes[len(codes)-1]
+
+	ml.init(last.ml)
+	of.init(last.of)
+	ll.init(last.ll)
+	writeExtra(last)
+
+	for i := len(codes) - 2; i >= 0; i-- { //nolint:mnd
+		c := codes[i]
+
+		of.encode(&w, c.of)
+		ml.encode(&w, c.ml)
+		ll.encode(&w, c.ll)
+		writeExtra(c)
+	}
+
+	ml.flush(&w)
+	of.flush(&w)
+	ll.flush(&w)
+	w.write(1, 1) // the end mark
+
+	return w.bytes()
+}
+
+// zstdLengthCode returns the code for the literal or match length.
+func zstdLengthCode(base []uint32, n uint32) uint8 {
+	code := len(base) - 1
+
+	for base[code] > n {
+		code--
+	}
+
+	return uint8(code) //nolint:gosec // the tables are short
+}
+
+//nolint:gochecknoglobals,lll
+var (
+	zstdLiteralLengthBase = [36]uint32{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 18, 20, 22, 24, 28, 32, 40, 48, 64, 128, 256, 512, 1024, 2048, 4096, 8192, 16384, 32768, 65536}
+	zstdLiteralLengthBits = [36]uint8{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 1, 1, 1, 2, 2, 3, 3, 4, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+	zstdMatchLengthBase   = [53]uint32{3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31, 32, 33, 34, 35, 37, 39, 41, 43, 47, 51, 59, 67, 83, 99, 131, 259, 515, 1027, 2051, 4099, 8195, 16387, 32771, 65539}
+	zstdMatchLengthBits   = [53]uint8{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 1, 1, 1, 2, 2, 3, 3, 4, 4, 5, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+
+	// the predefined distributions, in the order of the table descriptions
+	zstdDefaultTables = [3]*fseTable{
+		newFSETable([]int16{4, 3, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 1, 1, 1, 2, 2, 2, 2, 2, 2, 2, 2, 2, 3, 2, 1, 1, 1, 1, 1, -1, -1, -1, -1}, 6),
+		newFSETable([]int16{1, 1, 1, 1, 1, 1, 2, 2, 2, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, -1, -1, -1, -1, -1}, 5),
+		newFSETable([]int16{1, 4, 3, 2, 2, 2, 2, 2, 2, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, -1, -1, -1, -1, -1, -1, -1}, 6),
+	}
+)
@@ -0,0 +1,335 @@
+// Package preview implements a live preview of the error page templates: a gallery of every code and format, that
+// is reloaded in the browser (using server-sent events) as soon as the templates change.
+package preview
+
+import (
+	"bytes"
+	_ "embed"
+	"html/template"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gh.tarampamp.am/error-pages/v4/internal/formats"
+	"gh.tarampamp.am/error-pages/v4/internal/httpserver/handlers/error_page"
+	"gh.tarampamp.am/error-pages/v4/internal/logger"
+	tpl "gh.tarampamp.am/error-pages/v4/internal/template"
+)
+
+//go:embed gallery.tpl.html
+var galleryTemplate string
+
+// galleryTpl is the parsed gallery page template.
+var galleryTpl = template.Must(template.New("gallery").Parse(galleryTemplate)) //nolint:gochecknoglobals
+
+// Loader loads (or reloads) the templates. It is called on start and on every [Preview.Reload].
+type Loader func() (*tpl.Templates, error)
+
+// FakeHeaders maps the query parameters of the render endpoint to the request headers they fake, so the request
+// details (like the ingress name) can be previewed without a real ingress controller in front of the server.
+var FakeHeaders = map[string]string{ //nolint:gochecknoglobals
+	"original_uri":  "X-Original-Uri",
+	"namespace":     "X-Namespace",
+	"ingress_name":  "X-Ingress-Name",
+	"service_name":  "X-Service-Name",
+	"service_port":  "X-Service-Port",
+	"request_id":    "X-Request-Id",
+	"forwarded_for": "X-Forwarded-For",
+	"host":          "Host",
+}
+
+// Preview is an [http.Handler] that serves the gallery page, the rendered error pages, and the stream of reload
+// events.
+type Preview struct {
+	log         *logger.Logger
+	load        Loader
+	describer   error_page.CodeDescriber
+	codes       []uint16
+	homepageURL string
+	links       []tpl.Link
+
+	state atomic.Pointer[state]
+
+	mu      sync.Mutex
+	clients map[chan event]struct{}
+	done    chan struct{}
+	closed  sync.Once
+}
+
+// state is the result of the last templates loading.
+type state struct {
+	templates *tpl.Templates
+	err       error
+}
+
+// event is sent to the connected browsers when the templates are reloaded.
+type event struct {
+	name, data string
+}
+
+// New creates a new [Preview] for the given codes, and loads the templates. Loading errors are not fatal - they
+// are shown in the browser, until the templates are fixed and reloaded.
+func New(
+	log *logger.Logger,
+	load Loader,
+	describer error_page.CodeDescriber,
+	codes []uint16,
+	homepageURL string,
+	links []tpl.Link,
+) *Preview {
+	p := &Preview{
+		log:         log,
+		load:        load,
+		describer:   describer,
+		codes:       codes,
+		homepageURL: homepageURL,
+		links:       links,
+		clients:     make(map[chan event]struct{}),
+		done:        make(chan struct{}),
+	}
+
+	p.reload()
+
+	return p
+}
+
+// Reload reloads the templates and notifies all connected browsers.
+func (p *Preview) Reload() {
+	ev := event{name: "reload"}
+
+	if err := p.reload(); err != nil {
+		ev = event{name: "failure", data: err.Error()}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for ch := range p.clients {
+		select {
+		case ch <- ev:
+		default: // the client is too slow, skip the event
+		}
+	}
+}
+
+// reload loads the templates and stores the result.
+func (p *Preview) reload() error {
+	templates, err := p.load()
+	if err != nil {
+		p.log.Error("Failed to load the templates", logger.Error(err))
+	}
+
+	p.state.Store(&state{templates: templates, err: err})
+
+	return err
+}
+
+// Close disconnects all connected browsers. It should be called before the server shutdown, since the event streams
+// never end by themselves.
+func (p *Preview) Close() { p.closed.Do(func() { close(p.done) }) }
+
+// ServeHTTP implements the [http.Handler] interface.
+func (p *Preview) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	switch {
+	case r.URL.Path == "/":
+		p.serveGallery(w)
+	case r.URL.Path == "/events":
+		p.serveEvents(w, r)
+	case strings.HasPrefix(r.URL.Path, "/render/"):
+		p.serveRender(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// serveGallery renders the gallery page.
+func (p *Preview) serveGallery(w http.ResponseWriter) {
+	type (
+		codeItem struct {
+			Code    uint16
+			Message string
+		}
+		formatItem struct {
+			Name, Ext string
+		}
+	)
+
+	var data struct {
+		Codes   []codeItem
+		Formats []formatItem
+		Fields  []string
+		Error   string
+	}
+
+	for _, code := range p.codes {
+		desc, ok := p.describer(code)
+		if !ok {
+			desc.Short = http.StatusText(int(code))
+		}
+
+		data.Codes = append(data.Codes, codeItem{code, desc.Short})
+	}
+
+	for _, f := range formats.All() {
+		data.Formats = append(data.Formats, formatItem{f.String(), f.Extension()})
+	}
+
+	data.Fields = []string{
+		"host", "original_uri", "forwarded_for", "namespace", "ingress_name", "service_name", "service_port",
+		"request_id",
+	}
+
+	if s := p.state.Load(); s != nil && s.err != nil {
+		data.Error = s.err.Error()
+	}
+
+	var buf bytes.Buffer
+
+	if err := galleryTpl.Execute(&buf, data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+
+	_, _ = buf.WriteTo(w)
+}
+
+// serveRender renders the error page for the code and format from the URL path (like "/render/404.html"), using the
+// request details from the query parameters.
+func (p *Preview) serveRender(w http.ResponseWriter, r *http.Request) {
+	var (
+		name    = strings.TrimPrefix(r.URL.Path, "/render/")
+		ext     = path.Ext(name)
+		query   = r.URL.Query()
+		details = query.Get("details") == "1"
+	)
+
+	code, codeErr := strconv.ParseUint(strings.TrimSuffix(name, ext), 10, 16)
+
+	format, ok := formats.FromString(strings.TrimPrefix(ext, "."))
+	if codeErr != nil || code == 0 || code > 999 || !ok {
+		http.NotFound(w, r)
+
+		return
+	}
+
+	s := p.state.Load()
+	if s == nil || s.templates == nil {
+		http.Error(w, "Templates are not loaded", http.StatusServiceUnavailable)
+
+		return
+	}
+
+	// the error page handler reads the code and format from the path, and the details from the headers
+	req := r.Clone(r.Context())
+	req.URL.Path, req.URL.RawQuery = "/"+name, ""
+	req.Header = make(http.Header)
+
+	for param, header := range FakeHeaders {
+		if v := query.Get(param); v != "" && details {
+			if header == "Host" {
+				req.Host = v
+			} else {
+				req.Header.Set(header, v)
+			}
+		}
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+
+	if format == formats.YAMLFormat || format == formats.MarkdownFormat {
+		w = plainTextWriter{w} // the browsers download such responses instead of showing them in the frame
+	}
+
+	error_page.New(
+		p.log,
+		uint16(code),
+		false,
+		nil,
+		nil,
+		nil,
+		p.describer,
+		s.templates.Get,
+		details,
+		query.Get("l10n") == "0",
+		p.homepageURL,
+		p.links,
+		nil,
+	).ServeHTTP(w, req)
+}
+
+// plainTextWriter is an [http.ResponseWriter], that sends the response as plain text.
+type plainTextWriter struct{ http.ResponseWriter }
+
+func (w plainTextWriter) WriteHeader(status int) {
+	w.Header().Set("Content-Type", formats.PlainTextFormat.ContentType())
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// serveEvents streams the reload events to the browser (server-sent events).
+func (p *Preview) serveEvents(w http.ResponseWriter, r *http.Request) {
+	const keepAliveInterval = 15 * time.Second
+
+	rc := http.NewResponseController(w)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+
+	if r.Method == http.MethodHead {
+		return
+	}
+
+	ch := make(chan event, 1)
+
+	p.mu.Lock()
+	p.clients[ch] = struct{}{}
+	p.mu.Unlock()
+
+	defer func() {
+		p.mu.Lock()
+		delete(p.clients, ch)
+		p.mu.Unlock()
+	}()
+
+	if _, err := w.Write([]byte(": connected\n\n")); err != nil || rc.Flush() != nil {
+		return
+	}
+
+	ticker := time.NewTicker(keepAliveInterval)
+	defer ticker.Stop()
+
+	for {
+		var msg string
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-p.done:
+			return
+		case <-ticker.C:
+			msg = ": keep-alive\n\n"
+		case ev := <-ch:
+			msg = "event: " + ev.name + "\ndata: " + strings.ReplaceAll(ev.data, "\n", "\ndata: ") + "\n\n"
+		}
+
+		if _, err := w.Write([]byte(msg)); err != nil || rc.Flush() != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package preview_test
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"gh.tarampamp.am/error-pages/v4/internal/codes"
+	"gh.tarampamp.am/error-pages/v4/internal/logger"
+	"gh.tarampamp.am/error-pages/v4/internal/preview"
+	tpl "gh.tarampamp.am/error-pages/v4/internal/template"
+	"gh.tarampamp.am/error-pages/v4/internal/testutil/assert"
+)
+
+// loader returns a loader, that uses the given JSON template source.
+func loader(src *atomic.Pointer[string]) preview.Loader {
+	return func() (*tpl.Templates, error) {
+		return tpl.NewTemplates(tpl.WithCustomJSONTemplate(*src.Load()))
+	}
+}
+
+func newPreview(t *testing.T, src string) (*preview.Preview, *atomic.Pointer[string]) {
+	t.Helper()
+
+	var p atomic.Pointer[string]
+
+	p.Store(&src)
+
+	return preview.New(logger.NewNop(), loader(&p), codes.New(codes.BuiltInStandard).Find, []uint16{404, 503}, "/", nil), &p
+}
+
+func TestPreview_Gallery(t *testing.T) {
+	t.Parallel()
+
+	p, _ := newPreview(t, `{"code": {{ .StatusCode }}}`)
+
+	rec := httptest.NewRecorder()
+	p.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/html; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(), `"Code":404`)
+	assert.Contains(t, rec.Body.String(), `"Message":"Service Unavailable"`)
+	assert.Contains(t, rec.Body.String(), `value=".json"`)
+	assert.Contains(t, rec.Body.String(), `name="ingress_name"`)
+	assert.Contains(t, rec.Body.String(), `new EventSource('/events')`)
+}
+
+func TestPreview_Render(t *testing.T) {
+	t.Parallel()
+
+	p, _ := newPreview(t,
+		`{"code": {{ .StatusCode }}, "host": {{ .Host | toJson }}, "ingress": {{ .IngressName | toJson }}, `+
+			`"details": {{ .Config.ShowRequestDetails }}, "l10n": {{ not .Config.L10nDisabled }}}`,
+	)
+
+	for name, tt := range map[string]struct {
+		giveURL         string
+		wantStatus      int
+		wantBody        string
+		wantContentType string
+	}{
+		"defaults": {
+			giveURL:    "/render/503.json",
+			wantStatus: http.StatusOK,
+			wantBody:   `{"code": 503, "host": "", "ingress": "", "details": false, "l10n": true}`,
+		},
+		"fake headers without details": {
+			giveURL:    "/render/404.json?host=example.com&ingress_name=ing",
+			wantStatus: http.StatusOK,
+			wantBody:   `{"code": 404, "host": "", "ingress": "", "details": false, "l10n": true}`,
+		},
+		"fake headers with details": {
+			giveURL:    "/render/404.json?details=1&l10n=0&host=example.com&ingress_name=ing",
+			wantStatus: http.StatusOK,
+			wantBody:   `{"code": 404, "host": "example.com", "ingress": "ing", "details": true, "l10n": false}`,
+		},
+		"html": {
+			giveURL:    "/render/404.html",
+			wantStatus: http.StatusOK,
+			wantBody:   "<!DOCTYPE html>",
+		},
+		"markdown as plain text": {
+			giveURL:         "/render/404.md",
+			wantStatus:      http.StatusOK,
+			wantBody:        "# Error 404: Not Found",
+			wantContentType: "text/plain; charset=utf-8",
+		},
+		"unknown format":    {giveURL: "/render/404.pdf", wantStatus: http.StatusNotFound},
+		"wrong code":        {giveURL: "/render/foo.json", wantStatus: http.StatusNotFound},
+		"code out of range": {giveURL: "/render/1000.json", wantStatus: http.StatusNotFound},
+		"unknown path":      {giveURL: "/foo", wantStatus: http.StatusNotFound},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			rec := httptest.NewRecorder()
+			p.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.giveURL, nil))
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+
+			if tt.wantBody != "" {
+				assert.Contains(t, rec.Body.String(), tt.wantBody)
+			}
+
+			if tt.wantContentType != "" {
+				assert.Equal(t, tt.wantContentType, rec.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
+
+func TestPreview_MethodNotAllowed(t *testing.T) {
+	t.Parallel()
+
+	p, _ := newPreview(t, "")
+
+	rec := httptest.NewRecorder()
+	p.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", nil))
+
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	assert.Equal(t, "GET, HEAD", rec.Header().Get("Allow"))
+}
+
+func TestPreview_LoadingError(t *testing.T) {
+	t.Parallel()
+
+	var fail atomic.Bool
+
+	fail.Store(true)
+
+	p := preview.New(logger.NewNop(), func() (*tpl.Templates, error) {
+		if fail.Load() {
+			return nil, errors.New("broken template")
+		}
+
+		return tpl.NewTemplates()
+	}, codes.New(codes.BuiltInStandard).Find, []uint16{404}, "/", nil)
+
+	rec := httptest.NewRecorder()
+	p.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Contains(t, rec.Body.String(), "broken template")
+
+	rec = httptest.NewRecorder()
+	p.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/render/404.json", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+	fail.Store(false)
+	p.Reload()
+
+	rec = httptest.NewRecorder()
+	p.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/render/404.json", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestPreview_Events(t *testing.T) {
+	t.Parallel()
+
+	p, src := newPreview(t, `{"code": {{ .StatusCode }}}`)
+
+	srv := httptest.NewServer(p)
+	t.Cleanup(srv.Close)
+	t.Cleanup(p.Close)
+
+	resp, err := http.Get(srv.URL + "/events") //nolint:noctx
+	assert.NoError(t, err)
+
+	defer func() { _ = resp.Body.Close() }()
+
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	var reader = bufio.NewReader(resp.Body)
+
+	readEvent := func() string {
+		var b strings.Builder
+
+		for {
+			line, readErr := reader.ReadString('\n')
+			assert.NoError(t, readErr)
+
+			if line == "\n" {
+				return b.String()
+			}
+
+			b.WriteString(line)
+		}
+	}
+
+	assert.Equal(t, ": connected\n", readEvent()) // the client is subscribed now
+
+	p.Reload()
+	assert.Equal(t, "event: reload\ndata: \n", readEvent())
+
+	broken := `{{ if }}`
+	src.Store(&broken)
+
+	p.Reload()
+
+	ev := readEvent()
+	assert.Contains(t, ev, "event: failure\n")
+	assert.Contains(t, ev, "custom JSON template parsing")
+
+	p.Close() // the stream must be closed by the server
+
+	_, err = io.ReadAll(reader)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,57 @@
+package preview
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+// fileState is used to detect the file changes (without the inotify-like APIs, to keep things portable).
+type fileState struct {
+	modTime time.Time
+	size    int64
+	exists  bool
+}
+
+// statFiles returns the current state of each file.
+func statFiles(paths []string) []fileState {
+	var result = make([]fileState, len(paths))
+
+	for i, p := range paths {
+		if stat, err := os.Stat(p); err == nil {
+			result[i] = fileState{modTime: stat.ModTime(), size: stat.Size(), exists: true}
+		}
+	}
+
+	return result
+}
+
+// Watch polls the files with the given interval, and calls onChange when any of them is changed, created or
+// removed. It blocks until the context is canceled.
+func Watch(ctx context.Context, interval time.Duration, paths []string, onChange func()) {
+	var (
+		ticker = time.NewTicker(interval)
+		last   = statFiles(paths)
+	)
+
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			current := statFiles(paths)
+
+			for i := range current {
+				if current[i] != last[i] {
+					last = current
+
+					onChange()
+
+					break
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package preview_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gh.tarampamp.am/error-pages/v4/internal/preview"
+	"gh.tarampamp.am/error-pages/v4/internal/testutil/assert"
+)
+
+func TestWatch(t *testing.T) {
+	t.Parallel()
+
+	var (
+		file    = filepath.Join(t.TempDir(), "tpl.html")
+		changes = make(chan struct{}, 1)
+	)
+
+	assert.NoError(t, os.WriteFile(file, []byte("foo"), 0o600))
+
+	ctx, cancel := context.WithCancel(t.Context())
+
+	var done = make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		preview.Watch(ctx, 5*time.Millisecond, []string{file}, func() {
+			select {
+			case changes <- struct{}{}:
+			default:
+			}
+		})
+	}()
+
+	time.Sleep(20 * time.Millisecond) // let the watcher take the initial state
+
+	assert.NoError(t, os.WriteFile(file, []byte("foobar"), 0o600))
+
+	select {
+	case <-changes:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the change was not detected")
+	}
+
+	assert.NoError(t, os.Remove(file))
+
+	select {
+	case <-changes:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the removal was not detected")
+	}
+
+	cancel()
+	<-done
+}
@@ -0,0 +1,132 @@
+// Package raster draws the PNG placeholder images for the error pages - the status code and the message centered on
+// a plain background. The text is drawn using the built-in bitmap font, so the images look the same everywhere, and
+// no fonts are required.
+package raster
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+)
+
+// The indexes of the colors in the palette.
+const (
+	backgroundColor uint8 = iota
+	titleColor
+	subtitleColor
+)
+
+var palette = color.Palette{ //nolint:gochecknoglobals
+	backgroundColor: color.RGBA{R: 0xf2, G: 0xf2, B: 0xf2, A: 0xff},
+	titleColor:      color.RGBA{R: 0x8c, G: 0x8c, B: 0x8c, A: 0xff},
+	subtitleColor:   color.RGBA{R: 0xa6, G: 0xa6, B: 0xa6, A: 0xff},
+}
+
+// PNG draws the image of the given size with the title (e.g. the status code) and the subtitle (e.g. the message)
+// centered on it, and writes it to w in PNG format. The text is scaled to fit the image, the subtitle is truncated if
+// it does not fit even with the smallest scale, and is omitted on the tiny images. The characters out of the
+// printable ASCII range are drawn as '?'.
+func PNG(w io.Writer, width, height int, title, subtitle string) error {
+	if width <= 0 || height <= 0 {
+		return fmt.Errorf("wrong image size %dx%d", width, height)
+	}
+
+	var (
+		img   = image.NewPaletted(image.Rect(0, 0, width, height), palette)
+		top   = []rune(title)
+		below = []rune(subtitle)
+	)
+
+	titleScale := fitScale(len(top), width*4/5, height*2/5) //nolint:mnd // 80% of the width, 40% of the height
+
+	subScale := min(max(titleScale/3, 1), fitScale(len(below), width*9/10, height/5)) //nolint:mnd
+	if subScale == 0 {                                                                // truncate to fit with scale 1
+		below, subScale = truncate(below, width*9/10), 1 //nolint:mnd
+	}
+
+	var (
+		titleHeight = titleScale * glyphHeight
+		gap         = subScale * glyphHeight / 2 //nolint:mnd
+		subHeight   = subScale * glyphHeight
+	)
+
+	if len(below) == 0 || titleHeight+gap+subHeight > height {
+		gap, subHeight = 0, 0
+	}
+
+	y := (height - titleHeight - gap - subHeight) / 2 //nolint:mnd
+
+	if titleScale > 0 {
+		drawText(img, (width-textWidth(len(top))*titleScale)/2, y, titleScale, top, titleColor) //nolint:mnd
+	}
+
+	if subHeight > 0 {
+		y += titleHeight + gap
+
+		drawText(img, (width-textWidth(len(below))*subScale)/2, y, subScale, below, subtitleColor) //nolint:mnd
+	}
+
+	return png.Encode(w, img)
+}
+
+// textWidth returns the width of the text of n characters, in the font pixels (the glyphs are separated by one
+// pixel).
+func textWidth(n int) int {
+	if n == 0 {
+		return 0
+	}
+
+	return n*(glyphWidth+1) - 1
+}
+
+// fitScale returns the largest scale, with which the text of n characters fits into the box, or zero if it does
+// not fit even with scale 1.
+func fitScale(n, maxWidth, maxHeight int) int {
+	if n == 0 {
+		return 0
+	}
+
+	return max(min(maxWidth/textWidth(n), maxHeight/glyphHeight), 0)
+}
+
+// truncate shortens the text to fit into the width with scale 1, replacing the tail with "...". The text is dropped
+// entirely if there is no room for a few characters.
+func truncate(text []rune, maxWidth int) []rune {
+	const ellipsis, minChars = "...", 4
+
+	n := (maxWidth + 1) / (glyphWidth + 1)
+	if n < len(ellipsis)+minChars {
+		return nil
+	}
+
+	if len(text) <= n {
+		return text
+	}
+
+	return append(text[:n-len(ellipsis):n-len(ellipsis)], []rune(ellipsis)...)
+}
+
+// drawText draws the text with its top left corner at (x, y). Each font pixel is drawn as a scale x scale square.
+func drawText(img *image.Paletted, x, y, scale int, text []rune, colorIndex uint8) {
+	for i, r := range text {
+		g := glyph(r)
+
+		for col := range glyphWidth {
+			for row := range glyphHeight {
+				if g[col]&(1<<row) == 0 {
+					continue
+				}
+
+				px, py := x+(i*(glyphWidth+1)+col)*scale, y+row*scale
+
+				for dy := range scale {
+					for dx := range scale {
+						img.SetColorIndex(px+dx, py+dy, colorIndex)
+					}
+				}
+			}
+		}
+	}
+}
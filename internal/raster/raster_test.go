@@ -0,0 +1,72 @@
+package raster_test
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"testing"
+
+	"gh.tarampamp.am/error-pages/v4/internal/raster"
+	"gh.tarampamp.am/error-pages/v4/internal/testutil/assert"
+)
+
+func TestPNG(t *testing.T) {
+	t.Parallel()
+
+	for name, tt := range map[string]struct {
+		giveWidth, giveHeight int
+		giveTitle, giveSub    string
+	}{
+		"default":          {giveWidth: 400, giveHeight: 300, giveTitle: "404", giveSub: "Not Found"},
+		"wide":             {giveWidth: 2048, giveHeight: 16, giveTitle: "503", giveSub: "Service Unavailable"},
+		"tiny":             {giveWidth: 16, giveHeight: 16, giveTitle: "404", giveSub: "Not Found"},
+		"long subtitle":    {giveWidth: 100, giveHeight: 100, giveTitle: "418", giveSub: "I'm a teapot, short and stout"},
+		"non-ascii":        {giveWidth: 200, giveHeight: 100, giveTitle: "404", giveSub: "Страница не найдена"},
+		"without subtitle": {giveWidth: 200, giveHeight: 100, giveTitle: "404"},
+		"empty":            {giveWidth: 20, giveHeight: 10},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+
+			assert.NoError(t, raster.PNG(&buf, tt.giveWidth, tt.giveHeight, tt.giveTitle, tt.giveSub))
+
+			img, err := png.Decode(&buf)
+			assert.NoError(t, err)
+			assert.Equal(t, image.Rect(0, 0, tt.giveWidth, tt.giveHeight), img.Bounds())
+
+			// the corner is always the background
+			r, g, b, _ := img.At(0, 0).RGBA()
+			assert.Equal(t, [3]uint32{0xf2f2, 0xf2f2, 0xf2f2}, [3]uint32{r, g, b})
+		})
+	}
+
+	t.Run("text is drawn", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+
+		assert.NoError(t, raster.PNG(&buf, 400, 300, "404", "Not Found"))
+
+		img, err := png.Decode(&buf)
+		assert.NoError(t, err)
+
+		var colors = make(map[uint32]struct{})
+
+		for y := range 300 {
+			for x := range 400 {
+				r, _, _, _ := img.At(x, y).RGBA()
+				colors[r] = struct{}{}
+			}
+		}
+
+		assert.Equal(t, 3, len(colors)) // the background, the title, and the subtitle
+	})
+
+	t.Run("wrong size", func(t *testing.T) {
+		t.Parallel()
+
+		assert.ErrorContains(t, raster.PNG(&bytes.Buffer{}, 0, 10, "404", ""), "wrong image size 0x10")
+	})
+}
@@ -41,6 +41,12 @@ var (
 	identRe  = regexp.MustCompile(`\.?[a-zA-Z_][a-zA-Z0-9_]*(?:\.[a-zA-Z_][a-zA-Z0-9_]*)*`)
 )
 
+// Rewrite describes a single replacement of a v3 token (or field) with its v4 equivalent.
+type Rewrite struct {
+	Line     int    // 1-based line number of the replaced token in the source
+	Old, New string // the original token and its v4 replacement
+}
+
 // convertV3toV4 takes a template source string and replaces all occurrences of old function-style tokens with
 // their new dot-field path equivalents.
 //
@@ -48,24 +54,68 @@ var (
 // new format. It will be removed in the future, so please update your templates to use the new dot-field syntax
 // as soon as possible.
 func convertV3toV4(src string) string {
-	return actionRe.ReplaceAllStringFunc(src, func(action string) string {
-		inner := actionRe.FindStringSubmatch(action)[1]
-		replaced := identRe.ReplaceAllStringFunc(inner, func(ident string) string {
-			if strings.HasPrefix(ident, ".") {
-				if v, ok := v3tov4Fields[ident]; ok {
-					return v
-				}
-
-				return ident
+	out, _ := rewriteV3toV4(src)
+
+	return out
+}
+
+// rewriteV3toV4 does the same as [convertV3toV4], but also returns the list of all made replacements.
+//
+// Deprecated: see [convertV3toV4].
+func rewriteV3toV4(src string) (string, []Rewrite) {
+	var (
+		out      strings.Builder
+		rewrites []Rewrite
+		last     int // end of the previously processed action in src
+		line     = 1 // line number at the last position
+	)
+
+	out.Grow(len(src))
+
+	for _, action := range actionRe.FindAllStringSubmatchIndex(src, -1) {
+		out.WriteString(src[last:action[2]]) // text before the action, including the opening braces
+
+		line += strings.Count(src[last:action[2]], "\n")
+		inner, pos := src[action[2]:action[3]], 0
+
+		for _, ident := range identRe.FindAllStringIndex(inner, -1) {
+			token := inner[ident[0]:ident[1]]
+
+			var (
+				replacement string
+				ok          bool
+			)
+
+			if strings.HasPrefix(token, ".") {
+				replacement, ok = v3tov4Fields[token]
+			} else {
+				replacement, ok = v3tov4Tokens[token]
 			}
 
-			if v, ok := v3tov4Tokens[ident]; ok {
-				return v
+			if !ok {
+				continue
 			}
 
-			return ident
-		})
+			out.WriteString(inner[pos:ident[0]])
+			out.WriteString(replacement)
+
+			rewrites = append(rewrites, Rewrite{
+				Line: line + strings.Count(inner[:ident[0]], "\n"),
+				Old:  token,
+				New:  replacement,
+			})
+
+			pos = ident[1]
+		}
+
+		out.WriteString(inner[pos:])
+		out.WriteString("}}")
+
+		line += strings.Count(inner, "\n")
+		last = action[1]
+	}
+
+	out.WriteString(src[last:])
 
-		return "{{" + replaced + "}}"
-	})
+	return out.String(), rewrites
 }
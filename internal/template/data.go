@@ -8,20 +8,22 @@ package tpl
 // Note: After adding new fields, make sure to update the test data in [template_test.go] and add tests that verify
 // the new fields are correctly rendered in the templates.
 type Data struct {
-	StatusCode   uint16 // http status code
-	Message      string // status message
-	Description  string // status description
-	OriginalURI  string // (ingress-nginx) URI that caused the error
-	Namespace    string // (ingress-nginx) namespace where the backend Service is located
-	IngressName  string // (ingress-nginx) name of the Ingress where the backend is defined
-	ServiceName  string // (ingress-nginx) name of the Service backing the backend
-	ServicePort  string // (ingress-nginx) port number of the Service backing the backend
-	RequestID    string // (ingress-nginx, Envoy Gateway) unique ID that identifies the request
-	ForwardedFor string // (ingress-nginx, Envoy Gateway) the value of the `X-Forwarded-For` header
-	Host         string // the value of the `Host` header
-	HomepageURL  string // homepage URL (optional, set via --homepage-url)
-	Links        []Link // additional links to display on the error page (optional, set via --add-link)
-	Config       Config // configuration values
+	StatusCode         uint16 // http status code
+	OriginalStatusCode uint16 // http status code before it was remapped (set via --map-code), or the same as StatusCode
+	Message            string // status message
+	Description        string // status description
+	OriginalURI        string // (ingress-nginx) URI that caused the error
+	Namespace          string // (ingress-nginx) namespace where the backend Service is located
+	IngressName        string // (ingress-nginx) name of the Ingress where the backend is defined
+	ServiceName        string // (ingress-nginx) name of the Service backing the backend
+	ServicePort        string // (ingress-nginx) port number of the Service backing the backend
+	RequestID          string // (ingress-nginx, Envoy Gateway) unique ID that identifies the request
+	ForwardedFor       string // (ingress-nginx, Envoy Gateway) the value of the `X-Forwarded-For` header
+	Host               string // the value of the `Host` header
+	HomepageURL        string // homepage URL (optional, set via --homepage-url)
+	Links              []Link // additional links to display on the error page (optional, set via --add-link)
+	Image              Image  // the size of the image (for the image formats, set via the query parameters)
+	Config             Config // configuration values
 
 	// TODO: add incoming request headers as a map[string]string field, so they can be used in the templates?
 }
@@ -34,6 +36,25 @@ type Link struct {
 	URL   string // target URL
 }
 
+// Image holds the size of the image (in pixels), that is rendered for the image formats (SVG and PNG).
+//
+// DO NOT MODIFY EXISTING FIELDS OR THEIR TYPES.
+type Image struct {
+	Width  int
+	Height int
+}
+
+// The default size of the image, and the limits of the size requested by the client.
+const (
+	DefaultImageWidth  = 400
+	DefaultImageHeight = 300
+	MinImageSize       = 16
+	MaxImageSize       = 2048
+)
+
+// DefaultImage returns the image of the default size.
+func DefaultImage() Image { return Image{Width: DefaultImageWidth, Height: DefaultImageHeight} }
+
 // Config holds configuration values that can be used in the templates.
 //
 // DO NOT MODIFY EXISTING FIELDS OR THEIR TYPES.
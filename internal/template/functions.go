@@ -59,6 +59,11 @@ var fns = template.FuncMap{ //nolint:gochecknoglobals
 	//	`{{ "<test>" | escape }}`	// `&lt;test&gt;`
 	"escape": html.EscapeString,
 
+	// returns the escaped string, safe for Markdown contexts (including the table cells). It backslash-escapes the
+	// characters with a special meaning, and replaces the line breaks with spaces:
+	//	`{{ "*bold* [link](x)" | escapeMarkdown }}`	// `\*bold\* \[link\](x)`
+	"escapeMarkdown": markdownEscaper.Replace,
+
 	// returns trimmed string with leading and trailing whitespace removed:
 	//	`{{ "  test  " | trim }}`	// `test`
 	"trim": strings.TrimSpace,
@@ -206,6 +211,21 @@ var fns = template.FuncMap{ //nolint:gochecknoglobals
 	"json": toJSON,
 }
 
+// deprecatedFns maps the names of deprecated template functions to their recommended replacements (an empty value
+// means there is no direct replacement).
+var deprecatedFns = map[string]string{ //nolint:gochecknoglobals
+	"nowUnix":       "now.Unix",
+	"strCount":      "count",
+	"strContains":   "contains",
+	"strTrimSpace":  "trim",
+	"strTrimPrefix": "trimPrefix",
+	"strTrimSuffix": "trimSuffix",
+	"strReplace":    "replace",
+	"strIndex":      "",
+	"strFields":     "fields",
+	"json":          "toJson",
+}
+
 // nowUnix returns the current time in Unix format (seconds since 1970 UTC).
 //
 // Deprecated: use `{{ now.Unix }}` instead.
@@ -218,6 +238,13 @@ func hostname() (hostname string) {
 	return
 }
 
+// markdownEscaper escapes the characters, which may start the Markdown markup (emphasis, links, images, code, HTML
+// tags, headings, and the table cells).
+var markdownEscaper = strings.NewReplacer( //nolint:gochecknoglobals
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`, "<", `\<`, ">", `\>`,
+	"#", `\#`, "|", `\|`, "~", `\~`, "!", `\!`, "\r\n", " ", "\n", " ", "\r", " ",
+)
+
 // toJSON is a helper function that converts any value to its JSON string representation. It ignores any errors during
 // marshaling, returning an empty string if the conversion fails.
 func toJSON(v any) string {
@@ -81,6 +81,11 @@ func TestFunctions(t *testing.T) {
 				want: "&lt;script&gt;alert(&#39;XSS&#39; + &#34;HERE&#34;)&lt;/script&gt;",
 			},
 
+			"escapeMarkdown": {
+				give: "{{ escapeMarkdown \"# *a* _b_ [c](d) <e> `f` | g\\nh\" }}",
+				want: "\\# \\*a\\* \\_b\\_ \\[c\\](d) \\<e\\> \\`f\\` \\| g h",
+			},
+
 			"trimPrefix":            {give: `{{ "test" | trimPrefix "te" }}`, want: "st"},
 			"trimSuffix":            {give: `{{ "test" | trimSuffix "st" }}`, want: "te"},
 			"trimSuffix (non-pipe)": {give: `{{ trimSuffix "st" "test"  }}`, want: "te"},
@@ -0,0 +1,463 @@
+package tpl
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"gh.tarampamp.am/error-pages/v4/internal/formats"
+)
+
+// Severity is the severity level of a [Diagnostic].
+type Severity string
+
+const (
+	SeverityError   Severity = "error"   // the template is broken, or produces broken output
+	SeverityWarning Severity = "warning" // the template works, but something should be fixed
+)
+
+// Lint rules, reported in [Diagnostic.Rule].
+const (
+	RuleParse         = "parse"          // the template cannot be parsed
+	RuleRender        = "render"         // the template cannot be rendered
+	RuleUnknownField  = "unknown-field"  // the template references a field that does not exist
+	RuleDeprecatedFn  = "deprecated-fn"  // the template uses a deprecated function
+	RuleV3Syntax      = "v3-syntax"      // the template uses a v3 token, rewritten at parse time
+	RuleInvalidOutput = "invalid-output" // the rendered JSON/XML is not well-formed
+	RuleL10nMarkers   = "l10n-markers"   // the rendered HTML has no data-l10n markers
+	RuleL10nScript    = "l10n-script"    // the HTML template has data-l10n markers, but no localization script
+	RuleUnescaped     = "unescaped"      // a user-controlled field is rendered without escaping
+)
+
+// Diagnostic is a single problem found by [Lint].
+type Diagnostic struct {
+	Rule     string    `json:"rule"`
+	Severity Severity  `json:"severity"`
+	Message  string    `json:"message"`
+	Line     int       `json:"line,omitempty"` // 1-based line number in the source (0 if unknown)
+	Case     *LintCase `json:"case,omitempty"` // the render case the problem was found in (if any)
+}
+
+// LintCase describes the data a template was rendered with, when the problem was found.
+type LintCase struct {
+	StatusCode         uint16 `json:"status_code"`
+	ShowRequestDetails bool   `json:"show_request_details"`
+	L10nDisabled       bool   `json:"l10n_disabled"`
+}
+
+// String returns a human-readable representation of the case.
+func (c LintCase) String() string {
+	return fmt.Sprintf("code %d, details %s, l10n %s",
+		c.StatusCode, onOff(c.ShowRequestDetails), onOff(!c.L10nDisabled),
+	)
+}
+
+// onOff returns "on" or "off" depending on the given value.
+func onOff(v bool) string {
+	if v {
+		return "on"
+	}
+
+	return "off"
+}
+
+// userControlledFields lists the [Data] fields whose values come from the request headers, and thus can be set
+// by anyone who can send a request to the server.
+var userControlledFields = [...]string{ //nolint:gochecknoglobals
+	"OriginalURI", "Namespace", "IngressName", "ServiceName", "ServicePort", "RequestID", "ForwardedFor", "Host",
+}
+
+// Lint checks the template source (expected to produce the output of the given format) for common problems. Static
+// checks (syntax, unknown fields, deprecated functions and v3 tokens) are performed once, then the template is
+// rendered with each of the given samples (or with an empty [Data] if none given) with request details and
+// localization switched on and off, and the output is checked too.
+//
+// The request details (like [Data.Host]) of the samples are overwritten by the linter.
+func Lint(src string, format formats.Format, samples ...Data) []Diagnostic {
+	var l = linter{format: format, seen: make(map[string]struct{})}
+
+	converted, rewrites := rewriteV3toV4(src)
+
+	for _, r := range rewrites {
+		l.report(Diagnostic{
+			Rule:     RuleV3Syntax,
+			Severity: SeverityWarning,
+			Message:  fmt.Sprintf("v3 token %q is deprecated, use %q instead", r.Old, r.New),
+			Line:     r.Line,
+		})
+	}
+
+	parsed, err := template.New("tpl").Funcs(fns).Parse(converted)
+	if err != nil {
+		l.report(Diagnostic{Rule: RuleParse, Severity: SeverityError, Message: err.Error(), Line: errorLine(err)})
+
+		return l.result
+	}
+
+	l.inspect(parsed)
+
+	var t = Template{tpl: parsed}
+
+	if len(samples) == 0 {
+		samples = []Data{{}}
+	}
+
+	for _, sample := range samples {
+		for _, details := range [...]bool{false, true} {
+			for _, l10nDisabled := range [...]bool{false, true} {
+				sample.Config = Config{ShowRequestDetails: details, L10nDisabled: l10nDisabled}
+
+				l.render(&t, sample)
+			}
+		}
+	}
+
+	return l.result
+}
+
+// linter holds the state of a single [Lint] run.
+type linter struct {
+	format     formats.Format
+	result     []Diagnostic
+	seen       map[string]struct{} // to avoid reporting the same problem for each render case
+	usesScript bool                // the l10nScript function is used by the template
+}
+
+// report appends the diagnostic to the result, unless the same problem was already reported.
+func (l *linter) report(d Diagnostic) {
+	key := d.Rule + "\x00" + strconv.Itoa(d.Line) + "\x00" + d.Message
+
+	if _, dup := l.seen[key]; dup {
+		return
+	}
+
+	l.seen[key] = struct{}{}
+	l.result = append(l.result, d)
+}
+
+// inspect walks the parse trees of the template and reports unknown fields and deprecated functions.
+func (l *linter) inspect(t *template.Template) {
+	for _, tt := range t.Templates() {
+		if tt.Tree == nil || tt.Root == nil {
+			continue
+		}
+
+		w := treeWalker{linter: l, tree: tt.Tree}
+
+		if tt.Name() == t.Name() { // the type of the data is known for the main template only
+			w.root = reflect.TypeFor[Data]()
+		}
+
+		w.walk(tt.Root, w.root)
+	}
+}
+
+// render renders the template with the given data, and checks the output.
+func (l *linter) render(t *Template, data Data) {
+	var lc = LintCase{data.StatusCode, data.Config.ShowRequestDetails, data.Config.L10nDisabled}
+
+	if data.Config.ShowRequestDetails {
+		data.OriginalURI, data.Namespace, data.IngressName = "/path?query=value", "default", "ingress"
+		data.ServiceName, data.ServicePort, data.RequestID = "service", "8080", "0123456789abcdef"
+		data.ForwardedFor, data.Host = "203.0.113.1", "example.com"
+	}
+
+	out, err := t.Render(data)
+	if err != nil {
+		l.report(Diagnostic{Rule: RuleRender, Severity: SeverityError, Message: err.Error(), Case: &lc})
+
+		return
+	}
+
+	if err = validateOutput(l.format, out); err != nil {
+		l.report(Diagnostic{
+			Rule:     RuleInvalidOutput,
+			Severity: SeverityError,
+			Message:  fmt.Sprintf("rendered %s is not valid: %s", strings.ToUpper(l.format.String()), err.Error()),
+			Case:     &lc,
+		})
+	}
+
+	if l.format == formats.HTMLFormat && !data.Config.L10nDisabled {
+		l.checkL10n(string(out), lc)
+	}
+
+	if data.Config.ShowRequestDetails && l.format != formats.PlainTextFormat {
+		l.checkEscaping(t, data, lc)
+	}
+}
+
+// checkL10n reports missing localization markers or script in the rendered HTML.
+func (l *linter) checkL10n(out string, lc LintCase) {
+	switch {
+	case !strings.Contains(out, "data-l10n"):
+		l.report(Diagnostic{
+			Rule:     RuleL10nMarkers,
+			Severity: SeverityWarning,
+			Message:  "no data-l10n markers found, so the page will not be localized",
+			Case:     &lc,
+		})
+	case !l.usesScript:
+		l.report(Diagnostic{
+			Rule:     RuleL10nScript,
+			Severity: SeverityWarning,
+			Message:  "data-l10n markers are used, but the localization script is not included (use {{ l10nScript }})",
+		})
+	}
+}
+
+// checkEscaping renders the template with the user-controlled fields set to a markup payload, and reports the
+// fields that appear in the output as-is.
+func (l *linter) checkEscaping(t *Template, data Data, lc LintCase) {
+	var v = reflect.ValueOf(&data).Elem()
+
+	for _, name := range userControlledFields {
+		v.FieldByName(name).SetString(`"'><lint-` + name + `>`)
+	}
+
+	out, err := t.Render(data)
+	if err != nil {
+		return // most likely, the payload broke something that is already reported
+	}
+
+	var unescaped []string
+
+	for _, name := range userControlledFields {
+		if strings.Contains(string(out), "<lint-"+name+">") {
+			unescaped = append(unescaped, "."+name)
+		}
+	}
+
+	if len(unescaped) > 0 {
+		l.report(Diagnostic{
+			Rule:     RuleUnescaped,
+			Severity: SeverityWarning,
+			Message: fmt.Sprintf("user-controlled fields are rendered without escaping (use the %s function): %s",
+				escapeFnFor(l.format), strings.Join(unescaped, ", ")),
+			Case: &lc,
+		})
+	}
+}
+
+// escapeFnFor returns the name of the template function, that should be used to escape values in the given format.
+func escapeFnFor(f formats.Format) string {
+	switch f { //nolint:exhaustive
+	case formats.JSONFormat, formats.YAMLFormat: // the JSON strings are valid YAML scalars
+		return "toJson"
+	case formats.MarkdownFormat:
+		return "escapeMarkdown"
+	}
+
+	return "escape"
+}
+
+// validateOutput checks that the rendered output is well-formed, if the format has a well-defined syntax.
+func validateOutput(f formats.Format, out []byte) error {
+	switch f { //nolint:exhaustive
+	case formats.JSONFormat:
+		var v any
+
+		return json.Unmarshal(out, &v)
+	case formats.XMLFormat, formats.SVGFormat:
+		dec := xml.NewDecoder(strings.NewReader(string(out)))
+
+		for {
+			if _, err := dec.Token(); err != nil {
+				if errors.Is(err, io.EOF) {
+					return nil
+				}
+
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// errorLine extracts the line number from the template parsing error (in "template: name:line: ..." format).
+func errorLine(err error) int {
+	_, rest, ok := strings.Cut(err.Error(), ":")
+	if !ok {
+		return 0
+	}
+
+	parts := strings.SplitN(rest, ":", 3) //nolint:mnd
+
+	if len(parts) < 3 { //nolint:mnd
+		return 0
+	}
+
+	n, _ := strconv.Atoi(parts[1]) //nolint:errcheck
+
+	return n
+}
+
+// treeWalker walks the template parse tree, tracking the type of the dot where possible.
+type treeWalker struct {
+	*linter
+
+	tree *parse.Tree
+	root reflect.Type // the type of the data passed to the template (nil if unknown)
+}
+
+// walk inspects the node and its children. The dot is nil if its type is unknown.
+func (w *treeWalker) walk(node parse.Node, dot reflect.Type) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+
+		for _, child := range n.Nodes {
+			w.walk(child, dot)
+		}
+	case *parse.ActionNode:
+		w.pipe(n.Pipe, dot)
+	case *parse.IfNode:
+		w.pipe(n.Pipe, dot)
+		w.walk(n.List, dot)
+		w.walk(n.ElseList, dot)
+	case *parse.RangeNode:
+		w.pipe(n.Pipe, dot)
+
+		var elem reflect.Type
+
+		if t := w.pipeType(n.Pipe, dot); t != nil && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+			elem = t.Elem()
+		}
+
+		w.walk(n.List, elem)
+		w.walk(n.ElseList, dot)
+	case *parse.WithNode:
+		w.pipe(n.Pipe, dot)
+		w.walk(n.List, w.pipeType(n.Pipe, dot))
+		w.walk(n.ElseList, dot)
+	case *parse.TemplateNode:
+		w.pipe(n.Pipe, dot)
+	}
+}
+
+// pipe inspects the commands of the pipeline.
+func (w *treeWalker) pipe(p *parse.PipeNode, dot reflect.Type) {
+	if p == nil {
+		return
+	}
+
+	for _, cmd := range p.Cmds {
+		for _, arg := range cmd.Args {
+			switch a := arg.(type) {
+			case *parse.IdentifierNode:
+				w.identifier(a)
+			case *parse.FieldNode:
+				_, _ = w.resolve(a, dot, a.Ident)
+			case *parse.VariableNode:
+				if len(a.Ident) > 1 && a.Ident[0] == "$" {
+					_, _ = w.resolve(a, w.root, a.Ident[1:])
+				}
+			case *parse.ChainNode:
+				if pn, ok := a.Node.(*parse.PipeNode); ok {
+					w.pipe(pn, dot)
+				}
+			case *parse.PipeNode:
+				w.pipe(a, dot)
+			}
+		}
+	}
+}
+
+// identifier reports the usage of deprecated functions.
+func (w *treeWalker) identifier(n *parse.IdentifierNode) {
+	if n.Ident == "l10nScript" {
+		w.usesScript = true
+	}
+
+	replacement, deprecated := deprecatedFns[n.Ident]
+	if !deprecated {
+		return
+	}
+
+	msg := fmt.Sprintf("function %q is deprecated", n.Ident)
+	if replacement != "" {
+		msg += fmt.Sprintf(", use %q instead", replacement)
+	}
+
+	w.report(Diagnostic{Rule: RuleDeprecatedFn, Severity: SeverityWarning, Message: msg, Line: w.line(n)})
+}
+
+// resolve returns the type of the field chain, starting from the given type. It returns nil if the type cannot
+// be determined statically, and false if an unknown field was found (and reported).
+func (w *treeWalker) resolve(n parse.Node, t reflect.Type, names []string) (reflect.Type, bool) {
+	for _, name := range names {
+		if t == nil {
+			return nil, true
+		}
+
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+
+		if _, isMethod := reflect.PointerTo(t).MethodByName(name); isMethod {
+			return nil, true
+		}
+
+		if t.Kind() != reflect.Struct {
+			return nil, true
+		}
+
+		f, ok := t.FieldByName(name)
+		if !ok || !f.IsExported() {
+			w.report(Diagnostic{
+				Rule:     RuleUnknownField,
+				Severity: SeverityError,
+				Message:  fmt.Sprintf("unknown field %q (the %s type has no such field)", name, t.Name()),
+				Line:     w.line(n),
+			})
+
+			return nil, false
+		}
+
+		t = f.Type
+	}
+
+	return t, true
+}
+
+// pipeType returns the type of the pipeline value, if it can be determined statically (single field chain).
+func (w *treeWalker) pipeType(p *parse.PipeNode, dot reflect.Type) reflect.Type {
+	if p == nil || len(p.Cmds) != 1 || len(p.Cmds[0].Args) != 1 {
+		return nil
+	}
+
+	switch a := p.Cmds[0].Args[0].(type) {
+	case *parse.DotNode:
+		return dot
+	case *parse.FieldNode:
+		t, _ := w.resolve(a, dot, a.Ident)
+
+		return t
+	}
+
+	return nil
+}
+
+// line returns the 1-based line number of the node in the template source.
+func (w *treeWalker) line(n parse.Node) int {
+	location, _ := w.tree.ErrorContext(n)
+
+	// the location has the "name:line:col" format
+	if parts := strings.Split(location, ":"); len(parts) >= 3 { //nolint:mnd
+		if line, err := strconv.Atoi(parts[len(parts)-2]); err == nil {
+			return line
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,150 @@
+package tpl_test
+
+import (
+	"testing"
+
+	"gh.tarampamp.am/error-pages/v4/internal/formats"
+	tpl "gh.tarampamp.am/error-pages/v4/internal/template"
+	"gh.tarampamp.am/error-pages/v4/internal/testutil/assert"
+	"gh.tarampamp.am/error-pages/v4/templates"
+)
+
+func TestLint(t *testing.T) {
+	t.Parallel()
+
+	type want struct {
+		rule     string
+		severity tpl.Severity
+		line     int
+	}
+
+	for name, tt := range map[string]struct {
+		giveSrc    string
+		giveFormat formats.Format
+		want       []want
+	}{
+		"clean plain text": {
+			giveSrc:    "{{ .StatusCode }}: {{ .Message }}{{ if .Config.ShowRequestDetails }} {{ .Host }}{{ end }}",
+			giveFormat: formats.PlainTextFormat,
+		},
+		"parse error": {
+			giveSrc:    "line 1\n{{ if .Message }}",
+			giveFormat: formats.PlainTextFormat,
+			want:       []want{{tpl.RuleParse, tpl.SeverityError, 2}},
+		},
+		"unknown field": {
+			giveSrc:    "{{ .StatusCode }}\n{{ .Foo }}\n{{ .Config.Bar }}\n{{ $.Baz }}",
+			giveFormat: formats.PlainTextFormat,
+			want: []want{
+				{tpl.RuleUnknownField, tpl.SeverityError, 2},
+				{tpl.RuleUnknownField, tpl.SeverityError, 3},
+				{tpl.RuleUnknownField, tpl.SeverityError, 4},
+				{tpl.RuleRender, tpl.SeverityError, 0},
+			},
+		},
+		"fields in range and with": {
+			giveSrc:    "{{ range .Links }}{{ .Label }}{{ .Nope }}{{ end }}{{ with .Links }}{{ range . }}{{ .URL }}{{ end }}{{ end }}",
+			giveFormat: formats.PlainTextFormat,
+			want:       []want{{tpl.RuleUnknownField, tpl.SeverityError, 1}},
+		},
+		"deprecated functions and v3 tokens": {
+			giveSrc:    "{{ nowUnix }}\n{{ code | json }}\n{{ .Code }}",
+			giveFormat: formats.PlainTextFormat,
+			want: []want{
+				{tpl.RuleV3Syntax, tpl.SeverityWarning, 2},
+				{tpl.RuleV3Syntax, tpl.SeverityWarning, 3},
+				{tpl.RuleDeprecatedFn, tpl.SeverityWarning, 1},
+				{tpl.RuleDeprecatedFn, tpl.SeverityWarning, 2},
+			},
+		},
+		"invalid json": {
+			giveSrc:    `{"code": {{ .StatusCode }}, "message": "{{ .Message }}"`,
+			giveFormat: formats.JSONFormat,
+			want:       []want{{tpl.RuleInvalidOutput, tpl.SeverityError, 0}},
+		},
+		"invalid xml": {
+			giveSrc:    `<error><code>{{ .StatusCode }}</code>`,
+			giveFormat: formats.XMLFormat,
+			want:       []want{{tpl.RuleInvalidOutput, tpl.SeverityError, 0}},
+		},
+		"invalid svg": {
+			giveSrc:    `<svg><text>{{ .Message }}</svg>`,
+			giveFormat: formats.SVGFormat,
+			want:       []want{{tpl.RuleInvalidOutput, tpl.SeverityError, 0}},
+		},
+		"unescaped json field": {
+			giveSrc:    `{"host": "{{ .Host }}", "uri": {{ .OriginalURI | toJson }}}`,
+			giveFormat: formats.JSONFormat,
+			want:       []want{{tpl.RuleUnescaped, tpl.SeverityWarning, 0}},
+		},
+		"html without l10n": {
+			giveSrc:    `<p>{{ .Message }}</p><p>{{ .Host | escape }}</p>`,
+			giveFormat: formats.HTMLFormat,
+			want:       []want{{tpl.RuleL10nMarkers, tpl.SeverityWarning, 0}},
+		},
+		"html without l10n script": {
+			giveSrc:    `<p data-l10n>{{ .Message }}</p><p>{{ .RequestID }}</p>`,
+			giveFormat: formats.HTMLFormat,
+			want: []want{
+				{tpl.RuleL10nScript, tpl.SeverityWarning, 0},
+				{tpl.RuleUnescaped, tpl.SeverityWarning, 0},
+			},
+		},
+		"html with l10n": {
+			giveSrc:    `<p data-l10n>{{ .Message }}</p>{{ if not .Config.L10nDisabled }}<script>{{ l10nScript }}</script>{{ end }}`,
+			giveFormat: formats.HTMLFormat,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got := tpl.Lint(tt.giveSrc, tt.giveFormat, tpl.Data{StatusCode: 404, Message: "Not Found"})
+
+			assert.Equal(t, len(tt.want), len(got))
+
+			for i, w := range tt.want {
+				if i >= len(got) {
+					break
+				}
+
+				assert.Equal(t, w.rule, got[i].Rule)
+				assert.Equal(t, w.severity, got[i].Severity)
+				assert.Equal(t, w.line, got[i].Line)
+				assert.True(t, got[i].Message != "")
+			}
+		})
+	}
+}
+
+func TestLint_BuiltInTemplates(t *testing.T) {
+	t.Parallel()
+
+	for name, tt := range map[string]struct {
+		giveSrc    string
+		giveFormat formats.Format
+	}{
+		"json":       {giveSrc: templates.JSON, giveFormat: formats.JSONFormat},
+		"xml":        {giveSrc: templates.XML, giveFormat: formats.XMLFormat},
+		"plain text": {giveSrc: templates.PlaintText, giveFormat: formats.PlainTextFormat},
+		"yaml":       {giveSrc: templates.YAML, giveFormat: formats.YAMLFormat},
+		"markdown":   {giveSrc: templates.Markdown, giveFormat: formats.MarkdownFormat},
+		"svg":        {giveSrc: templates.SVG, giveFormat: formats.SVGFormat},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			for _, d := range tpl.Lint(tt.giveSrc, tt.giveFormat, tpl.Data{StatusCode: 503, Message: "Unavailable"}) {
+				assert.True(t, d.Severity != tpl.SeverityError)
+			}
+		})
+	}
+}
+
+func TestLintCase_String(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t,
+		"code 503, details on, l10n off",
+		tpl.LintCase{StatusCode: 503, ShowRequestDetails: true, L10nDisabled: true}.String(),
+	)
+}
@@ -0,0 +1,245 @@
+package tpl
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+// safeFnRenames maps the deprecated template functions, that can be replaced without changing their arguments, to
+// their replacements.
+var safeFnRenames = map[string]string{ //nolint:gochecknoglobals
+	"json":         "toJson",
+	"nowUnix":      "now.Unix",
+	"strTrimSpace": "trim",
+	"strFields":    "fields",
+}
+
+// Migration is the result of [MigrateV3toV4].
+type Migration struct {
+	Result   string           // the migrated template source
+	Rewrites []Rewrite        // all the made replacements
+	Issues   []MigrationIssue // the constructs that could not be converted automatically
+}
+
+// MigrationIssue describes a construct in the template, that should be reviewed and fixed manually.
+type MigrationIssue struct {
+	Line    int    // 1-based line number in the source (0 if unknown)
+	Message string // what is wrong and (if possible) how to fix it
+}
+
+// MigrateV3toV4 converts the template source from the v3 syntax to the v4 one, using the same token and field maps
+// as the (deprecated) conversion that is applied at parse time. Unlike that conversion, it does not touch string
+// literals, comments and variables, and additionally replaces the deprecated functions that have a drop-in
+// replacement. The constructs that cannot be converted automatically are reported as issues.
+func MigrateV3toV4(src string) Migration {
+	var (
+		m    Migration
+		out  strings.Builder
+		last int // end of the previously processed action in src
+		line = 1 // line number at the last position
+	)
+
+	out.Grow(len(src))
+
+	for _, action := range actionRe.FindAllStringSubmatchIndex(src, -1) {
+		out.WriteString(src[last:action[2]]) // text before the action, including the opening braces
+
+		line += strings.Count(src[last:action[2]], "\n")
+		inner := src[action[2]:action[3]]
+
+		out.WriteString(m.migrateAction(inner, line))
+		out.WriteString("}}")
+
+		line += strings.Count(inner, "\n")
+		last = action[1]
+	}
+
+	out.WriteString(src[last:])
+
+	m.Result = out.String()
+	m.checkResult()
+
+	return m
+}
+
+// migrateAction converts the inner part of a single template action (between the braces) and returns the result.
+func (m *Migration) migrateAction(inner string, line int) string {
+	var out strings.Builder
+
+	for _, seg := range splitAction(inner) {
+		segLine := line + strings.Count(inner[:seg.from], "\n")
+
+		if !seg.code {
+			for _, token := range identRe.FindAllString(seg.text, -1) {
+				if _, ok := v3tov4Tokens[token]; ok && seg.text[0] != '/' { // comments are not interesting
+					m.issue(segLine, "the v3 token %q inside a string literal was left as-is (at parse time it was "+
+						"rewritten to %q, which is most likely not what you want)", token, v3tov4Tokens[token])
+				}
+			}
+
+			out.WriteString(seg.text)
+
+			continue
+		}
+
+		var pos int
+
+		for _, ident := range identRe.FindAllStringIndex(seg.text, -1) {
+			var (
+				token       = seg.text[ident[0]:ident[1]]
+				tokenLine   = segLine + strings.Count(seg.text[:ident[0]], "\n")
+				replacement string
+				ok          bool
+			)
+
+			if ident[0] > 0 && seg.text[ident[0]-1] == '$' { // variable, like $code
+				if _, isToken := v3tov4Tokens[token]; isToken {
+					m.issue(tokenLine, "the variable $%s was left as-is (at parse time it was rewritten to $%s, "+
+						"which is most likely broken), consider renaming it", token, v3tov4Tokens[token])
+				}
+
+				continue
+			}
+
+			if strings.HasPrefix(token, ".") {
+				replacement, ok = v3tov4Fields[token]
+			} else if replacement, ok = v3tov4Tokens[token]; !ok {
+				replacement, ok = m.function(token, tokenLine)
+			}
+
+			if !ok {
+				continue
+			}
+
+			out.WriteString(seg.text[pos:ident[0]])
+			out.WriteString(replacement)
+
+			m.Rewrites = append(m.Rewrites, Rewrite{Line: tokenLine, Old: token, New: replacement})
+			pos = ident[1]
+		}
+
+		out.WriteString(seg.text[pos:])
+	}
+
+	return out.String()
+}
+
+// function returns the replacement for the deprecated function, if it can be replaced automatically, or reports
+// an issue otherwise.
+func (m *Migration) function(name string, line int) (string, bool) {
+	if replacement, ok := safeFnRenames[name]; ok {
+		return replacement, true
+	}
+
+	if replacement, ok := deprecatedFns[name]; ok {
+		if replacement == "" {
+			m.issue(line, "the deprecated function %q has no replacement, consider removing it", name)
+		} else {
+			m.issue(line, "the deprecated function %q should be replaced with %q manually (the order of the "+
+				"arguments is different)", name, replacement)
+		}
+	}
+
+	return "", false
+}
+
+// checkResult parses the migrated template and reports the problems that are left.
+func (m *Migration) checkResult() {
+	parsed, err := template.New("tpl").Funcs(fns).Parse(m.Result)
+	if err != nil {
+		m.issue(errorLine(err), "the migrated template cannot be parsed: %s", err.Error())
+
+		return
+	}
+
+	var l = linter{seen: make(map[string]struct{})}
+
+	l.inspect(parsed)
+
+	for _, d := range l.result {
+		if d.Rule == RuleUnknownField {
+			m.issue(d.Line, "%s (v3 fields used inside of the range/with blocks must be fixed manually)", d.Message)
+		}
+	}
+
+	slices.SortStableFunc(m.Issues, func(a, b MigrationIssue) int { return a.Line - b.Line })
+}
+
+// issue appends a new issue to the migration result.
+func (m *Migration) issue(line int, format string, args ...any) {
+	m.Issues = append(m.Issues, MigrationIssue{Line: line, Message: fmt.Sprintf(format, args...)})
+}
+
+// actionSegment is a part of the template action: either code, or a string literal/comment.
+type actionSegment struct {
+	text string
+	from int  // offset in the action
+	code bool // false for string literals and comments
+}
+
+// splitAction splits the inner part of the template action into code, string literals and comments.
+func splitAction(inner string) []actionSegment {
+	var (
+		result []actionSegment
+		start  int
+	)
+
+	flush := func(end int, code bool) {
+		if end > start {
+			result = append(result, actionSegment{text: inner[start:end], from: start, code: code})
+		}
+
+		start = end
+	}
+
+	for i := 0; i < len(inner); i++ {
+		var end int
+
+		switch {
+		case inner[i] == '"' || inner[i] == '\'':
+			end = quotedEnd(inner, i)
+		case inner[i] == '`':
+			if j := strings.IndexByte(inner[i+1:], '`'); j >= 0 {
+				end = i + 1 + j + 1
+			} else {
+				end = len(inner)
+			}
+		case strings.HasPrefix(inner[i:], "/*"):
+			if j := strings.Index(inner[i+2:], "*/"); j >= 0 {
+				end = i + 2 + j + 2
+			} else {
+				end = len(inner)
+			}
+		default:
+			continue
+		}
+
+		flush(i, true)
+		flush(end, false)
+
+		i = end - 1
+	}
+
+	flush(len(inner), true)
+
+	return result
+}
+
+// quotedEnd returns the offset right after the closing quote of the string (or rune) literal, that starts at the
+// given offset. Escaped quotes are skipped.
+func quotedEnd(s string, from int) int {
+	quote := s[from]
+
+	for i := from + 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case quote:
+			return i + 1
+		}
+	}
+
+	return len(s)
+}
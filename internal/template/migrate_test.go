@@ -0,0 +1,80 @@
+package tpl_test
+
+import (
+	"testing"
+
+	tpl "gh.tarampamp.am/error-pages/v4/internal/template"
+	"gh.tarampamp.am/error-pages/v4/internal/testutil/assert"
+)
+
+func TestMigrateV3toV4(t *testing.T) {
+	t.Parallel()
+
+	for name, tt := range map[string]struct {
+		giveSrc        string
+		wantResult     string
+		wantRewrites   []tpl.Rewrite
+		wantIssueLines []int
+	}{
+		"nothing to migrate": {
+			giveSrc:    "{{ .StatusCode }}: {{ .Message | escape }}",
+			wantResult: "{{ .StatusCode }}: {{ .Message | escape }}",
+		},
+		"tokens and fields": {
+			giveSrc:    "<h1>{{code}}</h1>\n<p>{{ message }}</p>{{ if show_details }}{{ .Code }}{{ end }}",
+			wantResult: "<h1>{{.StatusCode}}</h1>\n<p>{{ .Message }}</p>{{ if .Config.ShowRequestDetails }}{{ .StatusCode }}{{ end }}",
+			wantRewrites: []tpl.Rewrite{
+				{Line: 1, Old: "code", New: ".StatusCode"},
+				{Line: 2, Old: "message", New: ".Message"},
+				{Line: 2, Old: "show_details", New: ".Config.ShowRequestDetails"},
+				{Line: 2, Old: ".Code", New: ".StatusCode"},
+			},
+		},
+		"deprecated functions": {
+			giveSrc:    "{{ code | json }} {{ nowUnix }}\n{{ strReplace .Message \"a\" \"b\" }}",
+			wantResult: "{{ .StatusCode | toJson }} {{ now.Unix }}\n{{ strReplace .Message \"a\" \"b\" }}",
+			wantRewrites: []tpl.Rewrite{
+				{Line: 1, Old: "code", New: ".StatusCode"},
+				{Line: 1, Old: "json", New: "toJson"},
+				{Line: 1, Old: "nowUnix", New: "now.Unix"},
+			},
+			wantIssueLines: []int{2},
+		},
+		"string literals, comments and variables": {
+			giveSrc:        "{{ \"code\" }}{{/* message */}}\n{{ $code := 1 }}{{ $code }}{{ `host` }}",
+			wantResult:     "{{ \"code\" }}{{/* message */}}\n{{ $code := 1 }}{{ $code }}{{ `host` }}",
+			wantIssueLines: []int{1, 2, 2, 2},
+		},
+		"fields inside of blocks": {
+			giveSrc:        "{{ with .Config }}\n{{ .ShowRequestDetails }}{{ end }}",
+			wantResult:     "{{ with .Config }}\n{{ .Config.ShowRequestDetails }}{{ end }}",
+			wantRewrites:   []tpl.Rewrite{{Line: 2, Old: ".ShowRequestDetails", New: ".Config.ShowRequestDetails"}},
+			wantIssueLines: []int{2},
+		},
+		"broken result": {
+			giveSrc:        "{{ if code }}",
+			wantResult:     "{{ if .StatusCode }}",
+			wantRewrites:   []tpl.Rewrite{{Line: 1, Old: "code", New: ".StatusCode"}},
+			wantIssueLines: []int{1},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			m := tpl.MigrateV3toV4(tt.giveSrc)
+
+			assert.Equal(t, tt.wantResult, m.Result)
+			assert.DeepEqual(t, tt.wantRewrites, m.Rewrites)
+
+			var lines []int
+
+			for _, issue := range m.Issues {
+				assert.True(t, issue.Message != "")
+
+				lines = append(lines, issue.Line)
+			}
+
+			assert.DeepEqual(t, tt.wantIssueLines, lines)
+		})
+	}
+}
@@ -12,22 +12,24 @@ func TestTemplate_Render(t *testing.T) {
 	t.Parallel()
 
 	fullData := tpl.Data{
-		StatusCode:   123,
-		Message:      "Test Message",
-		Description:  "Test Description",
-		OriginalURI:  "/test",
-		Namespace:    "test-namespace",
-		IngressName:  "test-ingress",
-		ServiceName:  "test-service",
-		ServicePort:  "8080",
-		RequestID:    "test-request-id",
-		ForwardedFor: "123.123.123.123:321",
-		Host:         "test-host",
-		HomepageURL:  "https://app.example.com/home",
+		StatusCode:         123,
+		OriginalStatusCode: 321,
+		Message:            "Test Message",
+		Description:        "Test Description",
+		OriginalURI:        "/test",
+		Namespace:          "test-namespace",
+		IngressName:        "test-ingress",
+		ServiceName:        "test-service",
+		ServicePort:        "8080",
+		RequestID:          "test-request-id",
+		ForwardedFor:       "123.123.123.123:321",
+		Host:               "test-host",
+		HomepageURL:        "https://app.example.com/home",
 		Links: []tpl.Link{
 			{Label: "Status Page", URL: "https://status.example.com"},
 			{Label: "Contact", URL: "https://example.com/contact"},
 		},
+		Image: tpl.Image{Width: 640, Height: 480},
 		Config: tpl.Config{
 			ShowRequestDetails: true,
 			L10nDisabled:       true,
@@ -38,6 +40,7 @@ func TestTemplate_Render(t *testing.T) {
 		t.Parallel()
 
 		const src = `StatusCode={{ .StatusCode }}
+OriginalStatusCode={{ .OriginalStatusCode }}
 Message={{ .Message }}
 Description={{ .Description }}
 OriginalURI={{ .OriginalURI }}
@@ -49,6 +52,7 @@ RequestID={{ .RequestID }}
 ForwardedFor={{ .ForwardedFor }}
 Host={{ .Host }}
 HomepageURL={{ .HomepageURL }}
+Image={{ .Image.Width }}x{{ .Image.Height }}
 Config.ShowRequestDetails={{ .Config.ShowRequestDetails }}
 Config.L10nDisabled={{ .Config.L10nDisabled }}
 {{ range .Links }}Link={{ .Label }}={{ .URL }}
@@ -61,6 +65,7 @@ Config.L10nDisabled={{ .Config.L10nDisabled }}
 		assert.NoError(t, err)
 
 		assert.Equal(t, `StatusCode=123
+OriginalStatusCode=321
 Message=Test Message
 Description=Test Description
 OriginalURI=/test
@@ -72,6 +77,7 @@ RequestID=test-request-id
 ForwardedFor=123.123.123.123:321
 Host=test-host
 HomepageURL=https://app.example.com/home
+Image=640x480
 Config.ShowRequestDetails=true
 Config.L10nDisabled=true
 Link=Status Page=https://status.example.com
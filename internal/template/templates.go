@@ -3,6 +3,7 @@ package tpl
 import (
 	"errors"
 	"fmt"
+	"io"
 	"math/rand/v2"
 	"slices"
 	"strings"
@@ -46,6 +47,9 @@ type Templates struct {
 	json      *Template
 	xml       *Template
 	plainText *Template
+	yaml      *Template
+	markdown  *Template
+	svg       *Template
 }
 
 // TemplatesOption is a functional option for configuring a [Templates] instance via [NewTemplates].
@@ -149,6 +153,66 @@ func WithCustomPlainTextTemplate(src string) TemplatesOption {
 	}
 }
 
+// WithCustomYAMLTemplate sets a custom YAML response template, overriding the built-in default.
+func WithCustomYAMLTemplate(src string) TemplatesOption {
+	src = strings.TrimSpace(src)
+
+	if src == "" {
+		return func(t *Templates) error { return nil }
+	}
+
+	return func(t *Templates) error {
+		tpl, err := New(src + "\n")
+		if err != nil {
+			return fmt.Errorf("custom YAML template parsing: %w", err)
+		}
+
+		t.yaml = tpl
+
+		return nil
+	}
+}
+
+// WithCustomMarkdownTemplate sets a custom Markdown response template, overriding the built-in default.
+func WithCustomMarkdownTemplate(src string) TemplatesOption {
+	src = strings.TrimSpace(src)
+
+	if src == "" {
+		return func(t *Templates) error { return nil }
+	}
+
+	return func(t *Templates) error {
+		tpl, err := New(src + "\n")
+		if err != nil {
+			return fmt.Errorf("custom Markdown template parsing: %w", err)
+		}
+
+		t.markdown = tpl
+
+		return nil
+	}
+}
+
+// WithCustomSVGTemplate sets a custom SVG image template, overriding the built-in default.
+func WithCustomSVGTemplate(src string) TemplatesOption {
+	src = strings.TrimSpace(src)
+
+	if src == "" {
+		return func(t *Templates) error { return nil }
+	}
+
+	return func(t *Templates) error {
+		tpl, err := New(src + "\n")
+		if err != nil {
+			return fmt.Errorf("custom SVG template parsing: %w", err)
+		}
+
+		t.svg = tpl
+
+		return nil
+	}
+}
+
 // WithRotationMode sets the HTML template rotation strategy. Has no effect when a custom HTML template is
 // configured via [WithCustomHTMLTemplate].
 func WithRotationMode(m RotationMode) TemplatesOption {
@@ -229,6 +293,33 @@ func NewTemplates(opts ...TemplatesOption) (*Templates, error) {
 		t.plainText = v
 	}
 
+	if t.yaml == nil {
+		v, err := New(templates.YAML)
+		if err != nil {
+			return nil, fmt.Errorf("built-in YAML template parsing: %w", err)
+		}
+
+		t.yaml = v
+	}
+
+	if t.markdown == nil {
+		v, err := New(templates.Markdown)
+		if err != nil {
+			return nil, fmt.Errorf("built-in Markdown template parsing: %w", err)
+		}
+
+		t.markdown = v
+	}
+
+	if t.svg == nil {
+		v, err := New(templates.SVG)
+		if err != nil {
+			return nil, fmt.Errorf("built-in SVG template parsing: %w", err)
+		}
+
+		t.svg = v
+	}
+
 	if t.html.rotationMode == RotationModeRandomOnStartup {
 		t.html.useTemplateName = t.getRandomBuiltInTemplateName()
 	}
@@ -254,8 +345,9 @@ var ErrNoHTMLTpl = errors.New("no HTML template available: no built-in templates
 // ErrFormatIsNotSupported is returned by [Templates.Get] when the requested [formats.Format] is not recognized.
 var ErrFormatIsNotSupported = errors.New("format is not supported")
 
-// Get returns the [Template] for the given format. For [formats.HTMLFormat], the selected template depends on
-// the configured [RotationMode]:
+// Get returns the [Template] for the given format ([formats.PNGFormat] is not supported, since the PNG images are
+// not rendered from the templates). For [formats.HTMLFormat], the selected template depends on the configured
+// [RotationMode]:
 //   - [RotationModeDisabled] and [RotationModeRandomOnStartup]: returns the fixed template (set at construction).
 //   - [RotationModeRandomOnEachRequest]: picks a random built-in template on every call.
 //   - [RotationModeRandomHourly]: rotates to a new random template once per UTC hour.
@@ -332,7 +424,58 @@ func (t *Templates) Get(format formats.Format) (*Template, error) {
 		return t.xml, nil
 	case formats.PlainTextFormat:
 		return t.plainText, nil
+	case formats.YAMLFormat:
+		return t.yaml, nil
+	case formats.MarkdownFormat:
+		return t.markdown, nil
+	case formats.SVGFormat:
+		return t.svg, nil
 	}
 
 	return nil, ErrFormatIsNotSupported
 }
+
+// TestRender renders every template that [Templates.Get] may return (for each supported format) with the given data,
+// discarding the output, and returns the first error encountered. For [formats.HTMLFormat], all built-in templates
+// participating in the rotation are rendered (or only the custom one, if set).
+//
+// It is intended to be used at startup to ensure the templates are not only parsed but also renderable, before
+// reporting the application as ready.
+func (t *Templates) TestRender(data Data) error {
+	type named struct {
+		name string
+		tpl  *Template
+	}
+
+	var list = []named{
+		{"JSON", t.json}, {"XML", t.xml}, {"plain text", t.plainText}, {"YAML", t.yaml}, {"Markdown", t.markdown},
+		{"SVG", t.svg},
+	}
+
+	switch {
+	case t.html.custom != nil:
+		list = append(list, named{"custom HTML", t.html.custom})
+	case t.html.rotationMode == RotationModeDisabled || t.html.rotationMode == RotationModeRandomOnStartup:
+		if t.html.useTemplateName == "" {
+			return ErrNoHTMLTpl
+		}
+
+		list = append(list, named{fmt.Sprintf("HTML %q", t.html.useTemplateName), t.html.builtIn.m[t.html.useTemplateName]})
+	default:
+		for _, name := range t.html.builtIn.names {
+			list = append(list, named{fmt.Sprintf("HTML %q", name), t.html.builtIn.m[name]})
+		}
+	}
+
+	for _, item := range list {
+		if item.tpl == nil {
+			return fmt.Errorf("%s template is not loaded", item.name)
+		}
+
+		if err := item.tpl.RenderTo(data, io.Discard); err != nil {
+			return fmt.Errorf("%s template rendering test: %w", item.name, err)
+		}
+	}
+
+	return nil
+}
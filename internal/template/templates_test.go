@@ -47,6 +47,18 @@ func TestNewTemplates(t *testing.T) {
 				giveOpt:       tpl.WithCustomPlainTextTemplate("{{.Invalid"),
 				wantErrSubstr: "custom plain text template parsing",
 			},
+			"invalid custom YAML template": {
+				giveOpt:       tpl.WithCustomYAMLTemplate("{{.Invalid"),
+				wantErrSubstr: "custom YAML template parsing",
+			},
+			"invalid custom Markdown template": {
+				giveOpt:       tpl.WithCustomMarkdownTemplate("{{.Invalid"),
+				wantErrSubstr: "custom Markdown template parsing",
+			},
+			"invalid custom SVG template": {
+				giveOpt:       tpl.WithCustomSVGTemplate("{{.Invalid"),
+				wantErrSubstr: "custom SVG template parsing",
+			},
 		} {
 			t.Run(name, func(t *testing.T) {
 				t.Parallel()
@@ -290,9 +302,15 @@ func TestTemplates_Get(t *testing.T) {
 			"json/built-in":       {giveFormat: formats.JSONFormat},
 			"xml/built-in":        {giveFormat: formats.XMLFormat},
 			"plain-text/built-in": {giveFormat: formats.PlainTextFormat},
+			"yaml/built-in":       {giveFormat: formats.YAMLFormat},
+			"markdown/built-in":   {giveFormat: formats.MarkdownFormat},
+			"svg/built-in":        {giveFormat: formats.SVGFormat},
 			"json/custom":         {giveFormat: formats.JSONFormat, giveOpts: []tpl.TemplatesOption{tpl.WithCustomJSONTemplate(`{"c": {{code}}}`)}},
 			"xml/custom":          {giveFormat: formats.XMLFormat, giveOpts: []tpl.TemplatesOption{tpl.WithCustomXMLTemplate(`<c>{{code}}</c>`)}},
 			"plain-text/custom":   {giveFormat: formats.PlainTextFormat, giveOpts: []tpl.TemplatesOption{tpl.WithCustomPlainTextTemplate(`{{code}}`)}},
+			"yaml/custom":         {giveFormat: formats.YAMLFormat, giveOpts: []tpl.TemplatesOption{tpl.WithCustomYAMLTemplate(`c: {{code}}`)}},
+			"markdown/custom":     {giveFormat: formats.MarkdownFormat, giveOpts: []tpl.TemplatesOption{tpl.WithCustomMarkdownTemplate(`# {{code}}`)}},
+			"svg/custom":          {giveFormat: formats.SVGFormat, giveOpts: []tpl.TemplatesOption{tpl.WithCustomSVGTemplate(`<svg>{{code}}</svg>`)}},
 		} {
 			t.Run(name, func(t *testing.T) {
 				t.Parallel()
@@ -323,5 +341,52 @@ func TestTemplates_Get(t *testing.T) {
 		got, getErr := ts.Get(formats.Format(255))
 		assert.ErrorIs(t, getErr, tpl.ErrFormatIsNotSupported)
 		assert.True(t, got == nil)
+
+		got, getErr = ts.Get(formats.PNGFormat) // rendered without a template
+		assert.ErrorIs(t, getErr, tpl.ErrFormatIsNotSupported)
+		assert.True(t, got == nil)
 	})
 }
+
+func TestTemplates_TestRender(t *testing.T) {
+	t.Parallel()
+
+	var data = tpl.Data{StatusCode: 503, Message: "Service Unavailable", Config: tpl.Config{ShowRequestDetails: true}}
+
+	for name, tt := range map[string]struct {
+		giveOpts      []tpl.TemplatesOption
+		wantErrSubstr string
+	}{
+		"built-in templates": {},
+		"all built-in HTML templates with rotation": {
+			giveOpts: []tpl.TemplatesOption{tpl.WithRotationMode(tpl.RotationModeRandomOnEachRequest)},
+		},
+		"custom templates": {
+			giveOpts: []tpl.TemplatesOption{
+				tpl.WithCustomHTMLTemplate("<h1>{{ .StatusCode }}</h1>"),
+				tpl.WithCustomJSONTemplate(`{"code": {{ .StatusCode }}}`),
+			},
+		},
+		"custom HTML template fails to render": {
+			giveOpts:      []tpl.TemplatesOption{tpl.WithCustomHTMLTemplate(`{{ template "missing" }}`)},
+			wantErrSubstr: "custom HTML template rendering test",
+		},
+		"custom plain text template fails to render": {
+			giveOpts:      []tpl.TemplatesOption{tpl.WithCustomPlainTextTemplate(`{{ .Config.Unknown }}`)},
+			wantErrSubstr: "plain text template rendering test",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ts, err := tpl.NewTemplates(tt.giveOpts...)
+			assert.NoError(t, err)
+
+			if renderErr := ts.TestRender(data); tt.wantErrSubstr != "" {
+				assert.ErrorContains(t, renderErr, tt.wantErrSubstr)
+			} else {
+				assert.NoError(t, renderErr)
+			}
+		})
+	}
+}
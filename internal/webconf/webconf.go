@@ -0,0 +1,442 @@
+// Package webconf generates the configuration snippets for the popular web servers and proxies, that serve the
+// static error pages built by the builder.
+package webconf
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gh.tarampamp.am/error-pages/v4/internal/formats"
+	"gh.tarampamp.am/error-pages/v4/internal/precompress"
+)
+
+// Server is the web server (or proxy) to generate the configuration for.
+type Server string
+
+const (
+	Nginx   Server = "nginx"
+	Caddy   Server = "caddy"
+	Apache  Server = "apache"
+	HAProxy Server = "haproxy"
+	Traefik Server = "traefik"
+)
+
+// Servers returns all supported servers.
+func Servers() []Server { return []Server{Nginx, Caddy, Apache, HAProxy, Traefik} }
+
+// FileName returns the name of the configuration snippet file.
+func (s Server) FileName() string {
+	switch s {
+	case Nginx:
+		return "error-pages.nginx.conf"
+	case Caddy:
+		return "error-pages.caddy"
+	case Apache:
+		return "error-pages.apache.conf"
+	case HAProxy:
+		return "error-pages.haproxy.cfg"
+	case Traefik:
+		return "error-pages.traefik.yml"
+	}
+
+	return ""
+}
+
+// Layout describes the generated error pages.
+type Layout struct {
+	Root       string           // path to the directory with the pages on the target server
+	Codes      []uint16         // rendered HTTP codes
+	Formats    []formats.Format // rendered formats (the first one is used by default)
+	ErrorFiles bool             // whether the {code}.http files (see [HAProxyErrorFile]) are written too
+
+	Precompressed []precompress.Encoding // the compressed copies, written next to the pages
+}
+
+// InternalPrefix is the URL path prefix the error pages are available under (internally, where possible).
+const InternalPrefix = "/_error-pages/"
+
+// Generate returns the configuration snippet for the server. Codes that the server cannot handle are skipped (and
+// listed in the comments).
+func Generate(s Server, l Layout) ([]byte, error) {
+	if len(l.Formats) == 0 {
+		return nil, fmt.Errorf("no formats to generate the %s configuration for", s)
+	}
+
+	var b strings.Builder
+
+	switch s {
+	case Nginx:
+		nginx(&b, l)
+	case Caddy:
+		caddy(&b, l)
+	case Apache:
+		apache(&b, l)
+	case HAProxy:
+		haproxy(&b, l)
+	case Traefik:
+		traefik(&b, l)
+	default:
+		return nil, fmt.Errorf("unsupported server %q", s)
+	}
+
+	return []byte(b.String()), nil
+}
+
+// negotiated returns the formats in the order they should be checked against the Accept header. HTML goes first,
+// since browsers also accept XML (like "text/html,application/xml;q=0.9"), the images go before XML, since the SVG
+// type is "image/svg+xml", and the plain text goes last, since the Markdown clients may accept it too.
+func negotiated(l Layout) []formats.Format {
+	var result = make([]formats.Format, 0, len(l.Formats))
+
+	for _, f := range []formats.Format{
+		formats.HTMLFormat, formats.JSONFormat, formats.SVGFormat, formats.PNGFormat, formats.XMLFormat,
+		formats.YAMLFormat, formats.MarkdownFormat, formats.PlainTextFormat,
+	} {
+		if slices.Contains(l.Formats, f) {
+			result = append(result, f)
+		}
+	}
+
+	return result
+}
+
+// acceptPattern returns the substring of the Accept header value, that selects the format.
+func acceptPattern(f formats.Format) string {
+	switch f {
+	case formats.HTMLFormat:
+		return "text/html"
+	case formats.JSONFormat:
+		return "json"
+	case formats.XMLFormat:
+		return "xml"
+	case formats.PlainTextFormat:
+		return "text/plain"
+	case formats.YAMLFormat:
+		return "yaml"
+	case formats.MarkdownFormat:
+		return "markdown"
+	case formats.SVGFormat:
+		return "image/svg"
+	case formats.PNGFormat:
+		return "image/png"
+	}
+
+	return ""
+}
+
+// mimeType returns the content type of the format without parameters.
+func mimeType(f formats.Format) string {
+	mime, _, _ := strings.Cut(f.ContentType(), ";")
+
+	return mime
+}
+
+// splitCodes splits the codes into the ones in the [min, max] range, and the rest.
+func splitCodes(codes []uint16, minCode, maxCode uint16) (supported, skipped []uint16) {
+	for _, code := range codes {
+		if code >= minCode && code <= maxCode {
+			supported = append(supported, code)
+		} else {
+			skipped = append(skipped, code)
+		}
+	}
+
+	return supported, skipped
+}
+
+// writeSkipped writes the comment with the skipped codes, if any.
+func writeSkipped(b *strings.Builder, s Server, skipped []uint16) {
+	if len(skipped) > 0 {
+		b.WriteString("# skipped codes (not supported by " + string(s) + "): " + joinCodes(skipped, " ") + "\n")
+	}
+}
+
+func joinCodes(codes []uint16, sep string) string {
+	var parts = make([]string, len(codes))
+
+	for i, code := range codes {
+		parts[i] = strconv.FormatUint(uint64(code), 10)
+	}
+
+	return strings.Join(parts, sep)
+}
+
+func nginx(b *strings.Builder, l Layout) {
+	const minCode, maxCode = 300, 599 // limits of the error_page directive
+
+	var (
+		codes, skipped = splitCodes(l.Codes, minCode, maxCode)
+		def            = l.Formats[0]
+	)
+
+	b.WriteString("# Generated by the error-pages builder, include it into the \"server\" block.\n")
+	writeSkipped(b, Nginx, skipped)
+	b.WriteString("\n")
+
+	for _, code := range codes {
+		_, _ = fmt.Fprintf(b, "error_page %d %s%d;\n", code, InternalPrefix, code)
+	}
+
+	// the format is selected by the "rewrite ... last" directives only, since "if" cannot be safely combined with
+	// other directives (like try_files) in the same location - each format is served by its own location instead
+	_, _ = fmt.Fprintf(b, "\nlocation ~ ^%s(?<ep_code>\\d{3})$ {\n", InternalPrefix)
+	b.WriteString("    internal;\n\n")
+
+	if order := negotiated(l); len(order) > 1 {
+		for _, f := range order { // the first matching condition wins
+			_, _ = fmt.Fprintf(b, "    if ($http_accept ~* %q) { rewrite ^ %s last; }\n",
+				acceptPattern(f), nginxFormatPath(f, "$ep_code"),
+			)
+		}
+	}
+
+	_, _ = fmt.Fprintf(b, "    rewrite ^ %s last;\n", nginxFormatPath(def, "$ep_code"))
+	b.WriteString("}\n")
+
+	for _, f := range l.Formats {
+		_, _ = fmt.Fprintf(b, "\nlocation ~ ^%s(?<ep_code>\\d{3})$ {\n", nginxFormatPath(f, ""))
+		b.WriteString("    internal;\n")
+		_, _ = fmt.Fprintf(b, "    root %q;\n\n", l.Root)
+		b.WriteString("    types { }\n")
+		_, _ = fmt.Fprintf(b, "    default_type %s;\n", mimeType(f))
+
+		if strings.Contains(f.ContentType(), "charset=") { // the text types only, not the binary ones like PNG
+			_, _ = fmt.Fprintf(b, "    charset utf-8;\n    charset_types %s;\n", mimeType(f))
+		}
+
+		b.WriteString("    add_header Cache-Control \"no-cache\" always;\n")
+
+		// nginx serves the compressed copies with the static modules only (brotli and zstd ones are third-party),
+		// and PNG images are never precompressed
+		if len(l.Precompressed) > 0 && f != formats.PNGFormat {
+			b.WriteString("\n")
+
+			for _, e := range l.Precompressed {
+				switch e {
+				case precompress.Gzip:
+					b.WriteString("    gzip_static on;\n")
+				case precompress.Brotli:
+					b.WriteString("    # brotli_static on; # requires the ngx_brotli module\n")
+				case precompress.Zstd:
+					b.WriteString("    # zstd_static on; # requires the zstd-nginx-module\n")
+				}
+			}
+		}
+
+		_, _ = fmt.Fprintf(b, "\n    try_files /$ep_code%s =404;\n", f.Extension())
+		b.WriteString("}\n")
+	}
+}
+
+// nginxFormatPath returns the internal path of the location, that serves the pages of the format.
+func nginxFormatPath(f formats.Format, code string) string {
+	return InternalPrefix + strings.TrimPrefix(f.Extension(), ".") + "/" + code
+}
+
+func caddy(b *strings.Builder, l Layout) {
+	b.WriteString("# Generated by the error-pages builder, import it into the site block.\n")
+	b.WriteString("handle_errors {\n")
+	_, _ = fmt.Fprintf(b, "\t@error_pages expression `{err.status_code} in [%s]`\n\n", joinCodes(l.Codes, ", "))
+	b.WriteString("\thandle @error_pages {\n")
+	_, _ = fmt.Fprintf(b, "\t\troot * %q\n", l.Root)
+	b.WriteString("\t\theader Cache-Control \"no-cache\"\n\n")
+
+	if order := negotiated(l); len(order) > 1 {
+		for _, f := range order {
+			_, _ = fmt.Fprintf(b, "\t\t@ep_%s header Accept *%s*\n", f, acceptPattern(f))
+		}
+
+		b.WriteString("\n")
+
+		// rewrites in the same block are mutually exclusive, the first matching one is applied
+		for _, f := range order {
+			_, _ = fmt.Fprintf(b, "\t\trewrite @ep_%s /{err.status_code}%s\n", f, f.Extension())
+		}
+	}
+
+	_, _ = fmt.Fprintf(b, "\t\trewrite * /{err.status_code}%s\n", l.Formats[0].Extension())
+
+	if len(l.Precompressed) > 0 {
+		var names = make([]string, 0, len(l.Precompressed))
+
+		for _, e := range l.Precompressed {
+			names = append(names, e.String())
+		}
+
+		_, _ = fmt.Fprintf(b, "\t\tfile_server {\n\t\t\tprecompressed %s\n\t\t}\n", strings.Join(names, " "))
+	} else {
+		b.WriteString("\t\tfile_server\n")
+	}
+	b.WriteString("\t}\n")
+	b.WriteString("}\n")
+}
+
+func apache(b *strings.Builder, l Layout) {
+	const minCode, maxCode = 400, 599 // ErrorDocument handles the error codes only
+
+	var (
+		codes, skipped = splitCodes(l.Codes, minCode, maxCode)
+		multiViews     = len(l.Formats) > 1
+		suffix         = l.Formats[0].Extension()
+	)
+
+	b.WriteString("# Generated by the error-pages builder, include it into the \"VirtualHost\" context.\n")
+
+	if multiViews {
+		b.WriteString("# Requires mod_alias, mod_headers, mod_negotiation (the format is selected by the Accept " +
+			"header) and mod_rewrite.\n")
+
+		suffix = "" // the extension is selected by the content negotiation
+	} else {
+		b.WriteString("# Requires mod_alias, mod_headers and mod_rewrite.\n")
+	}
+
+	writeSkipped(b, Apache, skipped)
+	_, _ = fmt.Fprintf(b, "\nAlias %q %q\n\n", InternalPrefix, strings.TrimSuffix(l.Root, "/")+"/")
+	_, _ = fmt.Fprintf(b, "<Directory %q>\n", l.Root)
+
+	if multiViews {
+		b.WriteString("    Options +MultiViews -Indexes\n")
+	} else {
+		b.WriteString("    Options -Indexes\n")
+	}
+
+	b.WriteString("    AllowOverride None\n")
+	b.WriteString("    Require all granted\n")
+	b.WriteString("    AddDefaultCharset utf-8\n")
+
+	for _, f := range l.Formats {
+		_, _ = fmt.Fprintf(b, "    AddType %s %s\n", mimeType(f), f.Extension())
+	}
+
+	b.WriteString("    Header always set Cache-Control \"no-cache\"\n\n")
+	b.WriteString("    # deny the direct access, the pages are served for the ErrorDocument internal redirects only\n")
+	b.WriteString("    RewriteEngine On\n")
+	b.WriteString("    RewriteCond \"%{ENV:REDIRECT_STATUS}\" \"^$\"\n")
+	b.WriteString("    RewriteRule \"^\" \"-\" [F]\n")
+	b.WriteString("</Directory>\n\n")
+
+	for _, code := range codes {
+		_, _ = fmt.Fprintf(b, "ErrorDocument %d %s%d%s\n", code, InternalPrefix, code, suffix)
+	}
+}
+
+func haproxy(b *strings.Builder, l Layout) {
+	const minCode, maxCode = 200, 599 // limits of the "return" action
+
+	var (
+		codes, skipped = splitCodes(l.Codes, minCode, maxCode)
+		order          = negotiated(l)
+	)
+
+	b.WriteString("# Generated by the error-pages builder, place it into the \"frontend\" (or \"backend\") section.\n")
+	b.WriteString("# Replaces the responses with the matching status codes (requires HAProxy 2.2 or newer).\n")
+	writeSkipped(b, HAProxy, skipped)
+
+	if len(order) > 1 {
+		b.WriteString("\n# the Accept header is not available at the response time, so it is captured in advance\n")
+		b.WriteString("http-request set-var(txn.ep_accept) req.hdr(accept)\n")
+	}
+
+	for _, code := range codes {
+		b.WriteString("\n")
+
+		if len(order) > 1 {
+			for _, f := range order {
+				_, _ = fmt.Fprintf(b, "http-response return status %d content-type %q file %q hdr Cache-Control "+
+					"no-cache if { status %d } { var(txn.ep_accept) -m sub %s }\n",
+					code, f.ContentType(), path.Join(l.Root, strconv.Itoa(int(code))+f.Extension()), code, acceptPattern(f),
+				)
+			}
+		}
+
+		def := l.Formats[0]
+
+		_, _ = fmt.Fprintf(b, "http-response return status %d content-type %q file %q hdr Cache-Control no-cache "+
+			"if { status %d }\n",
+			code, def.ContentType(), path.Join(l.Root, strconv.Itoa(int(code))+def.Extension()), code,
+		)
+	}
+
+	if !l.ErrorFiles {
+		return
+	}
+
+	var errorFiles = make([]uint16, 0, len(codes))
+
+	for _, code := range codes {
+		if slices.Contains(haproxyErrorFileCodes, code) {
+			errorFiles = append(errorFiles, code)
+		}
+	}
+
+	if len(errorFiles) > 0 {
+		b.WriteString("\n# the responses generated by HAProxy itself (like 503 when no server is available)\n")
+
+		for _, code := range errorFiles {
+			_, _ = fmt.Fprintf(b, "errorfile %d %q\n", code, path.Join(l.Root, strconv.Itoa(int(code))+".http"))
+		}
+	}
+}
+
+// haproxyErrorFileCodes are the codes the "errorfile" directive can be used for.
+var haproxyErrorFileCodes = []uint16{ //nolint:gochecknoglobals
+	200, 400, 401, 403, 404, 405, 407, 408, 410, 413, 425, 429, 500, 501, 502, 503, 504,
+}
+
+// HAProxyErrorFile returns the complete raw HTTP response (the status line, headers, and body), as the HAProxy
+// "errorfile" directive expects. The reason phrase is used when the code has no standard one.
+func HAProxyErrorFile(code uint16, reason string, f formats.Format, body []byte) []byte {
+	if std := http.StatusText(int(code)); std != "" {
+		reason = std
+	}
+
+	reason = strings.Join(strings.Fields(reason), " ") // no line breaks allowed in the status line
+
+	var b bytes.Buffer
+
+	b.Grow(len(body) + 160) //nolint:mnd // headers
+
+	_, _ = fmt.Fprintf(&b, "HTTP/1.0 %d %s\r\n", code, reason)
+	_, _ = fmt.Fprintf(&b, "Content-Type: %s\r\n", f.ContentType())
+	b.WriteString("Cache-Control: no-cache\r\n")
+	_, _ = fmt.Fprintf(&b, "Content-Length: %d\r\n", len(body))
+	b.WriteString("Connection: close\r\n\r\n")
+	b.Write(body)
+
+	return b.Bytes()
+}
+
+func traefik(b *strings.Builder, l Layout) {
+	b.WriteString("# Generated by the error-pages builder (dynamic configuration for the file provider).\n")
+	b.WriteString("# Traefik cannot serve static files by itself, so the \"error-pages\" service must serve the\n")
+	_, _ = fmt.Fprintf(b, "# files from %q (e.g. using nginx or \"caddy file-server\"), update its URL below.\n", l.Root)
+
+	if len(l.Formats) > 1 {
+		_, _ = fmt.Fprintf(b, "# The errors middleware cannot select the format by the Accept header, so only the %s "+
+			"pages are used.\n", l.Formats[0])
+	}
+
+	b.WriteString("http:\n")
+	b.WriteString("  middlewares:\n")
+	b.WriteString("    error-pages:\n")
+	b.WriteString("      errors:\n")
+	b.WriteString("        status:\n")
+
+	for _, code := range l.Codes {
+		_, _ = fmt.Fprintf(b, "          - \"%d\"\n", code)
+	}
+
+	b.WriteString("        service: error-pages\n")
+	_, _ = fmt.Fprintf(b, "        query: \"/{status}%s\"\n", l.Formats[0].Extension())
+	b.WriteString("  services:\n")
+	b.WriteString("    error-pages:\n")
+	b.WriteString("      loadBalancer:\n")
+	b.WriteString("        servers:\n")
+	b.WriteString("          - url: \"http://error-pages-static:80\"\n")
+}
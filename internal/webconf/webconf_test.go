@@ -0,0 +1,291 @@
+package webconf_test
+
+import (
+	"strings"
+	"testing"
+
+	"gh.tarampamp.am/error-pages/v4/internal/formats"
+	"gh.tarampamp.am/error-pages/v4/internal/precompress"
+	"gh.tarampamp.am/error-pages/v4/internal/testutil/assert"
+	"gh.tarampamp.am/error-pages/v4/internal/webconf"
+)
+
+func TestServer_FileName(t *testing.T) {
+	t.Parallel()
+
+	var seen = make(map[string]struct{})
+
+	for _, s := range webconf.Servers() {
+		name := s.FileName()
+
+		assert.True(t, name != "")
+
+		_, dup := seen[name]
+		assert.False(t, dup)
+
+		seen[name] = struct{}{}
+	}
+
+	assert.Equal(t, "", webconf.Server("foo").FileName())
+}
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+
+	var (
+		single = webconf.Layout{
+			Root:    "/var/www/errors",
+			Codes:   []uint16{200, 404, 503, 999},
+			Formats: []formats.Format{formats.HTMLFormat},
+		}
+		multi = webconf.Layout{
+			Root:    "/var/www/errors",
+			Codes:   []uint16{404, 503},
+			Formats: []formats.Format{formats.JSONFormat, formats.HTMLFormat, formats.XMLFormat},
+		}
+	)
+
+	for name, tt := range map[string]struct {
+		giveServer  webconf.Server
+		giveLayout  webconf.Layout
+		wantContain []string
+		wantMissing []string
+	}{
+		"nginx, single format": {
+			giveServer: webconf.Nginx,
+			giveLayout: single,
+			wantContain: []string{
+				"# skipped codes (not supported by nginx): 200 999\n",
+				"error_page 404 /_error-pages/404;\n",
+				"error_page 503 /_error-pages/503;\n",
+				"location ~ ^/_error-pages/(?<ep_code>\\d{3})$ {\n" +
+					"    internal;\n\n" +
+					"    rewrite ^ /_error-pages/html/$ep_code last;\n" +
+					"}\n",
+				"location ~ ^/_error-pages/html/(?<ep_code>\\d{3})$ {\n" +
+					"    internal;\n" +
+					"    root \"/var/www/errors\";\n\n" +
+					"    types { }\n" +
+					"    default_type text/html;\n" +
+					"    charset utf-8;\n" +
+					"    charset_types text/html;\n",
+				"    try_files /$ep_code.html =404;\n",
+			},
+			wantMissing: []string{"error_page 200", "error_page 999", "$http_accept", "gzip_static", "charset_types *"},
+		},
+		"nginx, multiple formats": {
+			giveServer: webconf.Nginx,
+			giveLayout: multi,
+			wantContain: []string{
+				"    if ($http_accept ~* \"text/html\") { rewrite ^ /_error-pages/html/$ep_code last; }\n" +
+					"    if ($http_accept ~* \"json\") { rewrite ^ /_error-pages/json/$ep_code last; }\n" +
+					"    if ($http_accept ~* \"xml\") { rewrite ^ /_error-pages/xml/$ep_code last; }\n" +
+					"    rewrite ^ /_error-pages/json/$ep_code last;\n",
+				"    default_type application/json;\n    charset utf-8;\n    charset_types application/json;\n",
+				"    default_type application/xml;\n",
+				"    try_files /$ep_code.json =404;\n",
+				"    try_files /$ep_code.html =404;\n",
+				"    try_files /$ep_code.xml =404;\n",
+			},
+			wantMissing: []string{"set $ep_ext", "try_files /$ep_code$ep_ext"}, // "if" must not be mixed with try_files
+		},
+		"nginx, yaml and markdown": {
+			giveServer: webconf.Nginx,
+			giveLayout: webconf.Layout{
+				Root:    "/var/www/errors",
+				Codes:   []uint16{404},
+				Formats: []formats.Format{formats.PlainTextFormat, formats.MarkdownFormat, formats.YAMLFormat},
+			},
+			wantContain: []string{
+				"    default_type application/yaml;\n",
+				"    default_type text/markdown;\n",
+				"    if ($http_accept ~* \"yaml\") { rewrite ^ /_error-pages/yaml/$ep_code last; }\n" +
+					"    if ($http_accept ~* \"markdown\") { rewrite ^ /_error-pages/md/$ep_code last; }\n",
+			},
+		},
+		"nginx, images": {
+			giveServer: webconf.Nginx,
+			giveLayout: webconf.Layout{
+				Root:          "/var/www/errors",
+				Codes:         []uint16{404},
+				Formats:       []formats.Format{formats.HTMLFormat, formats.XMLFormat, formats.PNGFormat, formats.SVGFormat},
+				Precompressed: []precompress.Encoding{precompress.Gzip},
+			},
+			wantContain: []string{
+				"    default_type image/svg+xml;\n    charset utf-8;\n",
+				"    if ($http_accept ~* \"text/html\") { rewrite ^ /_error-pages/html/$ep_code last; }\n" +
+					"    if ($http_accept ~* \"image/svg\") { rewrite ^ /_error-pages/svg/$ep_code last; }\n" +
+					"    if ($http_accept ~* \"image/png\") { rewrite ^ /_error-pages/png/$ep_code last; }\n" +
+					"    if ($http_accept ~* \"xml\") { rewrite ^ /_error-pages/xml/$ep_code last; }\n",
+				// no charset and no precompressed copies for the binary images
+				"    default_type image/png;\n" +
+					"    add_header Cache-Control \"no-cache\" always;\n\n" +
+					"    try_files /$ep_code.png =404;\n",
+			},
+		},
+		"nginx, precompressed": {
+			giveServer: webconf.Nginx,
+			giveLayout: webconf.Layout{
+				Root:          "/var/www/errors",
+				Codes:         []uint16{404},
+				Formats:       []formats.Format{formats.HTMLFormat},
+				Precompressed: []precompress.Encoding{precompress.Brotli, precompress.Gzip},
+			},
+			wantContain: []string{
+				"    # brotli_static on; # requires the ngx_brotli module\n    gzip_static on;\n",
+			},
+			wantMissing: []string{"zstd_static"},
+		},
+		"caddy, single format": {
+			giveServer: webconf.Caddy,
+			giveLayout: single,
+			wantContain: []string{
+				"\t@error_pages expression `{err.status_code} in [200, 404, 503, 999]`\n",
+				"\t\troot * \"/var/www/errors\"\n",
+				"\t\trewrite * /{err.status_code}.html\n\t\tfile_server\n",
+			},
+			wantMissing: []string{"header Accept", "precompressed"},
+		},
+		"caddy, precompressed": {
+			giveServer: webconf.Caddy,
+			giveLayout: webconf.Layout{
+				Root:          "/var/www/errors",
+				Codes:         []uint16{404},
+				Formats:       []formats.Format{formats.HTMLFormat},
+				Precompressed: []precompress.Encoding{precompress.Zstd, precompress.Gzip},
+			},
+			wantContain: []string{"\t\tfile_server {\n\t\t\tprecompressed zstd gzip\n\t\t}\n"},
+		},
+		"caddy, multiple formats": {
+			giveServer: webconf.Caddy,
+			giveLayout: multi,
+			wantContain: []string{
+				"\t\t@ep_html header Accept *text/html*\n",
+				"\t\t@ep_json header Accept *json*\n",
+				"\t\trewrite @ep_html /{err.status_code}.html\n" +
+					"\t\trewrite @ep_json /{err.status_code}.json\n" +
+					"\t\trewrite @ep_xml /{err.status_code}.xml\n" +
+					"\t\trewrite * /{err.status_code}.json\n",
+			},
+		},
+		"apache, single format": {
+			giveServer: webconf.Apache,
+			giveLayout: single,
+			wantContain: []string{
+				"# skipped codes (not supported by apache): 200 999\n",
+				"Alias \"/_error-pages/\" \"/var/www/errors/\"\n",
+				"<Directory \"/var/www/errors\">\n",
+				"    Options -Indexes\n",
+				"    AddType text/html .html\n",
+				"ErrorDocument 404 /_error-pages/404.html\n",
+			},
+			wantMissing: []string{"MultiViews", "ErrorDocument 200"},
+		},
+		"apache, multiple formats": {
+			giveServer: webconf.Apache,
+			giveLayout: multi,
+			wantContain: []string{
+				"    Options +MultiViews -Indexes\n",
+				"    AddType application/json .json\n",
+				"ErrorDocument 503 /_error-pages/503\n",
+			},
+		},
+		"haproxy, single format": {
+			giveServer: webconf.HAProxy,
+			giveLayout: single,
+			wantContain: []string{
+				"# skipped codes (not supported by haproxy): 999\n",
+				"http-response return status 200 content-type \"text/html; charset=utf-8\" " +
+					"file \"/var/www/errors/200.html\" hdr Cache-Control no-cache if { status 200 }\n",
+			},
+			wantMissing: []string{"set-var", "status 999"},
+		},
+		"haproxy, multiple formats": {
+			giveServer: webconf.HAProxy,
+			giveLayout: multi,
+			wantContain: []string{
+				"http-request set-var(txn.ep_accept) req.hdr(accept)\n",
+				"http-response return status 404 content-type \"text/html; charset=utf-8\" " +
+					"file \"/var/www/errors/404.html\" hdr Cache-Control no-cache " +
+					"if { status 404 } { var(txn.ep_accept) -m sub text/html }\n" +
+					"http-response return status 404 content-type \"application/json; charset=utf-8\" " +
+					"file \"/var/www/errors/404.json\" hdr Cache-Control no-cache " +
+					"if { status 404 } { var(txn.ep_accept) -m sub json }\n",
+				"http-response return status 503 content-type \"application/json; charset=utf-8\" " +
+					"file \"/var/www/errors/503.json\" hdr Cache-Control no-cache if { status 503 }\n",
+			},
+		},
+		"haproxy, error files": {
+			giveServer: webconf.HAProxy,
+			giveLayout: webconf.Layout{
+				Root:       "/var/www/errors",
+				Codes:      []uint16{404, 418, 503},
+				Formats:    []formats.Format{formats.HTMLFormat},
+				ErrorFiles: true,
+			},
+			wantContain: []string{
+				"errorfile 404 \"/var/www/errors/404.http\"\nerrorfile 503 \"/var/www/errors/503.http\"\n",
+			},
+			wantMissing: []string{"errorfile 418"},
+		},
+		"traefik": {
+			giveServer: webconf.Traefik,
+			giveLayout: multi,
+			wantContain: []string{
+				"so only the json pages are used",
+				"          - \"404\"\n          - \"503\"\n",
+				"        query: \"/{status}.json\"\n",
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := webconf.Generate(tt.giveServer, tt.giveLayout)
+			assert.NoError(t, err)
+
+			for _, want := range tt.wantContain {
+				assert.Contains(t, string(got), want)
+			}
+
+			for _, missing := range tt.wantMissing {
+				assert.False(t, strings.Contains(string(got), missing))
+			}
+		})
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := webconf.Generate("foo", single)
+		assert.ErrorContains(t, err, "unsupported server")
+
+		_, err = webconf.Generate(webconf.Nginx, webconf.Layout{Codes: []uint16{404}})
+		assert.ErrorContains(t, err, "no formats")
+	})
+}
+
+func TestHAProxyErrorFile(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t,
+		"HTTP/1.0 404 Not Found\r\n"+
+			"Content-Type: application/json; charset=utf-8\r\n"+
+			"Cache-Control: no-cache\r\n"+
+			"Content-Length: 13\r\n"+
+			"Connection: close\r\n"+
+			"\r\n"+
+			`{"code": 404}`,
+		string(webconf.HAProxyErrorFile(404, "Whatever", formats.JSONFormat, []byte(`{"code": 404}`))),
+	)
+
+	assert.Equal(t,
+		"HTTP/1.0 599 Custom error\r\n"+
+			"Content-Type: text/plain; charset=utf-8\r\n"+
+			"Cache-Control: no-cache\r\n"+
+			"Content-Length: 0\r\n"+
+			"Connection: close\r\n"+
+			"\r\n",
+		string(webconf.HAProxyErrorFile(599, "Custom\r\nerror", formats.PlainTextFormat, nil)),
+	)
+}
@@ -0,0 +1,154 @@
+package yaml
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// ParseJSON decodes the JSON document into the same [Node] tree as [Parse] does (JSON is a subset of YAML), so the
+// consumers may support both formats with the same code and line numbers in the errors.
+func ParseJSON(data []byte) (*Node, error) {
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+
+	var d = jsonDecoder{data: data, dec: json.NewDecoder(bytes.NewReader(data))}
+
+	for i, c := range data {
+		if c == '\n' {
+			d.lineStarts = append(d.lineStarts, i+1)
+		}
+	}
+
+	d.dec.UseNumber()
+
+	node, err := d.parseValue()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = d.dec.Token(); !errors.Is(err, io.EOF) {
+		return nil, errorf(d.lineAt(int(d.dec.InputOffset())), "unexpected content after the document")
+	}
+
+	return node, nil
+}
+
+type jsonDecoder struct {
+	data       []byte
+	dec        *json.Decoder
+	lineStarts []int // the offsets of the lines (except the first one)
+}
+
+// lineAt returns the line number of the offset.
+func (d *jsonDecoder) lineAt(offset int) int {
+	return sort.SearchInts(d.lineStarts, offset+1) + 1
+}
+
+// token returns the next token and the line it starts on.
+func (d *jsonDecoder) token() (json.Token, int, error) {
+	var start = int(d.dec.InputOffset())
+
+	for start < len(d.data) && bytes.IndexByte([]byte(" \t\r\n,:"), d.data[start]) >= 0 {
+		start++
+	}
+
+	tok, err := d.dec.Token()
+	if err != nil {
+		var syntaxErr *json.SyntaxError
+
+		switch {
+		case errors.As(err, &syntaxErr):
+			return nil, 0, errorf(d.lineAt(int(syntaxErr.Offset)), "%s", syntaxErr.Error())
+		case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+			return nil, 0, errorf(d.lineAt(len(d.data)), "unexpected end of the document")
+		}
+
+		return nil, 0, errorf(d.lineAt(start), "%s", err.Error())
+	}
+
+	return tok, d.lineAt(start), nil
+}
+
+// parseValue decodes the next value.
+func (d *jsonDecoder) parseValue() (*Node, error) {
+	tok, line, err := d.token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch v := tok.(type) {
+	case json.Delim:
+		if v == '[' {
+			return d.parseArray(line)
+		}
+
+		return d.parseObject(line)
+	case string:
+		return &Node{Kind: ScalarNode, Line: line, Value: v, Quoted: true}, nil
+	case json.Number:
+		return &Node{Kind: ScalarNode, Line: line, Value: v.String()}, nil
+	case bool:
+		return &Node{Kind: ScalarNode, Line: line, Value: fmt.Sprint(v)}, nil
+	}
+
+	return &Node{Kind: ScalarNode, Line: line, Value: "null"}, nil
+}
+
+// parseArray decodes the array after its opening bracket.
+func (d *jsonDecoder) parseArray(line int) (*Node, error) {
+	var node = &Node{Kind: SequenceNode, Line: line}
+
+	for d.dec.More() {
+		item, err := d.parseValue()
+		if err != nil {
+			return nil, err
+		}
+
+		node.Items = append(node.Items, item)
+	}
+
+	if _, _, err := d.token(); err != nil { // the closing bracket
+		return nil, err
+	}
+
+	return node, nil
+}
+
+// parseObject decodes the object after its opening brace.
+func (d *jsonDecoder) parseObject(line int) (*Node, error) {
+	var (
+		node = &Node{Kind: MappingNode, Line: line}
+		seen = make(map[string]struct{})
+	)
+
+	for d.dec.More() {
+		tok, keyLine, err := d.token()
+		if err != nil {
+			return nil, err
+		}
+
+		var key = tok.(string) //nolint:errcheck,forcetypeassert // the decoder guarantees the keys are strings
+
+		if _, dup := seen[key]; dup {
+			return nil, errorf(keyLine, "duplicate key %q", key)
+		}
+
+		seen[key] = struct{}{}
+
+		value, err := d.parseValue()
+		if err != nil {
+			return nil, err
+		}
+
+		node.Items = append(node.Items, &Node{Kind: ScalarNode, Line: keyLine, Value: key, Quoted: true}, value)
+	}
+
+	if _, _, err := d.token(); err != nil { // the closing brace
+		return nil, err
+	}
+
+	return node, nil
+}
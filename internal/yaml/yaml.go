@@ -0,0 +1,778 @@
+// Package yaml implements a decoder of the YAML subset used by the configuration files: block and flow mappings and
+// sequences, plain, quoted and block scalars, and comments. Anchors, aliases, tags and multiple documents are not
+// supported.
+//
+// The documents are decoded into the [Node] trees, which keep the line numbers for the error messages. The scalars
+// are not typed - the consumer decides how to interpret them. JSON documents map to the same trees (see [ParseJSON]).
+package yaml
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Kind is the kind of the [Node].
+type Kind int
+
+const (
+	ScalarNode Kind = iota + 1
+	MappingNode
+	SequenceNode
+)
+
+// String returns the human-readable name of the kind.
+func (k Kind) String() string {
+	switch k {
+	case ScalarNode:
+		return "scalar"
+	case MappingNode:
+		return "mapping"
+	case SequenceNode:
+		return "sequence"
+	}
+
+	return "unknown"
+}
+
+// Node is the node of the document tree.
+type Node struct {
+	Kind   Kind
+	Line   int     // 1-based
+	Value  string  // for the scalars
+	Quoted bool    // the scalar is quoted (or a block one), so it is always a string
+	Items  []*Node // the items of the sequence, or the keys and values (one after another) of the mapping
+}
+
+// IsNull reports whether the node is the null scalar (empty, "~" or "null").
+func (n *Node) IsNull() bool {
+	if n.Kind != ScalarNode || n.Quoted {
+		return false
+	}
+
+	switch n.Value {
+	case "", "~", "null", "Null", "NULL":
+		return true
+	}
+
+	return false
+}
+
+// IsBool reports whether the node is the boolean scalar ("true" or "false", in any of the YAML 1.2 spellings).
+func (n *Node) IsBool() bool {
+	if n.Kind != ScalarNode || n.Quoted {
+		return false
+	}
+
+	switch n.Value {
+	case "true", "True", "TRUE", "false", "False", "FALSE":
+		return true
+	}
+
+	return false
+}
+
+// numberRe matches the numbers of the YAML 1.2 core schema (the integers, including the octal and hexadecimal ones,
+// the floats, the infinities and NaN), which cover the JSON numbers too.
+var numberRe = regexp.MustCompile( //nolint:gochecknoglobals
+	`^(?:[-+]?(?:\.[0-9]+|[0-9]+(?:\.[0-9]*)?)(?:[eE][-+]?[0-9]+)?|0o[0-7]+|0x[0-9a-fA-F]+|` +
+		`[-+]?\.(?:inf|Inf|INF)|\.(?:nan|NaN|NAN))$`,
+)
+
+// IsNumber reports whether the node is the number scalar (e.g. "404", "-1.5e3" or "0x1F").
+func (n *Node) IsNumber() bool {
+	return n.Kind == ScalarNode && !n.Quoted && numberRe.MatchString(n.Value)
+}
+
+// Error is the decoding error.
+type Error struct {
+	Line int // 1-based
+	Msg  string
+}
+
+func (e *Error) Error() string { return fmt.Sprintf("line %d: %s", e.Line, e.Msg) }
+
+// errorf returns the [Error] for the line.
+func errorf(line int, format string, args ...any) error {
+	return &Error{Line: line, Msg: fmt.Sprintf(format, args...)}
+}
+
+// line is the line of the document.
+type line struct {
+	num    int    // 1-based
+	indent int    // the number of leading spaces
+	text   string // without the indentation
+}
+
+// isBlank reports whether the line is empty or a comment.
+func (l line) isBlank() bool { return l.text == "" || l.text[0] == '#' }
+
+type parser struct {
+	lines []line
+	pos   int
+}
+
+// Parse decodes the YAML document. The empty document is decoded as the null scalar.
+func Parse(data []byte) (*Node, error) {
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+
+	if !utf8.Valid(data) {
+		return nil, errorf(1, "the document is not valid UTF-8")
+	}
+
+	var p parser
+
+	for i, raw := range strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n") {
+		text := strings.TrimLeft(raw, " ")
+
+		if strings.HasPrefix(text, "\t") && strings.TrimSpace(text) != "" {
+			return nil, errorf(i+1, "tabs are not allowed for indentation")
+		}
+
+		p.lines = append(p.lines, line{num: i + 1, indent: len(raw) - len(text), text: strings.TrimRight(text, " \t")})
+	}
+
+	if err := p.stripDocumentMarkers(); err != nil {
+		return nil, err
+	}
+
+	p.skipBlank()
+
+	if p.eof() {
+		return &Node{Kind: ScalarNode, Line: 1}, nil
+	}
+
+	if cur := p.cur(); cur.indent != 0 {
+		return nil, errorf(cur.num, "the document must not be indented")
+	}
+
+	node, err := p.parseBlock(0)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.skipBlank(); !p.eof() {
+		return nil, errorf(p.cur().num, "unexpected content, check the indentation")
+	}
+
+	return node, nil
+}
+
+// stripDocumentMarkers blanks the "---" and "..." markers of the single document.
+func (p *parser) stripDocumentMarkers() error {
+	var content bool
+
+	for i, l := range p.lines {
+		switch {
+		case l.indent == 0 && (l.text == "---" || strings.HasPrefix(l.text, "--- ")):
+			if content {
+				return errorf(l.num, "multiple documents are not supported")
+			}
+
+			if rest := strings.TrimSpace(l.text[3:]); rest != "" && rest[0] != '#' {
+				return errorf(l.num, "the content must start on the line after the document marker")
+			}
+
+			p.lines[i].text = ""
+		case l.indent == 0 && l.text == "...":
+			for _, rest := range p.lines[i+1:] {
+				if !rest.isBlank() {
+					return errorf(rest.num, "multiple documents are not supported")
+				}
+			}
+
+			p.lines = p.lines[:i]
+
+			return nil
+		case l.indent == 0 && strings.HasPrefix(l.text, "%"):
+			return errorf(l.num, "directives are not supported")
+		case !l.isBlank():
+			content = true
+		}
+	}
+
+	return nil
+}
+
+func (p *parser) eof() bool { return p.pos >= len(p.lines) }
+func (p *parser) cur() line { return p.lines[p.pos] }
+
+// skipBlank skips the empty and comment lines.
+func (p *parser) skipBlank() {
+	for !p.eof() && p.cur().isBlank() {
+		p.pos++
+	}
+}
+
+// parseBlock parses the node starting at the current (non-blank) line, whose indentation is at least the minimum.
+func (p *parser) parseBlock(minIndent int) (*Node, error) {
+	cur := p.cur()
+
+	if cur.indent < minIndent {
+		return &Node{Kind: ScalarNode, Line: cur.num}, nil // nothing is nested, so it is null
+	}
+
+	if isSequenceEntry(cur.text) {
+		return p.parseSequence(cur.indent)
+	}
+
+	if _, _, ok, err := splitMappingEntry(cur); err != nil {
+		return nil, err
+	} else if ok {
+		return p.parseMapping(cur.indent)
+	}
+
+	p.pos++
+
+	return p.parseValue(cur.text, cur, cur.indent-1)
+}
+
+// isSequenceEntry reports whether the text is the entry of the block sequence ("- item").
+func isSequenceEntry(text string) bool { return text == "-" || strings.HasPrefix(text, "- ") }
+
+// parseSequence parses the block sequence with the indentation.
+func (p *parser) parseSequence(indent int) (*Node, error) {
+	var node = &Node{Kind: SequenceNode, Line: p.cur().num}
+
+	for p.skipBlank(); !p.eof(); p.skipBlank() {
+		cur := p.cur()
+
+		if cur.indent < indent || cur.indent == indent && !isSequenceEntry(cur.text) {
+			break // the parent continues
+		}
+
+		if cur.indent > indent {
+			return nil, errorf(cur.num, "expected a sequence entry (\"- \"), check the indentation")
+		}
+
+		rest := strings.TrimLeft(cur.text[1:], " ")
+
+		var (
+			item *Node
+			err  error
+		)
+
+		if rest == "" || rest[0] == '#' { // the item is on the next lines
+			p.pos++
+
+			if p.skipBlank(); p.eof() {
+				item = &Node{Kind: ScalarNode, Line: cur.num}
+			} else {
+				item, err = p.parseBlock(indent + 1)
+			}
+		} else { // the item starts on the same line, as if it was on the next one with the same indentation
+			p.lines[p.pos] = line{num: cur.num, indent: indent + len(cur.text) - len(rest), text: rest}
+			item, err = p.parseBlock(indent + 1)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		node.Items = append(node.Items, item)
+	}
+
+	return node, nil
+}
+
+// parseMapping parses the block mapping with the indentation.
+func (p *parser) parseMapping(indent int) (*Node, error) {
+	var (
+		node = &Node{Kind: MappingNode, Line: p.cur().num}
+		seen = make(map[string]struct{})
+	)
+
+	for p.skipBlank(); !p.eof(); p.skipBlank() {
+		cur := p.cur()
+
+		if cur.indent < indent {
+			break
+		}
+
+		key, rest, ok, err := splitMappingEntry(cur)
+		if err != nil {
+			return nil, err
+		}
+
+		if cur.indent > indent || !ok {
+			return nil, errorf(cur.num, "expected a mapping entry (\"key: value\"), check the indentation")
+		}
+
+		if _, dup := seen[key.Value]; dup {
+			return nil, errorf(cur.num, "duplicate key %q", key.Value)
+		}
+
+		seen[key.Value] = struct{}{}
+		p.pos++
+
+		var value *Node
+
+		if rest == "" || rest[0] == '#' { // the value is on the next lines
+			p.skipBlank()
+
+			switch {
+			case p.eof():
+				value = &Node{Kind: ScalarNode, Line: cur.num}
+			case p.cur().indent == indent && isSequenceEntry(p.cur().text): // the sequence is not indented
+				value, err = p.parseSequence(indent)
+			default:
+				value, err = p.parseBlock(indent + 1)
+			}
+		} else {
+			value, err = p.parseValue(rest, cur, indent)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		node.Items = append(node.Items, key, value)
+	}
+
+	return node, nil
+}
+
+// splitMappingEntry splits the line into the key and the rest after the colon, if the line is the mapping entry.
+func splitMappingEntry(l line) (*Node, string, bool, error) {
+	var text = l.text
+
+	if text == "" || strings.ContainsRune("[{#|>", rune(text[0])) || isSequenceEntry(text) {
+		return nil, "", false, nil
+	}
+
+	if text[0] == '"' || text[0] == '\'' {
+		value, n, err := parseQuoted(text, l.num)
+		if err != nil {
+			return nil, "", false, err
+		}
+
+		if rest := strings.TrimLeft(text[n:], " "); strings.HasPrefix(rest, ":") &&
+			(len(rest) == 1 || rest[1] == ' ') {
+			return &Node{Kind: ScalarNode, Line: l.num, Value: value, Quoted: true}, strings.TrimLeft(rest[1:], " "), true, nil
+		}
+
+		return nil, "", false, nil
+	}
+
+	for i := 0; i < len(text); i++ {
+		switch {
+		case text[i] == '#' && i > 0 && text[i-1] == ' ':
+			return nil, "", false, nil // the comment starts, no colon before it
+		case text[i] == ':' && (i+1 == len(text) || text[i+1] == ' '):
+			key := strings.TrimRight(text[:i], " ")
+			if key == "" {
+				return nil, "", false, errorf(l.num, "missing mapping key")
+			}
+
+			if strings.ContainsAny(key[:1], "&*!") {
+				return nil, "", false, errorf(l.num, "anchors, aliases and tags are not supported")
+			}
+
+			return &Node{Kind: ScalarNode, Line: l.num, Value: key}, strings.TrimLeft(text[i+1:], " "), true, nil
+		}
+	}
+
+	return nil, "", false, nil
+}
+
+// parseValue parses the value, which starts on the line (after the key or the sequence entry indicator) and may
+// continue on the next lines indented more than the parent.
+func (p *parser) parseValue(text string, l line, parentIndent int) (*Node, error) {
+	switch text[0] {
+	case '"', '\'':
+		value, n, err := parseQuoted(text, l.num)
+		if err != nil {
+			return nil, err
+		}
+
+		if err = expectLineEnd(text[n:], l.num); err != nil {
+			return nil, err
+		}
+
+		return &Node{Kind: ScalarNode, Line: l.num, Value: value, Quoted: true}, nil
+	case '[', '{':
+		f := flowParser{text: text, line: l.num}
+
+		node, err := f.parseValue()
+		if err != nil {
+			return nil, err
+		}
+
+		if err = expectLineEnd(text[f.pos:], l.num); err != nil {
+			return nil, err
+		}
+
+		return node, nil
+	case '|', '>':
+		return p.parseBlockScalar(text, l, parentIndent)
+	case '&', '*', '!':
+		return nil, errorf(l.num, "anchors, aliases and tags are not supported")
+	case '@', '`':
+		return nil, errorf(l.num, "the plain scalar must not start with %q", text[0])
+	}
+
+	return p.parsePlain(text, l, parentIndent)
+}
+
+// expectLineEnd returns an error if there is anything but the comment after the value.
+func expectLineEnd(rest string, num int) error {
+	if rest = strings.TrimLeft(rest, " "); rest != "" && rest[0] != '#' {
+		return errorf(num, "unexpected %q after the value", rest)
+	}
+
+	return nil
+}
+
+// parsePlain parses the plain scalar, which may continue on the next lines (they are folded into spaces, and the
+// empty lines into the new lines).
+func (p *parser) parsePlain(text string, l line, parentIndent int) (*Node, error) {
+	var (
+		value = stripComment(text)
+		node  = &Node{Kind: ScalarNode, Line: l.num}
+		empty int // the empty lines before the continuation line
+	)
+
+	if strings.Contains(value, ": ") || strings.HasSuffix(value, ":") {
+		return nil, errorf(l.num, "mapping values are not allowed here (quote the value)")
+	}
+
+	if value != text { // the comment ends the scalar
+		node.Value = value
+
+		return node, nil
+	}
+
+	for !p.eof() {
+		cur := p.cur()
+
+		if cur.text == "" {
+			empty, p.pos = empty+1, p.pos+1
+
+			continue
+		}
+
+		if cur.indent <= parentIndent || cur.text[0] == '#' {
+			break
+		}
+
+		if _, _, ok, _ := splitMappingEntry(cur); ok || isSequenceEntry(cur.text) {
+			return nil, errorf(cur.num, "mapping values are not allowed here, check the indentation")
+		}
+
+		if empty > 0 {
+			value += strings.Repeat("\n", empty)
+		} else {
+			value += " "
+		}
+
+		part := stripComment(cur.text)
+		value, empty, p.pos = value+part, 0, p.pos+1
+
+		if part != cur.text {
+			break // the comment ends the scalar
+		}
+	}
+
+	p.pos -= empty // leave the trailing empty lines to the parent
+
+	node.Value = value
+
+	return node, nil
+}
+
+// stripComment removes the comment (started with " #") from the plain scalar.
+func stripComment(text string) string {
+	if i := strings.Index(text, " #"); i >= 0 {
+		return strings.TrimRight(text[:i], " ")
+	}
+
+	return text
+}
+
+// parseBlockScalar parses the literal ("|") or folded (">") block scalar.
+func (p *parser) parseBlockScalar(header string, l line, parentIndent int) (*Node, error) {
+	var (
+		folded   = header[0] == '>'
+		chomping byte // '-' strips the final line breaks, '+' keeps them, the default keeps one
+		indent   int  // the explicit indentation indicator
+	)
+
+	for _, c := range []byte(stripComment(header[1:])) {
+		switch {
+		case (c == '-' || c == '+') && chomping == 0:
+			chomping = c
+		case c >= '1' && c <= '9' && indent == 0:
+			indent = parentIndent + 1 + int(c-'0')
+		default:
+			return nil, errorf(l.num, "wrong block scalar header %q", header)
+		}
+	}
+
+	var lines []string
+
+	for ; !p.eof(); p.pos++ {
+		cur := p.cur()
+
+		if cur.text == "" {
+			lines = append(lines, "")
+
+			continue
+		}
+
+		if indent == 0 {
+			if cur.indent <= parentIndent {
+				break
+			}
+
+			indent = cur.indent
+		}
+
+		if cur.indent < indent {
+			if cur.indent > parentIndent && cur.text[0] != '#' {
+				return nil, errorf(cur.num, "the block scalar line is less indented than the first one")
+			}
+
+			break
+		}
+
+		lines = append(lines, strings.Repeat(" ", cur.indent-indent)+cur.text)
+	}
+
+	var trailing int // the trailing empty lines
+
+	for trailing < len(lines) && lines[len(lines)-1-trailing] == "" {
+		trailing++
+	}
+
+	p.pos -= trailing // leave the trailing empty lines to the parent (they may be the comments)
+	lines = lines[:len(lines)-trailing]
+
+	var value = joinBlockLines(lines, folded)
+
+	switch {
+	case len(lines) == 0:
+	case chomping == '+':
+		value += strings.Repeat("\n", trailing+1)
+	case chomping != '-':
+		value += "\n"
+	}
+
+	return &Node{Kind: ScalarNode, Line: l.num, Value: value, Quoted: true}, nil
+}
+
+// joinBlockLines joins the lines of the block scalar: the literal ones with the new lines, and the folded ones with
+// the spaces (except the empty and more indented lines).
+func joinBlockLines(lines []string, folded bool) string {
+	if !folded {
+		return strings.Join(lines, "\n")
+	}
+
+	var b strings.Builder
+
+	for i, l := range lines {
+		if i > 0 {
+			prev := lines[i-1]
+
+			switch {
+			case l == "" || prev != "" && (prev[0] == ' ' || l[0] == ' '):
+				b.WriteByte('\n')
+			case prev != "":
+				b.WriteByte(' ')
+			}
+		}
+
+		b.WriteString(l)
+	}
+
+	return b.String()
+}
+
+// parseQuoted parses the single- or double-quoted scalar at the beginning of the text, and returns its value and
+// the length in the text.
+func parseQuoted(text string, num int) (string, int, error) {
+	var (
+		quote = text[0]
+		b     strings.Builder
+	)
+
+	for i := 1; i < len(text); i++ {
+		c := text[i]
+
+		switch {
+		case c == quote && quote == '\'' && i+1 < len(text) && text[i+1] == '\'':
+			b.WriteByte('\'')
+			i++
+		case c == quote:
+			return b.String(), i + 1, nil
+		case c == '\\' && quote == '"':
+			r, n, err := unescape(text[i:], num)
+			if err != nil {
+				return "", 0, err
+			}
+
+			b.WriteString(r)
+			i += n - 1
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return "", 0, errorf(num, "unterminated quoted scalar (the multi-line quoted scalars are not supported)")
+}
+
+// unescape decodes the escape sequence at the beginning of the text, and returns it and its length.
+func unescape(text string, num int) (string, int, error) {
+	if len(text) < 2 { //nolint:mnd
+		return "", 0, errorf(num, "unterminated escape sequence")
+	}
+
+	if r, ok := map[byte]string{
+		'0': "\x00", 'a': "\a", 'b': "\b", 't': "\t", '\t': "\t", 'n': "\n", 'v': "\v", 'f': "\f", 'r': "\r",
+		'e': "\x1b", ' ': " ", '"': "\"", '/': "/", '\\': "\\", 'N': "\u0085", '_': "\u00a0", 'L': "\u2028",
+		'P': "\u2029",
+	}[text[1]]; ok {
+		return r, 2, nil //nolint:mnd
+	}
+
+	var size int
+
+	switch text[1] {
+	case 'x':
+		size = 2
+	case 'u':
+		size = 4
+	case 'U':
+		size = 8
+	default:
+		return "", 0, errorf(num, "unknown escape sequence %q", text[:2])
+	}
+
+	if len(text) < 2+size {
+		return "", 0, errorf(num, "unterminated escape sequence %q", text)
+	}
+
+	code, err := strconv.ParseUint(text[2:2+size], 16, 32)
+	if err != nil || !utf8.ValidRune(rune(code)) {
+		return "", 0, errorf(num, "wrong escape sequence %q", text[:2+size])
+	}
+
+	return string(rune(code)), 2 + size, nil
+}
+
+// flowParser parses the flow collections ("[a, b]" and "{a: b}"), which must fit the single line.
+type flowParser struct {
+	text string
+	pos  int
+	line int
+}
+
+func (f *flowParser) skipSpaces() {
+	for f.pos < len(f.text) && f.text[f.pos] == ' ' {
+		f.pos++
+	}
+}
+
+// parseValue parses the flow collection or scalar at the current position.
+func (f *flowParser) parseValue() (*Node, error) {
+	f.skipSpaces()
+
+	if f.pos >= len(f.text) {
+		return nil, errorf(f.line, "unterminated flow collection (the multi-line ones are not supported)")
+	}
+
+	switch f.text[f.pos] {
+	case '[':
+		return f.parseCollection(SequenceNode, ']')
+	case '{':
+		return f.parseCollection(MappingNode, '}')
+	case '"', '\'':
+		value, n, err := parseQuoted(f.text[f.pos:], f.line)
+		if err != nil {
+			return nil, err
+		}
+
+		f.pos += n
+
+		return &Node{Kind: ScalarNode, Line: f.line, Value: value, Quoted: true}, nil
+	case '&', '*', '!':
+		return nil, errorf(f.line, "anchors, aliases and tags are not supported")
+	}
+
+	var start = f.pos
+
+	for f.pos < len(f.text) {
+		c := f.text[f.pos]
+
+		if c == ',' || c == ']' || c == '}' || (c == ':' && (f.pos+1 == len(f.text) || strings.ContainsRune(" ,]}",
+			rune(f.text[f.pos+1])))) || (c == '#' && f.text[f.pos-1] == ' ') {
+			break
+		}
+
+		f.pos++
+	}
+
+	return &Node{Kind: ScalarNode, Line: f.line, Value: strings.TrimRight(f.text[start:f.pos], " ")}, nil
+}
+
+// parseCollection parses the flow sequence or mapping.
+func (f *flowParser) parseCollection(kind Kind, end byte) (*Node, error) {
+	var (
+		node = &Node{Kind: kind, Line: f.line}
+		seen = make(map[string]struct{})
+	)
+
+	f.pos++ // the opening bracket
+
+	for {
+		f.skipSpaces()
+
+		if f.pos < len(f.text) && f.text[f.pos] == end {
+			f.pos++
+
+			return node, nil
+		}
+
+		item, err := f.parseValue()
+		if err != nil {
+			return nil, err
+		}
+
+		if kind == MappingNode {
+			if item.Kind != ScalarNode {
+				return nil, errorf(f.line, "the mapping key must be a scalar")
+			}
+
+			if _, dup := seen[item.Value]; dup {
+				return nil, errorf(f.line, "duplicate key %q", item.Value)
+			}
+
+			seen[item.Value] = struct{}{}
+
+			if f.skipSpaces(); f.pos >= len(f.text) || f.text[f.pos] != ':' {
+				return nil, errorf(f.line, "expected \":\" after the mapping key %q", item.Value)
+			}
+
+			f.pos++
+
+			value, vErr := f.parseValue()
+			if vErr != nil {
+				return nil, vErr
+			}
+
+			node.Items = append(node.Items, item, value)
+		} else {
+			node.Items = append(node.Items, item)
+		}
+
+		switch f.skipSpaces(); {
+		case f.pos < len(f.text) && f.text[f.pos] == ',':
+			f.pos++
+		case f.pos < len(f.text) && f.text[f.pos] == end:
+		default:
+			return nil, errorf(f.line, "expected \",\" or %q in the flow collection", end)
+		}
+	}
+}
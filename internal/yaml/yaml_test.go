@@ -0,0 +1,178 @@
+package yaml_test
+
+import (
+	"strings"
+	"testing"
+
+	"gh.tarampamp.am/error-pages/v4/internal/testutil/assert"
+	"gh.tarampamp.am/error-pages/v4/internal/yaml"
+)
+
+// dump returns the compact representation of the node, with the line numbers of the scalars.
+func dump(n *yaml.Node) string {
+	var b strings.Builder
+
+	switch n.Kind {
+	case yaml.ScalarNode:
+		if n.IsNull() {
+			b.WriteString("null")
+		} else {
+			b.WriteString(strings.ReplaceAll(n.Value, "\n", `\n`))
+		}
+
+		b.WriteString("@")
+		b.WriteString(string(rune('0' + n.Line)))
+	case yaml.SequenceNode:
+		b.WriteString("[")
+
+		for i, item := range n.Items {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+
+			b.WriteString(dump(item))
+		}
+
+		b.WriteString("]")
+	case yaml.MappingNode:
+		b.WriteString("{")
+
+		for i := 0; i < len(n.Items); i += 2 {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+
+			b.WriteString(n.Items[i].Value + ": " + dump(n.Items[i+1]))
+		}
+
+		b.WriteString("}")
+	}
+
+	return b.String()
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		giveDoc  string
+		wantDump string
+	}{
+		"empty":  {giveDoc: "# only a comment\n", wantDump: "null@1"},
+		"scalar": {giveDoc: "foo", wantDump: "foo@1"},
+		"mapping": {
+			giveDoc:  "---\n# comment\nfoo: bar # comment\nbaz: 'qu''ux'\n\n\"x y\": \"a\\tb\"\nnull:\n",
+			wantDump: `{foo: bar@3, baz: qu'ux@4, x y: a` + "\t" + `b@6, null: null@7}`,
+		},
+		"nested": {
+			giveDoc:  "a:\n  b:\n    c: d\n  e: f\ng: h\n",
+			wantDump: "{a: {b: {c: d@3}, e: f@4}, g: h@5}",
+		},
+		"sequences": {
+			giveDoc:  "a:\n- b\n- c: d\n  e: f\n-\n  - g\n  - - h\nx: [1, 'two', {k: v}]\n",
+			wantDump: "{a: [b@2, {c: d@3, e: f@4}, [g@6, [h@7]]], x: [1@8, two@8, {k: v@8}]}",
+		},
+		"multi-line plain": {
+			giveDoc:  "a: foo\n  bar\n\n  baz\nb: c\n",
+			wantDump: `{a: foo bar\nbaz@1, b: c@5}`,
+		},
+		"literal block": {
+			giveDoc:  "a: |\n  foo\n    bar\n\n  baz\n\nb: |-\n  x\n",
+			wantDump: `{a: foo\n  bar\n\nbaz\n@1, b: x@7}`,
+		},
+		"folded block": {
+			giveDoc:  "a: >\n  foo\n  bar\n\n  baz\nb: c\n",
+			wantDump: `{a: foo bar\nbaz\n@1, b: c@6}`,
+		},
+		"colons in values": {
+			giveDoc:  "url: https://example.com/a:b\n",
+			wantDump: "{url: https://example.com/a:b@1}",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			node, err := yaml.Parse([]byte(tc.giveDoc))
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantDump, dump(node))
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		giveDoc   string
+		wantError string
+	}{
+		"tabs":              {giveDoc: "a:\n\tb: c\n", wantError: "line 2: tabs are not allowed for indentation"},
+		"duplicate key":     {giveDoc: "a: 1\nb: 2\na: 3\n", wantError: `line 3: duplicate key "a"`},
+		"wrong indentation": {giveDoc: "a:\n    b: 1\n  c: 2\n", wantError: "line 3: expected a mapping entry"},
+		"mapping in plain":  {giveDoc: "a: b: c\n", wantError: "line 1: mapping values are not allowed here"},
+		"anchor":            {giveDoc: "a: &x b\n", wantError: "line 1: anchors, aliases and tags are not supported"},
+		"alias":             {giveDoc: "a: b\nc: *x\n", wantError: "line 2: anchors, aliases and tags are not supported"},
+		"documents":         {giveDoc: "a: b\n---\nc: d\n", wantError: "line 2: multiple documents are not supported"},
+		"unterminated":      {giveDoc: "a: \"b\n", wantError: "line 1: unterminated quoted scalar"},
+		"flow":              {giveDoc: "a: [b, c\n", wantError: `line 1: expected "," or ']'`},
+		"after quoted":      {giveDoc: "a: 'b' c\n", wantError: `line 1: unexpected "c" after the value`},
+		"sequence in map":   {giveDoc: "a: b\n- c\n", wantError: "line 2: expected a mapping entry"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := yaml.Parse([]byte(tc.giveDoc))
+			assert.ErrorContains(t, err, tc.wantError)
+		})
+	}
+}
+
+func TestParseJSON(t *testing.T) {
+	t.Parallel()
+
+	node, err := yaml.ParseJSON([]byte("{\n  \"a\": \"b\",\n  \"c\": [1, true,\n    null],\n  \"d\": {}\n}\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, "{a: b@2, c: [1@3, true@3, null@4], d: {}}", dump(node))
+
+	for name, tc := range map[string]struct {
+		giveDoc   string
+		wantError string
+	}{
+		"syntax":        {giveDoc: "{\n  \"a\": 1,\n  \"b\" 2\n}", wantError: "line 3: invalid character"},
+		"duplicate key": {giveDoc: "{\n  \"a\": 1,\n  \"a\": 2\n}", wantError: `line 3: duplicate key "a"`},
+		"truncated":     {giveDoc: "{\n  \"a\": [1,\n", wantError: "line 3: unexpected end of JSON input"},
+		"trailing":      {giveDoc: "{}\n{}", wantError: "line 2: unexpected content after the document"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := yaml.ParseJSON([]byte(tc.giveDoc))
+			assert.ErrorContains(t, err, tc.wantError)
+		})
+	}
+}
+
+func TestNode_Types(t *testing.T) {
+	t.Parallel()
+
+	node, err := yaml.Parse([]byte("[~, true, FALSE, yes, 404, -1.5e3, .5, 0o17, 0x1F, -.inf, .NaN, 1_000, 404 Not Found, '1']"))
+	assert.NoError(t, err)
+
+	var (
+		wantNull   = []bool{true, false, false, false, false, false, false, false, false, false, false, false, false, false}
+		wantBool   = []bool{false, true, true, false, false, false, false, false, false, false, false, false, false, false}
+		wantNumber = []bool{false, false, false, false, true, true, true, true, true, true, true, false, false, false}
+	)
+
+	for i, item := range node.Items {
+		assert.Equal(t, wantNull[i], item.IsNull())
+		assert.Equal(t, wantBool[i], item.IsBool())
+		assert.Equal(t, wantNumber[i], item.IsNumber())
+	}
+
+	node, err = yaml.ParseJSON([]byte(`[1, "1", true, "true"]`))
+	assert.NoError(t, err)
+	assert.Equal(t, 4, len(node.Items))
+	assert.True(t, node.Items[0].IsNumber() && node.Items[2].IsBool())
+	assert.False(t, node.Items[1].IsNumber() || node.Items[3].IsBool())
+}
@@ -21,6 +21,40 @@
    * @type {Map<string, Map<string, string>>}
    */
   const locales = Object.freeze(new Map([
+    [t("A Timeout Occurred"), new Map([
+      ["de", "Eine Zeitüberschreitung ist aufgetreten"],
+      ["es", "Se agotó el tiempo de espera"],
+      ["fr", "Un délai d'attente a expiré"],
+      ["hu", "Időtúllépés történt"],
+      ["id", "Terjadi batas waktu"],
+      ["it", "Si è verificato un timeout"],
+      ["ko", "시간 초과 발생"],
+      ["nl", "Er is een time-out opgetreden"],
+      ["no", "Et tidsavbrudd oppstod"],
+      ["pl", "Przekroczono limit czasu"],
+      ["pt", "Ocorreu um tempo limite"],
+      ["ro", "A expirat timpul de așteptare"],
+      ["ru", "Истекло время ожидания"],
+      ["uk", "Минув час очікування"],
+      ["zh", "发生超时"],
+    ])],
+    [t("A plain HTTP request was sent to the HTTPS port"), new Map([
+      ["de", "Eine einfache HTTP-Anfrage wurde an den HTTPS-Port gesendet"],
+      ["es", "Se envió una petición HTTP simple al puerto HTTPS"],
+      ["fr", "Une requête HTTP simple a été envoyée au port HTTPS"],
+      ["hu", "Egyszerű HTTP-kérés érkezett a HTTPS-portra"],
+      ["id", "Permintaan HTTP biasa dikirim ke port HTTPS"],
+      ["it", "Una semplice richiesta HTTP è stata inviata alla porta HTTPS"],
+      ["ko", "일반 HTTP 요청이 HTTPS 포트로 전송되었어요"],
+      ["nl", "Er is een gewoon HTTP-verzoek naar de HTTPS-poort verzonden"],
+      ["no", "En vanlig HTTP-forespørsel ble sendt til HTTPS-porten"],
+      ["pl", "Zwykłe żądanie HTTP zostało wysłane na port HTTPS"],
+      ["pt", "Uma solicitação HTTP simples foi enviada à porta HTTPS"],
+      ["ro", "O cerere HTTP simplă a fost trimisă către portul HTTPS"],
+      ["ru", "Обычный HTTP-запрос был отправлен на порт HTTPS"],
+      ["uk", "Звичайний HTTP-запит було надіслано на порт HTTPS"],
+      ["zh", "普通 HTTP 请求被发送到了 HTTPS 端口"],
+    ])],
     [t("Access is forbidden to the requested page"), new Map([
       ["de", "Der Zugriff auf die angeforderte Seite ist verboten"],
       ["es", "El acceso está prohibido para la página solicitada"],
@@ -89,6 +123,57 @@
       ["uk", "Хибний запит"],
       ["zh", "错误请求"],
     ])],
+    [t("Bandwidth Limit Exceeded"), new Map([
+      ["de", "Bandbreitenlimit überschritten"],
+      ["es", "Límite de ancho de banda excedido"],
+      ["fr", "Limite de bande passante dépassée"],
+      ["hu", "Sávszélességkorlát túllépve"],
+      ["id", "Batas Bandwidth Terlampaui"],
+      ["it", "Limite di banda superato"],
+      ["ko", "대역폭 한도 초과"],
+      ["nl", "Bandbreedtelimiet overschreden"],
+      ["no", "Båndbreddegrensen er overskredet"],
+      ["pl", "Przekroczono limit transferu"],
+      ["pt", "Limite de largura de banda excedido"],
+      ["ro", "Limita de lățime de bandă a fost depășită"],
+      ["ru", "Превышен лимит трафика"],
+      ["uk", "Перевищено ліміт трафіку"],
+      ["zh", "超出带宽限制"],
+    ])],
+    [t("Client Closed Connection"), new Map([
+      ["de", "Client hat die Verbindung geschlossen"],
+      ["es", "El cliente cerró la conexión"],
+      ["fr", "Connexion fermée par le client"],
+      ["hu", "A kliens bontotta a kapcsolatot"],
+      ["id", "Klien menutup koneksi"],
+      ["it", "Il client ha chiuso la connessione"],
+      ["ko", "클라이언트가 연결을 닫음"],
+      ["nl", "Client heeft verbinding verbroken"],
+      ["no", "Klienten lukket tilkoblingen"],
+      ["pl", "Klient zamknął połączenie"],
+      ["pt", "Conexão fechada pelo cliente"],
+      ["ro", "Clientul a închis conexiunea"],
+      ["ru", "Клиент закрыл соединение"],
+      ["uk", "Клієнт закрив з'єднання"],
+      ["zh", "客户端关闭了连接"],
+    ])],
+    [t("Client Closed Request"), new Map([
+      ["de", "Client hat die Anfrage abgebrochen"],
+      ["es", "El cliente cerró la petición"],
+      ["fr", "Requête fermée par le client"],
+      ["hu", "A kliens megszakította a kérést"],
+      ["id", "Klien menutup permintaan"],
+      ["it", "Il client ha chiuso la richiesta"],
+      ["ko", "클라이언트가 요청을 닫음"],
+      ["nl", "Client heeft verzoek afgebroken"],
+      ["no", "Klienten avbrøt forespørselen"],
+      ["pl", "Klient zamknął żądanie"],
+      ["pt", "Solicitação fechada pelo cliente"],
+      ["ro", "Clientul a închis cererea"],
+      ["ru", "Клиент закрыл запрос"],
+      ["uk", "Клієнт закрив запит"],
+      ["zh", "客户端关闭了请求"],
+    ])],
     [t("Conflict"), new Map([
       ["de", "Konflikt"],
       ["es", "Conflicto"],
@@ -106,6 +191,23 @@
       ["uk", "Конфлікт"],
       ["zh", "冲突"],
     ])],
+    [t("Connection Timed Out"), new Map([
+      ["de", "Zeitüberschreitung der Verbindung"],
+      ["es", "Tiempo de conexión agotado"],
+      ["fr", "Délai de connexion dépassé"],
+      ["hu", "A kapcsolat időtúllépése"],
+      ["id", "Waktu koneksi habis"],
+      ["it", "Connessione scaduta"],
+      ["ko", "연결 시간 초과"],
+      ["nl", "Time-out van de verbinding"],
+      ["no", "Tidsavbrudd for tilkoblingen"],
+      ["pl", "Przekroczono czas połączenia"],
+      ["pt", "Tempo de conexão esgotado"],
+      ["ro", "Conexiunea a expirat"],
+      ["ru", "Время ожидания соединения истекло"],
+      ["uk", "Час очікування з'єднання минув"],
+      ["zh", "连接超时"],
+    ])],
     [t("Double-check the URL"), new Map([
       ["de", "Überprüfen Sie die URL"],
       ["es", "Verifique la url"],
@@ -140,6 +242,40 @@
       ["uk", "Помилка"],
       ["zh", "错误"],
     ])],
+    [t("Expectation Failed"), new Map([
+      ["de", "Erwartung fehlgeschlagen"],
+      ["es", "Expectativa fallida"],
+      ["fr", "Attente non satisfaite"],
+      ["hu", "Sikertelen elvárás"],
+      ["id", "Ekspektasi gagal"],
+      ["it", "Aspettativa fallita"],
+      ["ko", "기대 실패"],
+      ["nl", "Verwachting mislukt"],
+      ["no", "Forventning mislyktes"],
+      ["pl", "Oczekiwanie nie powiodło się"],
+      ["pt", "Expectativa falhou"],
+      ["ro", "Așteptare eșuată"],
+      ["ru", "Ожидание не выполнено"],
+      ["uk", "Очікування не виконано"],
+      ["zh", "预期失败"],
+    ])],
+    [t("Failed Dependency"), new Map([
+      ["de", "Fehlgeschlagene Abhängigkeit"],
+      ["es", "Dependencia fallida"],
+      ["fr", "Échec de dépendance"],
+      ["hu", "Sikertelen függőség"],
+      ["id", "Dependensi gagal"],
+      ["it", "Dipendenza fallita"],
+      ["ko", "의존성 실패"],
+      ["nl", "Mislukte afhankelijkheid"],
+      ["no", "Avhengighet mislyktes"],
+      ["pl", "Błąd zależności"],
+      ["pt", "Falha de dependência"],
+      ["ro", "Dependență eșuată"],
+      ["ru", "Ошибка зависимости"],
+      ["uk", "Помилка залежності"],
+      ["zh", "依赖失败"],
+    ])],
     [t("Forbidden"), new Map([
       ["de", "Verboten"],
       ["es", "Prohibido"],
@@ -174,6 +310,23 @@
       ["uk", "Перенаправлений"],
       ["zh", "转发自"],
     ])],
+    [t("Further extensions to the request are required to fulfill it"), new Map([
+      ["de", "Für die Ausführung der Anfrage sind weitere Erweiterungen erforderlich"],
+      ["es", "Se requieren más extensiones de la petición para completarla"],
+      ["fr", "Des extensions supplémentaires de la requête sont nécessaires pour la traiter"],
+      ["hu", "A kérés teljesítéséhez további kiterjesztések szükségesek"],
+      ["id", "Ekstensi tambahan pada permintaan diperlukan untuk memenuhinya"],
+      ["it", "Sono necessarie ulteriori estensioni della richiesta per soddisfarla"],
+      ["ko", "요청을 처리하려면 추가 확장이 필요해요"],
+      ["nl", "Er zijn aanvullende uitbreidingen van het verzoek nodig om het te verwerken"],
+      ["no", "Ytterligere utvidelser av forespørselen kreves for å oppfylle den"],
+      ["pl", "Do realizacji żądania wymagane są dodatkowe rozszerzenia"],
+      ["pt", "São necessárias mais extensões na solicitação para atendê-la"],
+      ["ro", "Pentru a îndeplini cererea sunt necesare extensii suplimentare"],
+      ["ru", "Для выполнения запроса требуются дополнительные расширения"],
+      ["uk", "Для виконання запиту потрібні додаткові розширення"],
+      ["zh", "需要对请求进行进一步扩展才能完成"],
+    ])],
     [t("Gateway Timeout"), new Map([
       ["de", "Gateway Zeitüberschreitung"],
       ["es", "Tiempo límite de puerta de enlace excedido"],
@@ -242,6 +395,23 @@
       ["uk", "Успіхів"],
       ["zh", "祝好运"],
     ])],
+    [t("HTTP Request Sent to HTTPS Port"), new Map([
+      ["de", "HTTP-Anfrage an HTTPS-Port gesendet"],
+      ["es", "Petición HTTP enviada al puerto HTTPS"],
+      ["fr", "Requête HTTP envoyée au port HTTPS"],
+      ["hu", "HTTP-kérés a HTTPS-portra"],
+      ["id", "Permintaan HTTP dikirim ke port HTTPS"],
+      ["it", "Richiesta HTTP inviata alla porta HTTPS"],
+      ["ko", "HTTPS 포트로 HTTP 요청이 전송됨"],
+      ["nl", "HTTP-verzoek naar HTTPS-poort verzonden"],
+      ["no", "HTTP-forespørsel sendt til HTTPS-port"],
+      ["pl", "Żądanie HTTP wysłane na port HTTPS"],
+      ["pt", "Solicitação HTTP enviada à porta HTTPS"],
+      ["ro", "Cerere HTTP trimisă către portul HTTPS"],
+      ["ru", "HTTP-запрос отправлен на порт HTTPS"],
+      ["uk", "HTTP-запит надіслано на порт HTTPS"],
+      ["zh", "HTTP 请求发送到了 HTTPS 端口"],
+    ])],
     [t("HTTP Version Not Supported"), new Map([
       ["de", "HTTP-Version wird nicht unterstützt"],
       ["es", "Versión de HTTP no soportada"],
@@ -327,6 +497,23 @@
       ["uk", "Ім'я входу"],
       ["zh", "入口名"],
     ])],
+    [t("Insufficient Storage"), new Map([
+      ["de", "Unzureichender Speicherplatz"],
+      ["es", "Almacenamiento insuficiente"],
+      ["fr", "Espace de stockage insuffisant"],
+      ["hu", "Nincs elég tárhely"],
+      ["id", "Penyimpanan tidak cukup"],
+      ["it", "Spazio di archiviazione insufficiente"],
+      ["ko", "저장 공간 부족"],
+      ["nl", "Onvoldoende opslagruimte"],
+      ["no", "Utilstrekkelig lagringsplass"],
+      ["pl", "Niewystarczająca ilość miejsca"],
+      ["pt", "Armazenamento insuficiente"],
+      ["ro", "Spațiu de stocare insuficient"],
+      ["ru", "Недостаточно места"],
+      ["uk", "Недостатньо місця"],
+      ["zh", "存储空间不足"],
+    ])],
     [t("Internal Server Error"), new Map([
       ["de", "Interner Server-Fehler"],
       ["es", "Error Interno"],
@@ -344,6 +531,40 @@
       ["uk", "Внутрішня помилка сервера"],
       ["zh", "内部服务器错误"],
     ])],
+    [t("Invalid SSL Certificate"), new Map([
+      ["de", "Ungültiges SSL-Zertifikat"],
+      ["es", "Certificado SSL no válido"],
+      ["fr", "Certificat SSL invalide"],
+      ["hu", "Érvénytelen SSL-tanúsítvány"],
+      ["id", "Sertifikat SSL tidak valid"],
+      ["it", "Certificato SSL non valido"],
+      ["ko", "유효하지 않은 SSL 인증서"],
+      ["nl", "Ongeldig SSL-certificaat"],
+      ["no", "Ugyldig SSL-sertifikat"],
+      ["pl", "Nieprawidłowy certyfikat SSL"],
+      ["pt", "Certificado SSL inválido"],
+      ["ro", "Certificat SSL invalid"],
+      ["ru", "Недействительный SSL-сертификат"],
+      ["uk", "Недійсний SSL-сертифікат"],
+      ["zh", "无效的 SSL 证书"],
+    ])],
+    [t("Invalid Token"), new Map([
+      ["de", "Ungültiges Token"],
+      ["es", "Token no válido"],
+      ["fr", "Jeton invalide"],
+      ["hu", "Érvénytelen token"],
+      ["id", "Token tidak valid"],
+      ["it", "Token non valido"],
+      ["ko", "유효하지 않은 토큰"],
+      ["nl", "Ongeldig token"],
+      ["no", "Ugyldig token"],
+      ["pl", "Nieprawidłowy token"],
+      ["pt", "Token inválido"],
+      ["ro", "Token invalid"],
+      ["ru", "Недействительный токен"],
+      ["uk", "Недійсний токен"],
+      ["zh", "无效令牌"],
+    ])],
     [t("It was never here"), new Map([
       ["de", "Es war nie hier"],
       ["es", "Nunca ha estado aquí"],
@@ -378,6 +599,40 @@
       ["uk", "Потрібно вказати довжину"],
       ["zh", "需要长度"],
     ])],
+    [t("Locked"), new Map([
+      ["de", "Gesperrt"],
+      ["es", "Bloqueado"],
+      ["fr", "Verrouillé"],
+      ["hu", "Zárolva"],
+      ["id", "Terkunci"],
+      ["it", "Bloccato"],
+      ["ko", "잠김"],
+      ["nl", "Vergrendeld"],
+      ["no", "Låst"],
+      ["pl", "Zablokowane"],
+      ["pt", "Bloqueado"],
+      ["ro", "Blocat"],
+      ["ru", "Заблокировано"],
+      ["uk", "Заблоковано"],
+      ["zh", "已锁定"],
+    ])],
+    [t("Loop Detected"), new Map([
+      ["de", "Schleife erkannt"],
+      ["es", "Bucle detectado"],
+      ["fr", "Boucle détectée"],
+      ["hu", "Hurok észlelve"],
+      ["id", "Perulangan terdeteksi"],
+      ["it", "Ciclo rilevato"],
+      ["ko", "루프 감지됨"],
+      ["nl", "Lus gedetecteerd"],
+      ["no", "Løkke oppdaget"],
+      ["pl", "Wykryto pętlę"],
+      ["pt", "Loop detectado"],
+      ["ro", "Buclă detectată"],
+      ["ru", "Обнаружен цикл"],
+      ["uk", "Виявлено цикл"],
+      ["zh", "检测到循环"],
+    ])],
     [t("Method Not Allowed"), new Map([
       ["de", "Methode nicht erlaubt"],
       ["es", "Método no permitido"],
@@ -395,6 +650,23 @@
       ["uk", "Неприпустимий метод"],
       ["zh", "方法不被允许"],
     ])],
+    [t("Misdirected Request"), new Map([
+      ["de", "Fehlgeleitete Anfrage"],
+      ["es", "Petición mal dirigida"],
+      ["fr", "Requête mal dirigée"],
+      ["hu", "Rossz helyre irányított kérés"],
+      ["id", "Permintaan salah arah"],
+      ["it", "Richiesta indirizzata erroneamente"],
+      ["ko", "잘못 전달된 요청"],
+      ["nl", "Verkeerd gericht verzoek"],
+      ["no", "Feilrettet forespørsel"],
+      ["pl", "Błędnie skierowane żądanie"],
+      ["pt", "Solicitação mal direcionada"],
+      ["ro", "Cerere direcționată greșit"],
+      ["ru", "Неверно направленный запрос"],
+      ["uk", "Неправильно спрямований запит"],
+      ["zh", "错误定向的请求"],
+    ])],
     [t("My Computer"), new Map([
       ["de", "Arbeitsplatz"],
       ["es", "Mi PC"],
@@ -463,6 +735,74 @@
       ["uk", "Мережа"],
       ["zh", "网络"],
     ])],
+    [t("Network Authentication Required"), new Map([
+      ["de", "Netzwerkauthentifizierung erforderlich"],
+      ["es", "Autenticación de red requerida"],
+      ["fr", "Authentification réseau requise"],
+      ["hu", "Hálózati hitelesítés szükséges"],
+      ["id", "Autentikasi jaringan diperlukan"],
+      ["it", "Autenticazione di rete richiesta"],
+      ["ko", "네트워크 인증 필요"],
+      ["nl", "Netwerkauthenticatie vereist"],
+      ["no", "Nettverksautentisering kreves"],
+      ["pl", "Wymagane uwierzytelnienie w sieci"],
+      ["pt", "Autenticação de rede necessária"],
+      ["ro", "Autentificare în rețea necesară"],
+      ["ru", "Требуется сетевая аутентификация"],
+      ["uk", "Потрібна мережева автентифікація"],
+      ["zh", "需要网络认证"],
+    ])],
+    [t("No Response"), new Map([
+      ["de", "Keine Antwort"],
+      ["es", "Sin respuesta"],
+      ["fr", "Aucune réponse"],
+      ["hu", "Nincs válasz"],
+      ["id", "Tidak ada respons"],
+      ["it", "Nessuna risposta"],
+      ["ko", "응답 없음"],
+      ["nl", "Geen antwoord"],
+      ["no", "Ingen respons"],
+      ["pl", "Brak odpowiedzi"],
+      ["pt", "Sem resposta"],
+      ["ro", "Niciun răspuns"],
+      ["ru", "Нет ответа"],
+      ["uk", "Немає відповіді"],
+      ["zh", "无响应"],
+    ])],
+    [t("Not Acceptable"), new Map([
+      ["de", "Nicht akzeptabel"],
+      ["es", "No aceptable"],
+      ["fr", "Non acceptable"],
+      ["hu", "Nem elfogadható"],
+      ["id", "Tidak dapat diterima"],
+      ["it", "Non accettabile"],
+      ["ko", "허용되지 않음"],
+      ["nl", "Niet acceptabel"],
+      ["no", "Ikke akseptabelt"],
+      ["pl", "Nieakceptowalne"],
+      ["pt", "Não aceitável"],
+      ["ro", "Inacceptabil"],
+      ["ru", "Неприемлемый формат"],
+      ["uk", "Неприйнятний формат"],
+      ["zh", "无法接受"],
+    ])],
+    [t("Not Extended"), new Map([
+      ["de", "Nicht erweitert"],
+      ["es", "No extendido"],
+      ["fr", "Non étendu"],
+      ["hu", "Nincs kiterjesztve"],
+      ["id", "Tidak diperluas"],
+      ["it", "Non esteso"],
+      ["ko", "확장되지 않음"],
+      ["nl", "Niet uitgebreid"],
+      ["no", "Ikke utvidet"],
+      ["pl", "Nie rozszerzono"],
+      ["pt", "Não estendido"],
+      ["ro", "Neextins"],
+      ["ru", "Не расширено"],
+      ["uk", "Не розширено"],
+      ["zh", "未扩展"],
+    ])],
     [t("Not Found"), new Map([
       ["de", "Nicht gefunden"],
       ["es", "No encontrado"],
@@ -480,6 +820,57 @@
       ["uk", "Сторінку не знайдено"],
       ["zh", "未找到"],
     ])],
+    [t("Not Implemented"), new Map([
+      ["de", "Nicht implementiert"],
+      ["es", "No implementado"],
+      ["fr", "Non implémenté"],
+      ["hu", "Nincs megvalósítva"],
+      ["id", "Tidak diimplementasikan"],
+      ["it", "Non implementato"],
+      ["ko", "구현되지 않음"],
+      ["nl", "Niet geïmplementeerd"],
+      ["no", "Ikke implementert"],
+      ["pl", "Nie zaimplementowano"],
+      ["pt", "Não implementado"],
+      ["ro", "Neimplementat"],
+      ["ru", "Не реализовано"],
+      ["uk", "Не реалізовано"],
+      ["zh", "未实现"],
+    ])],
+    [t("Origin DNS Error"), new Map([
+      ["de", "DNS-Fehler des Ursprungs"],
+      ["es", "Error de DNS del origen"],
+      ["fr", "Erreur DNS de l'origine"],
+      ["hu", "A forrás DNS-hibája"],
+      ["id", "Kesalahan DNS server asal"],
+      ["it", "Errore DNS dell'origine"],
+      ["ko", "원본 DNS 오류"],
+      ["nl", "DNS-fout van oorsprong"],
+      ["no", "DNS-feil for opprinnelsen"],
+      ["pl", "Błąd DNS serwera źródłowego"],
+      ["pt", "Erro de DNS da origem"],
+      ["ro", "Eroare DNS a originii"],
+      ["ru", "Ошибка DNS исходного сервера"],
+      ["uk", "Помилка DNS вихідного сервера"],
+      ["zh", "源站 DNS 错误"],
+    ])],
+    [t("Origin Is Unreachable"), new Map([
+      ["de", "Ursprung nicht erreichbar"],
+      ["es", "Origen inaccesible"],
+      ["fr", "Origine injoignable"],
+      ["hu", "A forrás nem érhető el"],
+      ["id", "Server asal tidak dapat dijangkau"],
+      ["it", "Origine non raggiungibile"],
+      ["ko", "원본에 연결할 수 없음"],
+      ["nl", "Oorsprong is onbereikbaar"],
+      ["no", "Opprinnelsen er utilgjengelig"],
+      ["pl", "Serwer źródłowy jest nieosiągalny"],
+      ["pt", "Origem inacessível"],
+      ["ro", "Originea este inaccesibilă"],
+      ["ru", "Исходный сервер недоступен"],
+      ["uk", "Вихідний сервер недоступний"],
+      ["zh", "源站无法访问"],
+    ])],
     [t("Original URI"), new Map([
       ["de", "Originale URI"],
       ["es", "URI original"],
@@ -514,6 +905,40 @@
       ["uk", "Занадто великий запит"],
       ["zh", "请求体过大"],
     ])],
+    [t("Payment Required"), new Map([
+      ["de", "Zahlung erforderlich"],
+      ["es", "Pago requerido"],
+      ["fr", "Paiement requis"],
+      ["hu", "Fizetés szükséges"],
+      ["id", "Pembayaran diperlukan"],
+      ["it", "Pagamento richiesto"],
+      ["ko", "결제 필요"],
+      ["nl", "Betaling vereist"],
+      ["no", "Betaling kreves"],
+      ["pl", "Wymagana płatność"],
+      ["pt", "Pagamento necessário"],
+      ["ro", "Plată necesară"],
+      ["ru", "Требуется оплата"],
+      ["uk", "Потрібна оплата"],
+      ["zh", "需要付款"],
+    ])],
+    [t("Payment is required to access the requested page"), new Map([
+      ["de", "Für den Zugriff auf die angeforderte Seite ist eine Zahlung erforderlich"],
+      ["es", "Se requiere un pago para acceder a la página solicitada"],
+      ["fr", "Un paiement est requis pour accéder à la page demandée"],
+      ["hu", "A kért oldal eléréséhez fizetés szükséges"],
+      ["id", "Pembayaran diperlukan untuk mengakses halaman yang diminta"],
+      ["it", "È richiesto un pagamento per accedere alla pagina richiesta"],
+      ["ko", "요청한 페이지에 접근하려면 결제가 필요해요"],
+      ["nl", "Betaling is vereist om de pagina te openen"],
+      ["no", "Betaling kreves for å få tilgang til den forespurte siden"],
+      ["pl", "Dostęp do żądanej strony wymaga płatności"],
+      ["pt", "É necessário um pagamento para acessar a página solicitada"],
+      ["ro", "Este necesară o plată pentru a accesa pagina solicitată"],
+      ["ru", "Для доступа к запрашиваемой странице требуется оплата"],
+      ["uk", "Для доступу до запитаної сторінки потрібна оплата"],
+      ["zh", "访问请求的页面需要付款"],
+    ])],
     [t("Please check your authorization data"), new Map([
       ["de", "Bitte überprüfen Sie Ihre Zugangsdaten"],
       ["es", "Verifique sus datos de autorización"],
@@ -599,6 +1024,23 @@
       ["uk", "Збій під час обробки попередньої умови"],
       ["zh", "前置条件判定失败"],
     ])],
+    [t("Precondition Required"), new Map([
+      ["de", "Vorbedingung erforderlich"],
+      ["es", "Precondición requerida"],
+      ["fr", "Condition préalable requise"],
+      ["hu", "Előfeltétel szükséges"],
+      ["id", "Prasyarat diperlukan"],
+      ["it", "Precondizione richiesta"],
+      ["ko", "전제 조건 필요"],
+      ["nl", "Voorwaarde vereist"],
+      ["no", "Forutsetning kreves"],
+      ["pl", "Wymagany warunek wstępny"],
+      ["pt", "Pré-condição necessária"],
+      ["ro", "Precondiție necesară"],
+      ["ru", "Требуется предусловие"],
+      ["uk", "Потрібна передумова"],
+      ["zh", "需要前提条件"],
+    ])],
     [t("Proxy Authentication Required"), new Map([
       ["de", "Proxy-Authentifizierung benötigt"],
       ["es", "Autenticación de proxy requerida"],
@@ -616,6 +1058,57 @@
       ["uk", "Потрібна ідентифікація проксі"],
       ["zh", "需要代理服务器身份验证"],
     ])],
+    [t("Railgun Error"), new Map([
+      ["de", "Railgun-Fehler"],
+      ["es", "Error de Railgun"],
+      ["fr", "Erreur Railgun"],
+      ["hu", "Railgun-hiba"],
+      ["id", "Kesalahan Railgun"],
+      ["it", "Errore Railgun"],
+      ["ko", "Railgun 오류"],
+      ["nl", "Railgun-fout"],
+      ["no", "Railgun-feil"],
+      ["pl", "Błąd Railgun"],
+      ["pt", "Erro do Railgun"],
+      ["ro", "Eroare Railgun"],
+      ["ru", "Ошибка Railgun"],
+      ["uk", "Помилка Railgun"],
+      ["zh", "Railgun 错误"],
+    ])],
+    [t("Request Header Fields Too Large"), new Map([
+      ["de", "Header-Felder der Anfrage zu groß"],
+      ["es", "Campos de cabecera de la petición demasiado grandes"],
+      ["fr", "Champs d'en-tête de requête trop volumineux"],
+      ["hu", "Túl nagy kérésfejléc-mezők"],
+      ["id", "Kolom header permintaan terlalu besar"],
+      ["it", "Campi di intestazione della richiesta troppo grandi"],
+      ["ko", "요청 헤더 필드가 너무 큼"],
+      ["nl", "Headervelden van verzoek te groot"],
+      ["no", "Headerfeltene i forespørselen er for store"],
+      ["pl", "Pola nagłówka żądania są zbyt duże"],
+      ["pt", "Campos de cabeçalho da solicitação muito grandes"],
+      ["ro", "Câmpurile antetului cererii sunt prea mari"],
+      ["ru", "Слишком большие заголовки запроса"],
+      ["uk", "Занадто великі заголовки запиту"],
+      ["zh", "请求头字段过大"],
+    ])],
+    [t("Request Header Too Large"), new Map([
+      ["de", "Anfrage-Header zu groß"],
+      ["es", "Cabecera de la petición demasiado grande"],
+      ["fr", "En-tête de requête trop volumineux"],
+      ["hu", "Túl nagy kérésfejléc"],
+      ["id", "Header permintaan terlalu besar"],
+      ["it", "Intestazione della richiesta troppo grande"],
+      ["ko", "요청 헤더가 너무 큼"],
+      ["nl", "Header van verzoek te groot"],
+      ["no", "Forespørselsheaderen er for stor"],
+      ["pl", "Nagłówek żądania jest zbyt duży"],
+      ["pt", "Cabeçalho da solicitação muito grande"],
+      ["ro", "Antetul cererii este prea mare"],
+      ["ru", "Слишком большой заголовок запроса"],
+      ["uk", "Занадто великий заголовок запиту"],
+      ["zh", "请求头过大"],
+    ])],
     [t("Request ID"), new Map([
       ["de", "Anfrage ID"],
       ["es", "ID de la petición"],
@@ -684,6 +1177,57 @@
       ["uk", "Запитуваний діапазон недосяжний"],
       ["zh", "不满足请求范围"],
     ])],
+    [t("SSL Certificate Error"), new Map([
+      ["de", "SSL-Zertifikatsfehler"],
+      ["es", "Error de certificado SSL"],
+      ["fr", "Erreur de certificat SSL"],
+      ["hu", "SSL-tanúsítványhiba"],
+      ["id", "Kesalahan sertifikat SSL"],
+      ["it", "Errore del certificato SSL"],
+      ["ko", "SSL 인증서 오류"],
+      ["nl", "SSL-certificaatfout"],
+      ["no", "SSL-sertifikatfeil"],
+      ["pl", "Błąd certyfikatu SSL"],
+      ["pt", "Erro de certificado SSL"],
+      ["ro", "Eroare de certificat SSL"],
+      ["ru", "Ошибка SSL-сертификата"],
+      ["uk", "Помилка SSL-сертифіката"],
+      ["zh", "SSL 证书错误"],
+    ])],
+    [t("SSL Certificate Required"), new Map([
+      ["de", "SSL-Zertifikat erforderlich"],
+      ["es", "Certificado SSL requerido"],
+      ["fr", "Certificat SSL requis"],
+      ["hu", "SSL-tanúsítvány szükséges"],
+      ["id", "Sertifikat SSL diperlukan"],
+      ["it", "Certificato SSL richiesto"],
+      ["ko", "SSL 인증서 필요"],
+      ["nl", "SSL-certificaat vereist"],
+      ["no", "SSL-sertifikat kreves"],
+      ["pl", "Wymagany certyfikat SSL"],
+      ["pt", "Certificado SSL necessário"],
+      ["ro", "Certificat SSL necesar"],
+      ["ru", "Требуется SSL-сертификат"],
+      ["uk", "Потрібен SSL-сертифікат"],
+      ["zh", "需要 SSL 证书"],
+    ])],
+    [t("SSL Handshake Failed"), new Map([
+      ["de", "SSL-Handshake fehlgeschlagen"],
+      ["es", "Falló el protocolo de enlace SSL"],
+      ["fr", "Échec de la négociation SSL"],
+      ["hu", "Sikertelen SSL-kézfogás"],
+      ["id", "Jabat tangan SSL gagal"],
+      ["it", "Handshake SSL non riuscito"],
+      ["ko", "SSL 핸드셰이크 실패"],
+      ["nl", "SSL-handshake mislukt"],
+      ["no", "SSL-håndtrykk mislyktes"],
+      ["pl", "Uzgadnianie SSL nie powiodło się"],
+      ["pt", "Falha no handshake SSL"],
+      ["ro", "Negocierea SSL a eșuat"],
+      ["ru", "Ошибка SSL-рукопожатия"],
+      ["uk", "Помилка SSL-рукостискання"],
+      ["zh", "SSL 握手失败"],
+    ])],
     [t("Service Unavailable"), new Map([
       ["de", "Dienst nicht verfügbar"],
       ["es", "Servicio no disponible"],
@@ -735,6 +1279,23 @@
       ["uk", "Порт сервісу"],
       ["zh", "服务端口"],
     ])],
+    [t("Site Is Overloaded"), new Map([
+      ["de", "Website ist überlastet"],
+      ["es", "El sitio está sobrecargado"],
+      ["fr", "Le site est surchargé"],
+      ["hu", "Az oldal túlterhelt"],
+      ["id", "Situs kelebihan beban"],
+      ["it", "Il sito è sovraccarico"],
+      ["ko", "사이트 과부하"],
+      ["nl", "Site is overbelast"],
+      ["no", "Nettstedet er overbelastet"],
+      ["pl", "Witryna jest przeciążona"],
+      ["pt", "O site está sobrecarregado"],
+      ["ro", "Site-ul este supraîncărcat"],
+      ["ru", "Сайт перегружен"],
+      ["uk", "Сайт перевантажений"],
+      ["zh", "网站过载"],
+    ])],
     [t("Start"), new Map([
       ["de", "Start"],
       ["es", "Inicio"],
@@ -769,6 +1330,193 @@
       ["uk", "Заголовок \"Content-Length\" не був переданий. Сервер не може обробити запит без нього"],
       ["zh", "未指定Content-Length(内容长度)。服务器将不接受不包含此头信息的请求"],
     ])],
+    [t("The SSL certificate of the origin server is invalid"), new Map([
+      ["de", "Das SSL-Zertifikat des Ursprungsservers ist ungültig"],
+      ["es", "El certificado SSL del servidor de origen no es válido"],
+      ["fr", "Le certificat SSL du serveur d'origine n'est pas valide"],
+      ["hu", "A forrásszerver SSL-tanúsítványa érvénytelen"],
+      ["id", "Sertifikat SSL server asal tidak valid"],
+      ["it", "Il certificato SSL del server di origine non è valido"],
+      ["ko", "원본 서버의 SSL 인증서가 유효하지 않아요"],
+      ["nl", "Het SSL-certificaat van de oorspronkelijke server is ongeldig"],
+      ["no", "SSL-sertifikatet til opprinnelsesserveren er ugyldig"],
+      ["pl", "Certyfikat SSL serwera źródłowego jest nieprawidłowy"],
+      ["pt", "O certificado SSL do servidor de origem é inválido"],
+      ["ro", "Certificatul SSL al serverului de origine este invalid"],
+      ["ru", "SSL-сертификат исходного сервера недействителен"],
+      ["uk", "SSL-сертифікат вихідного сервера недійсний"],
+      ["zh", "源服务器的 SSL 证书无效"],
+    ])],
+    [t("The SSL handshake with the origin server failed"), new Map([
+      ["de", "Der SSL-Handshake mit dem Ursprungsserver ist fehlgeschlagen"],
+      ["es", "Falló el protocolo de enlace SSL con el servidor de origen"],
+      ["fr", "La négociation SSL avec le serveur d'origine a échoué"],
+      ["hu", "Az SSL-kézfogás a forrásszerverrel sikertelen volt"],
+      ["id", "Jabat tangan SSL dengan server asal gagal"],
+      ["it", "L'handshake SSL con il server di origine non è riuscito"],
+      ["ko", "원본 서버와의 SSL 핸드셰이크에 실패했어요"],
+      ["nl", "De SSL-handshake met de oorspronkelijke server is mislukt"],
+      ["no", "SSL-håndtrykket med opprinnelsesserveren mislyktes"],
+      ["pl", "Uzgadnianie SSL z serwerem źródłowym nie powiodło się"],
+      ["pt", "O handshake SSL com o servidor de origem falhou"],
+      ["ro", "Negocierea SSL cu serverul de origine a eșuat"],
+      ["ru", "SSL-рукопожатие с исходным сервером не удалось"],
+      ["uk", "SSL-рукостискання з вихідним сервером не вдалося"],
+      ["zh", "与源服务器的 SSL 握手失败"],
+    ])],
+    [t("The access token is expired or otherwise invalid"), new Map([
+      ["de", "Das Zugriffstoken ist abgelaufen oder anderweitig ungültig"],
+      ["es", "El token de acceso ha caducado o no es válido"],
+      ["fr", "Le jeton d'accès a expiré ou n'est pas valide"],
+      ["hu", "A hozzáférési token lejárt vagy más okból érvénytelen"],
+      ["id", "Token akses sudah kedaluwarsa atau tidak valid"],
+      ["it", "Il token di accesso è scaduto o non è valido"],
+      ["ko", "액세스 토큰이 만료되었거나 유효하지 않아요"],
+      ["nl", "Het toegangstoken is verlopen of anderszins ongeldig"],
+      ["no", "Tilgangstokenet er utløpt eller ugyldig"],
+      ["pl", "Token dostępu wygasł lub jest nieprawidłowy"],
+      ["pt", "O token de acesso expirou ou é inválido"],
+      ["ro", "Tokenul de acces a expirat sau este invalid"],
+      ["ru", "Токен доступа истёк или недействителен"],
+      ["uk", "Токен доступу прострочений або недійсний"],
+      ["zh", "访问令牌已过期或无效"],
+    ])],
+    [t("The address of the origin server could not be resolved"), new Map([
+      ["de", "Die Adresse des Ursprungsservers konnte nicht aufgelöst werden"],
+      ["es", "No se pudo resolver la dirección del servidor de origen"],
+      ["fr", "L'adresse du serveur d'origine n'a pas pu être résolue"],
+      ["hu", "A forrásszerver címét nem sikerült feloldani"],
+      ["id", "Alamat server asal tidak dapat ditemukan"],
+      ["it", "Non è stato possibile risolvere l'indirizzo del server di origine"],
+      ["ko", "원본 서버의 주소를 확인할 수 없어요"],
+      ["nl", "Het adres van de oorspronkelijke server kon niet worden opgezocht"],
+      ["no", "Adressen til opprinnelsesserveren kunne ikke slås opp"],
+      ["pl", "Nie udało się ustalić adresu serwera źródłowego"],
+      ["pt", "Não foi possível resolver o endereço do servidor de origem"],
+      ["ro", "Adresa serverului de origine nu a putut fi rezolvată"],
+      ["ru", "Не удалось определить адрес исходного сервера"],
+      ["uk", "Не вдалося визначити адресу вихідного сервера"],
+      ["zh", "无法解析源服务器的地址"],
+    ])],
+    [t("The client SSL certificate is invalid"), new Map([
+      ["de", "Das SSL-Zertifikat des Clients ist ungültig"],
+      ["es", "El certificado SSL del cliente no es válido"],
+      ["fr", "Le certificat SSL du client n'est pas valide"],
+      ["hu", "A kliens SSL-tanúsítványa érvénytelen"],
+      ["id", "Sertifikat SSL klien tidak valid"],
+      ["it", "Il certificato SSL del client non è valido"],
+      ["ko", "클라이언트 SSL 인증서가 유효하지 않아요"],
+      ["nl", "Het SSL-certificaat van de client is ongeldig"],
+      ["no", "Klientens SSL-sertifikat er ugyldig"],
+      ["pl", "Certyfikat SSL klienta jest nieprawidłowy"],
+      ["pt", "O certificado SSL do cliente é inválido"],
+      ["ro", "Certificatul SSL al clientului este invalid"],
+      ["ru", "Клиентский SSL-сертификат недействителен"],
+      ["uk", "Клієнтський SSL-сертифікат недійсний"],
+      ["zh", "客户端 SSL 证书无效"],
+    ])],
+    [t("The client SSL certificate is required, but was not provided"), new Map([
+      ["de", "Das SSL-Zertifikat des Clients ist erforderlich, wurde aber nicht übermittelt"],
+      ["es", "Se requiere el certificado SSL del cliente, pero no se proporcionó"],
+      ["fr", "Le certificat SSL du client est requis, mais n'a pas été fourni"],
+      ["hu", "A kliens SSL-tanúsítványa szükséges, de nem lett megadva"],
+      ["id", "Sertifikat SSL klien diperlukan, tetapi tidak diberikan"],
+      ["it", "Il certificato SSL del client è richiesto, ma non è stato fornito"],
+      ["ko", "클라이언트 SSL 인증서가 필요하지만 제공되지 않았어요"],
+      ["nl", "Het SSL-certificaat van de client is vereist, maar werd niet meegestuurd"],
+      ["no", "Klientens SSL-sertifikat kreves, men ble ikke oppgitt"],
+      ["pl", "Wymagany jest certyfikat SSL klienta, ale nie został przesłany"],
+      ["pt", "O certificado SSL do cliente é necessário, mas não foi fornecido"],
+      ["ro", "Certificatul SSL al clientului este necesar, dar nu a fost furnizat"],
+      ["ru", "Требуется клиентский SSL-сертификат, но он не был предоставлен"],
+      ["uk", "Потрібен клієнтський SSL-сертифікат, але його не надано"],
+      ["zh", "需要客户端 SSL 证书，但未提供"],
+    ])],
+    [t("The client closed the connection before the load balancer responded"), new Map([
+      ["de", "Der Client hat die Verbindung geschlossen, bevor der Load Balancer geantwortet hat"],
+      ["es", "El cliente cerró la conexión antes de que respondiera el balanceador de carga"],
+      ["fr", "Le client a fermé la connexion avant la réponse de l'équilibreur de charge"],
+      ["hu", "A kliens bontotta a kapcsolatot, mielőtt a terheléselosztó válaszolt volna"],
+      ["id", "Klien menutup koneksi sebelum penyeimbang beban merespons"],
+      ["it", "Il client ha chiuso la connessione prima che il bilanciatore di carico rispondesse"],
+      ["ko", "로드 밸런서가 응답하기 전에 클라이언트가 연결을 닫았어요"],
+      ["nl", "De client heeft de verbinding verbroken voordat de load balancer antwoordde"],
+      ["no", "Klienten lukket tilkoblingen før lastbalansereren svarte"],
+      ["pl", "Klient zamknął połączenie, zanim odpowiedział load balancer"],
+      ["pt", "O cliente fechou a conexão antes de o balanceador de carga responder"],
+      ["ro", "Clientul a închis conexiunea înainte ca echilibrorul de încărcare să răspundă"],
+      ["ru", "Клиент закрыл соединение до ответа балансировщика нагрузки"],
+      ["uk", "Клієнт закрив з'єднання до відповіді балансувальника навантаження"],
+      ["zh", "客户端在负载均衡器响应之前关闭了连接"],
+    ])],
+    [t("The client closed the connection before the server responded"), new Map([
+      ["de", "Der Client hat die Verbindung geschlossen, bevor der Server geantwortet hat"],
+      ["es", "El cliente cerró la conexión antes de que respondiera el servidor"],
+      ["fr", "Le client a fermé la connexion avant la réponse du serveur"],
+      ["hu", "A kliens bontotta a kapcsolatot, mielőtt a szerver válaszolt volna"],
+      ["id", "Klien menutup koneksi sebelum server merespons"],
+      ["it", "Il client ha chiuso la connessione prima che il server rispondesse"],
+      ["ko", "서버가 응답하기 전에 클라이언트가 연결을 닫았어요"],
+      ["nl", "De client heeft de verbinding verbroken voordat de server antwoordde"],
+      ["no", "Klienten lukket tilkoblingen før serveren svarte"],
+      ["pl", "Klient zamknął połączenie, zanim serwer odpowiedział"],
+      ["pt", "O cliente fechou a conexão antes de o servidor responder"],
+      ["ro", "Clientul a închis conexiunea înainte ca serverul să răspundă"],
+      ["ru", "Клиент закрыл соединение до ответа сервера"],
+      ["uk", "Клієнт закрив з'єднання до відповіді сервера"],
+      ["zh", "客户端在服务器响应之前关闭了连接"],
+    ])],
+    [t("The client must switch to a different protocol"), new Map([
+      ["de", "Der Client muss zu einem anderen Protokoll wechseln"],
+      ["es", "El cliente debe cambiar a un protocolo diferente"],
+      ["fr", "Le client doit passer à un autre protocole"],
+      ["hu", "A kliensnek másik protokollra kell váltania"],
+      ["id", "Klien harus beralih ke protokol lain"],
+      ["it", "Il client deve passare a un protocollo diverso"],
+      ["ko", "클라이언트가 다른 프로토콜로 전환해야 해요"],
+      ["nl", "De client moet overschakelen naar een ander protocol"],
+      ["no", "Klienten må bytte til en annen protokoll"],
+      ["pl", "Klient musi przełączyć się na inny protokół"],
+      ["pt", "O cliente deve mudar para um protocolo diferente"],
+      ["ro", "Clientul trebuie să treacă la un alt protocol"],
+      ["ru", "Клиент должен перейти на другой протокол"],
+      ["uk", "Клієнт має перейти на інший протокол"],
+      ["zh", "客户端必须切换到其他协议"],
+    ])],
+    [t("The connection between the CDN and the origin server was interrupted"), new Map([
+      ["de", "Die Verbindung zwischen dem CDN und dem Ursprungsserver wurde unterbrochen"],
+      ["es", "Se interrumpió la conexión entre la CDN y el servidor de origen"],
+      ["fr", "La connexion entre le CDN et le serveur d'origine a été interrompue"],
+      ["hu", "A CDN és a forrásszerver közötti kapcsolat megszakadt"],
+      ["id", "Koneksi antara CDN dan server asal terputus"],
+      ["it", "La connessione tra la CDN e il server di origine è stata interrotta"],
+      ["ko", "CDN과 원본 서버 간의 연결이 끊어졌어요"],
+      ["nl", "De verbinding tussen het CDN en de oorspronkelijke server is onderbroken"],
+      ["no", "Tilkoblingen mellom CDN-et og opprinnelsesserveren ble brutt"],
+      ["pl", "Połączenie między CDN a serwerem źródłowym zostało przerwane"],
+      ["pt", "A conexão entre a CDN e o servidor de origem foi interrompida"],
+      ["ro", "Conexiunea dintre CDN și serverul de origine a fost întreruptă"],
+      ["ru", "Соединение между CDN и исходным сервером было прервано"],
+      ["uk", "З'єднання між CDN і вихідним сервером було перервано"],
+      ["zh", "CDN 与源服务器之间的连接中断了"],
+    ])],
+    [t("The connection to the origin server timed out"), new Map([
+      ["de", "Die Verbindung zum Ursprungsserver hat das Zeitlimit überschritten"],
+      ["es", "Se agotó el tiempo de conexión con el servidor de origen"],
+      ["fr", "La connexion au serveur d'origine a expiré"],
+      ["hu", "A forrásszerverhez való kapcsolódás túllépte az időkorlátot"],
+      ["id", "Koneksi ke server asal kehabisan waktu"],
+      ["it", "La connessione al server di origine è scaduta"],
+      ["ko", "원본 서버와의 연결 시간이 초과되었어요"],
+      ["nl", "De verbinding met de oorspronkelijke server is verlopen"],
+      ["no", "Tilkoblingen til opprinnelsesserveren fikk tidsavbrudd"],
+      ["pl", "Przekroczono czas połączenia z serwerem źródłowym"],
+      ["pt", "A conexão com o servidor de origem expirou"],
+      ["ro", "Conexiunea la serverul de origine a expirat"],
+      ["ru", "Истекло время ожидания соединения с исходным сервером"],
+      ["uk", "Минув час очікування з'єднання з вихідним сервером"],
+      ["zh", "与源服务器的连接超时"],
+    ])],
     [t("The gateway has timed out"), new Map([
       ["de", "Das Zeitlimit für den Verbindungsaufbau mit dem Upstream-Server ist abgelaufen"],
       ["es", "La puerta de enlace ha sobrepasado el tiempo límite"],
@@ -786,6 +1534,40 @@
       ["uk", "У шлюзу закінчився час очікування"],
       ["zh", "网关响应已经超时"],
     ])],
+    [t("The load balancer could not authenticate the user with the identity provider"), new Map([
+      ["de", "Der Load Balancer konnte den Benutzer beim Identitätsanbieter nicht authentifizieren"],
+      ["es", "El balanceador de carga no pudo autenticar al usuario con el proveedor de identidad"],
+      ["fr", "L'équilibreur de charge n'a pas pu authentifier l'utilisateur auprès du fournisseur d'identité"],
+      ["hu", "A terheléselosztó nem tudta hitelesíteni a felhasználót az identitásszolgáltatónál"],
+      ["id", "Penyeimbang beban tidak dapat mengautentikasi pengguna dengan penyedia identitas"],
+      ["it", "Il bilanciatore di carico non è riuscito ad autenticare l'utente presso il provider di identità"],
+      ["ko", "로드 밸런서가 ID 공급자를 통해 사용자를 인증할 수 없었어요"],
+      ["nl", "De load balancer kon de gebruiker niet authenticeren bij de identiteitsprovider"],
+      ["no", "Lastbalansereren kunne ikke autentisere brukeren hos identitetsleverandøren"],
+      ["pl", "Load balancer nie mógł uwierzytelnić użytkownika u dostawcy tożsamości"],
+      ["pt", "O balanceador de carga não conseguiu autenticar o usuário no provedor de identidade"],
+      ["ro", "Echilibrorul de încărcare nu a putut autentifica utilizatorul la furnizorul de identitate"],
+      ["ru", "Балансировщик нагрузки не смог аутентифицировать пользователя у поставщика удостоверений"],
+      ["uk", "Балансувальник навантаження не зміг автентифікувати користувача в постачальника ідентифікації"],
+      ["zh", "负载均衡器无法通过身份提供商验证用户"],
+    ])],
+    [t("The media type of the request payload is not supported"), new Map([
+      ["de", "Der Medientyp des Anfrageinhalts wird nicht unterstützt"],
+      ["es", "El tipo de medio del contenido de la petición no está soportado"],
+      ["fr", "Le type de média du contenu de la requête n'est pas pris en charge"],
+      ["hu", "A kérés tartalmának médiatípusa nem támogatott"],
+      ["id", "Jenis media dari muatan permintaan tidak didukung"],
+      ["it", "Il tipo di media del contenuto della richiesta non è supportato"],
+      ["ko", "요청 본문의 미디어 유형이 지원되지 않아요"],
+      ["nl", "Het mediatype van de inhoud van het verzoek wordt niet ondersteund"],
+      ["no", "Medietypen til innholdet i forespørselen støttes ikke"],
+      ["pl", "Typ danych w treści żądania nie jest obsługiwany"],
+      ["pt", "O tipo de mídia do conteúdo da solicitação não é suportado"],
+      ["ro", "Tipul media al conținutului cererii nu este acceptat"],
+      ["ru", "Тип данных в теле запроса не поддерживается"],
+      ["uk", "Тип даних у тілі запиту не підтримується"],
+      ["zh", "不支持请求内容的媒体类型"],
+    ])],
     [t("The method specified in the request is not allowed"), new Map([
       ["de", "Die in der Anfrage angegebene Methode ist nicht zulässig"],
       ["es", "El método especificado en la petición no está permitido"],
@@ -803,6 +1585,74 @@
       ["uk", "Метод, зазначений у запиті, не підтримується"],
       ["zh", "请求指定的方法不被允许"],
     ])],
+    [t("The origin server could not be reached"), new Map([
+      ["de", "Der Ursprungsserver konnte nicht erreicht werden"],
+      ["es", "No se pudo alcanzar el servidor de origen"],
+      ["fr", "Le serveur d'origine n'a pas pu être joint"],
+      ["hu", "A forrásszerver nem volt elérhető"],
+      ["id", "Server asal tidak dapat dijangkau"],
+      ["it", "Non è stato possibile raggiungere il server di origine"],
+      ["ko", "원본 서버에 연결할 수 없어요"],
+      ["nl", "De oorspronkelijke server kon niet worden bereikt"],
+      ["no", "Opprinnelsesserveren kunne ikke nås"],
+      ["pl", "Nie udało się połączyć z serwerem źródłowym"],
+      ["pt", "Não foi possível alcançar o servidor de origem"],
+      ["ro", "Serverul de origine nu a putut fi contactat"],
+      ["ru", "Не удалось связаться с исходным сервером"],
+      ["uk", "Не вдалося зв'язатися з вихідним сервером"],
+      ["zh", "无法访问源服务器"],
+    ])],
+    [t("The origin server did not respond in time"), new Map([
+      ["de", "Der Ursprungsserver hat nicht rechtzeitig geantwortet"],
+      ["es", "El servidor de origen no respondió a tiempo"],
+      ["fr", "Le serveur d'origine n'a pas répondu à temps"],
+      ["hu", "A forrásszerver nem válaszolt időben"],
+      ["id", "Server asal tidak merespons tepat waktu"],
+      ["it", "Il server di origine non ha risposto in tempo"],
+      ["ko", "원본 서버가 제때 응답하지 않았어요"],
+      ["nl", "De oorspronkelijke server heeft niet op tijd geantwoord"],
+      ["no", "Opprinnelsesserveren svarte ikke i tide"],
+      ["pl", "Serwer źródłowy nie odpowiedział na czas"],
+      ["pt", "O servidor de origem não respondeu a tempo"],
+      ["ro", "Serverul de origine nu a răspuns la timp"],
+      ["ru", "Исходный сервер не ответил вовремя"],
+      ["uk", "Вихідний сервер не відповів вчасно"],
+      ["zh", "源服务器未及时响应"],
+    ])],
+    [t("The origin server refused the connection"), new Map([
+      ["de", "Der Ursprungsserver hat die Verbindung abgelehnt"],
+      ["es", "El servidor de origen rechazó la conexión"],
+      ["fr", "Le serveur d'origine a refusé la connexion"],
+      ["hu", "A forrásszerver elutasította a kapcsolatot"],
+      ["id", "Server asal menolak koneksi"],
+      ["it", "Il server di origine ha rifiutato la connessione"],
+      ["ko", "원본 서버가 연결을 거부했어요"],
+      ["nl", "De oorspronkelijke server heeft de verbinding geweigerd"],
+      ["no", "Opprinnelsesserveren avviste tilkoblingen"],
+      ["pl", "Serwer źródłowy odrzucił połączenie"],
+      ["pt", "O servidor de origem recusou a conexão"],
+      ["ro", "Serverul de origine a refuzat conexiunea"],
+      ["ru", "Исходный сервер отклонил соединение"],
+      ["uk", "Вихідний сервер відхилив з'єднання"],
+      ["zh", "源服务器拒绝了连接"],
+    ])],
+    [t("The origin server returned an empty, unknown, or unexpected response"), new Map([
+      ["de", "Der Ursprungsserver hat eine leere, unbekannte oder unerwartete Antwort zurückgegeben"],
+      ["es", "El servidor de origen devolvió una respuesta vacía, desconocida o inesperada"],
+      ["fr", "Le serveur d'origine a renvoyé une réponse vide, inconnue ou inattendue"],
+      ["hu", "A forrásszerver üres, ismeretlen vagy váratlan választ adott vissza"],
+      ["id", "Server asal mengembalikan respons yang kosong, tidak dikenal, atau tidak terduga"],
+      ["it", "Il server di origine ha restituito una risposta vuota, sconosciuta o inattesa"],
+      ["ko", "원본 서버가 비어 있거나 알 수 없거나 예기치 않은 응답을 반환했어요"],
+      ["nl", "De oorspronkelijke server gaf een leeg, onbekend of onverwacht antwoord"],
+      ["no", "Opprinnelsesserveren returnerte et tomt, ukjent eller uventet svar"],
+      ["pl", "Serwer źródłowy zwrócił pustą, nieznaną lub nieoczekiwaną odpowiedź"],
+      ["pt", "O servidor de origem retornou uma resposta vazia, desconhecida ou inesperada"],
+      ["ro", "Serverul de origine a returnat un răspuns gol, necunoscut sau neașteptat"],
+      ["ru", "Исходный сервер вернул пустой, неизвестный или неожиданный ответ"],
+      ["uk", "Вихідний сервер повернув порожню, невідому або неочікувану відповідь"],
+      ["zh", "源服务器返回了空的、未知的或意外的响应"],
+    ])],
     [t("The pre condition given in the request evaluated to false by the server"), new Map([
       ["de", "Die in der Anfrage angegebene Vorbedingung wird vom Server als falsch bewertet"],
       ["es", "La precondición ha sido evaluada como negativa para esta petición por el servidor"],
@@ -837,6 +1687,57 @@
       ["uk", "Запит не може бути оброблений через конфлікт"],
       ["zh", "由于冲突，请求无法完成"],
     ])],
+    [t("The request failed because the request it depends on failed"), new Map([
+      ["de", "Die Anfrage ist fehlgeschlagen, weil die Anfrage, von der sie abhängt, fehlgeschlagen ist"],
+      ["es", "La petición falló porque falló la petición de la que depende"],
+      ["fr", "La requête a échoué car la requête dont elle dépend a échoué"],
+      ["hu", "A kérés sikertelen, mert az a kérés, amelytől függ, sikertelen volt"],
+      ["id", "Permintaan gagal karena permintaan yang menjadi dependensinya gagal"],
+      ["it", "La richiesta non è riuscita perché la richiesta da cui dipende non è riuscita"],
+      ["ko", "요청이 의존하는 요청이 실패하여 요청이 실패했어요"],
+      ["nl", "Het verzoek is mislukt omdat het verzoek waarvan het afhankelijk is, is mislukt"],
+      ["no", "Forespørselen mislyktes fordi forespørselen den er avhengig av, mislyktes"],
+      ["pl", "Żądanie nie powiodło się, ponieważ nie powiodło się żądanie, od którego zależy"],
+      ["pt", "A solicitação falhou porque a solicitação da qual ela depende falhou"],
+      ["ro", "Cererea a eșuat deoarece cererea de care depinde a eșuat"],
+      ["ru", "Запрос не выполнен, так как не выполнен запрос, от которого он зависит"],
+      ["uk", "Запит не виконано, оскільки не виконано запит, від якого він залежить"],
+      ["zh", "请求失败，因为它所依赖的请求失败了"],
+    ])],
+    [t("The request headers are too large for the server to process"), new Map([
+      ["de", "Die Header der Anfrage sind zu groß, um vom Server verarbeitet zu werden"],
+      ["es", "Las cabeceras de la petición son demasiado grandes para que el servidor las procese"],
+      ["fr", "Les en-têtes de la requête sont trop volumineux pour être traités par le serveur"],
+      ["hu", "A kérés fejlécei túl nagyok ahhoz, hogy a szerver feldolgozza őket"],
+      ["id", "Header permintaan terlalu besar untuk diproses server"],
+      ["it", "Le intestazioni della richiesta sono troppo grandi per essere elaborate dal server"],
+      ["ko", "요청 헤더가 너무 커서 서버가 처리할 수 없어요"],
+      ["nl", "De headers van het verzoek zijn te groot voor de server om te verwerken"],
+      ["no", "Headerne i forespørselen er for store til at serveren kan behandle dem"],
+      ["pl", "Nagłówki żądania są zbyt duże, aby serwer mógł je przetworzyć"],
+      ["pt", "Os cabeçalhos da solicitação são grandes demais para o servidor processar"],
+      ["ro", "Antetele cererii sunt prea mari pentru a fi procesate de server"],
+      ["ru", "Заголовки запроса слишком велики для обработки сервером"],
+      ["uk", "Заголовки запиту завеликі для обробки сервером"],
+      ["zh", "请求头过大，服务器无法处理"],
+    ])],
+    [t("The request is well-formed, but could not be processed"), new Map([
+      ["de", "Die Anfrage ist korrekt aufgebaut, konnte aber nicht verarbeitet werden"],
+      ["es", "La petición está bien formada, pero no se pudo procesar"],
+      ["fr", "La requête est bien formée, mais n'a pas pu être traitée"],
+      ["hu", "A kérés formailag helyes, de nem sikerült feldolgozni"],
+      ["id", "Permintaan sudah benar, tetapi tidak dapat diproses"],
+      ["it", "La richiesta è ben formata, ma non è stato possibile elaborarla"],
+      ["ko", "요청 형식은 올바르지만 처리할 수 없어요"],
+      ["nl", "Het verzoek is correct opgesteld, maar kon niet worden verwerkt"],
+      ["no", "Forespørselen er korrekt utformet, men kunne ikke behandles"],
+      ["pl", "Żądanie jest poprawne, ale nie mogło zostać przetworzone"],
+      ["pt", "A solicitação está bem formada, mas não pôde ser processada"],
+      ["ro", "Cererea este corect formulată, dar nu a putut fi procesată"],
+      ["ru", "Запрос составлен корректно, но не может быть обработан"],
+      ["uk", "Запит складено правильно, але його не вдалося обробити"],
+      ["zh", "请求格式正确，但无法处理"],
+    ])],
     [t("The request took longer than the server was prepared to wait"), new Map([
       ["de", "Die Anfrage hat länger gedauert, als der Server bereit war zu warten"],
       ["es", "La petición esta tardando más de lo que el servidor estaba preparado para esperar"],
@@ -854,6 +1755,40 @@
       ["uk", "Надсилання запиту зайняло надто багато часу"],
       ["zh", "请求用时超过了服务器设置的最长等待时间"],
     ])],
+    [t("The request was sent to a server that can not respond to it"), new Map([
+      ["de", "Die Anfrage wurde an einen Server gesendet, der sie nicht beantworten kann"],
+      ["es", "La petición se envió a un servidor que no puede responderla"],
+      ["fr", "La requête a été envoyée à un serveur qui ne peut pas y répondre"],
+      ["hu", "A kérés olyan szerverhez került, amely nem tud rá válaszolni"],
+      ["id", "Permintaan dikirim ke server yang tidak dapat meresponsnya"],
+      ["it", "La richiesta è stata inviata a un server che non può rispondere"],
+      ["ko", "요청이 응답할 수 없는 서버로 전송되었어요"],
+      ["nl", "Het verzoek is verzonden naar een server die er niet op kan antwoorden"],
+      ["no", "Forespørselen ble sendt til en server som ikke kan svare på den"],
+      ["pl", "Żądanie zostało wysłane do serwera, który nie może na nie odpowiedzieć"],
+      ["pt", "A solicitação foi enviada a um servidor que não pode respondê-la"],
+      ["ro", "Cererea a fost trimisă unui server care nu îi poate răspunde"],
+      ["ru", "Запрос был отправлен на сервер, который не может на него ответить"],
+      ["uk", "Запит було надіслано на сервер, який не може на нього відповісти"],
+      ["zh", "请求被发送到了无法响应它的服务器"],
+    ])],
+    [t("The requested URL is longer than the server is willing to process"), new Map([
+      ["de", "Die angeforderte URL ist länger, als der Server verarbeiten möchte"],
+      ["es", "La URL solicitada es más larga de lo que el servidor está dispuesto a procesar"],
+      ["fr", "L'URL demandée est plus longue que ce que le serveur accepte de traiter"],
+      ["hu", "A kért URL hosszabb, mint amit a szerver hajlandó feldolgozni"],
+      ["id", "URL yang diminta lebih panjang dari yang bersedia diproses server"],
+      ["it", "L'URL richiesto è più lungo di quanto il server sia disposto a elaborare"],
+      ["ko", "요청한 URL이 서버가 처리할 수 있는 길이보다 길어요"],
+      ["nl", "De opgevraagde URL is langer dan de server wil verwerken"],
+      ["no", "Den forespurte URL-en er lengre enn serveren er villig til å behandle"],
+      ["pl", "Żądany adres URL jest dłuższy, niż serwer jest w stanie przetworzyć"],
+      ["pt", "A URL solicitada é mais longa do que o servidor está disposto a processar"],
+      ["ro", "URL-ul solicitat este mai lung decât poate procesa serverul"],
+      ["ru", "Запрашиваемый URL длиннее, чем сервер готов обработать"],
+      ["uk", "Запитаний URL довший, ніж сервер готовий обробити"],
+      ["zh", "请求的 URL 超出了服务器愿意处理的长度"],
+    ])],
     [t("The requested byte range is not available and is out of bounds"), new Map([
       ["de", "Der angefragte Teilbereich der Ressource existiert nicht oder ist ungültig"],
       ["es", "El intervalo de bytes requerido no está disponible o se encuentra fuera de los límites"],
@@ -888,6 +1823,23 @@
       ["uk", "Запитувана сторінка більше не доступна"],
       ["zh", "请求的页面不再可用"],
     ])],
+    [t("The requested page is unavailable for legal reasons"), new Map([
+      ["de", "Die angeforderte Seite ist aus rechtlichen Gründen nicht verfügbar"],
+      ["es", "La página solicitada no está disponible por razones legales"],
+      ["fr", "La page demandée est indisponible pour des raisons légales"],
+      ["hu", "A kért oldal jogi okokból nem elérhető"],
+      ["id", "Halaman yang diminta tidak tersedia karena alasan hukum"],
+      ["it", "La pagina richiesta non è disponibile per motivi legali"],
+      ["ko", "요청한 페이지는 법적 사유로 이용할 수 없어요"],
+      ["nl", "De opgevraagde pagina is om juridische redenen niet beschikbaar"],
+      ["no", "Den forespurte siden er utilgjengelig av juridiske årsaker"],
+      ["pl", "Żądana strona jest niedostępna z przyczyn prawnych"],
+      ["pt", "A página solicitada está indisponível por motivos legais"],
+      ["ro", "Pagina solicitată este indisponibilă din motive legale"],
+      ["ru", "Запрашиваемая страница недоступна по юридическим причинам"],
+      ["uk", "Запитана сторінка недоступна з юридичних причин"],
+      ["zh", "请求的页面因法律原因不可用"],
+    ])],
     [t("The requested page needs a username and a password"), new Map([
       ["de", "Die angeforderte Seite benötigt einen Benutzernamen und ein Passwort"],
       ["es", "La página solicitada necesita un usuario y una contraseña"],
@@ -905,6 +1857,23 @@
       ["uk", "Щоб отримати доступ до сторінки, потрібний логін та пароль"],
       ["zh", "请求的页面需要用户名和密码"],
     ])],
+    [t("The requested resource is locked"), new Map([
+      ["de", "Die angeforderte Ressource ist gesperrt"],
+      ["es", "El recurso solicitado está bloqueado"],
+      ["fr", "La ressource demandée est verrouillée"],
+      ["hu", "A kért erőforrás zárolva van"],
+      ["id", "Sumber daya yang diminta terkunci"],
+      ["it", "La risorsa richiesta è bloccata"],
+      ["ko", "요청한 리소스가 잠겨 있어요"],
+      ["nl", "De opgevraagde bron is vergrendeld"],
+      ["no", "Den forespurte ressursen er låst"],
+      ["pl", "Żądany zasób jest zablokowany"],
+      ["pt", "O recurso solicitado está bloqueado"],
+      ["ro", "Resursa solicitată este blocată"],
+      ["ru", "Запрашиваемый ресурс заблокирован"],
+      ["uk", "Запитаний ресурс заблоковано"],
+      ["zh", "请求的资源已被锁定"],
+    ])],
     [t("The server can not find the requested page"), new Map([
       ["de", "Der Server kann die angeforderte Seite nicht finden"],
       ["es", "El servidor no puede encontrar la página solicitada"],
@@ -922,6 +1891,74 @@
       ["uk", "Сервер не зміг знайти запитану сторінку"],
       ["zh", "服务器找不到请求的页面"],
     ])],
+    [t("The server can not meet the requirements of the \"Expect\" header"), new Map([
+      ["de", "Der Server kann die Anforderungen des „Expect“-Headers nicht erfüllen"],
+      ["es", "El servidor no puede cumplir los requisitos de la cabecera \"Expect\""],
+      ["fr", "Le serveur ne peut satisfaire les exigences de l'en-tête « Expect »"],
+      ["hu", "A szerver nem tudja teljesíteni az „Expect” fejléc követelményeit"],
+      ["id", "Server tidak dapat memenuhi persyaratan header \"Expect\""],
+      ["it", "Il server non può soddisfare i requisiti dell'intestazione \"Expect\""],
+      ["ko", "서버가 \"Expect\" 헤더의 요구 사항을 충족할 수 없어요"],
+      ["nl", "De server kan niet voldoen aan de eisen van de \"Expect\"-header"],
+      ["no", "Serveren kan ikke oppfylle kravene i \"Expect\"-headeren"],
+      ["pl", "Serwer nie może spełnić wymagań nagłówka „Expect”"],
+      ["pt", "O servidor não consegue atender aos requisitos do cabeçalho \"Expect\""],
+      ["ro", "Serverul nu poate îndeplini cerințele antetului „Expect”"],
+      ["ru", "Сервер не может выполнить требования заголовка «Expect»"],
+      ["uk", "Сервер не може виконати вимоги заголовка «Expect»"],
+      ["zh", "服务器无法满足“Expect”请求头的要求"],
+    ])],
+    [t("The server can not produce a response in any of the accepted formats"), new Map([
+      ["de", "Der Server kann keine Antwort in einem der akzeptierten Formate erzeugen"],
+      ["es", "El servidor no puede generar una respuesta en ninguno de los formatos aceptados"],
+      ["fr", "Le serveur ne peut produire une réponse dans aucun des formats acceptés"],
+      ["hu", "A szerver egyik elfogadott formátumban sem tud választ adni"],
+      ["id", "Server tidak dapat menghasilkan respons dalam format yang diterima"],
+      ["it", "Il server non può produrre una risposta in nessuno dei formati accettati"],
+      ["ko", "서버가 허용된 형식으로 응답을 만들 수 없어요"],
+      ["nl", "De server kan geen antwoord geven in een van de geaccepteerde formaten"],
+      ["no", "Serveren kan ikke lage et svar i noen av de aksepterte formatene"],
+      ["pl", "Serwer nie może wygenerować odpowiedzi w żadnym z akceptowanych formatów"],
+      ["pt", "O servidor não consegue produzir uma resposta em nenhum dos formatos aceitos"],
+      ["ro", "Serverul nu poate genera un răspuns în niciunul dintre formatele acceptate"],
+      ["ru", "Сервер не может сформировать ответ ни в одном из допустимых форматов"],
+      ["uk", "Сервер не може сформувати відповідь у жодному з прийнятних форматів"],
+      ["zh", "服务器无法以任何可接受的格式生成响应"],
+    ])],
+    [t("The server closed the connection without sending a response"), new Map([
+      ["de", "Der Server hat die Verbindung geschlossen, ohne eine Antwort zu senden"],
+      ["es", "El servidor cerró la conexión sin enviar una respuesta"],
+      ["fr", "Le serveur a fermé la connexion sans envoyer de réponse"],
+      ["hu", "A szerver válasz küldése nélkül bontotta a kapcsolatot"],
+      ["id", "Server menutup koneksi tanpa mengirim respons"],
+      ["it", "Il server ha chiuso la connessione senza inviare una risposta"],
+      ["ko", "서버가 응답을 보내지 않고 연결을 닫았어요"],
+      ["nl", "De server heeft de verbinding verbroken zonder een antwoord te sturen"],
+      ["no", "Serveren lukket tilkoblingen uten å sende et svar"],
+      ["pl", "Serwer zamknął połączenie bez wysłania odpowiedzi"],
+      ["pt", "O servidor fechou a conexão sem enviar uma resposta"],
+      ["ro", "Serverul a închis conexiunea fără a trimite un răspuns"],
+      ["ru", "Сервер закрыл соединение, не отправив ответ"],
+      ["uk", "Сервер закрив з'єднання, не надіславши відповіді"],
+      ["zh", "服务器未发送响应就关闭了连接"],
+    ])],
+    [t("The server detected an infinite loop while processing the request"), new Map([
+      ["de", "Der Server hat bei der Verarbeitung der Anfrage eine Endlosschleife erkannt"],
+      ["es", "El servidor detectó un bucle infinito al procesar la petición"],
+      ["fr", "Le serveur a détecté une boucle infinie lors du traitement de la requête"],
+      ["hu", "A szerver végtelen hurkot észlelt a kérés feldolgozása közben"],
+      ["id", "Server mendeteksi perulangan tak terbatas saat memproses permintaan"],
+      ["it", "Il server ha rilevato un ciclo infinito durante l'elaborazione della richiesta"],
+      ["ko", "서버가 요청을 처리하는 중에 무한 루프를 감지했어요"],
+      ["nl", "De server heeft een oneindige lus gedetecteerd tijdens het verwerken van het verzoek"],
+      ["no", "Serveren oppdaget en uendelig løkke under behandling av forespørselen"],
+      ["pl", "Serwer wykrył nieskończoną pętlę podczas przetwarzania żądania"],
+      ["pt", "O servidor detectou um loop infinito ao processar a solicitação"],
+      ["ro", "Serverul a detectat o buclă infinită în timpul procesării cererii"],
+      ["ru", "Сервер обнаружил бесконечный цикл при обработке запроса"],
+      ["uk", "Сервер виявив нескінченний цикл під час обробки запиту"],
+      ["zh", "服务器在处理请求时检测到无限循环"],
+    ])],
     [t("The server did not understand the request"), new Map([
       ["de", "Der Server hat die Anfrage nicht verstanden"],
       ["es", "El servidor no entendió la petición"],
@@ -956,6 +1993,40 @@
       ["uk", "Сервер не підтримує запитану версію HTTP-протоколу"],
       ["zh", "服务器不支持该HTTP协议版本"],
     ])],
+    [t("The server does not support the functionality required by the request"), new Map([
+      ["de", "Der Server unterstützt die für die Anfrage erforderliche Funktionalität nicht"],
+      ["es", "El servidor no admite la funcionalidad requerida por la petición"],
+      ["fr", "Le serveur ne prend pas en charge la fonctionnalité requise par la requête"],
+      ["hu", "A szerver nem támogatja a kéréshez szükséges funkciót"],
+      ["id", "Server tidak mendukung fungsionalitas yang dibutuhkan oleh permintaan"],
+      ["it", "Il server non supporta la funzionalità richiesta dalla richiesta"],
+      ["ko", "서버가 요청에 필요한 기능을 지원하지 않아요"],
+      ["nl", "De server ondersteunt de functionaliteit die het verzoek vereist niet"],
+      ["no", "Serveren støtter ikke funksjonaliteten som forespørselen krever"],
+      ["pl", "Serwer nie obsługuje funkcjonalności wymaganej przez żądanie"],
+      ["pt", "O servidor não suporta a funcionalidade exigida pela solicitação"],
+      ["ro", "Serverul nu acceptă funcționalitatea necesară cererii"],
+      ["ru", "Сервер не поддерживает функциональность, необходимую для выполнения запроса"],
+      ["uk", "Сервер не підтримує функціональність, необхідну для виконання запиту"],
+      ["zh", "服务器不支持请求所需的功能"],
+    ])],
+    [t("The server has a configuration error in the content negotiation"), new Map([
+      ["de", "Der Server hat einen Konfigurationsfehler in der Inhaltsaushandlung"],
+      ["es", "El servidor tiene un error de configuración en la negociación de contenido"],
+      ["fr", "Le serveur a une erreur de configuration dans la négociation de contenu"],
+      ["hu", "A szerver tartalomegyeztetési beállításaiban hiba van"],
+      ["id", "Server memiliki kesalahan konfigurasi dalam negosiasi konten"],
+      ["it", "Il server ha un errore di configurazione nella negoziazione del contenuto"],
+      ["ko", "서버의 콘텐츠 협상 구성에 오류가 있어요"],
+      ["nl", "De server heeft een configuratiefout in de onderhandeling over inhoud"],
+      ["no", "Serveren har en konfigurasjonsfeil i innholdsforhandlingen"],
+      ["pl", "Serwer ma błąd konfiguracji w negocjacji treści"],
+      ["pt", "O servidor tem um erro de configuração na negociação de conteúdo"],
+      ["ro", "Serverul are o eroare de configurare în negocierea conținutului"],
+      ["ru", "В настройке согласования содержимого на сервере есть ошибка"],
+      ["uk", "У налаштуванні узгодження вмісту на сервері є помилка"],
+      ["zh", "服务器的内容协商存在配置错误"],
+    ])],
     [t("The server is temporarily overloading or down"), new Map([
       ["de", "Der Server ist vorübergehend überlastet oder ausgefallen"],
       ["es", "El servidor está temporalmente sobrecargado o inactivo"],
@@ -973,6 +2044,40 @@
       ["uk", "Сервер тимчасово не може обробляти запити з технічних причин"],
       ["zh", "服务器暂时过载或不可用"],
     ])],
+    [t("The server is unable to store the data needed to complete the request"), new Map([
+      ["de", "Der Server kann die zur Ausführung der Anfrage benötigten Daten nicht speichern"],
+      ["es", "El servidor no puede almacenar los datos necesarios para completar la petición"],
+      ["fr", "Le serveur ne peut pas stocker les données nécessaires pour traiter la requête"],
+      ["hu", "A szerver nem tudja eltárolni a kérés teljesítéséhez szükséges adatokat"],
+      ["id", "Server tidak dapat menyimpan data yang diperlukan untuk menyelesaikan permintaan"],
+      ["it", "Il server non è in grado di memorizzare i dati necessari per completare la richiesta"],
+      ["ko", "서버가 요청을 완료하는 데 필요한 데이터를 저장할 수 없어요"],
+      ["nl", "De server kan de gegevens die nodig zijn om het verzoek te voltooien niet opslaan"],
+      ["no", "Serveren kan ikke lagre dataene som trengs for å fullføre forespørselen"],
+      ["pl", "Serwer nie może zapisać danych potrzebnych do realizacji żądania"],
+      ["pt", "O servidor não consegue armazenar os dados necessários para concluir a solicitação"],
+      ["ro", "Serverul nu poate stoca datele necesare pentru finalizarea cererii"],
+      ["ru", "Сервер не может сохранить данные, необходимые для выполнения запроса"],
+      ["uk", "Сервер не може зберегти дані, необхідні для виконання запиту"],
+      ["zh", "服务器无法存储完成请求所需的数据"],
+    ])],
+    [t("The server is unwilling to process a request that might be replayed"), new Map([
+      ["de", "Der Server ist nicht bereit, eine Anfrage zu verarbeiten, die wiederholt werden könnte"],
+      ["es", "El servidor no está dispuesto a procesar una petición que podría repetirse"],
+      ["fr", "Le serveur refuse de traiter une requête susceptible d'être rejouée"],
+      ["hu", "A szerver nem hajlandó feldolgozni egy esetleg újrajátszható kérést"],
+      ["id", "Server tidak bersedia memproses permintaan yang mungkin diputar ulang"],
+      ["it", "Il server non è disposto a elaborare una richiesta che potrebbe essere ripetuta"],
+      ["ko", "서버가 재전송될 수 있는 요청을 처리하지 않으려 해요"],
+      ["nl", "De server wil geen verzoek verwerken dat mogelijk opnieuw wordt afgespeeld"],
+      ["no", "Serveren er ikke villig til å behandle en forespørsel som kan bli spilt av på nytt"],
+      ["pl", "Serwer nie chce przetwarzać żądania, które może zostać powtórzone"],
+      ["pt", "O servidor não está disposto a processar uma solicitação que pode ser repetida"],
+      ["ro", "Serverul nu dorește să proceseze o cerere care ar putea fi repetată"],
+      ["ru", "Сервер не готов обрабатывать запрос, который может быть повторён"],
+      ["uk", "Сервер не готовий обробляти запит, який може бути повторено"],
+      ["zh", "服务器不愿处理可能被重放的请求"],
+    ])],
     [t("The server met an unexpected condition"), new Map([
       ["de", "Der Server hat einen internen Fehler festgestellt"],
       ["es", "El servidor ha encontrado una condición no esperada"],
@@ -1007,6 +2112,23 @@
       ["uk", "Сервер отримав невірну відповідь від попереднього сервера"],
       ["zh", "服务器从上游服务器收到了无效的响应"],
     ])],
+    [t("The server requires the request to be conditional"), new Map([
+      ["de", "Der Server verlangt, dass die Anfrage bedingt ist"],
+      ["es", "El servidor requiere que la petición sea condicional"],
+      ["fr", "Le serveur exige que la requête soit conditionnelle"],
+      ["hu", "A szerver feltételes kérést vár el"],
+      ["id", "Server mengharuskan permintaan bersifat kondisional"],
+      ["it", "Il server richiede che la richiesta sia condizionale"],
+      ["ko", "서버가 조건부 요청을 요구해요"],
+      ["nl", "De server vereist dat het verzoek voorwaardelijk is"],
+      ["no", "Serveren krever at forespørselen er betinget"],
+      ["pl", "Serwer wymaga, aby żądanie było warunkowe"],
+      ["pt", "O servidor exige que a solicitação seja condicional"],
+      ["ro", "Serverul cere ca cererea să fie condiționată"],
+      ["ru", "Сервер требует, чтобы запрос был условным"],
+      ["uk", "Сервер вимагає, щоб запит був умовним"],
+      ["zh", "服务器要求请求必须是有条件的"],
+    ])],
     [t("The server will not accept the request, because the request entity is too large"), new Map([
       ["de", "Der Server akzeptiert die Anfrage nicht, da die Datenmenge zu groß ist"],
       ["es", "El servidor no aceptará esta petición, porque la carga es demasiado grande"],
@@ -1024,6 +2146,40 @@
       ["uk", "Сервер не може обробити запит, оскільки він занадто великий"],
       ["zh", "请求体过大，服务器将不接受该请求"],
     ])],
+    [t("The site can not process the request, because it is overloaded"), new Map([
+      ["de", "Die Website kann die Anfrage nicht verarbeiten, da sie überlastet ist"],
+      ["es", "El sitio no puede procesar la petición porque está sobrecargado"],
+      ["fr", "Le site ne peut pas traiter la requête, car il est surchargé"],
+      ["hu", "Az oldal nem tudja feldolgozni a kérést, mert túlterhelt"],
+      ["id", "Situs tidak dapat memproses permintaan karena kelebihan beban"],
+      ["it", "Il sito non può elaborare la richiesta perché è sovraccarico"],
+      ["ko", "사이트가 과부하 상태라 요청을 처리할 수 없어요"],
+      ["nl", "De site kan het verzoek niet verwerken, omdat deze overbelast is"],
+      ["no", "Nettstedet kan ikke behandle forespørselen fordi det er overbelastet"],
+      ["pl", "Witryna nie może przetworzyć żądania, ponieważ jest przeciążona"],
+      ["pt", "O site não consegue processar a solicitação porque está sobrecarregado"],
+      ["ro", "Site-ul nu poate procesa cererea, deoarece este supraîncărcat"],
+      ["ru", "Сайт не может обработать запрос, так как он перегружен"],
+      ["uk", "Сайт не може обробити запит, оскільки він перевантажений"],
+      ["zh", "网站过载，无法处理请求"],
+    ])],
+    [t("The site has used up the bandwidth allowed by its hosting plan"), new Map([
+      ["de", "Die Website hat die in ihrem Hosting-Tarif erlaubte Bandbreite aufgebraucht"],
+      ["es", "El sitio ha agotado el ancho de banda permitido por su plan de alojamiento"],
+      ["fr", "Le site a épuisé la bande passante autorisée par son offre d'hébergement"],
+      ["hu", "A webhely elhasználta a tárhelycsomagjában engedélyezett sávszélességet"],
+      ["id", "Situs telah menghabiskan bandwidth yang diizinkan oleh paket hostingnya"],
+      ["it", "Il sito ha esaurito la banda consentita dal suo piano di hosting"],
+      ["ko", "사이트가 호스팅 요금제에서 허용된 대역폭을 모두 사용했어요"],
+      ["nl", "De site heeft de bandbreedte van zijn hostingpakket opgebruikt"],
+      ["no", "Nettstedet har brukt opp båndbredden som vertsplanen tillater"],
+      ["pl", "Witryna wykorzystała transfer dozwolony w jej planie hostingowym"],
+      ["pt", "O site esgotou a largura de banda permitida pelo seu plano de hospedagem"],
+      ["ro", "Site-ul a consumat lățimea de bandă permisă de planul său de găzduire"],
+      ["ru", "Сайт израсходовал трафик, разрешённый его тарифом хостинга"],
+      ["uk", "Сайт вичерпав трафік, дозволений його тарифом хостингу"],
+      ["zh", "该网站已用完其托管套餐允许的带宽"],
+    ])],
     [t("The site was moved"), new Map([
       ["de", "Die Seite wurde verschoben"],
       ["es", "El sitio se ha trasladado"],
@@ -1058,6 +2214,23 @@
       ["uk", "Мітка часу"],
       ["zh", "时间戳"],
     ])],
+    [t("Too Early"), new Map([
+      ["de", "Zu früh"],
+      ["es", "Demasiado pronto"],
+      ["fr", "Trop tôt"],
+      ["hu", "Túl korai"],
+      ["id", "Terlalu dini"],
+      ["it", "Troppo presto"],
+      ["ko", "너무 이름"],
+      ["nl", "Te vroeg"],
+      ["no", "For tidlig"],
+      ["pl", "Zbyt wcześnie"],
+      ["pt", "Muito cedo"],
+      ["ro", "Prea devreme"],
+      ["ru", "Слишком рано"],
+      ["uk", "Занадто рано"],
+      ["zh", "太早"],
+    ])],
     [t("Too Many Requests"), new Map([
       ["de", "Zu viele Anfragen"],
       ["es", "Demasiadas peticiones"],
@@ -1109,6 +2282,23 @@
       ["uk", "Упс"],
       ["zh", "哎呀"],
     ])],
+    [t("URI Too Long"), new Map([
+      ["de", "URI zu lang"],
+      ["es", "URI demasiado larga"],
+      ["fr", "URI trop longue"],
+      ["hu", "Túl hosszú URI"],
+      ["id", "URI terlalu panjang"],
+      ["it", "URI troppo lungo"],
+      ["ko", "URI가 너무 김"],
+      ["nl", "URI te lang"],
+      ["no", "URI-en er for lang"],
+      ["pl", "URI jest zbyt długi"],
+      ["pt", "URI muito longa"],
+      ["ro", "URI prea lung"],
+      ["ru", "Слишком длинный URI"],
+      ["uk", "Занадто довгий URI"],
+      ["zh", "URI 过长"],
+    ])],
     [t("Unauthorized"), new Map([
       ["de", "Nicht autorisiert"],
       ["es", "No autorizado"],
@@ -1126,6 +2316,23 @@
       ["uk", "Несанкціонований доступ"],
       ["zh", "未经授权"],
     ])],
+    [t("Unavailable For Legal Reasons"), new Map([
+      ["de", "Aus rechtlichen Gründen nicht verfügbar"],
+      ["es", "No disponible por razones legales"],
+      ["fr", "Indisponible pour raisons légales"],
+      ["hu", "Jogi okokból nem elérhető"],
+      ["id", "Tidak tersedia karena alasan hukum"],
+      ["it", "Non disponibile per motivi legali"],
+      ["ko", "법적 사유로 이용 불가"],
+      ["nl", "Niet beschikbaar om juridische redenen"],
+      ["no", "Utilgjengelig av juridiske årsaker"],
+      ["pl", "Niedostępne z przyczyn prawnych"],
+      ["pt", "Indisponível por motivos legais"],
+      ["ro", "Indisponibil din motive legale"],
+      ["ru", "Недоступно по юридическим причинам"],
+      ["uk", "Недоступно з юридичних причин"],
+      ["zh", "因法律原因不可用"],
+    ])],
     [t("Unknown"), new Map([
       ["de", "Unbekannt"],
       ["es", "Desconocido"],
@@ -1143,6 +2350,74 @@
       ["uk", "Невідомо"],
       ["zh", "未知"],
     ])],
+    [t("Unprocessable Entity"), new Map([
+      ["de", "Nicht verarbeitbare Entität"],
+      ["es", "Entidad no procesable"],
+      ["fr", "Entité non traitable"],
+      ["hu", "Feldolgozhatatlan entitás"],
+      ["id", "Entitas tidak dapat diproses"],
+      ["it", "Entità non elaborabile"],
+      ["ko", "처리할 수 없는 엔티티"],
+      ["nl", "Onverwerkbare entiteit"],
+      ["no", "Kan ikke behandles"],
+      ["pl", "Nieprzetwarzalna encja"],
+      ["pt", "Entidade não processável"],
+      ["ro", "Entitate neprocesabilă"],
+      ["ru", "Необрабатываемый экземпляр"],
+      ["uk", "Необроблюваний запит"],
+      ["zh", "无法处理的实体"],
+    ])],
+    [t("Unsupported Media Type"), new Map([
+      ["de", "Nicht unterstützter Medientyp"],
+      ["es", "Tipo de medio no soportado"],
+      ["fr", "Type de média non pris en charge"],
+      ["hu", "Nem támogatott médiatípus"],
+      ["id", "Jenis media tidak didukung"],
+      ["it", "Tipo di media non supportato"],
+      ["ko", "지원되지 않는 미디어 유형"],
+      ["nl", "Niet-ondersteund mediatype"],
+      ["no", "Medietypen støttes ikke"],
+      ["pl", "Nieobsługiwany typ danych"],
+      ["pt", "Tipo de mídia não suportado"],
+      ["ro", "Tip media neacceptat"],
+      ["ru", "Неподдерживаемый тип данных"],
+      ["uk", "Непідтримуваний тип даних"],
+      ["zh", "不支持的媒体类型"],
+    ])],
+    [t("Upgrade Required"), new Map([
+      ["de", "Upgrade erforderlich"],
+      ["es", "Actualización requerida"],
+      ["fr", "Mise à niveau requise"],
+      ["hu", "Frissítés szükséges"],
+      ["id", "Peningkatan diperlukan"],
+      ["it", "Aggiornamento richiesto"],
+      ["ko", "업그레이드 필요"],
+      ["nl", "Upgrade vereist"],
+      ["no", "Oppgradering kreves"],
+      ["pl", "Wymagana aktualizacja"],
+      ["pt", "Atualização necessária"],
+      ["ro", "Actualizare necesară"],
+      ["ru", "Требуется обновление"],
+      ["uk", "Потрібне оновлення"],
+      ["zh", "需要升级"],
+    ])],
+    [t("Variant Also Negotiates"), new Map([
+      ["de", "Variante verhandelt ebenfalls"],
+      ["es", "La variante también negocia"],
+      ["fr", "La variante négocie également"],
+      ["hu", "A változat is egyeztet"],
+      ["id", "Varian juga bernegosiasi"],
+      ["it", "Anche la variante negozia"],
+      ["ko", "변형도 협상함"],
+      ["nl", "Variant onderhandelt ook"],
+      ["no", "Variant forhandler også"],
+      ["pl", "Wariant również negocjuje"],
+      ["pt", "Variante também negocia"],
+      ["ro", "Varianta negociază de asemenea"],
+      ["ru", "Вариант тоже проводит согласование"],
+      ["uk", "Варіант теж узгоджується"],
+      ["zh", "变体也在协商"],
+    ])],
     [t("Web Server"), new Map([
       ["de", "Webserver"],
       ["es", "Servidor Web"],
@@ -1160,6 +2435,40 @@
       ["uk", "Web-сервер"],
       ["zh", "Web服务器"],
     ])],
+    [t("Web Server Is Down"), new Map([
+      ["de", "Webserver ist nicht erreichbar"],
+      ["es", "El servidor web está caído"],
+      ["fr", "Le serveur web est hors service"],
+      ["hu", "A webszerver nem működik"],
+      ["id", "Server web sedang mati"],
+      ["it", "Il server web non è attivo"],
+      ["ko", "웹 서버가 다운됨"],
+      ["nl", "Webserver is niet beschikbaar"],
+      ["no", "Webserveren er nede"],
+      ["pl", "Serwer WWW nie działa"],
+      ["pt", "O servidor web está fora do ar"],
+      ["ro", "Serverul web este căzut"],
+      ["ru", "Веб-сервер не работает"],
+      ["uk", "Вебсервер не працює"],
+      ["zh", "Web 服务器已宕机"],
+    ])],
+    [t("Web Server Returned an Unknown Error"), new Map([
+      ["de", "Webserver hat einen unbekannten Fehler zurückgegeben"],
+      ["es", "El servidor web devolvió un error desconocido"],
+      ["fr", "Le serveur web a renvoyé une erreur inconnue"],
+      ["hu", "A webszerver ismeretlen hibát adott vissza"],
+      ["id", "Server web mengembalikan kesalahan yang tidak diketahui"],
+      ["it", "Il server web ha restituito un errore sconosciuto"],
+      ["ko", "웹 서버가 알 수 없는 오류를 반환함"],
+      ["nl", "Webserver gaf een onbekende fout"],
+      ["no", "Webserveren returnerte en ukjent feil"],
+      ["pl", "Serwer WWW zwrócił nieznany błąd"],
+      ["pt", "O servidor web retornou um erro desconhecido"],
+      ["ro", "Serverul web a returnat o eroare necunoscută"],
+      ["ru", "Веб-сервер вернул неизвестную ошибку"],
+      ["uk", "Вебсервер повернув невідому помилку"],
+      ["zh", "Web 服务器返回了未知错误"],
+    ])],
     [t("What can I do?"), new Map([
       ["de", "Was kann ich machen?"],
       ["es", "¿Que puedo hacer?"],
@@ -1228,6 +2537,23 @@
       ["uk", "Ви могли помилитися в URL-адресі"],
       ["zh", "您可能输入了错误的地址"],
     ])],
+    [t("You must authenticate to gain network access"), new Map([
+      ["de", "Sie müssen sich authentifizieren, um Netzwerkzugang zu erhalten"],
+      ["es", "Debe autenticarse para obtener acceso a la red"],
+      ["fr", "Vous devez vous authentifier pour accéder au réseau"],
+      ["hu", "A hálózati hozzáféréshez hitelesítés szükséges"],
+      ["id", "Anda harus melakukan autentikasi untuk mendapatkan akses jaringan"],
+      ["it", "È necessario autenticarsi per accedere alla rete"],
+      ["ko", "네트워크에 접근하려면 인증해야 해요"],
+      ["nl", "U moet zich authenticeren om netwerktoegang te krijgen"],
+      ["no", "Du må autentisere deg for å få nettverkstilgang"],
+      ["pl", "Musisz się uwierzytelnić, aby uzyskać dostęp do sieci"],
+      ["pt", "Você deve se autenticar para obter acesso à rede"],
+      ["ro", "Trebuie să vă autentificați pentru a obține acces la rețea"],
+      ["ru", "Для доступа к сети необходимо пройти аутентификацию"],
+      ["uk", "Для доступу до мережі потрібно пройти автентифікацію"],
+      ["zh", "您必须进行认证才能访问网络"],
+    ])],
     [t("You must authenticate with a proxy server before this request can be served"), new Map([
       ["de", "Sie müssen sich bei einem Proxy-Server authentifizieren, bevor diese Anfrage bedient werden kann"],
       ["es", "Debes autentificarte con un servidor proxy antes de que esta petición pueda ser atendida"],
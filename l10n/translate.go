@@ -0,0 +1,149 @@
+package l10n
+
+import (
+	_ "embed"
+	"encoding/json"
+	"html"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+)
+
+//go:embed locales.json
+var localesJSON []byte
+
+// translations maps the tokens (see [token]) to the translations, keyed by the lowercase language codes.
+type translations map[string]map[string]string
+
+// locales returns the parsed locales.json file (the file is embedded and validated by the generator, so the parsing
+// errors are not expected; the invalid entries are skipped the same way the generator does).
+var locales = sync.OnceValue(func() translations { //nolint:gochecknoglobals
+	var raw map[string]json.RawMessage
+
+	_ = json.Unmarshal(localesJSON, &raw) //nolint:errcheck // the embedded file is always valid
+
+	var result = make(translations, len(raw))
+
+	for key, val := range raw {
+		var byLang map[string]string
+		if err := json.Unmarshal(val, &byLang); err != nil {
+			continue // skip non-object values, like "$schema"
+		}
+
+		lowered := make(map[string]string, len(byLang))
+
+		for lang, text := range byLang {
+			lowered[strings.ToLower(lang)] = text
+		}
+
+		result[token(key)] = lowered
+	}
+
+	return result
+})
+
+// token lowercases the string and removes all characters except the latin letters and digits, so that the raw text
+// matches the token regardless of the whitespace, punctuation and case (the same way the browser script does).
+func token(s string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			return r
+		}
+
+		return -1
+	}, strings.ToLower(s))
+}
+
+// languages returns the sorted list of the supported language codes.
+var languages = sync.OnceValue(func() []string { //nolint:gochecknoglobals
+	var set = make(map[string]struct{})
+
+	for _, byLang := range locales() {
+		for lang := range byLang {
+			set[lang] = struct{}{}
+		}
+	}
+
+	return slices.Sorted(maps.Keys(set))
+})
+
+// Languages returns the sorted list of the supported language codes (English, the default one, is not included).
+func Languages() []string { return slices.Clone(languages()) }
+
+// ResolveLanguage resolves the BCP 47 language tag to the supported language code, falling back to the base language
+// if necessary (e.g. "fr-CA" resolves to "fr"). English tags always resolve to "en".
+func ResolveLanguage(tag string) (string, bool) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+
+	if tag == "en" || strings.HasPrefix(tag, "en-") {
+		return "en", true
+	}
+
+	supported := languages()
+
+	if slices.Contains(supported, tag) {
+		return tag, true
+	}
+
+	if base, _, ok := strings.Cut(tag, "-"); ok && slices.Contains(supported, base) {
+		return base, true
+	}
+
+	return "", false
+}
+
+// Translate returns the translation of the (usually English) text to the language, if there is one.
+func Translate(text, lang string) (string, bool) {
+	lang, ok := ResolveLanguage(lang)
+	if !ok || lang == "en" {
+		return "", false
+	}
+
+	translated, ok := locales()[token(text)][lang]
+
+	return translated, ok && translated != ""
+}
+
+var (
+	// l10nElement matches the opening tag with the data-l10n attribute, the text content and the closing tag name.
+	l10nElement = regexp.MustCompile( //nolint:gochecknoglobals
+		`<([a-zA-Z][a-zA-Z0-9-]*)(\s[^>]*?)?\sdata-l10n(?:\s*=\s*(?:"[^"]*"|'[^']*'))?(\s[^>]*)?>` +
+			`([^<]*)</([a-zA-Z0-9-]+)\s*>`,
+	)
+	htmlLang = regexp.MustCompile(`(<html\b[^>]*?\slang\s*=\s*)("[^"]*"|'[^']*'|[^\s>]*)`) //nolint:gochecknoglobals
+)
+
+// LocalizeHTML translates the text of the elements with the data-l10n attribute to the language at once, as the
+// browser script would do, and sets the language of the document. Elements with the nested markup and the texts
+// without translations are kept as is.
+//
+// The original text of every translated element is kept in the data-l10n attribute, so that the script (if the page
+// still includes it) is able to translate the page to another language later.
+func LocalizeHTML(doc, lang string) string {
+	doc = l10nElement.ReplaceAllStringFunc(doc, func(el string) string {
+		m := l10nElement.FindStringSubmatch(el)
+
+		openName, before, after, text, closeName := m[1], m[2], m[3], m[4], m[5]
+		if !strings.EqualFold(openName, closeName) {
+			return el
+		}
+
+		original := html.UnescapeString(text)
+
+		translated, ok := Translate(original, lang)
+		if !ok {
+			return el
+		}
+
+		return "<" + openName + before + ` data-l10n="` + html.EscapeString(original) + `"` + after + ">" +
+			html.EscapeString(translated) + "</" + closeName + ">"
+	})
+
+	if resolved, ok := ResolveLanguage(lang); ok {
+		doc = htmlLang.ReplaceAllString(doc, `${1}"`+resolved+`"`)
+	}
+
+	return doc
+}
@@ -0,0 +1,104 @@
+package l10n_test
+
+import (
+	"slices"
+	"testing"
+
+	"gh.tarampamp.am/error-pages/v4/internal/testutil/assert"
+	"gh.tarampamp.am/error-pages/v4/l10n"
+)
+
+func TestLanguages(t *testing.T) {
+	t.Parallel()
+
+	got := l10n.Languages()
+
+	assert.True(t, slices.Contains(got, "de"))
+	assert.True(t, slices.Contains(got, "zh"))
+	assert.False(t, slices.Contains(got, "en"))
+	assert.True(t, slices.IsSorted(got))
+}
+
+func TestResolveLanguage(t *testing.T) {
+	t.Parallel()
+
+	for give, want := range map[string]string{
+		"de": "de", "DE": "de", " fr-CA ": "fr", "en": "en", "en-US": "en", "xx": "", "xx-de": "", "": "",
+	} {
+		got, ok := l10n.ResolveLanguage(give)
+
+		assert.Equal(t, want != "", ok)
+		assert.Equal(t, want, got)
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	t.Parallel()
+
+	got, ok := l10n.Translate("Not Found", "de")
+	assert.True(t, ok)
+	assert.Equal(t, "Nicht gefunden", got)
+
+	got, ok = l10n.Translate("  not-found!", "de-AT") // the token and the language are normalized
+	assert.True(t, ok)
+	assert.Equal(t, "Nicht gefunden", got)
+
+	_, ok = l10n.Translate("Not Found", "en")
+	assert.False(t, ok)
+
+	_, ok = l10n.Translate("Not Found", "xx")
+	assert.False(t, ok)
+
+	_, ok = l10n.Translate("There is no such token", "de")
+	assert.False(t, ok)
+}
+
+func TestLocalizeHTML(t *testing.T) {
+	t.Parallel()
+
+	for name, tt := range map[string]struct {
+		give, lang, want string
+	}{
+		"translated": {
+			give: `<html lang="en"><h1 class="a" data-l10n>Not Found</h1></html>`,
+			lang: "de",
+			want: `<html lang="de"><h1 class="a" data-l10n="Not Found">Nicht gefunden</h1></html>`,
+		},
+		"attribute after": {
+			give: `<span data-l10n id="x">Not Found</span>`,
+			lang: "fr",
+			want: `<span data-l10n="Not Found" id="x">Introuvable</span>`,
+		},
+		"escaped text": {
+			give: `<p data-l10n>Bad&#32;Request</p>`,
+			lang: "de",
+			want: `<p data-l10n="Bad Request">Fehlerhafte Anfrage</p>`,
+		},
+		"no translation": {
+			give: `<p data-l10n>Something else</p>`,
+			lang: "de",
+			want: `<p data-l10n>Something else</p>`,
+		},
+		"nested markup": {
+			give: `<p data-l10n><b>Not Found</b></p>`,
+			lang: "de",
+			want: `<p data-l10n><b>Not Found</b></p>`,
+		},
+		"no marker": {
+			give: `<p>Not Found</p><p data-l10n-x>Not Found</p>`,
+			lang: "de",
+			want: `<p>Not Found</p><p data-l10n-x>Not Found</p>`,
+		},
+		"english": {
+			give: `<html lang="en"><p data-l10n>Not Found</p>`,
+			lang: "en",
+			want: `<html lang="en"><p data-l10n>Not Found</p>`,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, l10n.LocalizeHTML(tt.give, tt.lang))
+		})
+	}
+}
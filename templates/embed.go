@@ -21,3 +21,18 @@ var XML string
 //
 //go:embed default.tpl.txt
 var PlaintText string
+
+// YAML holds the embedded YAML template for error responses.
+//
+//go:embed default.tpl.yaml
+var YAML string
+
+// Markdown holds the embedded Markdown template for error responses.
+//
+//go:embed default.tpl.md
+var Markdown string
+
+// SVG holds the embedded SVG image template for error responses.
+//
+//go:embed default.tpl.svg
+var SVG string